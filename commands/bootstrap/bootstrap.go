@@ -59,7 +59,7 @@ func init() {
 	bootstrapCmd.PersistentFlags().StringVarP(&locationName,
 		"location", "l", "", "Name identifying the location where to deploy Yorc")
 	bootstrapCmd.PersistentFlags().StringVarP(&infrastructureType,
-		"infrastructure", "i", "", "Define the type of infrastructure where to deploy Yorc: google, openstack, aws, hostspool")
+		"infrastructure", "i", "", "Define the type of infrastructure where to deploy Yorc: google, openstack, aws, azure, hostspool")
 	viper.BindPFlag("infrastructure", bootstrapCmd.PersistentFlags().Lookup("infrastructure"))
 	bootstrapCmd.PersistentFlags().StringVarP(&deploymentType,
 		"deployment_type", "d", "single_node", "Define deployment type: single_node or HA")
@@ -401,6 +401,8 @@ func initializeInputs(inputFilePath, resourcesPath string, configuration config.
 			infrastructureType = "google"
 		case "AWS":
 			infrastructureType = "aws"
+		case "Azure":
+			infrastructureType = "azure"
 		case "HostsPool":
 			infrastructureType = "hostspool"
 		default:
@@ -428,7 +430,7 @@ func initializeInputs(inputFilePath, resourcesPath string, configuration config.
 			fmt.Println("")
 			prompt := &survey.Select{
 				Message: "Select an infrastructure type:",
-				Options: []string{"Google", "AWS", "OpenStack", "HostsPool"},
+				Options: []string{"Google", "AWS", "Azure", "OpenStack", "HostsPool"},
 			}
 			survey.AskOne(prompt, &infraSelected, nil)
 			infrastructureType = strings.ToLower(infraSelected)
@@ -474,6 +476,9 @@ func initializeInputs(inputFilePath, resourcesPath string, configuration config.
 	case "aws":
 		infraNodeType = "org.ystia.yorc.pub.location.AWSConfig"
 		networkNodeType = "yorc.nodes.aws.PublicNetwork"
+	case "azure":
+		infraNodeType = "org.ystia.yorc.pub.location.AzureConfig"
+		networkNodeType = "yorc.nodes.azure.PublicIP"
 	case "hostspool":
 		// No infrastructure defined in case of hosts pool
 		// Hosts Pool don't have network-reletad on-demand resources
@@ -766,6 +771,8 @@ func initializeInputs(inputFilePath, resourcesPath string, configuration config.
 		inputValues.Location.Type = "Google Cloud"
 	case "aws":
 		inputValues.Location.Type = "AWS"
+	case "azure":
+		inputValues.Location.Type = "Azure"
 	case "hostspool":
 		inputValues.Location.Type = "HostsPool"
 	default:
@@ -1532,6 +1539,7 @@ func getTerraformPluginsDownloadURLs() []string {
 		"google":    commands.TfGooglePluginVersion,
 		"openstack": commands.TfOpenStackPluginVersion,
 		"aws":       commands.TfAWSPluginVersion,
+		"azurerm":   commands.TfAzurePluginVersion,
 	}
 
 	var pluginURLs []string
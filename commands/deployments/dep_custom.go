@@ -35,6 +35,7 @@ func init() {
 	var customCName string
 	var interfaceName string
 	var inputs []string
+	var checkMode bool
 	var customCmd = &cobra.Command{
 		Use:   "custom <id>",
 		Short: "Execute a custom command",
@@ -43,7 +44,7 @@ func init() {
 			if err != nil {
 				return err
 			}
-			return executeCustomCommand(client, args, jsonParam, nodeName, customCName, interfaceName, inputs)
+			return executeCustomCommand(client, args, jsonParam, nodeName, customCName, interfaceName, inputs, checkMode)
 		},
 	}
 	customCmd.PersistentFlags().StringVarP(&jsonParam, "data", "d", "", "Provide the JSON format of the custom command with node, interface, custom and inputs data")
@@ -51,10 +52,11 @@ func init() {
 	customCmd.PersistentFlags().StringVarP(&interfaceName, "interface", "", "", "Provide the interface name (mandatory)")
 	customCmd.PersistentFlags().StringVarP(&customCName, "custom", "", "", "Provide the custom command name (mandatory)")
 	customCmd.PersistentFlags().StringArrayVarP(&inputs, "input", "i", make([]string, 0), "Provide the input for the custom command")
+	customCmd.PersistentFlags().BoolVarP(&checkMode, "check", "", false, "Run the custom command in check mode: Ansible implementations report the changes they would make without applying them, other implementations are skipped")
 	DeploymentsCmd.AddCommand(customCmd)
 }
 
-func executeCustomCommand(client httputil.HTTPClient, args []string, jsonParam, nodeName, customCName, interfaceName string, inputs []string) error {
+func executeCustomCommand(client httputil.HTTPClient, args []string, jsonParam, nodeName, customCName, interfaceName string, inputs []string, checkMode bool) error {
 	if len(args) != 1 {
 		return errors.Errorf("Expecting an id (got %d parameters)", len(args))
 	}
@@ -90,7 +92,11 @@ func executeCustomCommand(client httputil.HTTPClient, args []string, jsonParam,
 		jsonParam = string(tmp)
 	}
 
-	request, err := client.NewRequest("POST", "/deployments/"+args[0]+"/custom", bytes.NewBuffer([]byte(jsonParam)))
+	url := "/deployments/" + args[0] + "/custom"
+	if checkMode {
+		url = url + "?checkMode"
+	}
+	request, err := client.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonParam)))
 	if err != nil {
 		return err
 	}
@@ -60,26 +60,26 @@ func (c *httpClientMockExecCustom) PostForm(path string, data url.Values) (*http
 }
 
 func TestExecuteCustomCommand(t *testing.T) {
-	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "node1", "custom", "custom", []string{"key1=\"[value1, value3]\"", "key2=\"value2\""})
+	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "node1", "custom", "custom", []string{"key1=\"[value1, value3]\"", "key2=\"value2\""}, false)
 	require.NoError(t, err, "Failed to execute custom command")
 }
 
 func TestExecuteCustomCommandWithBadInputs(t *testing.T) {
-	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "node1", "custom", "custom", []string{"key1=[value1, value3]", "key2=value2"})
+	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "node1", "custom", "custom", []string{"key1=[value1, value3]", "key2=value2"}, false)
 	require.Error(t, err, "Expected error as inputs aren't quoted")
 }
 
 func TestExecuteCustomCommandWithoutInfo(t *testing.T) {
-	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "", "", "", []string{"key1=value1", "key2=value2"})
+	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{"id"}, "", "", "", "", []string{"key1=value1", "key2=value2"}, false)
 	require.Error(t, err, "Expect error as no info has been provided")
 }
 
 func TestExecuteCustomCommandWithoutID(t *testing.T) {
-	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{}, "", "node1", "custom-command", "interface", []string{})
+	err := executeCustomCommand(&httpClientMockExecCustom{}, []string{}, "", "node1", "custom-command", "interface", []string{}, false)
 	require.Error(t, err, "Expect error as no ID has been provided")
 }
 
 func TestExecuteCustomCommandWithHTTPFailure(t *testing.T) {
-	err := executeCustomCommand(&httpClientMockExecCustom{testID: "fails"}, []string{}, "", "node1", "custom-command", "interface", []string{})
+	err := executeCustomCommand(&httpClientMockExecCustom{testID: "fails"}, []string{}, "", "node1", "custom-command", "interface", []string{}, false)
 	require.Error(t, err, "Expected error due to HTTP failure")
 }
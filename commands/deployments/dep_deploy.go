@@ -16,6 +16,7 @@ package deployments
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -35,19 +36,24 @@ func init() {
 	var shouldStreamLogs bool
 	var shouldStreamEvents bool
 	var deploymentID string
+	var signatureFile string
 	var deployCmd = &cobra.Command{
 		Use:   "deploy <csar_path>",
 		Short: "Deploy an application",
 		Long: `Deploy a file or directory pointed by <csar_path>
 	If <csar_path> point to a valid zip archive it is submitted to Yorc as it.
 	If <csar_path> point to a file or directory it is zipped before being submitted to Yorc.
-	If <csar_path> point to a single file it should be TOSCA YAML description.`,
+	If <csar_path> point to a single file it should be TOSCA YAML description.
+	If the Yorc server verifies CSARs signatures, a zip archive may be submitted
+	along with its detached signature using the --signature flag. Directories
+	should contain a signed TOSCA-Metadata/TOSCA.meta file instead (see the
+	"manifest" command).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := httputil.GetClient(ClientConfig)
 			if err != nil {
 				return err
 			}
-			return deploy(client, args, shouldStreamLogs, shouldStreamEvents, deploymentID)
+			return deploy(client, args, shouldStreamLogs, shouldStreamEvents, deploymentID, signatureFile)
 		},
 	}
 	deployCmd.PersistentFlags().BoolVarP(&shouldStreamLogs, "stream-logs", "l", false, "Stream logs after deploying the CSAR. In this mode logs can't be filtered, to use this feature see the \"log\" command.")
@@ -55,13 +61,22 @@ func init() {
 	// Do not impose a max id length as it doesn't have a concrete impact for now
 	//deployCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a id for this deployment. This id should not already exists, should respect the following format: %q and should be less than %d characters long", rest.YorcDeploymentIDPattern, rest.YorcDeploymentIDMaxLength))
 	deployCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a id for this deployment. This id should not already exists, should respect the following format: %q", rest.YorcDeploymentIDPattern))
+	deployCmd.PersistentFlags().StringVarP(&signatureFile, "signature", "", "", "Path to a detached signature (OpenPGP or cosign) of the CSAR zip archive to submit along with it.")
 	DeploymentsCmd.AddCommand(deployCmd)
 }
 
-func deploy(client httputil.HTTPClient, args []string, shouldStreamLogs, shouldStreamEvents bool, deploymentID string) error {
+func deploy(client httputil.HTTPClient, args []string, shouldStreamLogs, shouldStreamEvents bool, deploymentID, signatureFile string) error {
 	if len(args) != 1 {
 		return errors.Errorf("Expecting a path to a file or directory (got %d parameters)", len(args))
 	}
+	var signature []byte
+	if signatureFile != "" {
+		var err error
+		signature, err = ioutil.ReadFile(signatureFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read CSAR signature")
+		}
+	}
 
 	absPath, err := filepath.Abs(args[0])
 	if err != nil {
@@ -85,7 +100,7 @@ func deploy(client httputil.HTTPClient, args []string, shouldStreamLogs, shouldS
 		}
 		fileType := http.DetectContentType(buff)
 		if fileType == "application/zip" {
-			location, err = SubmitCSAR(buff, client, deploymentID)
+			location, err = SubmitSignedCSAR(buff, signature, client, deploymentID)
 			if err != nil {
 				return err
 			}
@@ -93,6 +108,9 @@ func deploy(client httputil.HTTPClient, args []string, shouldStreamLogs, shouldS
 	}
 
 	if location == "" {
+		if signature != nil {
+			return errors.New("A detached signature can only be provided for a zip archive, sign the TOSCA-Metadata/TOSCA.meta file of other CSARs")
+		}
 		csarZip, err := ziputil.ZipPath(absPath)
 		if err != nil {
 			return err
@@ -121,6 +139,13 @@ func deploy(client httputil.HTTPClient, args []string, shouldStreamLogs, shouldS
 
 // SubmitCSAR submits the deployment of an archive
 func SubmitCSAR(csarZip []byte, client httputil.HTTPClient, deploymentID string) (string, error) {
+	return SubmitSignedCSAR(csarZip, nil, client, deploymentID)
+}
+
+// SubmitSignedCSAR submits the deployment of an archive along with its detached signature
+//
+// The signature is not sent if empty.
+func SubmitSignedCSAR(csarZip, signature []byte, client httputil.HTTPClient, deploymentID string) (string, error) {
 	var request *http.Request
 	var err error
 	if deploymentID != "" {
@@ -132,6 +157,9 @@ func SubmitCSAR(csarZip []byte, client httputil.HTTPClient, deploymentID string)
 		return "", err
 	}
 	request.Header.Add("Content-Type", "application/zip")
+	if len(signature) > 0 {
+		request.Header.Set(rest.CSARSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	}
 	response, err := client.Do(request)
 	if err != nil {
 		return "", err
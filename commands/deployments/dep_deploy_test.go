@@ -61,21 +61,32 @@ func (c *httpClientMockDeploy) PostForm(path string, data url.Values) (*http.Res
 }
 
 func TestDeploy(t *testing.T) {
-	err := deploy(&httpClientMockDeploy{}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID")
+	err := deploy(&httpClientMockDeploy{}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID", "")
 	require.NoError(t, err, "Failed to deploy")
 }
 
 func TestDeployWithoutFilePath(t *testing.T) {
-	err := deploy(&httpClientMockDeploy{}, []string{}, false, false, "myDeploymentID")
+	err := deploy(&httpClientMockDeploy{}, []string{}, false, false, "myDeploymentID", "")
 	require.Error(t, err, "Expect error as no file path has been provided")
 }
 
 func TestDeployWithBadFilePath(t *testing.T) {
-	err := deploy(&httpClientMockDeploy{}, []string{"fake.zip"}, false, false, "myDeploymentID")
+	err := deploy(&httpClientMockDeploy{}, []string{"fake.zip"}, false, false, "myDeploymentID", "")
 	require.Error(t, err, "Expect error as file doesn't exist")
 }
 
 func TestDeployWithHTTPFailure(t *testing.T) {
-	err := deploy(&httpClientMockDeploy{testID: "fails"}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID")
+	err := deploy(&httpClientMockDeploy{testID: "fails"}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID", "")
 	require.Error(t, err, "Expected error due to HTTP failure")
 }
+
+func TestDeployWithSignature(t *testing.T) {
+	err := deploy(&httpClientMockDeploy{}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID", "./testdata/deployment.zip")
+	require.NoError(t, err, "Failed to deploy")
+
+	err = deploy(&httpClientMockDeploy{}, []string{"./testdata"}, false, false, "myDeploymentID", "./testdata/deployment.zip")
+	require.Error(t, err, "Expect error as only zip archives can have a detached signature")
+
+	err = deploy(&httpClientMockDeploy{}, []string{"./testdata/deployment.zip"}, false, false, "myDeploymentID", "fake.sig")
+	require.Error(t, err, "Expect error as signature file doesn't exist")
+}
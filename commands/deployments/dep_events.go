@@ -198,6 +198,8 @@ func formatEvent(event json.RawMessage, colorize bool) string {
 			data[events.ETaskID.String()], data[events.ETaskExecutionID.String()], data[events.EWorkflowID.String()], data[events.EInstanceID.String()], data[events.EWorkflowStepID.String()], data[events.ENodeID.String()], data[events.EOperationName.String()], formatOptionalInfo(data), data[events.EStatus.String()])
 	case events.StatusChangeTypeAttributeValue:
 		ret = fmt.Sprintf("%s:\t Deployment: %s\t Node: %s\t Instance: %s\t Attribute: %s\t Value: %s\t Status: %s\t\n", ts, data[events.EDeploymentID.String()], data[events.ENodeID.String()], data[events.EInstanceID.String()], data[events.EAttributeName.String()], data[events.EAttributeValue.String()], data[events.EStatus.String()])
+	case events.StatusChangeTypeAnsibleTask:
+		ret = fmt.Sprintf("%s:\t Deployment: %s\t Node: %s\t Instance: %s\t Host: %s\t Ansible task: %q\t Duration: %s\t Status: %s\n", ts, data[events.EDeploymentID.String()], data[events.ENodeID.String()], data[events.EInstanceID.String()], data[events.EHostName.String()], data[events.EAnsibleTaskName.String()], data[events.EDuration.String()], data[events.EStatus.String()])
 
 	}
 
@@ -0,0 +1,58 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/helper/csarutil"
+)
+
+func init() {
+	var manifestCmd = &cobra.Command{
+		Use:   "manifest <csar_directory>",
+		Short: "Write the digests of the files of a CSAR directory into its TOSCA.meta manifest",
+		Long: `Write into the TOSCA-Metadata/TOSCA.meta file of a CSAR directory the digests of all its files.
+	Existing entries of TOSCA.meta are kept, it is created if it does not exist.
+	The resulting TOSCA.meta file should then be signed into TOSCA-Metadata/TOSCA.meta.sig
+	for instance using:
+	  gpg --detach-sign -o TOSCA-Metadata/TOSCA.meta.sig TOSCA-Metadata/TOSCA.meta
+	or:
+	  cosign sign-blob --key cosign.key --output-signature TOSCA-Metadata/TOSCA.meta.sig TOSCA-Metadata/TOSCA.meta`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a path to a CSAR directory (got %d parameters)", len(args))
+			}
+			fileInfo, err := os.Stat(args[0])
+			if err != nil {
+				return err
+			}
+			if !fileInfo.IsDir() {
+				return errors.Errorf("%q is not a directory", args[0])
+			}
+			err = csarutil.UpdateMetaDigests(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Digests written into %s, it should now be signed into %s\n", csarutil.MetaFile, csarutil.MetaSignatureFile)
+			return nil
+		},
+	}
+	DeploymentsCmd.AddCommand(manifestCmd)
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+)
+
+func init() {
+	var stateShowCmd = &cobra.Command{
+		Use:   "show <DeploymentId> <NodeName>",
+		Short: "Show the Terraform state of a node",
+		Long: `Print the Terraform state of a given node as stored in the Terraform backend
+configured for the node location.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.Errorf("Expecting a deployment id and a node name (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			request, err := client.NewRequest("GET", path.Join("/deployments", args[0], "nodes", args[1], "terraform_state"), nil)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			request.Header.Add("Accept", "application/json")
+			response, err := client.Do(request)
+			if err != nil {
+				httputil.ErrExit(errors.Wrap(err, httputil.YorcAPIDefaultErrorMsg))
+			}
+			defer response.Body.Close()
+			httputil.HandleHTTPStatusCode(response, args[0]+"/"+args[1], "deployment/node", http.StatusOK)
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			fmt.Println(string(body))
+			return nil
+		},
+	}
+
+	var force bool
+	var stateImportCmd = &cobra.Command{
+		Use:   "import <DeploymentId> <NodeName> <StateFile>",
+		Short: "Import a Terraform state for a node",
+		Long: `Store the given Terraform state file for a node into the Terraform backend
+configured for the node location. Use "-" as state file to read it from the standard input.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 {
+				return errors.Errorf("Expecting a deployment id, a node name and a state file (got %d parameters)", len(args))
+			}
+			var state []byte
+			var err error
+			if args[2] == "-" {
+				state, err = ioutil.ReadAll(os.Stdin)
+			} else {
+				state, err = ioutil.ReadFile(args[2])
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to read state file %q", args[2])
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			request, err := client.NewRequest("PUT", path.Join("/deployments", args[0], "nodes", args[1], "terraform_state"), bytes.NewBuffer(state))
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			request.Header.Add("Content-Type", "application/json")
+			query := request.URL.Query()
+			query.Set("force", strconv.FormatBool(force))
+			request.URL.RawQuery = query.Encode()
+			response, err := client.Do(request)
+			if err != nil {
+				httputil.ErrExit(errors.Wrap(err, httputil.YorcAPIDefaultErrorMsg))
+			}
+			defer response.Body.Close()
+			httputil.HandleHTTPStatusCode(response, args[0]+"/"+args[1], "deployment/node", http.StatusOK)
+			fmt.Printf("Terraform state of node %q imported\n", args[1])
+			return nil
+		},
+	}
+	stateImportCmd.PersistentFlags().BoolVarP(&force, "force", "f", false, "Replace the stored state even if it has a different lineage or a higher serial.")
+
+	var stateCmd = &cobra.Command{
+		Use:   "state",
+		Short: "Show or import Terraform states of nodes",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Print(err)
+			}
+		},
+	}
+	stateCmd.AddCommand(stateShowCmd, stateImportCmd)
+	terraformCmd.AddCommand(stateCmd)
+}
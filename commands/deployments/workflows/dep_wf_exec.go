@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -31,6 +32,7 @@ func init() {
 	var shouldStreamLogs bool
 	var shouldStreamEvents bool
 	var continueOnError bool
+	var checkMode bool
 	var workflowName string
 	var jsonParam string
 	var wfExecCmd = &cobra.Command{
@@ -49,8 +51,15 @@ func init() {
 				return errors.New("Missing mandatory \"workflow-name\" parameter")
 			}
 			url := fmt.Sprintf("/deployments/%s/workflows/%s", args[0], workflowName)
+			query := make([]string, 0)
 			if continueOnError {
-				url = url + "?continueOnError"
+				query = append(query, "continueOnError")
+			}
+			if checkMode {
+				query = append(query, "checkMode")
+			}
+			if len(query) > 0 {
+				url = url + "?" + strings.Join(query, "&")
 			}
 			var request *http.Request
 			if len(jsonParam) == 0 {
@@ -83,6 +92,7 @@ func init() {
 	}
 	wfExecCmd.PersistentFlags().StringVarP(&workflowName, "workflow-name", "w", "", "The workflows name (mandatory)")
 	wfExecCmd.PersistentFlags().BoolVarP(&continueOnError, "continue-on-error", "", false, "By default if an error occurs in a step of a workflow then other running steps are cancelled and the workflow is stopped. This flag allows to continue to the next steps even if an error occurs.")
+	wfExecCmd.PersistentFlags().BoolVarP(&checkMode, "check", "", false, "Run the workflow in check mode: Ansible operations report the changes they would make without applying them, other operations are skipped and instances states are left unchanged.")
 	wfExecCmd.PersistentFlags().StringVarP(&jsonParam, "data", "d", "", "Provide the JSON format for the node instances selection")
 	wfExecCmd.PersistentFlags().BoolVarP(&shouldStreamLogs, "stream-logs", "l", false, "Stream logs after triggering a workflow. In this mode logs can't be filtered, to use this feature see the \"log\" command.")
 	wfExecCmd.PersistentFlags().BoolVarP(&shouldStreamEvents, "stream-events", "e", false, "Stream events after triggering a workflow.")
@@ -37,6 +37,9 @@ func init() {
 	var user string
 	var host string
 	var port uint64
+	var protocol string
+	var authType string
+	var certificate string
 	var labels []string
 
 	var addCmd = &cobra.Command{
@@ -48,7 +51,11 @@ func init() {
 			if err != nil {
 				return err
 			}
-			return addHost(client, args, location, jsonParam, privateKey, password, user, host, port, labels)
+			if protocol == "winrm" && !cmd.Flags().Changed("port") {
+				// Let the WinRM default port be used
+				port = 0
+			}
+			return addHost(client, args, location, jsonParam, privateKey, password, user, host, port, protocol, authType, certificate, labels)
 		},
 	}
 	addCmd.Flags().StringVarP(&location, "location", "l", "", "Need to provide the specified hosts pool location name")
@@ -58,12 +65,15 @@ func init() {
 	addCmd.Flags().Uint64VarP(&port, "port", "", 22, "Port used to connect to the host.")
 	addCmd.Flags().StringVarP(&privateKey, "key", "k", "", "Need to provide a private key or a password for the host pool")
 	addCmd.Flags().StringVarP(&password, "password", "p", "", "Need to provide a private key or a password for the host pool")
+	addCmd.Flags().StringVarP(&protocol, "protocol", "", "", `Protocol used to connect to the host, "ssh" (the default) or "winrm" for Windows hosts`)
+	addCmd.Flags().StringVarP(&authType, "auth-type", "", "", `WinRM authentication type: "ntlm" (the default), "kerberos" or "certificate"`)
+	addCmd.Flags().StringVarP(&certificate, "certificate", "", "", "Client certificate of the WinRM certificate authentication, its private key being provided with the key flag")
 	addCmd.Flags().StringSliceVarP(&labels, "label", "", nil, "Label in form 'key=value' to add to the host. May be specified several time.")
 
 	hostsPoolCmd.AddCommand(addCmd)
 }
 
-func addHost(client httputil.HTTPClient, args []string, location, jsonParam, privateKey, password, user, host string, port uint64, labels []string) error {
+func addHost(client httputil.HTTPClient, args []string, location, jsonParam, privateKey, password, user, host string, port uint64, protocol, authType, certificate string, labels []string) error {
 	if len(args) != 1 {
 		return errors.Errorf("Expecting a hostname (got %d parameters)", len(args))
 	}
@@ -76,11 +86,14 @@ func addHost(client httputil.HTTPClient, args []string, location, jsonParam, pri
 	if len(jsonParam) == 0 {
 		var hostRequest rest.HostRequest
 		hostRequest.Connection = &hostspool.Connection{
-			User:       user,
-			Host:       host,
-			Port:       port,
-			Password:   password,
-			PrivateKey: privateKey,
+			User:        user,
+			Host:        host,
+			Port:        port,
+			Password:    password,
+			PrivateKey:  privateKey,
+			Protocol:    protocol,
+			AuthType:    authType,
+			Certificate: certificate,
 		}
 		for _, l := range labels {
 			parts := strings.SplitN(l, "=", 2)
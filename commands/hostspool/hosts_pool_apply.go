@@ -341,7 +341,9 @@ func toPrintableConnection(connection hostspool.Connection) string {
 
 	return "user: " + connection.User + ",password: " + connection.Password +
 		",private key:" + connection.PrivateKey + ",host: " +
-		connection.Host + ",port: " + strconv.FormatUint(connection.Port, 10)
+		connection.Host + ",port: " + strconv.FormatUint(connection.Port, 10) +
+		",protocol: " + connection.Protocol + ",auth type: " + connection.AuthType +
+		",certificate: " + connection.Certificate
 }
 
 // Add rows to a table, for both old and new values
@@ -37,6 +37,9 @@ func init() {
 	var user string
 	var host string
 	var port uint64
+	var protocol string
+	var authType string
+	var certificate string
 	var labelsAdd []string
 	var labelsRemove []string
 
@@ -49,7 +52,7 @@ func init() {
 			if err != nil {
 				return err
 			}
-			return updateHost(client, args, location, jsonParam, privateKey, password, user, host, port, labelsAdd, labelsRemove)
+			return updateHost(client, args, location, jsonParam, privateKey, password, user, host, port, protocol, authType, certificate, labelsAdd, labelsRemove)
 		},
 	}
 	updCmd.Flags().StringVarP(&location, "location", "l", "", "Need to provide the specified hosts pool location name")
@@ -59,13 +62,16 @@ func init() {
 	updCmd.Flags().Uint64VarP(&port, "port", "", 0, "Port used to connect to the host.")
 	updCmd.Flags().StringVarP(&privateKey, "key", "k", "", `At any time a host of the pool should have at least one of private key or password. To delete a registered password use the "-" character.`)
 	updCmd.Flags().StringVarP(&password, "password", "p", "", `At any time a host of the pool should have at least one of private key or password. To delete a registered private key use the "-" character.`)
+	updCmd.Flags().StringVarP(&protocol, "protocol", "", "", `Protocol used to connect to the host, "ssh" or "winrm" for Windows hosts`)
+	updCmd.Flags().StringVarP(&authType, "auth-type", "", "", `WinRM authentication type: "ntlm", "kerberos" or "certificate"`)
+	updCmd.Flags().StringVarP(&certificate, "certificate", "", "", "Client certificate of the WinRM certificate authentication")
 	updCmd.Flags().StringSliceVarP(&labelsAdd, "add-label", "", nil, "Add a label in form 'key=value' to the host. May be specified several time.")
 	updCmd.Flags().StringSliceVarP(&labelsRemove, "remove-label", "", nil, "Remove a label from the host. May be specified several time.")
 
 	hostsPoolCmd.AddCommand(updCmd)
 }
 
-func updateHost(client httputil.HTTPClient, args []string, location, jsonParam, privateKey, password, user, host string, port uint64, labelsAdd, labelsRemove []string) error {
+func updateHost(client httputil.HTTPClient, args []string, location, jsonParam, privateKey, password, user, host string, port uint64, protocol, authType, certificate string, labelsAdd, labelsRemove []string) error {
 	if len(args) != 1 {
 		return errors.Errorf("Expecting a hostname (got %d parameters)", len(args))
 	}
@@ -75,11 +81,14 @@ func updateHost(client httputil.HTTPClient, args []string, location, jsonParam,
 	if len(jsonParam) == 0 {
 		var hostRequest rest.HostRequest
 		hostRequest.Connection = &hostspool.Connection{
-			User:       user,
-			Host:       host,
-			Port:       port,
-			Password:   password,
-			PrivateKey: privateKey,
+			User:        user,
+			Host:        host,
+			Port:        port,
+			Password:    password,
+			PrivateKey:  privateKey,
+			Protocol:    protocol,
+			AuthType:    authType,
+			Certificate: certificate,
 		}
 		for _, l := range labelsAdd {
 			parts := strings.SplitN(l, "=", 2)
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
@@ -54,6 +55,18 @@ var (
 	TfAWSPluginVersion           = "tf AWS plugin version"
 	tfAWSPluginVersionConstraint = versionToConstraint("~>", TfAWSPluginVersion, "minor")
 
+	// TfAzurePluginVersion is the Terraform Azure plugin lowest supported version
+	TfAzurePluginVersion           = "tf Azure plugin version"
+	tfAzurePluginVersionConstraint = versionToConstraint("~>", TfAzurePluginVersion, "minor")
+
+	// TfVSpherePluginVersion is the Terraform vSphere plugin lowest supported version
+	TfVSpherePluginVersion           = "tf vSphere plugin version"
+	tfVSpherePluginVersionConstraint = versionToConstraint("~>", TfVSpherePluginVersion, "minor")
+
+	// TfLibvirtPluginVersion is the Terraform libvirt plugin lowest supported version
+	TfLibvirtPluginVersion           = "tf libvirt plugin version"
+	tfLibvirtPluginVersionConstraint = versionToConstraint("~>", TfLibvirtPluginVersion, "minor")
+
 	// TfOpenStackPluginVersion is the Terraform OpenStack plugin lowest supported version
 	TfOpenStackPluginVersion           = "tf OpenStack plugin version"
 	tfOpenStackPluginVersionConstraint = versionToConstraint("~>", TfOpenStackPluginVersion, "minor")
@@ -72,7 +85,9 @@ var ansibleConfiguration = map[string]interface{}{
 	"ansible.archive_artifacts":            config.DefaultArchiveArtifacts,
 	"ansible.cache_facts":                  config.DefaultCacheFacts,
 	"ansible.keep_generated_recipes":       false,
+	"ansible.galaxy_mirror_dir":            "",
 	"ansible.job_monitoring_time_interval": config.DefaultAnsibleJobMonInterval,
+	"ansible.ssh_control_persist":          time.Duration(0),
 }
 
 var consulConfiguration = map[string]interface{}{
@@ -93,8 +108,11 @@ var terraformConfiguration = map[string]interface{}{
 	"terraform.plugins_dir":                         "",
 	"terraform.consul_plugin_version_constraint":    tfConsulPluginVersionConstraint,
 	"terraform.aws_plugin_version_constraint":       tfAWSPluginVersionConstraint,
+	"terraform.azure_plugin_version_constraint":     tfAzurePluginVersionConstraint,
 	"terraform.google_plugin_version_constraint":    tfGooglePluginVersionConstraint,
 	"terraform.openstack_plugin_version_constraint": tfOpenStackPluginVersionConstraint,
+	"terraform.vsphere_plugin_version_constraint":   tfVSpherePluginVersionConstraint,
+	"terraform.libvirt_plugin_version_constraint":   tfLibvirtPluginVersionConstraint,
 	"terraform.keep_generated_files":                false,
 }
 
@@ -291,7 +309,9 @@ func setConfig() {
 	serverCmd.PersistentFlags().Bool("ansible_archive_artifacts", config.DefaultArchiveArtifacts, "Define wether artifacts should be ./archived before being copied on remote nodes (requires tar to be installed on remote nodes).")
 	serverCmd.PersistentFlags().Bool("ansible_cache_facts", config.DefaultCacheFacts, "Define wether Ansible facts (useful variables about remote hosts) should be cached.")
 	serverCmd.PersistentFlags().Bool("ansible_keep_generated_recipes", false, "Define if Yorc should not delete generated Ansible recipes")
+	serverCmd.PersistentFlags().String("ansible_galaxy_mirror_dir", "", "Directory of roles and collections archives used instead of Ansible Galaxy to install playbooks requirements")
 	serverCmd.PersistentFlags().Duration("ansible_job_monitoring_time_interval", config.DefaultAnsibleJobMonInterval, "Default duration for monitoring time interval for jobs handled by Ansible")
+	serverCmd.PersistentFlags().Duration("ansible_ssh_control_persist", 0, "Duration during which idle OpenSSH master connections opened by Ansible are kept to be reused by next operations of a task. Requires ansible_use_openssh, 0 disables SSH multiplexing")
 
 	//Flags definition for Terraform
 	serverCmd.PersistentFlags().Bool("terraform_keep_generated_files", false, "Define if Yorc should not delete generated Terraform infrastructures files")
@@ -300,8 +320,11 @@ func setConfig() {
 	serverCmd.PersistentFlags().StringP("terraform_plugins_dir", "", "", "The directory where to find Terraform plugins")
 	serverCmd.PersistentFlags().StringP("terraform_consul_plugin_version_constraint", "", tfConsulPluginVersionConstraint, "Terraform Consul plugin version constraint.")
 	serverCmd.PersistentFlags().StringP("terraform_aws_plugin_version_constraint", "", tfAWSPluginVersionConstraint, "Terraform AWS plugin version constraint.")
+	serverCmd.PersistentFlags().StringP("terraform_azure_plugin_version_constraint", "", tfAzurePluginVersionConstraint, "Terraform Azure plugin version constraint.")
 	serverCmd.PersistentFlags().StringP("terraform_openstack_plugin_version_constraint", "", tfOpenStackPluginVersionConstraint, "Terraform OpenStack plugin version constraint.")
 	serverCmd.PersistentFlags().StringP("terraform_google_plugin_version_constraint", "", tfGooglePluginVersionConstraint, "Terraform Google plugin version constraint.")
+	serverCmd.PersistentFlags().StringP("terraform_vsphere_plugin_version_constraint", "", tfVSpherePluginVersionConstraint, "Terraform vSphere plugin version constraint.")
+	serverCmd.PersistentFlags().StringP("terraform_libvirt_plugin_version_constraint", "", tfLibvirtPluginVersionConstraint, "Terraform libvirt plugin version constraint.")
 
 	//Bind Consul persistent flags
 	for key := range consulConfiguration {
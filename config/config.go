@@ -86,40 +86,50 @@ const DefaultSSHConnectionTimeout = 10 * time.Second
 
 // Configuration holds config information filled by Cobra and Viper (see commands package for more information)
 type Configuration struct {
-	Ansible                          Ansible       `yaml:"ansible,omitempty" mapstructure:"ansible"`
-	PluginsDirectory                 string        `yaml:"plugins_directory,omitempty" mapstructure:"plugins_directory"`
-	WorkingDirectory                 string        `yaml:"working_directory,omitempty" mapstructure:"working_directory"`
-	WorkersNumber                    int           `yaml:"workers_number,omitempty" mapstructure:"workers_number"`
-	ServerGracefulShutdownTimeout    time.Duration `yaml:"server_graceful_shutdown_timeout,omitempty" mapstructure:"server_graceful_shutdown_timeout"`
-	HTTPPort                         int           `yaml:"http_port,omitempty" mapstructure:"http_port"`
-	HTTPAddress                      string        `yaml:"http_address,omitempty" mapstructure:"http_address"`
-	KeyFile                          string        `yaml:"key_file,omitempty" mapstructure:"key_file"`
-	CertFile                         string        `yaml:"cert_file,omitempty" mapstructure:"cert_file"`
-	CAFile                           string        `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
-	CAPath                           string        `yaml:"ca_path,omitempty" mapstructure:"ca_path"`
-	SSLVerify                        bool          `yaml:"ssl_verify,omitempty" mapstructure:"ssl_verify"`
-	ResourcesPrefix                  string        `yaml:"resources_prefix,omitempty" mapstructure:"resources_prefix"`
-	Consul                           Consul        `yaml:"consul,omitempty" mapstructure:"consul"`
-	Telemetry                        Telemetry     `yaml:"telemetry,omitempty" mapstructure:"telemetry"`
-	LocationsFilePath                string        `yaml:"locations_file_path,omitempty" mapstructure:"locations_file_path"`
-	Vault                            DynamicMap    `yaml:"vault,omitempty" mapstructure:"vault"`
-	WfStepGracefulTerminationTimeout time.Duration `yaml:"wf_step_graceful_termination_timeout,omitempty" mapstructure:"wf_step_graceful_termination_timeout"`
-	PurgedDeploymentsEvictionTimeout time.Duration `yaml:"purged_deployments_eviction_timeout,omitempty" mapstructure:"purged_deployments_eviction_timeout"`
-	ServerID                         string        `yaml:"server_id,omitempty" mapstructure:"server_id"`
-	Terraform                        Terraform     `yaml:"terraform,omitempty" mapstructure:"terraform"`
-	DisableSSHAgent                  bool          `yaml:"disable_ssh_agent,omitempty" mapstructure:"disable_ssh_agent"`
-	Tasks                            Tasks         `yaml:"tasks,omitempty" mapstructure:"tasks"`
-	Storage                          Storage       `yaml:"storage,omitempty" mapstructure:"storage"`
-	UpgradeConcurrencyLimit          int           `yaml:"concurrency_limit_for_upgrades,omitempty" mapstructure:"concurrency_limit_for_upgrades"`
-	SSHConnectionTimeout             time.Duration `yaml:"ssh_connection_timeout,omitempty" mapstructure:"ssh_connection_timeout"`
-}
-
-// DockerSandbox holds the configuration for a docker sandbox
+	Ansible                          Ansible          `yaml:"ansible,omitempty" mapstructure:"ansible"`
+	PluginsDirectory                 string           `yaml:"plugins_directory,omitempty" mapstructure:"plugins_directory"`
+	WorkingDirectory                 string           `yaml:"working_directory,omitempty" mapstructure:"working_directory"`
+	WorkersNumber                    int              `yaml:"workers_number,omitempty" mapstructure:"workers_number"`
+	ServerGracefulShutdownTimeout    time.Duration    `yaml:"server_graceful_shutdown_timeout,omitempty" mapstructure:"server_graceful_shutdown_timeout"`
+	HTTPPort                         int              `yaml:"http_port,omitempty" mapstructure:"http_port"`
+	HTTPAddress                      string           `yaml:"http_address,omitempty" mapstructure:"http_address"`
+	KeyFile                          string           `yaml:"key_file,omitempty" mapstructure:"key_file"`
+	CertFile                         string           `yaml:"cert_file,omitempty" mapstructure:"cert_file"`
+	CAFile                           string           `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
+	CAPath                           string           `yaml:"ca_path,omitempty" mapstructure:"ca_path"`
+	SSLVerify                        bool             `yaml:"ssl_verify,omitempty" mapstructure:"ssl_verify"`
+	ResourcesPrefix                  string           `yaml:"resources_prefix,omitempty" mapstructure:"resources_prefix"`
+	Consul                           Consul           `yaml:"consul,omitempty" mapstructure:"consul"`
+	Telemetry                        Telemetry        `yaml:"telemetry,omitempty" mapstructure:"telemetry"`
+	LocationsFilePath                string           `yaml:"locations_file_path,omitempty" mapstructure:"locations_file_path"`
+	Vault                            DynamicMap       `yaml:"vault,omitempty" mapstructure:"vault"`
+	WfStepGracefulTerminationTimeout time.Duration    `yaml:"wf_step_graceful_termination_timeout,omitempty" mapstructure:"wf_step_graceful_termination_timeout"`
+	PurgedDeploymentsEvictionTimeout time.Duration    `yaml:"purged_deployments_eviction_timeout,omitempty" mapstructure:"purged_deployments_eviction_timeout"`
+	ServerID                         string           `yaml:"server_id,omitempty" mapstructure:"server_id"`
+	Terraform                        Terraform        `yaml:"terraform,omitempty" mapstructure:"terraform"`
+	DisableSSHAgent                  bool             `yaml:"disable_ssh_agent,omitempty" mapstructure:"disable_ssh_agent"`
+	Tasks                            Tasks            `yaml:"tasks,omitempty" mapstructure:"tasks"`
+	Storage                          Storage          `yaml:"storage,omitempty" mapstructure:"storage"`
+	UpgradeConcurrencyLimit          int              `yaml:"concurrency_limit_for_upgrades,omitempty" mapstructure:"concurrency_limit_for_upgrades"`
+	SSHConnectionTimeout             time.Duration    `yaml:"ssh_connection_timeout,omitempty" mapstructure:"ssh_connection_timeout"`
+	CSARVerification                 CSARVerification `yaml:"csar_verification,omitempty" mapstructure:"csar_verification"`
+}
+
+// DockerSandbox holds the configuration for a sandbox running orchestrator-hosted operations.
+//
+// Despite its name, the sandbox is either a docker container (the default) or a Kubernetes pod
+// depending on its Type.
 type DockerSandbox struct {
+	Type       string   `mapstructure:"type"`
 	Image      string   `mapstructure:"image"`
 	Command    []string `mapstructure:"command"`
 	Entrypoint []string `mapstructure:"entrypoint"`
 	Env        []string `mapstructure:"env"`
+	// Kubernetes sandboxes options
+	Namespace          string `mapstructure:"namespace"`
+	KubeConfig         string `mapstructure:"kubeconfig"`
+	ServiceAccount     string `mapstructure:"service_account"`
+	RecipesVolumeClaim string `mapstructure:"recipes_volume_claim"`
 }
 
 // HostedOperations holds the configuration for operations executed on the orechestrator host (eg. with an operation_host equals to ORECHESTRATOR)
@@ -147,12 +157,23 @@ type Ansible struct {
 	KeepGeneratedRecipes    bool                         `yaml:"keep_generated_recipes,omitempty" mapstructure:"keep_generated_recipes" json:"keep_generated_recipes,omitempty"`
 	ArchiveArtifacts        bool                         `yaml:"archive_artifacts,omitempty" mapstructure:"archive_artifacts" json:"archive_artifacts,omitempty"`
 	CacheFacts              bool                         `yaml:"cache_facts,omitempty" mapstructure:"cache_facts" json:"cache_facts,omitempty"`
+	GalaxyMirrorDir         string                       `yaml:"galaxy_mirror_dir,omitempty" mapstructure:"galaxy_mirror_dir" json:"galaxy_mirror_dir,omitempty"`
 	HostedOperations        HostedOperations             `yaml:"hosted_operations,omitempty" mapstructure:"hosted_operations" json:"hosted_operations,omitempty"`
 	JobsChecksPeriod        time.Duration                `yaml:"job_monitoring_time_interval,omitempty" mapstructure:"job_monitoring_time_interval" json:"job_monitoring_time_interval,omitempty"`
+	SSHControlPersist       time.Duration                `yaml:"ssh_control_persist,omitempty" mapstructure:"ssh_control_persist" json:"ssh_control_persist,omitempty"`
+	ExecutableArtifacts     []ExecutableArtifact         `yaml:"executable_artifacts,omitempty" mapstructure:"executable_artifacts" json:"executable_artifacts,omitempty"`
 	Config                  map[string]map[string]string `yaml:"config,omitempty" mapstructure:"config"`
 	Inventory               map[string][]string          `yaml:"inventory,omitempty" mapstructure:"inventory"`
 }
 
+// ExecutableArtifact maps an implementation artifact type to the interpreter running its artifacts.
+//
+// An empty Interpreter means that artifacts are executables run directly.
+type ExecutableArtifact struct {
+	ArtifactType string `yaml:"artifact_type" mapstructure:"artifact_type" json:"artifact_type"`
+	Interpreter  string `yaml:"interpreter,omitempty" mapstructure:"interpreter" json:"interpreter,omitempty"`
+}
+
 // Consul configuration
 type Consul struct {
 	Token               string        `yaml:"token,omitempty" mapstructure:"token"`
@@ -183,9 +204,14 @@ type Terraform struct {
 	PluginsDir                       string `yaml:"plugins_dir,omitempty" mapstructure:"plugins_dir"`
 	ConsulPluginVersionConstraint    string `yaml:"consul_plugin_version_constraint,omitempty" mapstructure:"consul_plugin_version_constraint"`
 	AWSPluginVersionConstraint       string `yaml:"aws_plugin_version_constraint,omitempty" mapstructure:"aws_plugin_version_constraint"`
+	AzurePluginVersionConstraint     string `yaml:"azure_plugin_version_constraint,omitempty" mapstructure:"azure_plugin_version_constraint"`
+	VSpherePluginVersionConstraint   string `yaml:"vsphere_plugin_version_constraint,omitempty" mapstructure:"vsphere_plugin_version_constraint"`
+	LibvirtPluginVersionConstraint   string `yaml:"libvirt_plugin_version_constraint,omitempty" mapstructure:"libvirt_plugin_version_constraint"`
 	GooglePluginVersionConstraint    string `yaml:"google_plugin_version_constraint,omitempty" mapstructure:"google_plugin_version_constraint"`
 	OpenStackPluginVersionConstraint string `yaml:"openstack_plugin_version_constraint,omitempty" mapstructure:"openstack_plugin_version_constraint"`
 	KeepGeneratedFiles               bool   `yaml:"keep_generated_files,omitempty" mapstructure:"keep_generated_files"`
+	// ResourceTags are tags, like owner or cost center tags, added to all resources created on cloud infrastructures
+	ResourceTags map[string]string `yaml:"resource_tags,omitempty" mapstructure:"resource_tags"`
 }
 
 // Tasks processing configuration
@@ -206,6 +232,27 @@ type Storage struct {
 	DefaultProperties DynamicMap `yaml:"default_properties,omitempty" json:"default_properties,omitempty" mapstructure:"default_properties"`
 }
 
+// CSARVerification holds the configuration of the verification of uploaded CSARs signatures
+//
+// Verification is enabled as soon as trusted keys or tenants are defined.
+type CSARVerification struct {
+	TrustedKeys []string                          `yaml:"trusted_keys,omitempty" json:"trusted_keys,omitempty" mapstructure:"trusted_keys"`
+	Tenants     map[string]CSARVerificationTenant `yaml:"tenants,omitempty" json:"tenants,omitempty" mapstructure:"tenants"`
+	// TenantCertificateField is the field of the subject of TLS client certificates identifying tenants:
+	// common_name (the default), organization or organizational_unit
+	TenantCertificateField string `yaml:"tenant_certificate_field,omitempty" json:"tenant_certificate_field,omitempty" mapstructure:"tenant_certificate_field"`
+}
+
+// IsEnabled checks if uploaded CSARs signatures should be verified
+func (v CSARVerification) IsEnabled() bool {
+	return len(v.TrustedKeys) > 0 || len(v.Tenants) > 0
+}
+
+// CSARVerificationTenant holds the keys trusted for CSARs uploaded by a given tenant
+type CSARVerificationTenant struct {
+	TrustedKeys []string `yaml:"trusted_keys,omitempty" json:"trusted_keys,omitempty" mapstructure:"trusted_keys"`
+}
+
 // Store configuration
 type Store struct {
 	Name                  string     `yaml:"name" json:"name" mapstructure:"name"`
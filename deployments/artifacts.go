@@ -89,6 +89,65 @@ func GetFileArtifactsForNode(ctx context.Context, deploymentID, nodeName string)
 	return artifacts, errors.Wrapf(err, "Failed to get artifacts for node: %q", nodeName)
 }
 
+// GetArtifactsDefinitionsForType returns the definitions of artifacts of the given type.
+//
+// Like for GetFileArtifactsForType the 'derived_from' relations are traversed and artifacts files paths
+// are relative to root of the deployment archive, except for artifacts hosted in a repository.
+func GetArtifactsDefinitionsForType(ctx context.Context, deploymentID, typeName string) (map[string]tosca.ArtifactDefinition, error) {
+	parentType, err := GetParentType(ctx, deploymentID, typeName)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts map[string]tosca.ArtifactDefinition
+	if parentType != "" {
+		artifacts, err = GetArtifactsDefinitionsForType(ctx, deploymentID, parentType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		artifacts = make(map[string]tosca.ArtifactDefinition)
+	}
+
+	importPath, err := GetTypeImportPath(ctx, deploymentID, typeName)
+	if err != nil {
+		return nil, err
+	}
+	artifactsMap, err := getTypeArtifacts(ctx, deploymentID, typeName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get artifacts for type: %q", typeName)
+	}
+	for k, v := range artifactsMap {
+		if v.File == "" {
+			continue
+		}
+		if v.Repository == "" {
+			v.File = path.Join(importPath, v.File)
+		}
+		artifacts[k] = v
+	}
+	return artifacts, nil
+}
+
+// GetArtifactsDefinitionsForNode returns the definitions of artifacts of the given node.
+//
+// Artifacts are overridden in the same way than for GetFileArtifactsForNode.
+func GetArtifactsDefinitionsForNode(ctx context.Context, deploymentID, nodeName string) (map[string]tosca.ArtifactDefinition, error) {
+	node, err := getNodeTemplate(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := GetArtifactsDefinitionsForType(ctx, deploymentID, node.Type)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range node.Artifacts {
+		if v.File != "" {
+			artifacts[k] = v
+		}
+	}
+	return artifacts, nil
+}
+
 // GetArtifactTypeExtensions returns the extensions defined in this artifact type.
 // If the artifact doesn't define any extension then a nil slice is returned
 func GetArtifactTypeExtensions(ctx context.Context, deploymentID, artifactTypeName string) ([]string, error) {
@@ -52,6 +52,9 @@ func testArtifacts(t *testing.T, srv1 *testutil.TestServer) {
 		t.Run("TestGetArtifactsForNode", func(t *testing.T) {
 			testGetArtifactsForNode(t, deploymentID)
 		})
+		t.Run("TestGetArtifactsDefinitionsForNode", func(t *testing.T) {
+			testGetArtifactsDefinitionsForNode(t, deploymentID)
+		})
 	})
 }
 
@@ -112,3 +115,19 @@ func testGetArtifactsForNode(t *testing.T, deploymentID string) {
 	require.NotNil(t, artifacts)
 	require.Len(t, artifacts, 0)
 }
+
+func testGetArtifactsDefinitionsForNode(t *testing.T, deploymentID string) {
+	artifacts, err := GetArtifactsDefinitionsForNode(context.Background(), deploymentID, "NodeC")
+	require.Nil(t, err)
+	require.Len(t, artifacts, 5)
+
+	// Files of artifacts hosted in repositories are relative to their repository
+	require.Equal(t, "app/app-2.0.tgz", artifacts["art2"].File)
+	require.Equal(t, "binaries", artifacts["art2"].Repository)
+	require.Equal(t, "app/app-1.0.tgz", artifacts["art6"].File)
+	require.Equal(t, "binaries", artifacts["art6"].Repository)
+	require.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", artifacts["art6"].Checksum)
+	require.Equal(t, "path/to/typeA/TypeA", artifacts["art1"].File)
+	require.Equal(t, "", artifacts["art1"].Repository)
+	require.Equal(t, "ParentA", artifacts["art3"].File)
+}
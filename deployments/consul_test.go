@@ -149,6 +149,9 @@ func TestRunConsulDeploymentsPackageTests(t *testing.T) {
 		t.Run("TestTopologyTemplateMetadata", func(t *testing.T) {
 			testTopologyTemplateMetadata(t, deploymentID)
 		})
+		t.Run("TestGetTopologyMetadata", func(t *testing.T) {
+			testGetTopologyMetadata(t, deploymentID)
+		})
 		t.Run("TestAttributeNotifications", func(t *testing.T) {
 			testAttributeNotifications(t, deploymentID)
 		})
@@ -1200,3 +1200,19 @@ func BenchmarkDefinitionStore(b *testing.B) {
 	b.StopTimer()
 
 }
+
+func testGetTopologyMetadata(t *testing.T, deploymentID string) {
+	ctx := context.Background()
+	found, value, err := GetTopologyMetadata(ctx, deploymentID, "template_author")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "yorcTester", value)
+
+	found, _, err = GetTopologyMetadata(ctx, deploymentID, "unknown")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	found, _, err = GetTopologyMetadata(ctx, "unknownDeployment", "template_author")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
@@ -102,6 +102,20 @@ func GetDeploymentStatus(ctx context.Context, deploymentID string) (DeploymentSt
 	return DeploymentStatusFromString(value, true)
 }
 
+// GetTopologyMetadata retrieves a metadata of the topology template of a deployment if exists
+func GetTopologyMetadata(ctx context.Context, deploymentID, key string) (bool, string, error) {
+	metadata := make(map[string]string)
+	_, err := storage.GetStore(types.StoreTypeDeployment).Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "metadata"), &metadata)
+	if err != nil {
+		return false, "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	value, exist := metadata[key]
+	if !exist || value == "" {
+		return false, "", nil
+	}
+	return true, value, nil
+}
+
 // DoesDeploymentExists checks if a given deploymentId refer to an existing deployment
 func DoesDeploymentExists(ctx context.Context, deploymentID string) (bool, error) {
 	if _, err := GetDeploymentStatus(ctx, deploymentID); err != nil {
@@ -579,6 +579,21 @@ func GetNodeType(ctx context.Context, deploymentID, nodeName string) (string, er
 	return checkTypeForSubstitutableNode(ctx, deploymentID, nodeName, nodeType)
 }
 
+// GetNodeInfrastructureType returns the infrastructure type of a node, which is part of the name
+// of its type or of one of its parent types (yorc.nodes.<infrastructure>.<type>).
+//
+// An empty string is returned if the node type is not specific to an infrastructure.
+func GetNodeInfrastructureType(ctx context.Context, deploymentID, nodeName string) (string, error) {
+	typ, err := GetNodeType(ctx, deploymentID, nodeName)
+	for err == nil && typ != "" {
+		if parts := strings.Split(typ, "."); len(parts) > 3 && parts[0] == "yorc" && parts[1] == "nodes" {
+			return parts[2], nil
+		}
+		typ, err = GetParentType(ctx, deploymentID, typ)
+	}
+	return "", err
+}
+
 func checkTypeForSubstitutableNode(ctx context.Context, deploymentID, nodeName, nodeType string) (string, error) {
 	// If the corresponding node is substitutable, get its real node type
 	substitutable, err := isSubstitutableNode(ctx, deploymentID, nodeName)
@@ -713,6 +728,18 @@ func getInstancesDependentLinkedNodes(ctx context.Context, deploymentID, nodeNam
 //
 // For each node it returns a coma separated list of selected instances
 func SelectNodeStackInstances(ctx context.Context, deploymentID, nodeName string, instancesDelta int) (map[string]string, error) {
+	// TODO: Improve the way we relate node instances names to dependent (linked nodes) or hosted on instances names
+	instances, err := GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	return SelectNodeStackInstancesByName(ctx, deploymentID, nodeName, instances[len(instances)-int(instancesDelta):])
+}
+
+// SelectNodeStackInstancesByName selects the given instances of the given node, all the nodes hosted on this one and all nodes linked to it.
+//
+// For each node it returns a coma separated list of selected instances
+func SelectNodeStackInstancesByName(ctx context.Context, deploymentID, nodeName string, instances []string) (map[string]string, error) {
 	nodesStack, err := GetNodesHostedOn(ctx, deploymentID, nodeName)
 	if err != nil {
 		return nil, err
@@ -724,12 +751,7 @@ func SelectNodeStackInstances(ctx context.Context, deploymentID, nodeName string
 	}
 	nodesStack = append(nodesStack, linkedNodes...)
 
-	// TODO: Improve the way we relate node instances names to dependent (linked nodes) or hosted on instances names
-	instances, err := GetNodeInstancesIds(ctx, deploymentID, nodeName)
-	if err != nil {
-		return nil, err
-	}
-	instancesList := strings.Join(instances[len(instances)-int(instancesDelta):], ",")
+	instancesList := strings.Join(instances, ",")
 	nodesMap := make(map[string]string)
 	for _, node := range nodesStack {
 		nodesMap[node] = instancesList
@@ -20,6 +20,7 @@ import (
 	"github.com/ystia/yorc/v4/storage/types"
 	"github.com/ystia/yorc/v4/tosca"
 	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -64,13 +65,50 @@ func GetRepositoryTokenTypeFromName(ctx context.Context, deploymentID, repoName
 }
 
 // GetRepositoryTokenUserFromName This function get the credentials (user/token) for a given repoName
+//
+// If the token is a get_secret function it is resolved.
 func GetRepositoryTokenUserFromName(ctx context.Context, deploymentID, repoName string) (string, string, error) {
-	exist, repo, err := getRepository(ctx, deploymentID, repoName)
+	credential, err := GetRepositoryCredentialFromName(ctx, deploymentID, repoName)
 	if err != nil {
 		return "", "", err
 	}
+	return credential.Token, credential.User, nil
+}
+
+// GetRepositoryTypeFromName retrieves the type of a given repoName
+func GetRepositoryTypeFromName(ctx context.Context, deploymentID, repoName string) (string, error) {
+	exist, repo, err := getRepository(ctx, deploymentID, repoName)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.Errorf("The repository %v has been not found", repoName)
+	}
+	return repo.Type, nil
+}
+
+// GetRepositoryCredentialFromName retrieves the credential of a given repoName
+//
+// If the token of the credential is a get_secret function it is resolved.
+func GetRepositoryCredentialFromName(ctx context.Context, deploymentID, repoName string) (*tosca.Credential, error) {
+	exist, repo, err := getRepository(ctx, deploymentID, repoName)
+	if err != nil {
+		return nil, err
+	}
 	if !exist {
-		return "", "", errors.Errorf("The repository %v has been not found", repoName)
+		return nil, errors.Errorf("The repository %v has been not found", repoName)
+	}
+	credential := repo.Credit
+	if strings.HasPrefix(credential.Token, string(tosca.GetSecretOperator)+":") {
+		fn, err := tosca.ParseFunction(credential.Token)
+		if err != nil {
+			return nil, err
+		}
+		token, err := resolver(deploymentID).resolveFunction(ctx, fn)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the token of repository %q", repoName)
+		}
+		credential.Token = token.RawString()
 	}
-	return repo.Credit.Token, repo.Credit.User, nil
+	return &credential, nil
 }
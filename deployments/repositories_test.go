@@ -37,6 +37,12 @@ func testRepositories(t *testing.T) {
 	t.Run("GetRepositoryTokenUserFromName", func(t *testing.T) {
 		testGetRepositoryTokenUserFromName(t, deploymentID)
 	})
+	t.Run("GetRepositoryTypeFromName", func(t *testing.T) {
+		testGetRepositoryTypeFromName(t, deploymentID)
+	})
+	t.Run("GetRepositoryCredentialFromName", func(t *testing.T) {
+		testGetRepositoryCredentialFromName(t, deploymentID)
+	})
 }
 
 func testGetRepositoryURLFromName(t *testing.T, deploymentID string) {
@@ -117,3 +123,33 @@ func testGetRepositoryTokenUserFromName(t *testing.T, deploymentID string) {
 		})
 	}
 }
+
+func testGetRepositoryTypeFromName(t *testing.T, deploymentID string) {
+	repoType, err := GetRepositoryTypeFromName(context.Background(), deploymentID, "ystia-git")
+	require.NoError(t, err)
+	require.Equal(t, "git", repoType)
+	_, err = GetRepositoryTypeFromName(context.Background(), deploymentID, "ystia-artifactory-other")
+	require.Error(t, err)
+}
+
+func testGetRepositoryCredentialFromName(t *testing.T, deploymentID string) {
+	credential, err := GetRepositoryCredentialFromName(context.Background(), deploymentID, "ystia-artifactory-docker")
+	require.NoError(t, err)
+	require.Equal(t, "my_super_secret_passwd", credential.Token)
+	require.Equal(t, "myuser", credential.User)
+
+	defer func() { DefaultVaultClient = nil }()
+	DefaultVaultClient = &vaultClientMock{id: "/secrets/data/git", result: "resolved_token", expectedOptions: []string{"data=token"}}
+	credential, err = GetRepositoryCredentialFromName(context.Background(), deploymentID, "ystia-git")
+	require.NoError(t, err)
+	require.Equal(t, "resolved_token", credential.Token)
+	require.Equal(t, "myuser", credential.User)
+	token, user, err := GetRepositoryTokenUserFromName(context.Background(), deploymentID, "ystia-git")
+	require.NoError(t, err)
+	require.Equal(t, "resolved_token", token)
+	require.Equal(t, "myuser", user)
+
+	DefaultVaultClient = nil
+	_, err = GetRepositoryCredentialFromName(context.Background(), deploymentID, "ystia-git")
+	require.Error(t, err)
+}
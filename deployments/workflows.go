@@ -25,12 +25,17 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/deployments/internal"
+	"github.com/ystia/yorc/v4/helper/collections"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/tosca"
 )
 
 const (
 	workflowsPrefix = "workflows"
+
+	// jobDependencyRelationship is the base relationship of dependencies between jobs handled natively
+	// by their scheduler, infrastructures supporting such dependencies derive their own relationship from it
+	jobDependencyRelationship = "yorc.relationships.JobDependency"
 )
 
 // GetWorkflows returns the list of workflows names for a given deployment
@@ -114,12 +119,121 @@ func enhanceWorkflows(ctx context.Context, deploymentID string) error {
 			}
 		}
 	}
-	if wasUpdated {
+	updated, err := submitDependentJobsEarly(ctx, deploymentID, wf)
+	if err != nil {
+		return err
+	}
+	if wasUpdated || updated {
 		internal.StoreWorkflow(ctx, deploymentID, "run", wf)
 	}
 	return nil
 }
 
+// submitDependentJobsEarly reorders the run workflow so that jobs depending on other jobs through a
+// native job dependency relationship are submitted as soon as their targets are submitted.
+//
+// Generated run workflows wait for a job to be executed before submitting the jobs depending on it,
+// leaving no chance to the scheduler to handle the dependency itself.
+func submitDependentJobsEarly(ctx context.Context, deploymentID string, wf *tosca.Workflow) (bool, error) {
+	var wasUpdated bool
+	for submitStepName, submitStep := range wf.Steps {
+		if submitStep.TargetRelationShip != "" || !hasCallOperation(submitStep, tosca.RunnableSubmitOperationName) {
+			continue
+		}
+		target := submitStep.Target
+		// Dependent jobs are submitted once the target is in submitted state and has a job id
+		anchorName := submitStepName
+		for _, next := range submitStep.OnSuccess {
+			if ns, ok := wf.Steps[next]; ok && ns.Target == target && ns.TargetRelationShip == "" && hasSetState(ns, "submitted") {
+				anchorName = next
+				break
+			}
+		}
+		anchor := wf.Steps[anchorName]
+
+		// Walk through next steps of the target job to find the ones triggering dependent jobs steps
+		visited := map[string]bool{anchorName: true}
+		toVisit := append([]string{}, anchor.OnSuccess...)
+		for len(toVisit) > 0 {
+			stepName := toVisit[0]
+			toVisit = toVisit[1:]
+			step, ok := wf.Steps[stepName]
+			if !ok || visited[stepName] || step.Target != target || step.TargetRelationShip != "" {
+				continue
+			}
+			visited[stepName] = true
+			onSuccess := step.OnSuccess[:0]
+			for _, next := range step.OnSuccess {
+				ns, ok := wf.Steps[next]
+				if !ok || ns.Target == target {
+					onSuccess = append(onSuccess, next)
+					toVisit = append(toVisit, next)
+					continue
+				}
+				isNativeDep, err := hasNativeJobDependency(ctx, deploymentID, ns.Target, target)
+				if err != nil {
+					return false, err
+				}
+				if !isNativeDep {
+					onSuccess = append(onSuccess, next)
+					continue
+				}
+				log.Debugf("Deployment %q: step %q of job %q is triggered by step %q instead of %q to use a native job dependency", deploymentID, next, ns.Target, anchorName, stepName)
+				if !collections.ContainsString(anchor.OnSuccess, next) {
+					anchor.OnSuccess = append(anchor.OnSuccess, next)
+				}
+				wasUpdated = true
+			}
+			step.OnSuccess = onSuccess
+		}
+	}
+	return wasUpdated, nil
+}
+
+func hasCallOperation(step *tosca.Step, operation string) bool {
+	for _, a := range step.Activities {
+		if a.CallOperation != nil && strings.ToLower(a.CallOperation.Operation) == strings.ToLower(operation) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSetState(step *tosca.Step, state string) bool {
+	for _, a := range step.Activities {
+		if strings.ToLower(a.SetState) == state {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNativeJobDependency checks if nodeName has a native job dependency relationship to targetNode
+func hasNativeJobDependency(ctx context.Context, deploymentID, nodeName, targetNode string) (bool, error) {
+	reqIndexes, err := GetRequirementsIndexes(ctx, deploymentID, nodeName)
+	if err != nil {
+		return false, err
+	}
+	for _, reqIndex := range reqIndexes {
+		target, err := GetTargetNodeForRequirement(ctx, deploymentID, nodeName, reqIndex)
+		if err != nil {
+			return false, err
+		}
+		if target != targetNode {
+			continue
+		}
+		relType, err := GetRelationshipForRequirement(ctx, deploymentID, nodeName, reqIndex)
+		if err != nil {
+			return false, err
+		}
+		isJobDep, err := IsTypeDerivedFrom(ctx, deploymentID, relType, jobDependencyRelationship)
+		if err != nil || isJobDep {
+			return isJobDep, err
+		}
+	}
+	return false, nil
+}
+
 // ResolveWorkflowOutputs allows to resolve workflow outputs
 func ResolveWorkflowOutputs(ctx context.Context, deploymentID, workflowName string) (map[string]*TOSCAValue, error) {
 	wf, err := GetWorkflow(ctx, deploymentID, workflowName)
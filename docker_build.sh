@@ -35,8 +35,10 @@ ansible_version=$(grep ansible_version ${script_dir}/versions.yaml | awk '{print
 yorc_version=$(grep yorc_version "${script_dir}/versions.yaml" | awk '{print $2}')
 tf_consul_plugin_version=$(grep tf_consul_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
 tf_aws_plugin_version=$(grep tf_aws_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
+tf_azure_plugin_version=$(grep tf_azure_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
 tf_openstack_plugin_version=$(grep tf_openstack_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
 tf_google_plugin_version=$(grep tf_google_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
+tf_vsphere_plugin_version=$(grep tf_vsphere_plugin_version ${script_dir}/versions.yaml | awk '{print $2}')
 
 if [[ "${TRAVIS}" == "true" ]]; then
     if [[ "${TRAVIS_PULL_REQUEST}" == "false" ]] ; then
@@ -67,8 +69,10 @@ docker build ${BUILD_ARGS} \
         --build-arg "YORC_VERSION=${yorc_version}" \
         --build-arg "TF_CONSUL_PLUGIN_VERSION=${tf_consul_plugin_version}" \
         --build-arg "TF_AWS_PLUGIN_VERSION=${tf_aws_plugin_version}" \
+        --build-arg "TF_AZURE_PLUGIN_VERSION=${tf_azure_plugin_version}" \
         --build-arg "TF_OPENSTACK_PLUGIN_VERSION=${tf_openstack_plugin_version}" \
         --build-arg "TF_GOOGLE_PLUGIN_VERSION=${tf_google_plugin_version}" \
+        --build-arg "TF_VSPHERE_PLUGIN_VERSION=${tf_vsphere_plugin_version}" \
         -t "ystia/yorc:${DOCKER_TAG:-latest}" .
 
 if [[ "${TRAVIS}" == "true" ]]; then
@@ -17,6 +17,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"path"
 	"strconv"
 	"strings"
@@ -227,6 +228,56 @@ func PublishAndLogWorkflowStatusChange(ctx context.Context, deploymentID, taskID
 	return id, nil
 }
 
+// PublishAndLogAnsibleTaskResult publishes the result of an Ansible task on a host and log it into the log API
+// with the host, task name, status and duration as log entry fields
+//
+// Facts set by the task are published as a JSON object.
+//
+// PublishAndLogAnsibleTaskResult returns the published event id
+func PublishAndLogAnsibleTaskResult(ctx context.Context, deploymentID string, result *AnsibleTaskResult) (string, error) {
+	if result == nil {
+		return "", errors.Errorf("Ansible task result param must be provided")
+	}
+	info := buildInfoFromContext(ctx)
+	info[ENodeID] = result.NodeName
+	info[EInstanceID] = result.InstanceName
+	info[EHostName] = result.HostName
+	info[EAnsibleTaskName] = result.TaskName
+	info[EDuration] = result.Duration.String()
+	info[EMessage] = result.Message
+	if len(result.Facts) > 0 {
+		facts, err := json.Marshal(result.Facts)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to marshal facts of Ansible task %q", result.TaskName)
+		}
+		info[EFacts] = string(facts)
+	}
+	e, err := newStatusChange(ctx, StatusChangeTypeAnsibleTask, info, deploymentID, strings.ToLower(result.Status))
+	if err != nil {
+		return "", err
+	}
+	id, err := e.register()
+	if err != nil {
+		return "", err
+	}
+
+	level := LogLevelINFO
+	msg := fmt.Sprintf("Ansible task %q on host %q: %s", result.TaskName, result.HostName, result.Status)
+	switch result.Status {
+	case "failed", "unreachable":
+		level = LogLevelERROR
+		msg += ": " + result.Message
+	}
+	logCtx := AddLogOptionalFields(ctx, LogOptionalFields{
+		HostName:          result.HostName,
+		AnsibleTaskName:   result.TaskName,
+		AnsibleTaskStatus: result.Status,
+		Duration:          result.Duration.String(),
+	})
+	WithContextOptionalFields(logCtx).NewLogEntry(level, deploymentID).RegisterAsString(msg)
+	return id, nil
+}
+
 func getLogsOrEvents(ctx context.Context, deploymentID string, waitIndex uint64, timeout time.Duration, isEvents bool) ([]json.RawMessage, uint64, error) {
 	logsOrEvents := make([]json.RawMessage, 0)
 
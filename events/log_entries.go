@@ -76,6 +76,18 @@ const (
 
 	// TaskExecutionID is the field type representing the task execution ID in log entry
 	TaskExecutionID
+
+	// HostName is the field type representing the host on which an Ansible task ran in log entry
+	HostName
+
+	// AnsibleTaskName is the field type representing the name of an Ansible task in log entry
+	AnsibleTaskName
+
+	// AnsibleTaskStatus is the field type representing the status of an Ansible task in log entry
+	AnsibleTaskStatus
+
+	// Duration is the field type representing the duration of an Ansible task in log entry
+	Duration
 )
 
 // String allows to stringify the field type enumeration in JSON standard
@@ -97,6 +109,14 @@ func (ft FieldType) String() string {
 		return "type"
 	case TaskExecutionID:
 		return "alienTaskId"
+	case HostName:
+		return "host"
+	case AnsibleTaskName:
+		return "ansibleTask"
+	case AnsibleTaskStatus:
+		return "ansibleTaskStatus"
+	case Duration:
+		return "duration"
 	}
 	return ""
 }
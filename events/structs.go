@@ -42,6 +42,7 @@ Workflow
 WorkflowStep
 AlienTask
 AttributeValue
+AnsibleTask
 )
 */
 type StatusChangeType int
@@ -83,6 +84,16 @@ const (
 	EAttributeName
 	// EAttributeValue is event information related to attribute value
 	EAttributeValue
+	// EHostName is event information related to the host on which an Ansible task ran
+	EHostName
+	// EAnsibleTaskName is event information related to Ansible task name
+	EAnsibleTaskName
+	// EDuration is event information related to the duration of an Ansible task
+	EDuration
+	// EFacts is event information related to the facts set by an Ansible task, as a JSON object
+	EFacts
+	// EMessage is event information related to the error message of a failed Ansible task
+	EMessage
 )
 
 func (i InfoType) String() string {
@@ -121,6 +132,16 @@ func (i InfoType) String() string {
 		return "attribute"
 	case EAttributeValue:
 		return "value"
+	case EHostName:
+		return "host"
+	case EAnsibleTaskName:
+		return "ansibleTask"
+	case EDuration:
+		return "duration"
+	case EFacts:
+		return "facts"
+	case EMessage:
+		return "message"
 	}
 	return ""
 }
@@ -146,6 +167,20 @@ type WorkflowStepInfo struct {
 	TargetInstanceID string `json:"target_instance_id,omitempty"`
 }
 
+// AnsibleTaskResult represents the result of an Ansible task on a given host
+type AnsibleTaskResult struct {
+	NodeName     string `json:"node_name,omitempty"`
+	InstanceName string `json:"instance_name,omitempty"`
+	HostName     string `json:"host_name,omitempty"`
+	TaskName     string `json:"task_name,omitempty"`
+	// Status is one of ok, changed, skipped, failed, ignored (failed with errors ignored) or unreachable
+	Status   string                 `json:"status,omitempty"`
+	Duration time.Duration          `json:"duration,omitempty"`
+	Facts    map[string]interface{} `json:"facts,omitempty"`
+	// Message is the error message of a failed task
+	Message string `json:"message,omitempty"`
+}
+
 // Create a KVPair corresponding to an event and put it to Consul under the event prefix,
 // in a sub-tree corresponding to its deployment
 // The eventType goes to the KVPair's Flags field
@@ -208,6 +243,7 @@ func (e *statusChange) check() error {
 		StatusChangeTypeWorkflow:       {ETaskID},
 		StatusChangeTypeWorkflowStep:   {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID},
 		StatusChangeTypeAlienTask:      {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID, ETaskExecutionID},
+		StatusChangeTypeAnsibleTask:    {ENodeID, EHostName, EAnsibleTaskName},
 	}
 	// Check mandatory info in function of status change type
 	if mandatoryInfos, is := mandatoryMap[e.eventType]; is {
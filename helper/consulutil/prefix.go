@@ -61,3 +61,6 @@ const PurgedDeploymentKVPrefix string = yorcPrefix + "/purged"
 
 // StoresPrefix is the prefix in Consul KV store for stores
 const StoresPrefix string = yorcPrefix + "/stores"
+
+// InfraUsagePrefix is the prefix in Consul KV store for infrastructures usage data
+const InfraUsagePrefix string = yorcPrefix + "/infra_usage"
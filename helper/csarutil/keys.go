@@ -0,0 +1,227 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csarutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+)
+
+// TrustedKeys holds the public keys allowed to sign CSARs.
+//
+// It accepts OpenPGP keys as well as raw ECDSA, RSA and Ed25519 public keys
+// such as the ones used by Sigstore's cosign to sign blobs.
+type TrustedKeys struct {
+	pgpKeys    openpgp.EntityList
+	publicKeys []crypto.PublicKey
+}
+
+// LoadTrustedKeys reads trusted keys from the given files.
+//
+// Each file is either an OpenPGP public keyring (armored or binary) or a set of
+// PEM-encoded PKIX public keys.
+func LoadTrustedKeys(paths ...string) (*TrustedKeys, error) {
+	keys := &TrustedKeys{}
+	for _, p := range paths {
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read trusted key file %q", p)
+		}
+		err = keys.add(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted key file %q", p)
+		}
+	}
+	return keys, nil
+}
+
+func (k *TrustedKeys) add(content []byte) error {
+	if bytes.Contains(content, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----")) {
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(content))
+		if err != nil {
+			return errors.Wrap(err, "failed to read armored OpenPGP keyring")
+		}
+		k.pgpKeys = append(k.pgpKeys, entities...)
+		return nil
+	}
+	if bytes.Contains(content, []byte("-----BEGIN ")) {
+		var found bool
+		for block, rest := pem.Decode(content); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "PUBLIC KEY" {
+				continue
+			}
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse PEM public key")
+			}
+			k.publicKeys = append(k.publicKeys, pub)
+			found = true
+		}
+		if !found {
+			return errors.New("no PEM public key found")
+		}
+		return nil
+	}
+	entities, err := openpgp.ReadKeyRing(bytes.NewReader(content))
+	if err != nil {
+		return errors.Wrap(err, "failed to read OpenPGP keyring")
+	}
+	k.pgpKeys = append(k.pgpKeys, entities...)
+	return nil
+}
+
+// Len returns the number of trusted keys
+func (k *TrustedKeys) Len() int {
+	if k == nil {
+		return 0
+	}
+	return len(k.pgpKeys) + len(k.publicKeys)
+}
+
+// Verify checks that signature is a valid detached signature of message made by one of the trusted keys.
+//
+// Signatures may be raw bytes or base64-encoded as produced by cosign.
+func (k *TrustedKeys) Verify(message, signature []byte) error {
+	if k.Len() == 0 {
+		return errors.New("no trusted keys configured")
+	}
+	digest := sha256.Sum256(message)
+	for _, sig := range decodeSignatures(signature) {
+		if k.verifyPGP(bytes.NewReader(message), sig) || k.verifyDigest(digest[:], sig) || k.verifyEd25519(message, sig) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any trusted key")
+}
+
+// VerifyReader checks that signature is a valid detached signature of the content of r made by one
+// of the trusted keys. The content is hashed while it is read instead of being loaded in memory.
+//
+// As pure Ed25519 signatures can not be checked without the whole content, Ed25519 keys are not
+// used here.
+func (k *TrustedKeys) VerifyReader(r io.ReadSeeker, signature []byte) error {
+	if len(k.pgpKeys) == 0 && !k.hasDigestKeys() {
+		return errors.New("no trusted OpenPGP, ECDSA or RSA keys configured")
+	}
+	signatures := decodeSignatures(signature)
+	if k.hasDigestKeys() {
+		h := sha256.New()
+		if err := rewindAndCopy(h, r); err != nil {
+			return err
+		}
+		digest := h.Sum(nil)
+		for _, sig := range signatures {
+			if k.verifyDigest(digest, sig) {
+				return nil
+			}
+		}
+	}
+	for _, sig := range signatures {
+		if len(k.pgpKeys) == 0 {
+			break
+		}
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return errors.Wrap(err, "failed to read signed content")
+		}
+		if k.verifyPGP(r, sig) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any trusted key")
+}
+
+func rewindAndCopy(w io.Writer, r io.ReadSeeker) error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "failed to read signed content")
+	}
+	_, err := io.Copy(w, r)
+	return errors.Wrap(err, "failed to read signed content")
+}
+
+// decodeSignatures returns the candidate signatures: the raw one, and the base64-decoded
+// one if it is valid base64
+func decodeSignatures(signature []byte) [][]byte {
+	signatures := [][]byte{signature}
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature))); err == nil {
+		signatures = append(signatures, decoded)
+	}
+	return signatures
+}
+
+func (k *TrustedKeys) hasDigestKeys() bool {
+	for _, pub := range k.publicKeys {
+		switch pub.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+			return true
+		}
+	}
+	return false
+}
+
+func (k *TrustedKeys) verifyPGP(message io.Reader, signature []byte) bool {
+	if len(k.pgpKeys) == 0 {
+		return false
+	}
+	var err error
+	if bytes.Contains(signature, []byte("-----BEGIN PGP SIGNATURE-----")) {
+		_, err = openpgp.CheckArmoredDetachedSignature(k.pgpKeys, message, bytes.NewReader(signature))
+	} else {
+		_, err = openpgp.CheckDetachedSignature(k.pgpKeys, message, bytes.NewReader(signature))
+	}
+	return err == nil
+}
+
+// verifyDigest checks a signature of a SHA-256 digest made by an ECDSA or RSA key
+func (k *TrustedKeys) verifyDigest(digest, signature []byte) bool {
+	for _, pub := range k.publicKeys {
+		switch key := pub.(type) {
+		case *ecdsa.PublicKey:
+			var sig struct {
+				R, S *big.Int
+			}
+			if rest, err := asn1.Unmarshal(signature, &sig); err == nil && len(rest) == 0 && ecdsa.Verify(key, digest, sig.R, sig.S) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (k *TrustedKeys) verifyEd25519(message, signature []byte) bool {
+	for _, pub := range k.publicKeys {
+		if key, ok := pub.(ed25519.PublicKey); ok && ed25519.Verify(key, message, signature) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csarutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func writePEMPublicKey(t *testing.T, dir string, pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	p := filepath.Join(dir, "key.pem")
+	err = ioutil.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+	require.NoError(t, err)
+	return p
+}
+
+func newPGPEntity(t *testing.T, dir string, armored bool) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("yorc", "test", "yorc@example.com", nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	if armored {
+		w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+		require.NoError(t, err)
+		require.NoError(t, entity.Serialize(w))
+		require.NoError(t, w.Close())
+	} else {
+		require.NoError(t, entity.Serialize(&buf))
+	}
+	p := filepath.Join(dir, "key.gpg")
+	require.NoError(t, ioutil.WriteFile(p, buf.Bytes(), 0644))
+	return entity, p
+}
+
+func TestTrustedKeysVerify(t *testing.T) {
+	message := []byte("some CSAR content")
+	digest := sha256.Sum256(message)
+
+	tests := []struct {
+		name string
+		// setup returns the trusted key file and a signature of message
+		setup func(t *testing.T, dir string) (string, []byte)
+	}{
+		{"PGPBinary", func(t *testing.T, dir string) (string, []byte) {
+			entity, p := newPGPEntity(t, dir, false)
+			var sig bytes.Buffer
+			require.NoError(t, openpgp.DetachSign(&sig, entity, bytes.NewReader(message), nil))
+			return p, sig.Bytes()
+		}},
+		{"PGPArmored", func(t *testing.T, dir string) (string, []byte) {
+			entity, p := newPGPEntity(t, dir, true)
+			var sig bytes.Buffer
+			require.NoError(t, openpgp.ArmoredDetachSign(&sig, entity, bytes.NewReader(message), nil))
+			return p, sig.Bytes()
+		}},
+		{"ECDSABase64", func(t *testing.T, dir string) (string, []byte) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+			sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+			require.NoError(t, err)
+			return writePEMPublicKey(t, dir, key.Public()), []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+		}},
+		{"RSA", func(t *testing.T, dir string) (string, []byte) {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err)
+			sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+			require.NoError(t, err)
+			return writePEMPublicKey(t, dir, key.Public()), sig
+		}},
+		{"Ed25519", func(t *testing.T, dir string) (string, []byte) {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			require.NoError(t, err)
+			return writePEMPublicKey(t, dir, pub), ed25519.Sign(priv, message)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "yorc-csarutil-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			keyFile, sig := tt.setup(t, dir)
+			keys, err := LoadTrustedKeys(keyFile)
+			require.NoError(t, err)
+			require.Equal(t, 1, keys.Len())
+
+			require.NoError(t, keys.Verify(message, sig))
+			require.Error(t, keys.Verify([]byte("tampered content"), sig))
+
+			err = keys.VerifyReader(bytes.NewReader(message), sig)
+			if tt.name == "Ed25519" {
+				// Pure Ed25519 signatures can not be checked on streamed content
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Error(t, keys.VerifyReader(bytes.NewReader([]byte("tampered content")), sig))
+		})
+	}
+}
+
+func TestLoadTrustedKeysErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-csarutil-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = LoadTrustedKeys(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+
+	p := filepath.Join(dir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")}), 0644))
+	_, err = LoadTrustedKeys(p)
+	require.Error(t, err)
+
+	var keys *TrustedKeys
+	require.Error(t, keys.Verify([]byte("msg"), []byte("sig")))
+}
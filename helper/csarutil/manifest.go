@@ -0,0 +1,256 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csarutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MetaFile is the path of the TOSCA metadata file within a CSAR
+const MetaFile = "TOSCA-Metadata/TOSCA.meta"
+
+// MetaSignatureFile is the path of the detached signature of the TOSCA metadata file within a CSAR
+const MetaSignatureFile = MetaFile + ".sig"
+
+// DefaultDigestAlgorithm is the digest algorithm used when generating TOSCA.meta digests
+const DefaultDigestAlgorithm = "SHA-256"
+
+// metaEntry is a "Key: Value" line of a TOSCA.meta block
+type metaEntry struct {
+	key   string
+	value string
+}
+
+// metaBlock is a set of entries separated from other blocks by blank lines.
+//
+// The first block holds metadata on the CSAR itself, next ones describe files
+// of the CSAR identified by their "Name" entry.
+type metaBlock []metaEntry
+
+func (b metaBlock) get(key string) string {
+	for _, e := range b {
+		if e.key == key {
+			return e.value
+		}
+	}
+	return ""
+}
+
+func (b *metaBlock) set(key, value string) {
+	for i := range *b {
+		if (*b)[i].key == key {
+			(*b)[i].value = value
+			return
+		}
+	}
+	*b = append(*b, metaEntry{key: key, value: value})
+}
+
+func parseMeta(content []byte) ([]metaBlock, error) {
+	var blocks []metaBlock
+	var current metaBlock
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNb := 1; scanner.Scan(); lineNb++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+		case strings.HasPrefix(line, " "):
+			// continuation line
+			if len(current) == 0 {
+				return nil, errors.Errorf("unexpected continuation line %d", lineNb)
+			}
+			current[len(current)-1].value += line[1:]
+		default:
+			i := strings.Index(line, ":")
+			if i <= 0 {
+				return nil, errors.Errorf("invalid entry at line %d, expecting \"Key: Value\"", lineNb)
+			}
+			current = append(current, metaEntry{key: line[:i], value: strings.TrimSpace(line[i+1:])})
+		}
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks, errors.Wrap(scanner.Err(), "failed to read TOSCA.meta")
+}
+
+func formatMeta(blocks []metaBlock) []byte {
+	var buf bytes.Buffer
+	for i, b := range blocks {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		for _, e := range b {
+			buf.WriteString(e.key + ": " + e.value + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// FileDigest is the digest of a CSAR file declared in TOSCA.meta
+type FileDigest struct {
+	Algorithm string
+	Digest    string
+}
+
+// ParseMetaDigests returns the digests of files declared in a TOSCA.meta content indexed by file name
+//
+// Every file block should declare both a digest and its algorithm.
+func ParseMetaDigests(content []byte) (map[string]FileDigest, error) {
+	blocks, err := parseMeta(content)
+	if err != nil {
+		return nil, err
+	}
+	digests := make(map[string]FileDigest)
+	for _, b := range blocks {
+		name := b.get("Name")
+		if name == "" {
+			continue
+		}
+		d := FileDigest{Algorithm: b.get("Digest-Algorithm"), Digest: strings.ToLower(b.get("Digest"))}
+		if d.Algorithm == "" || d.Digest == "" {
+			return nil, errors.Errorf("missing digest for file %q in TOSCA.meta", name)
+		}
+		if _, ok := digests[name]; ok {
+			return nil, errors.Errorf("file %q is declared several times in TOSCA.meta", name)
+		}
+		digests[name] = d
+	}
+	return digests, nil
+}
+
+// newDigestHash returns the hash implementing a TOSCA.meta digest algorithm.
+//
+// Only collision-resistant algorithms are accepted as digests are the basis of the CSAR signature.
+func newDigestHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToUpper(strings.Replace(algorithm, "-", "", -1)) {
+	case "SHA256":
+		return sha256.New(), nil
+	case "SHA384":
+		return sha512.New384(), nil
+	case "SHA512":
+		return sha512.New(), nil
+	}
+	return nil, errors.Errorf("unsupported digest algorithm %q", algorithm)
+}
+
+// ComputeDigest computes the hex-encoded digest of the given content
+func ComputeDigest(algorithm string, r io.Reader) (string, error) {
+	h, err := newDigestHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(h, r)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute digest")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// UpdateMetaDigests writes into the TOSCA.meta file of a CSAR directory the digests of all its files.
+//
+// Existing entries of TOSCA.meta are preserved, blocks of files that do not exist anymore are removed.
+// The resulting TOSCA.meta file should then be signed into TOSCA.meta.sig.
+func UpdateMetaDigests(csarDir string) error {
+	metaPath := filepath.Join(csarDir, filepath.FromSlash(MetaFile))
+	var blocks []metaBlock
+	content, err := ioutil.ReadFile(metaPath)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to read TOSCA.meta")
+	}
+	if err == nil {
+		blocks, err = parseMeta(content)
+		if err != nil {
+			return err
+		}
+	}
+	if len(blocks) == 0 || blocks[0].get("Name") != "" {
+		header := metaBlock{
+			{key: "TOSCA-Meta-File-Version", value: "1.0"},
+			{key: "CSAR-Version", value: "1.1"},
+			{key: "Created-By", value: "Yorc"},
+		}
+		blocks = append([]metaBlock{header}, blocks...)
+	}
+
+	fileBlocks := make(map[string]metaBlock)
+	for _, b := range blocks[1:] {
+		if name := b.get("Name"); name != "" {
+			fileBlocks[name] = b
+		}
+	}
+	var names []string
+	err = filepath.Walk(csarDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(csarDir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if name == MetaFile || name == MetaSignatureFile {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open %q", p)
+		}
+		defer f.Close()
+		digest, err := ComputeDigest(DefaultDigestAlgorithm, f)
+		if err != nil {
+			return err
+		}
+		b, ok := fileBlocks[name]
+		if !ok {
+			b = metaBlock{{key: "Name", value: name}}
+		}
+		b.set("Digest-Algorithm", DefaultDigestAlgorithm)
+		b.set("Digest", digest)
+		fileBlocks[name] = b
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	blocks = blocks[:1]
+	for _, name := range names {
+		blocks = append(blocks, fileBlocks[name])
+	}
+	err = os.MkdirAll(filepath.Dir(metaPath), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create TOSCA-Metadata directory")
+	}
+	return errors.Wrap(ioutil.WriteFile(metaPath, formatMeta(blocks), 0644), "failed to write TOSCA.meta")
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csarutil
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsigned is returned by VerifyArchive when a CSAR has neither a detached signature
+// nor a signed TOSCA.meta file
+var ErrUnsigned = errors.New("CSAR is not signed")
+
+// VerifyArchive checks the signature of a CSAR zip archive of the given size.
+// The archive is read as needed instead of being loaded in memory.
+//
+// If signature is not empty it should be a detached signature of the whole archive.
+// Otherwise the archive should contain a TOSCA.meta file declaring digests of all other
+// files of the archive, and a TOSCA.meta.sig detached signature of this TOSCA.meta file.
+func VerifyArchive(archive io.ReaderAt, size int64, signature []byte, keys *TrustedKeys) error {
+	if len(signature) > 0 {
+		return errors.Wrap(keys.VerifyReader(io.NewSectionReader(archive, 0, size), signature), "invalid CSAR signature")
+	}
+
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return errors.Wrap(err, "failed to read CSAR archive")
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(f.Name, "./"))
+		if _, ok := files[name]; ok {
+			// Only one of the entries would be extracted, leaving the other one unverified
+			return errors.Errorf("file %q appears several times in CSAR", name)
+		}
+		files[name] = f
+	}
+
+	meta, sig := files[MetaFile], files[MetaSignatureFile]
+	if meta == nil || sig == nil {
+		return ErrUnsigned
+	}
+	metaContent, err := readZipFile(meta)
+	if err != nil {
+		return err
+	}
+	sigContent, err := readZipFile(sig)
+	if err != nil {
+		return err
+	}
+	err = keys.Verify(metaContent, sigContent)
+	if err != nil {
+		return errors.Wrap(err, "invalid TOSCA.meta signature")
+	}
+
+	digests, err := ParseMetaDigests(metaContent)
+	if err != nil {
+		return err
+	}
+	delete(files, MetaFile)
+	delete(files, MetaSignatureFile)
+	for name, f := range files {
+		expected, ok := digests[name]
+		if !ok {
+			return errors.Errorf("file %q is not declared in TOSCA.meta", name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return errors.Wrapf(err, "failed to open %q in CSAR", name)
+		}
+		digest, err := ComputeDigest(expected.Algorithm, rc)
+		rc.Close()
+		if err != nil {
+			return errors.Wrapf(err, "file %q", name)
+		}
+		if digest != expected.Digest {
+			return errors.Errorf("digest mismatch for file %q", name)
+		}
+	}
+	for name := range digests {
+		if _, ok := files[name]; !ok {
+			return errors.Errorf("file %q declared in TOSCA.meta is missing from CSAR", name)
+		}
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %q in CSAR", f.Name)
+	}
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	return content, errors.Wrapf(err, "failed to read %q in CSAR", f.Name)
+}
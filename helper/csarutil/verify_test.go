@@ -0,0 +1,150 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csarutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/ziputil"
+)
+
+func TestUpdateMetaDigests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-csarutil-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "TOSCA-Metadata"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "TOSCA-Metadata", "TOSCA.meta"), []byte(`TOSCA-Meta-File-Version: 1.0
+CSAR-Version: 1.1
+Created-By: someone
+Entry-Definitions: topology.yaml
+
+Name: topology.yaml
+Content-Type: application/vnd.oasis.tosca.definitions
+
+Name: removed.sh
+Digest-Algorithm: SHA-256
+Digest: 1234
+`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "topology.yaml"), []byte("tosca_definitions_version: alien_dsl_2_0_0\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "scripts", "create.sh"), []byte("echo hello\n"), 0644))
+
+	require.NoError(t, UpdateMetaDigests(dir))
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "TOSCA-Metadata", "TOSCA.meta"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(content), "TOSCA-Meta-File-Version: 1.0\nCSAR-Version: 1.1\nCreated-By: someone\nEntry-Definitions: topology.yaml\n"))
+	require.Contains(t, string(content), "Name: topology.yaml\nContent-Type: application/vnd.oasis.tosca.definitions\nDigest-Algorithm: SHA-256\n")
+	digests, err := ParseMetaDigests(content)
+	require.NoError(t, err)
+	require.Len(t, digests, 2)
+	expected, err := ComputeDigest("SHA-256", strings.NewReader("echo hello\n"))
+	require.NoError(t, err)
+	require.Equal(t, expected, digests["scripts/create.sh"].Digest)
+}
+
+func TestVerifyArchive(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keysDir, err := ioutil.TempDir("", "yorc-csarutil-")
+	require.NoError(t, err)
+	defer os.RemoveAll(keysDir)
+	keys, err := LoadTrustedKeys(writePEMPublicKey(t, keysDir, pub))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		// alter modifies the CSAR directory after it has been signed
+		alter   func(t *testing.T, dir string)
+		unsign  bool
+		wantErr string
+	}{
+		{"Signed", nil, false, ""},
+		{"Unsigned", nil, true, ErrUnsigned.Error()},
+		{"ModifiedFile", func(t *testing.T, dir string) {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "topology.yaml"), []byte("tampered"), 0644))
+		}, false, "digest mismatch"},
+		{"AddedFile", func(t *testing.T, dir string) {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "extra.sh"), []byte("rm -rf /"), 0644))
+		}, false, "not declared"},
+		{"RemovedFile", func(t *testing.T, dir string) {
+			require.NoError(t, os.Remove(filepath.Join(dir, "topology.yaml")))
+		}, false, "missing"},
+		{"ModifiedMeta", func(t *testing.T, dir string) {
+			f, err := os.OpenFile(filepath.Join(dir, "TOSCA-Metadata", "TOSCA.meta"), os.O_APPEND|os.O_WRONLY, 0644)
+			require.NoError(t, err)
+			defer f.Close()
+			_, err = f.WriteString("\nName: extra.sh\nDigest-Algorithm: SHA-256\nDigest: 00\n")
+			require.NoError(t, err)
+		}, false, "invalid TOSCA.meta signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "yorc-csarutil-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "topology.yaml"), []byte("tosca_definitions_version: alien_dsl_2_0_0\n"), 0644))
+			require.NoError(t, UpdateMetaDigests(dir))
+			if !tt.unsign {
+				meta, err := ioutil.ReadFile(filepath.Join(dir, "TOSCA-Metadata", "TOSCA.meta"))
+				require.NoError(t, err)
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "TOSCA-Metadata", "TOSCA.meta.sig"), ed25519.Sign(priv, meta), 0644))
+			}
+			if tt.alter != nil {
+				tt.alter(t, dir)
+			}
+			archive, err := ziputil.ZipPath(dir)
+			require.NoError(t, err)
+
+			err = VerifyArchive(bytes.NewReader(archive), int64(len(archive)), nil, keys)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("DetachedSignature", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		keys, err := LoadTrustedKeys(writePEMPublicKey(t, keysDir, key.Public()))
+		require.NoError(t, err)
+		archive := []byte("not even a zip")
+		sign := func(content []byte) []byte {
+			digest := sha256.Sum256(content)
+			sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+			require.NoError(t, err)
+			return sig
+		}
+		require.NoError(t, VerifyArchive(bytes.NewReader(archive), int64(len(archive)), sign(archive), keys))
+		require.Error(t, VerifyArchive(bytes.NewReader(archive), int64(len(archive)), sign([]byte("other")), keys))
+	})
+}
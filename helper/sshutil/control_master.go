@@ -0,0 +1,187 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshutil
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/metricsutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// used in metric names
+const sshControlMasters = "ssh-control-masters"
+
+// sshMuxConnectionError is the exit status of OpenSSH when it fails to connect
+const sshMuxConnectionError = 255
+
+// ControlMastersRootDir is the directory where are created the control sockets
+// of OpenSSH master connections.
+//
+// Unix sockets paths are limited to about a hundred characters, and OpenSSH
+// adds a temporary suffix while creating them, so this path is kept short
+// instead of being located in the working directory.
+var ControlMastersRootDir = filepath.Join(os.TempDir(), "yorc-ssh")
+
+// ControlMastersDir returns the directory of control sockets of the OpenSSH
+// master connections shared by the operations of a task
+func ControlMastersDir(taskID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(taskID))
+	return filepath.Join(ControlMastersRootDir, fmt.Sprintf("%08x", h.Sum32()))
+}
+
+// ControlPath returns the control socket, within a directory of control sockets,
+// of the master connection of a user to a host
+func ControlPath(dir, user, host string, port int) string {
+	if port == 0 {
+		port = 22
+	}
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%s@%s:%d", user, host, port)))
+	return filepath.Join(dir, fmt.Sprintf("%016x", h.Sum64()))
+}
+
+// IsControlMasterOpen returns true if the control socket of a master connection exists.
+// OpenSSH removes it when the master connection exits.
+func IsControlMasterOpen(controlPath string) bool {
+	fi, err := os.Stat(controlPath)
+	return err == nil && fi.Mode()&os.ModeSocket != 0
+}
+
+// ReportControlMasterUse reports in metrics the use of a master connection to a host,
+// either reused if it was already open or created otherwise
+func ReportControlMasterUse(host string, reused bool) {
+	event := "creations"
+	if reused {
+		event = "reuses"
+	}
+	labels := []metrics.Label{{Name: "Host", Value: host}}
+	metrics.IncrCounterWithLabels(metricsutil.CleanupMetricKey([]string{sshControlMasters, event}), 1, labels)
+}
+
+// CloseControlMasters asks the master connections of a directory of control sockets
+// to exit, then removes this directory
+func CloseControlMasters(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read SSH control sockets directory %q", dir)
+	}
+	for _, f := range files {
+		if f.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		// The destination is required by ssh but not used to reach the master connection
+		out, err := exec.Command("ssh", "-o", "ControlPath="+filepath.Join(dir, f.Name()), "-O", "exit", "yorc").CombinedOutput()
+		if err != nil {
+			log.Debugf("failed to close SSH master connection %q: %v: %s", f.Name(), err, out)
+		}
+	}
+	return errors.Wrapf(os.RemoveAll(dir), "failed to remove SSH control sockets directory %q", dir)
+}
+
+// PruneControlMastersDirs removes directories of control sockets left without
+// master connection since a given duration, as those of tasks ended on another
+// Yorc server
+func PruneControlMastersDirs(maxAge time.Duration) {
+	dirs, err := ioutil.ReadDir(ControlMastersRootDir)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() || time.Since(dir.ModTime()) < maxAge {
+			continue
+		}
+		dirPath := filepath.Join(ControlMastersRootDir, dir.Name())
+		files, err := ioutil.ReadDir(dirPath)
+		if err != nil {
+			continue
+		}
+		var open bool
+		for _, f := range files {
+			open = open || f.Mode()&os.ModeSocket != 0
+		}
+		if !open {
+			log.Debugf("Removing unused SSH control sockets directory %q", dirPath)
+			os.RemoveAll(dirPath)
+		}
+	}
+}
+
+// sshControlMasterCommand returns an ssh command running a remote command
+// through the master connection of the client
+func (client *SSHClient) sshControlMasterCommand(cmd string) *exec.Cmd {
+	return exec.Command("ssh", "-o", "ControlPath="+client.ControlPath, "-o", "ControlMaster=no", "-o", "BatchMode=yes",
+		"-p", strconv.Itoa(client.Port), "-l", client.Config.User, client.Host, "--", cmd)
+}
+
+// isSSHConnectionError returns true if an ssh command failed to connect to the host
+// instead of running the remote command
+func isSSHConnectionError(err error) bool {
+	exitErr, ok := err.(*exec.ExitError)
+	return ok && exitErr.ExitCode() == sshMuxConnectionError
+}
+
+// runCommandThroughControlMaster runs a command through the master connection of the client,
+// returning false if it could not be used
+func (client *SSHClient) runCommandThroughControlMaster(cmd string) (string, bool, error) {
+	if client.ControlPath == "" || client.Config == nil || !IsControlMasterOpen(client.ControlPath) {
+		return "", false, nil
+	}
+	log.Debugf("[SSHControlMaster] cmd: %q", cmd)
+	out, err := client.sshControlMasterCommand(cmd).CombinedOutput()
+	if isSSHConnectionError(err) {
+		log.Debugf("[SSHControlMaster] failed to use master connection %q: %s", client.ControlPath, out)
+		return "", false, nil
+	}
+	ReportControlMasterUse(client.Host, true)
+	stdOutErrStr := strings.Trim(string(out), "\x00")
+	log.Debugf("[SSHControlMaster] stdout/stderr: %q", stdOutErrStr)
+	return stdOutErrStr, true, errors.WithStack(err)
+}
+
+// copyFileThroughControlMaster copies a content to a remote file through the master connection
+// of the client, returning false if it could not be used
+func (client *SSHClient) copyFileThroughControlMaster(content []byte, remotePath string, permissions string) (bool, error) {
+	if client.ControlPath == "" || client.Config == nil || !IsControlMasterOpen(client.ControlPath) {
+		return false, nil
+	}
+	remoteCmd := fmt.Sprintf("mkdir -p '%s' && cat > '%s' && chmod %s '%s'", path.Dir(remotePath), remotePath, permissions, remotePath)
+	sshCmd := client.sshControlMasterCommand(remoteCmd)
+	sshCmd.Stdin = bytes.NewReader(content)
+	out, err := sshCmd.CombinedOutput()
+	if isSSHConnectionError(err) {
+		log.Debugf("[SSHControlMaster] failed to use master connection %q: %s", client.ControlPath, out)
+		return false, nil
+	}
+	ReportControlMasterUse(client.Host, true)
+	return true, errors.Wrapf(err, "failed to copy file %q: %s", remotePath, out)
+}
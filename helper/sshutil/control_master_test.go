@@ -0,0 +1,88 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshutil
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlPath(t *testing.T) {
+	dir := ControlMastersDir("t1")
+	assert.Equal(t, ControlMastersRootDir, filepath.Dir(dir))
+	assert.NotEqual(t, dir, ControlMastersDir("t2"))
+
+	p := ControlPath(dir, "centos", "10.0.0.1", 22)
+	assert.Equal(t, dir, filepath.Dir(p))
+	assert.Equal(t, p, ControlPath(dir, "centos", "10.0.0.1", 0))
+	assert.NotEqual(t, p, ControlPath(dir, "root", "10.0.0.1", 22))
+	assert.NotEqual(t, p, ControlPath(dir, "centos", "10.0.0.2", 22))
+	assert.NotEqual(t, p, ControlPath(dir, "centos", "10.0.0.1", 2222))
+}
+
+func TestControlMasters(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "TestControlMasters")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+	defaultRootDir := ControlMastersRootDir
+	ControlMastersRootDir = tempdir
+	defer func() { ControlMastersRootDir = defaultRootDir }()
+
+	dir := ControlMastersDir("t1")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	notASocket := filepath.Join(dir, "notasocket")
+	require.NoError(t, ioutil.WriteFile(notASocket, nil, 0600))
+	assert.False(t, IsControlMasterOpen(notASocket))
+	assert.False(t, IsControlMasterOpen(filepath.Join(dir, "missing")))
+
+	openDir := ControlMastersDir("t2")
+	require.NoError(t, os.MkdirAll(openDir, 0700))
+	socket := ControlPath(openDir, "centos", "10.0.0.1", 22)
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+	assert.True(t, IsControlMasterOpen(socket))
+
+	// Recent directories and directories with master connections are kept
+	PruneControlMastersDirs(time.Hour)
+	assert.DirExists(t, dir)
+	PruneControlMastersDirs(0)
+	assert.DirExists(t, openDir)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, CloseControlMasters(dir))
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, CloseControlMasters(dir))
+}
+
+func TestRunCommandWithoutControlMaster(t *testing.T) {
+	client := &SSHClient{Host: "10.0.0.1", Port: 22, ControlPath: filepath.Join(os.TempDir(), "missing")}
+	_, ok, err := client.runCommandThroughControlMaster("ls")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	ok, err = client.copyFileThroughControlMaster([]byte("content"), "/tmp/file", "0644")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
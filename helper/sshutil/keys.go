@@ -18,6 +18,7 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/mitchellh/mapstructure"
@@ -126,6 +127,16 @@ func GetPrivateKey(pathOrContent string) (*PrivateKey, error) {
 	return k, nil
 }
 
+// AuthorizedKey returns the public key matching this private key, serialized
+// in the format used by the OpenSSH authorized_keys file
+func (pk *PrivateKey) AuthorizedKey() (string, error) {
+	signer, err := ssh.ParsePrivateKey(pk.Content)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse private key")
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
+
 // GetDefaultKey returns Yorc's default private Key
 func GetDefaultKey() (*PrivateKey, error) {
 	k, err := GetPrivateKey(DefaultSSHPrivateKeyFilePath)
@@ -67,3 +67,14 @@ func testGetKeysFromCredentialsAttribute(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivateKeyAuthorizedKey(t *testing.T) {
+	pk := &PrivateKey{Content: expectedKey}
+	authorizedKey, err := pk.AuthorizedKey()
+	require.NoError(t, err)
+	assert.Regexp(t, `^ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC4SXlaOB2\+WpvPTHa9yWVJ\S+$`, authorizedKey)
+
+	pk = &PrivateKey{Content: []byte("not a key")}
+	_, err = pk.AuthorizedKey()
+	assert.Error(t, err)
+}
@@ -146,6 +146,9 @@ func (p *pool) getConn(k, addr string, config *ssh.ClientConfig) *conn {
 	if ok {
 		p.mu.Unlock()
 		<-c.ok
+		if c.err == nil {
+			metrics.IncrCounter(metricsutil.CleanupMetricKey([]string{sshConnectionsPool, "reuses"}), 1)
+		}
 		return c
 	}
 	c = &conn{ok: make(chan bool)}
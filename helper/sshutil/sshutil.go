@@ -81,6 +81,10 @@ type SSHClient struct {
 	Config *ssh.ClientConfig
 	Host   string
 	Port   int
+	// ControlPath is the control socket of an OpenSSH master connection to the host,
+	// shared with Ansible runs. While this master connection is open, commands are
+	// run and files are copied through it instead of a connection of the pool.
+	ControlPath string
 }
 
 // SSHAgent is an SSH agent
@@ -142,6 +146,9 @@ func (client *SSHClient) RunCommand(cmd string) (string, error) {
 }
 
 func (client *SSHClient) runCommand(cmd string) (string, error) {
+	if out, ok, err := client.runCommandThroughControlMaster(cmd); ok {
+		return out, err
+	}
 	session, err := client.newSession()
 	if err != nil {
 		return "", errors.Wrap(err, "Unable to create new session")
@@ -190,19 +197,22 @@ func (sw *SSHSessionWrapper) RunCommand(ctx context.Context, cmd string) error {
 // CopyFile allows to copy a reader over SSH with defined remote path and specific permissions
 // CopyFile allows to copy a reader over SSH with defined remote path and specific permissions
 func (client *SSHClient) CopyFile(source io.Reader, remotePath string, permissions string) error {
+	// determine the length by reading the reader
+	content, err := ioutil.ReadAll(source)
+	if err != nil {
+		return err
+	}
+	if ok, err := client.copyFileThroughControlMaster(content, remotePath, permissions); ok {
+		return err
+	}
+
 	// Create the remote directory
 	remoteDir := path.Dir(remotePath)
 	mkdirCmd := fmt.Sprintf("mkdir -p %s", remoteDir)
-	_, err := client.RunCommand(mkdirCmd)
+	_, err = client.RunCommand(mkdirCmd)
 	if err != nil {
 		return errors.Wrapf(err, "Couldn't create the remote directory:%q", remoteDir)
 	}
-
-	// determine the length by reading the reader
-	content, err := ioutil.ReadAll(source)
-	if err != nil {
-		return err
-	}
 	size := int64(len(content))
 
 	// Copy the file with scp
@@ -19,6 +19,7 @@ import (
 	_ "github.com/ystia/yorc/v4/commands/bootstrap"
 	_ "github.com/ystia/yorc/v4/commands/deployments"
 	_ "github.com/ystia/yorc/v4/commands/deployments/tasks"
+	_ "github.com/ystia/yorc/v4/commands/deployments/terraform"
 	_ "github.com/ystia/yorc/v4/commands/deployments/workflows"
 	_ "github.com/ystia/yorc/v4/commands/hostspool"
 	_ "github.com/ystia/yorc/v4/commands/locations"
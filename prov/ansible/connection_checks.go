@@ -0,0 +1,86 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/executil"
+)
+
+// podmanConnectionMinAnsibleVersion is the first Ansible version providing the podman connection plugin
+var podmanConnectionMinAnsibleVersion = semver.MustParse("2.8.0")
+
+// ansibleVersionRegexp matches the version in the output of ansible --version
+// like "ansible 2.7.9" or "ansible [core 2.11.6]"
+var ansibleVersionRegexp = regexp.MustCompile(`^ansible (?:\[core )?([0-9][0-9.]*)`)
+
+// ansibleVersions caches the version of ansible commands by path
+var ansibleVersions sync.Map
+
+// getAnsibleVersion returns the version of Ansible used to run the operation
+func (e *executionCommon) getAnsibleVersion(ctx context.Context) (semver.Version, error) {
+	ansible := e.ansibleCommand("ansible")
+	if v, ok := ansibleVersions.Load(ansible); ok {
+		return v.(semver.Version), nil
+	}
+	cmd := executil.Command(ctx, ansible, "--version")
+	cmd.Env = append(os.Environ(), e.virtualenvEnv()...)
+	out, err := cmd.Output()
+	if err != nil {
+		return semver.Version{}, errors.Wrap(err, "failed to get Ansible version")
+	}
+	version, err := parseAnsibleVersion(string(out))
+	if err != nil {
+		return version, err
+	}
+	ansibleVersions.Store(ansible, version)
+	return version, nil
+}
+
+func parseAnsibleVersion(output string) (semver.Version, error) {
+	matches := ansibleVersionRegexp.FindStringSubmatch(strings.TrimSpace(output))
+	if matches == nil {
+		return semver.Version{}, errors.Errorf("failed to parse Ansible version from %q", output)
+	}
+	version, err := semver.ParseTolerant(strings.TrimSuffix(matches[1], "."))
+	return version, errors.Wrapf(err, "failed to parse Ansible version from %q", output)
+}
+
+// checkConnectionsSupport checks that the Ansible version supports the connections to hosts
+func (e *executionCommon) checkConnectionsSupport(ctx context.Context) error {
+	for _, conn := range e.hosts {
+		if conn.connectionType != "podman" {
+			continue
+		}
+		version, err := e.getAnsibleVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if version.LT(podmanConnectionMinAnsibleVersion) {
+			return errors.Errorf("the podman connection to container %q requires Ansible %s or later, current version is %s",
+				conn.host, podmanConnectionMinAnsibleVersion, version)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnsibleVersion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"Legacy", "ansible 2.7.9\n  config file = None\n  python version = 2.7.5", "2.7.9", false},
+		{"Core", "ansible [core 2.11.6] \n  config file = None", "2.11.6", false},
+		{"ShortVersion", "ansible 2.8\n", "2.8.0", false},
+		{"Invalid", "command not found", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := parseAnsibleVersion(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, version.String())
+		})
+	}
+}
+
+func TestCheckConnectionsSupport(t *testing.T) {
+	t.Parallel()
+	e := &executionCommon{virtualenv: "/var/yorc/ansible/virtualenvs/podman-check"}
+	ansibleVersions.Store(e.ansibleCommand("ansible"), semver.MustParse("2.7.9"))
+
+	e.hosts = map[string]*hostConnection{"Compute_0": {host: "10.0.0.2"}}
+	assert.NoError(t, e.checkConnectionsSupport(context.Background()))
+
+	e.hosts["Container_0"] = &hostConnection{host: "8f3a", connectionType: "podman"}
+	err := e.checkConnectionsSupport(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires Ansible 2.8.0 or later")
+
+	ansibleVersions.Store(e.ansibleCommand("ansible"), semver.MustParse("2.9.27"))
+	assert.NoError(t, e.checkConnectionsSupport(context.Background()))
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/registry"
+)
+
+// RegisterExecutableArtifacts registers the artifact types mapped to interpreters by configuration
+// with the operation executor of generic executables
+func RegisterExecutableArtifacts(cfg config.Configuration) error {
+	if len(cfg.Ansible.ExecutableArtifacts) == 0 {
+		return nil
+	}
+	reg := registry.GetRegistry()
+	executor, err := reg.GetOperationExecutor(implementationArtifactExecutable)
+	if err != nil {
+		return err
+	}
+	artifactTypes := make([]string, 0, len(cfg.Ansible.ExecutableArtifacts))
+	for _, executable := range cfg.Ansible.ExecutableArtifacts {
+		if executable.ArtifactType == "" {
+			return errors.New("invalid Ansible executable artifacts configuration: missing artifact type")
+		}
+		artifactTypes = append(artifactTypes, executable.ArtifactType)
+	}
+	log.Debugf("Registering executable artifact types %v", artifactTypes)
+	reg.RegisterOperationExecutor(artifactTypes, executor, registry.BuiltinOrigin)
+	return nil
+}
+
+// getExecutableInterpreter checks if an artifact type is run as a generic executable and returns its interpreter.
+//
+// The interpreter is the one configured for the artifact type or for the closest of its parent types,
+// artifacts of types only derived from the generic executable type are run directly.
+func getExecutableInterpreter(ctx context.Context, cfg config.Configuration, deploymentID, artifactType string) (string, bool, error) {
+	for artifactType != "" {
+		for _, executable := range cfg.Ansible.ExecutableArtifacts {
+			if executable.ArtifactType == artifactType {
+				return executable.Interpreter, true, nil
+			}
+		}
+		if artifactType == implementationArtifactExecutable {
+			return "", true, nil
+		}
+		var err error
+		artifactType, err = deployments.GetParentType(ctx, deploymentID, artifactType)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	return "", false, nil
+}
@@ -125,6 +125,15 @@ type hostConnection struct {
 	privateKeys map[string]*sshutil.PrivateKey
 	password    string
 	bastion     *sshutil.BastionHostConfig
+	// connectionType is set to docker or podman for containers on which
+	// operations are run through the container engine instead of SSH
+	connectionType string
+	dockerHost     string
+	// winRM settings are used for Windows hosts reached through the winrm connection
+	winRM *winRMConnection
+	// controlPath is the control socket of the OpenSSH master connection to the host
+	// when SSH multiplexing is enabled
+	controlPath string
 }
 
 type sshCredentials struct {
@@ -173,13 +182,19 @@ type executionCommon struct {
 	isPerInstanceOperation   bool
 	isOrchestratorOperation  bool
 	IsCustomCommand          bool
+	CheckMode                bool
+	Windows                  bool
 	relationshipType         string
 	ansibleRunner            ansibleRunner
 	sourceNodeInstances      []string
 	targetNodeInstances      []string
 	cli                      *client.Client
 	containerID              string
+	recipePath               string
+	virtualenv               string
 	vaultToken               string
+	// playbookEnv are additional environment variables of ansible-playbook executions
+	playbookEnv []string
 }
 
 // Handling a command standard output and standard error
@@ -217,6 +232,10 @@ func newExecution(ctx context.Context, cfg config.Configuration, taskID, deploym
 	if err != nil {
 		return nil, err
 	}
+	isPowerShell, err := deployments.IsTypeDerivedFrom(ctx, deploymentID, operation.ImplementationArtifact, implementationArtifactPowerShell)
+	if err != nil {
+		return nil, err
+	}
 	isAnsible, err := deployments.IsTypeDerivedFrom(ctx, deploymentID, operation.ImplementationArtifact, implementationArtifactAnsible)
 	if err != nil {
 		return nil, err
@@ -225,9 +244,17 @@ func newExecution(ctx context.Context, cfg config.Configuration, taskID, deploym
 	if err != nil {
 		return nil, err
 	}
+	var interpreter string
+	var isExecutable bool
+	if !isBash && !isPython && !isPowerShell && !isAnsible && !isAlienAnsible {
+		interpreter, isExecutable, err = getExecutableInterpreter(ctx, cfg, deploymentID, operation.ImplementationArtifact)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var exec execution
-	if isBash || isPython {
-		execScript := &executionScript{executionCommon: execCommon, isPython: isPython}
+	if isBash || isPython || isPowerShell || isExecutable {
+		execScript := &executionScript{executionCommon: execCommon, isPython: isPython, isPowerShell: isPowerShell, isExecutable: isExecutable, Interpreter: interpreter}
 		execCommon.ansibleRunner = execScript
 		exec = execScript
 	} else if isAnsible || isAlienAnsible {
@@ -272,6 +299,15 @@ func (e *executionCommon) resolveOperation(ctx context.Context) error {
 		return operationNotImplemented{msg: fmt.Sprintf("primary implementation missing for operation %q of type %q in deployment %q is missing", e.operation.Name, e.NodeType, e.deploymentID)}
 	}
 	e.Primary = strings.TrimSpace(e.OperationImplementation.Primary)
+	// Implementation artifacts hosted in a repository are downloaded in the
+	// overlay when resolving artifacts
+	isRemote, remotePrimary, err := operations.GetRemoteArtifactPath(ctx, e.deploymentID, e.OperationImplementation.Artifact)
+	if err != nil {
+		return err
+	}
+	if isRemote {
+		e.Primary = remotePrimary
+	}
 	log.Debugf("Operation Definition: %+v, primary implementation: %q", e.OperationImplementation, e.Primary)
 	e.BasePrimary = path.Base(e.Primary)
 
@@ -337,6 +373,9 @@ func (e *executionCommon) resolveArtifacts(ctx context.Context) error {
 			return err
 		}
 	}
+	if _, _, err = operations.FetchOperationRemoteArtifacts(ctx, e.OverlayPath, e.deploymentID, e.NodeName, e.operation, e.Artifacts); err != nil {
+		return err
+	}
 	log.Debugf("Resolved artifacts: %v", e.Artifacts)
 	return nil
 }
@@ -369,9 +408,15 @@ func (e *executionCommon) setHostConnection(ctx context.Context, host, instanceI
 			conn.password = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.password", credentials.Token).(string)
 		}
 
-		conn.privateKeys, err = sshutil.GetKeysFromCredentialsDataType(credentials)
-		if err != nil {
-			return err
+		if isWinRMProtocol(credentials.Protocol) {
+			if err = setWinRMConnection(conn, credentials); err != nil {
+				return errors.Wrapf(err, "invalid WinRM credentials for node %q", host)
+			}
+		} else {
+			conn.privateKeys, err = sshutil.GetKeysFromCredentialsDataType(credentials)
+			if err != nil {
+				return err
+			}
 		}
 
 		port, err := deployments.GetInstanceCapabilityAttributeValue(ctx, e.deploymentID, host, instanceID, "endpoint", "port")
@@ -388,6 +433,37 @@ func (e *executionCommon) setHostConnection(ctx context.Context, host, instanceI
 	return nil
 }
 
+// setContainerHostConnection switches the connection to the container engine
+// for containers which do not use SSH
+func (e *executionCommon) setContainerHostConnection(ctx context.Context, host, instanceID string, conn *hostConnection) error {
+	connectionType, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, host, instanceID, "connection_type")
+	if err != nil || connectionType == nil {
+		return err
+	}
+	switch connectionType.RawString() {
+	case "docker", "podman":
+	default:
+		return nil
+	}
+	containerID, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, host, instanceID, "container_id")
+	if err != nil {
+		return err
+	}
+	if containerID == nil || containerID.RawString() == "" {
+		return errors.Errorf("missing container_id attribute for instance %q of node %q", instanceID, host)
+	}
+	conn.connectionType = connectionType.RawString()
+	conn.host = containerID.RawString()
+	dockerHost, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, host, instanceID, "docker_host")
+	if err != nil {
+		return err
+	}
+	if dockerHost != nil {
+		conn.dockerHost = dockerHost.RawString()
+	}
+	return nil
+}
+
 func (e *executionCommon) resolveHostsOrchestratorLocal(nodeName string, instances []string) error {
 	e.hosts = make(map[string]*hostConnection, len(instances))
 	for i := range instances {
@@ -446,6 +522,10 @@ func (e *executionCommon) resolveHostsOnCompute(ctx context.Context, nodeName st
 						events.WithContextOptionalFields(e.ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(mess)
 						return err
 					}
+					err = e.setContainerHostConnection(ctx, host, instance, hostConn)
+					if err != nil {
+						return err
+					}
 					hosts[instanceName] = hostConn
 					found = true
 				}
@@ -457,7 +537,7 @@ func (e *executionCommon) resolveHostsOnCompute(ctx context.Context, nodeName st
 		return errors.Errorf("Failed to resolve hosts for node %q", nodeName)
 	}
 	e.hosts = hosts
-	return nil
+	return e.resolveWindowsHosts(nodeName)
 }
 
 func (e *executionCommon) resolveHosts(ctx context.Context, nodeName string) error {
@@ -761,6 +841,14 @@ func (e *executionCommon) resolveExecution(ctx context.Context) error {
 }
 
 func (e *executionCommon) execute(ctx context.Context, retry bool) error {
+	if err := e.setupVirtualenv(ctx); err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+	if err := e.checkConnectionsSupport(ctx); err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
 	if e.isPerInstanceOperation {
 		var nodeName string
 		var instances []string
@@ -788,6 +876,9 @@ func (e *executionCommon) execute(ctx context.Context, retry bool) error {
 }
 
 func (e *executionCommon) generateHostConnectionForOrchestratorOperation(ctx context.Context, buffer *bytes.Buffer) error {
+	if isKubernetesSandbox(e.cfg.Ansible.HostedOperations.DefaultSandbox) {
+		return e.generateKubernetesSandboxConnection(ctx, buffer, e.cfg.Ansible.HostedOperations.DefaultSandbox)
+	}
 	if e.cli != nil && e.cfg.Ansible.HostedOperations.DefaultSandbox != nil {
 		var err error
 		e.containerID, err = createSandbox(ctx, e.cli, e.cfg.Ansible.HostedOperations.DefaultSandbox, e.deploymentID)
@@ -798,6 +889,9 @@ func (e *executionCommon) generateHostConnectionForOrchestratorOperation(ctx con
 		buffer.WriteString(e.containerID)
 	} else if e.cfg.Ansible.HostedOperations.UnsandboxedOperationsAllowed {
 		buffer.WriteString(" ansible_connection=local")
+		if e.virtualenv != "" {
+			buffer.WriteString(fmt.Sprintf(" ansible_python_interpreter=%s", filepath.Join(e.virtualenv, "bin", "python")))
+		}
 	} else {
 		actualRootCause := "there is no sandbox configured to handle it"
 		if e.cli == nil {
@@ -840,6 +934,7 @@ func (e *executionCommon) getSSHCredentials(ctx context.Context, host *hostConne
 func (e *executionCommon) generateHostConnection(ctx context.Context, buffer *bytes.Buffer, host *hostConnection) error {
 	buffer.WriteString(host.host)
 
+	var sshCommonArgs []string
 	if host.bastion != nil {
 		if host.bastion.Password != "" {
 			return errors.New("ansible provider does not support password authentication with bastion hosts")
@@ -847,10 +942,10 @@ func (e *executionCommon) generateHostConnection(ctx context.Context, buffer *by
 		if host.bastion.Port == "" {
 			host.bastion.Port = "22"
 		}
-		buffer.WriteString(fmt.Sprintf(" ansible_ssh_common_args='-o ProxyCommand=\"ssh -W %%h:%%p "+
+		sshCommonArgs = append(sshCommonArgs, fmt.Sprintf("-o ProxyCommand=\"ssh -W %%h:%%p %s"+
 			// disable host key checking on bastion host completeley
 			"-o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null "+
-			"-p %s %s@%s\"'", host.bastion.Port, host.bastion.User, host.bastion.Host))
+			"-p %s %s@%s\"", e.sshBastionMultiplexingArgs(host.bastion), host.bastion.Port, host.bastion.User, host.bastion.Host))
 	}
 
 	if e.isOrchestratorOperation {
@@ -858,6 +953,16 @@ func (e *executionCommon) generateHostConnection(ctx context.Context, buffer *by
 		if err != nil {
 			return err
 		}
+	} else if host.winRM != nil {
+		e.generateWinRMHostConnection(buffer, host)
+	} else if host.connectionType != "" {
+		buffer.WriteString(fmt.Sprintf(" ansible_connection=%s", host.connectionType))
+		if host.connectionType == "docker" && host.dockerHost != "" {
+			buffer.WriteString(fmt.Sprintf(" ansible_docker_extra_args=\"-H=%s\"", host.dockerHost))
+		}
+		if host.user != "" {
+			buffer.WriteString(fmt.Sprintf(" ansible_user=%s", host.user))
+		}
 	} else {
 		sshCredentials, err := e.getSSHCredentials(ctx, host)
 		if err != nil {
@@ -879,6 +984,18 @@ func (e *executionCommon) generateHostConnection(ctx context.Context, buffer *by
 		if host.port != 0 && host.port != 22 {
 			buffer.WriteString(fmt.Sprintf(" ansible_ssh_port=%d", host.port))
 		}
+		// Share the master connection to this host with SSH clients of Yorc
+		host.controlPath = e.sshControlPath(sshCredentials.user, host.host, host.port)
+		if host.controlPath != "" {
+			if len(sshCommonArgs) == 0 {
+				// Keep the default value of the hosts group overridden here
+				sshCommonArgs = append(sshCommonArgs, "-o ConnectionAttempts=20")
+			}
+			sshCommonArgs = append(sshCommonArgs, "-o ControlPath="+host.controlPath)
+		}
+	}
+	if len(sshCommonArgs) > 0 {
+		buffer.WriteString(fmt.Sprintf(" ansible_ssh_common_args='%s'", strings.Join(sshCommonArgs, " ")))
 	}
 	buffer.WriteString("\n")
 	return nil
@@ -909,6 +1026,8 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 		return err
 	}
 
+	e.recipePath = ansibleRecipePath
+
 	defer func() {
 		if !e.cfg.Ansible.KeepGeneratedRecipes {
 			err := os.RemoveAll(ansibleRecipePath)
@@ -941,6 +1060,11 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 		return err
 	}
 
+	if err = writeTaskResultsCallbackPlugin(ansibleRecipePath); err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
 	var buffer bytes.Buffer
 	var header, emptySectionHeader string
 	if e.isOrchestratorOperation {
@@ -953,6 +1077,12 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 	buffer.WriteString(fmt.Sprintf("[%s]\n", emptySectionHeader))
 	buffer.WriteString(fmt.Sprintf("[%s]\n", header))
 	for instanceName, host := range e.hosts {
+		if host.winRM != nil {
+			err = host.winRM.writeCertificateFiles(ansibleRecipePath, instanceName)
+			if err != nil {
+				return err
+			}
+		}
 		err = e.generateHostConnection(ctx, &buffer, host)
 		if err != nil {
 			return err
@@ -1208,12 +1338,17 @@ func (e *executionCommon) getInstanceIDFromHost(host string) (string, error) {
 
 func (e *executionCommon) executePlaybook(ctx context.Context, retry bool,
 	ansibleRecipePath string, handler outputHandler) error {
-	cmd := executil.Command(ctx, "ansible-playbook", "-i", "hosts", "run.ansible.yml", "--vault-password-file", filepath.Join(ansibleRecipePath, ".vault_pass"))
+	cmd := executil.Command(ctx, e.ansibleCommand("ansible-playbook"), "-i", "hosts", "run.ansible.yml", "--vault-password-file", filepath.Join(ansibleRecipePath, ".vault_pass"))
 	env := os.Environ()
 	env = append(env, "VAULT_PASSWORD="+e.vaultToken)
+	env = append(env, e.virtualenvEnv()...)
+	env = append(env, e.playbookEnv...)
 	if _, err := os.Stat(filepath.Join(ansibleRecipePath, "run.ansible.retry")); retry && (err == nil || !os.IsNotExist(err)) {
 		cmd.Args = append(cmd.Args, "--limit", filepath.Join("@", ansibleRecipePath, "run.ansible.retry"))
 	}
+	if e.CheckMode {
+		cmd.Args = append(cmd.Args, "--check", "--diff")
+	}
 	if e.cfg.Ansible.DebugExec {
 		cmd.Args = append(cmd.Args, "-vvvv")
 	} else {
@@ -1225,6 +1360,7 @@ func (e *executionCommon) executePlaybook(ctx context.Context, retry bool,
 	if !e.isOrchestratorOperation {
 		if e.cfg.Ansible.UseOpenSSH {
 			cmd.Args = append(cmd.Args, "-c", "ssh")
+			defer e.startSSHMultiplexingMetrics()()
 		} else {
 			cmd.Args = append(cmd.Args, "-c", "paramiko")
 		}
@@ -1251,6 +1387,14 @@ func (e *executionCommon) executePlaybook(ctx context.Context, retry bool,
 			}
 		}
 	}
+	resultsCollector, err := newTaskResultsCollector(ctx, e.deploymentID, e.NodeName, e.hosts)
+	if err != nil {
+		return err
+	}
+	// The writer end of the task results pipe is the file descriptor 3 of ansible-playbook
+	cmd.ExtraFiles = []*os.File{resultsCollector.writer}
+	env = append(env, taskResultsFDEnvVar+"=3")
+
 	cmd.Dir = ansibleRecipePath
 	cmd.Env = env
 	errbuf := events.NewBufferedLogEntryWriter()
@@ -1267,12 +1411,21 @@ func (e *executionCommon) executePlaybook(ctx context.Context, retry bool,
 		log.Printf("Error starting output handler: %s", err.Error())
 	}
 
-	err := cmd.Run()
+	err = cmd.Start()
+	resultsCollector.start()
+	if err == nil {
+		err = cmd.Wait()
+	}
+	resultsCollector.stop()
 	if handlerErr := handler.stop(); handlerErr != nil {
 		log.Printf("Error stopping output handler: %s", err.Error())
 	}
 	if err != nil {
-		return e.checkAnsibleRetriableError(ctx, err)
+		err = e.checkAnsibleRetriableError(ctx, err)
+		if failures := resultsCollector.failuresDescription(); failures != "" && !IsRetriable(err) {
+			err = errors.Wrapf(err, "Ansible %s", failures)
+		}
+		return err
 	}
 	return nil
 }
@@ -1411,6 +1564,10 @@ func (e *executionCommon) generateAnsibleConfigurationFile(
 		}
 	}
 
+	if err := e.setSSHMultiplexingConfig(ansibleConfig); err != nil {
+		return err
+	}
+
 	// Ansible configuration user-defined values provided in Yorc Server configuration
 	// can override default settings
 	for header, settings := range e.cfg.Ansible.Config {
@@ -1422,6 +1579,13 @@ func (e *executionCommon) generateAnsibleConfigurationFile(
 		}
 	}
 
+	// The task results callback plugin of the recipe is looked up first
+	callbackPlugins := ansibleConfig[ansibleConfigDefaultsHeader]["callback_plugins"]
+	if callbackPlugins == "" {
+		callbackPlugins = defaultAnsibleCallbackPluginsPath
+	}
+	ansibleConfig[ansibleConfigDefaultsHeader]["callback_plugins"] = filepath.Join(ansibleRecipePath, "callback_plugins") + ":" + callbackPlugins
+
 	var ansibleCfgContentBuilder strings.Builder
 	for header, settings := range ansibleConfig {
 		ansibleCfgContentBuilder.WriteString(fmt.Sprintf("[%s]\n", header))
@@ -33,10 +33,14 @@ const uploadArtifactsPlaybook = `
 - name: Upload artifacts
   hosts: all
   strategy: free
-  tasks:
-[[[ range $artName, $art := .Artifacts ]]]    [[[printf "- file: path=\"{{ ansible_env.HOME}}/%s/%s\" state=directory mode=0755" $.OperationRemotePath (path $art)]]]
+[[[if .CheckMode]]]
+  check_mode: no
+[[[end]]]  tasks:
+[[[ range $artName, $art := .Artifacts ]]][[[if $.Windows]]]    [[[printf "- win_file: path=\"{{ ansible_env.USERPROFILE}}/%s/%s\" state=directory" $.OperationRemotePath (path $art)]]]
+    [[[printf "- win_copy: src=\"%s/%s\" dest=\"{{ ansible_env.USERPROFILE}}/%s/%s/\"" $.OverlayPath $art $.OperationRemotePath (path $art)]]]
+[[[else]]]    [[[printf "- file: path=\"{{ ansible_env.HOME}}/%s/%s\" state=directory mode=0755" $.OperationRemotePath (path $art)]]]
     [[[printf "- copy: src=\"%s/%s\" dest=\"{{ ansible_env.HOME}}/%s/%s\"" $.OverlayPath $art $.OperationRemotePath (path $art)]]]
-[[[end]]]
+[[[end]]][[[end]]]
 `
 
 const ansiblePlaybook = `
@@ -46,17 +50,29 @@ const ansiblePlaybook = `
   hosts: all
   strategy: free
   tasks:
+[[[if .Windows]]]
+    [[[printf "- win_file: path=\"{{ ansible_env.USERPROFILE}}/%s\" state=directory" $.OperationRemotePath]]]
+    [[[printf "- win_template: src=\"outputs.csv.j2\" dest=\"{{ ansible_env.USERPROFILE}}/%s/out.csv\"" $.OperationRemotePath]]]
+    [[[printf "- fetch: src=\"{{ ansible_env.USERPROFILE}}/%s/out.csv\" dest={{dest_folder}}/{{ansible_host}}-out.csv flat=yes" $.OperationRemotePath]]]
+[[[else]]]
     [[[printf "- file: path=\"{{ ansible_env.HOME}}/%s\" state=directory mode=0755" $.OperationRemotePath]]]
     [[[printf "- template: src=\"outputs.csv.j2\" dest=\"{{ ansible_env.HOME}}/%s/out.csv\"" $.OperationRemotePath]]]
     [[[printf "- fetch: src=\"{{ ansible_env.HOME}}/%s/out.csv\" dest={{dest_folder}}/{{ansible_host}}-out.csv flat=yes" $.OperationRemotePath]]]
 [[[end]]]
+[[[end]]]
 [[[if not .KeepOperationRemotePath]]]
 - name: Cleanup temp directories
   hosts: all
   strategy: free
-  tasks:
+[[[if .CheckMode]]]
+  check_mode: no
+[[[end]]]  tasks:
+[[[if .Windows]]]
+    - win_file: path="{{ ansible_env.USERPROFILE}}/[[[.OperationRemoteBaseDir]]]" state=absent
+[[[else]]]
     - file: path="{{ ansible_env.HOME}}/[[[.OperationRemoteBaseDir]]]" state=absent
 [[[end]]]
+[[[end]]]
 `
 
 type executionAnsible struct {
@@ -110,9 +126,13 @@ func (e *executionAnsible) runAnsible(ctx context.Context, retry bool, currentIn
 	}
 
 	if !e.isAlienAnsible {
+		remoteHome := "ansible_env.HOME"
+		if e.Windows {
+			remoteHome = "ansible_env.USERPROFILE"
+		}
 		for artName, art := range e.Artifacts {
 			buffer.WriteString(artName)
-			buffer.WriteString(": \"{{ansible_env.HOME}}/")
+			buffer.WriteString(": \"{{" + remoteHome + "}}/")
 			buffer.WriteString(e.OperationRemotePath)
 			buffer.WriteString("/")
 			buffer.WriteString(art)
@@ -197,6 +217,11 @@ func (e *executionAnsible) runAnsible(ctx context.Context, retry bool, currentIn
 		return err
 	}
 
+	if err = e.installGalaxyRequirements(ctx); err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
 	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).RegisterAsString(fmt.Sprintf("Ansible recipe for node %q: executing %q on remote host(s)", e.NodeName, filepath.Base(e.PlaybookPath)))
 
 	outputHandler := &playbookOutputHandler{execution: e, context: ctx}
@@ -58,6 +58,37 @@ done
 
 `
 
+const executableCustomWrapper = `#!/usr/bin/env bash
+
+[[[if .HaveOutput]]]
+# As executables can't change the environment of this wrapper, they write their outputs
+# as NAME=value lines in this file, these are then exported as for shell scripts
+[[[printf "export YORC_OUTPUTS_FILE=\"$HOME/%s/outputs.env\"" $.OperationRemotePath]]]
+touch "${YORC_OUTPUTS_FILE}"
+
+# Retrieving outputs in a trap to be sure to get them even if the executable exists prematurely (even with success code)
+function finish {
+  while IFS='=' read -r yorc_output_name yorc_output_value ; do
+    [[ -n "${yorc_output_name}" ]] && export "${yorc_output_name}=${yorc_output_value}"
+  done < "${YORC_OUTPUTS_FILE}"
+  [[[range $artName, $art := .Outputs -]]]
+  [[[printf "echo %s,\\\"$%s\\\" >> $HOME/%s/out.csv" $artName (cut $artName) $.OperationRemotePath]]]
+  [[[end]]]
+  [[[printf "chmod 777 $HOME/%s/out.csv" $.OperationRemotePath]]]
+}
+
+trap finish EXIT
+[[[end]]]
+
+# Removing the space prefixing JSON structures, see the shell scripts wrapper
+for yorc_escape_workaround in [[[StringsJoin .VarInputsNames " "]]] ;
+do
+  eval "[[ \"\${${yorc_escape_workaround}}\" == \" \"* ]] && { export ${yorc_escape_workaround}=\${${yorc_escape_workaround}:1};}"
+done
+[[[if .Interpreter]]][[[.Interpreter]]] [[[end]]][[[printf "\"$HOME/%s/%s\"" $.OperationRemotePath .BasePrimary]]]
+
+`
+
 const pythonCustomWrapper = `#!/usr/bin/env python
 
 from os import chmod
@@ -106,6 +137,43 @@ with open(outFile, 'w') as csvfile:
 
 `
 
+const powerShellCustomWrapper = `
+# Workaround JSON structures being treated as python objects
+# basically it prevent double quotes to be changed into single quotes
+# by prefixing the value by a space
+# We remove this space here. Obviously becomes a reserved keyword yorcEscapeWorkaround
+foreach ($yorcEscapeWorkaround in @([[[qJoin .VarInputsNames]]])) {
+  $yorcValue = [Environment]::GetEnvironmentVariable($yorcEscapeWorkaround)
+  if ($yorcValue -ne $null -and $yorcValue.StartsWith(" ")) {
+    [Environment]::SetEnvironmentVariable($yorcEscapeWorkaround, $yorcValue.Substring(1))
+  }
+}
+[[[if .HaveOutput]]]
+# Outputs are either variables or environment variables set by the script
+function Get-YorcOutput([string]$name) {
+  $value = Get-Variable -Name $name -ValueOnly -ErrorAction SilentlyContinue
+  if ($value -eq $null) {
+    $value = [Environment]::GetEnvironmentVariable($name)
+  }
+  return $value
+}
+
+# Retrieving outputs in a finally block to be sure to get them even if the script exits prematurely
+try {
+[[[end]]]
+[[[printf ". \"$env:USERPROFILE/%s/%s\"" $.OperationRemotePath .BasePrimary]]]
+[[[if .HaveOutput]]]
+} finally {
+  $yorcOutputs = @()
+  [[[range $outName, $outVal := .Outputs -]]]
+  [[[printf "$yorcOutputs += '%s,\"' + (Get-YorcOutput '%s') + '\"'" $outName (cut $outName)]]]
+  [[[end]]]
+  # Written without byte order mark
+  [[[printf "[IO.File]::WriteAllLines(\"$env:USERPROFILE/%s/out.csv\", [string[]]$yorcOutputs)" $.OperationRemotePath]]]
+}
+[[[end]]]
+`
+
 func quoteAndComaJoin(s []string) string {
 	var b bytes.Buffer
 	for i, e := range s {
@@ -173,9 +241,48 @@ const shellAnsiblePlaybook = `
     [[[end]]]
 `
 
+const powerShellAnsiblePlaybook = `
+- name: Executing PowerShell script [[[.ScriptToRun]]]
+  hosts: all
+  strategy: free
+  tasks:
+    - win_file: path="{{ ansible_env.USERPROFILE}}/[[[.OperationRemotePath]]]" state=directory
+    [[[printf  "- win_copy: src=\"%s\" dest=\"{{ ansible_env.USERPROFILE}}/%s/wrapper.ps1\"" $.WrapperLocation $.OperationRemotePath]]]
+    - win_copy: src="[[[.ScriptToRun]]]" dest="{{ ansible_env.USERPROFILE}}/[[[.OperationRemotePath]]]/"
+    [[[ range $artName, $art := .Artifacts -]]]
+    [[[printf "- win_file: path=\"{{ ansible_env.USERPROFILE}}/%s/%s\" state=directory" $.OperationRemotePath (path $art)]]]
+    [[[printf "- win_copy: src=\"%s/%s\" dest=\"{{ ansible_env.USERPROFILE}}/%s/%s/\"" $.OverlayPath $art $.OperationRemotePath (path $art)]]]
+    [[[end]]]
+    [[[printf "- win_shell: \"& '{{ ansible_env.USERPROFILE}}/%s/wrapper.ps1'\"" $.OperationRemotePath]]]
+      environment:
+        [[[ range $key, $envInput := .EnvInputs -]]]
+        [[[ if gt (len $envInput.InstanceName) 0]]][[[ if gt (len $envInput.Value) 0]]][[[printf  "%s_%s: %s" $envInput.InstanceName $envInput.Name (encEnvInput $envInput)]]][[[else]]][[[printf  "%s_%s: \"\"" $envInput.InstanceName $envInput.Name]]]
+        [[[end]]][[[else]]][[[ if gt (len $envInput.Value) 0]]][[[printf  "%s: %s" $envInput.Name (encEnvInput $envInput)]]][[[else]]]
+        [[[printf  "%s: \"\"" $envInput.Name]]]
+        [[[end]]][[[end]]]
+        [[[end]]][[[ range $artName, $art := .Artifacts -]]]
+        [[[printf "%s: \"{{ ansible_env.USERPROFILE}}/%s/%s\"" $artName $.OperationRemotePath $art]]]
+        [[[end]]][[[ range $contextK, $contextV := .Context -]]]
+        [[[printf "%s: %q" $contextK $contextV]]]
+        [[[end]]][[[ range $cContextK, $cContextV := .CapabilitiesCtx -]]]
+        [[[printf "%s: %s" $cContextK (encTOSCAValue $cContextV)]]]
+        [[[end]]][[[ range $hostVarIndex, $hostVarValue := .VarInputsNames -]]]
+        [[[printf "%s: \" {{%s}}\"" $hostVarValue $hostVarValue]]]
+        [[[end]]]
+    [[[if .HaveOutput]]]
+    [[[printf "- fetch: src={{ ansible_env.USERPROFILE}}/%s/out.csv dest=%s/{{ansible_host}}-out.csv flat=yes" $.OperationRemotePath $.DestFolder]]]
+    [[[end]]]
+    [[[if not .KeepOperationRemotePath ]]]
+    - win_file: path="{{ ansible_env.USERPROFILE}}/[[[.OperationRemoteBaseDir]]]" state=absent
+    [[[end]]]
+`
+
 type executionScript struct {
 	*executionCommon
 	isPython        bool
+	isPowerShell    bool
+	isExecutable    bool
+	Interpreter     string
 	ScriptToRun     string
 	WrapperLocation string
 	DestFolder      string
@@ -220,6 +327,18 @@ func (e *executionScript) runAnsible(ctx context.Context, retry bool, currentIns
 	}
 
 	e.WrapperLocation = filepath.Join(e.DestFolder, "wrapper")
+	if e.isPowerShell && !e.Windows {
+		err = errors.Errorf("PowerShell implementation of operation %q on node %q requires Windows hosts reached through WinRM", e.operation.Name, e.NodeName)
+	} else if !e.isPowerShell && e.Windows {
+		err = errors.Errorf("script implementation of operation %q on node %q is not supported on Windows hosts, use a PowerShell script instead", e.operation.Name, e.NodeName)
+	}
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+	if e.isPowerShell {
+		e.WrapperLocation = filepath.Join(e.DestFolder, "wrapper.ps1")
+	}
 
 	outputHandler := &scriptOutputHandler{execution: e, context: ctx, instanceName: currentInstance}
 
@@ -232,6 +351,10 @@ func (e *executionScript) runAnsible(ctx context.Context, retry bool, currentIns
 	wrapTemplate = wrapTemplate.Delims("[[[", "]]]")
 	if e.isPython {
 		wrapTemplate, err = tmpl.Parse(pythonCustomWrapper)
+	} else if e.isExecutable {
+		wrapTemplate, err = tmpl.Parse(executableCustomWrapper)
+	} else if e.isPowerShell {
+		wrapTemplate, err = tmpl.Parse(powerShellCustomWrapper)
 	} else {
 		wrapTemplate, err = tmpl.Parse(scriptCustomWrapper)
 	}
@@ -250,7 +373,11 @@ func (e *executionScript) runAnsible(ctx context.Context, retry bool, currentIns
 	}
 
 	buffer.Reset()
-	tmpl, err = tmpl.Parse(shellAnsiblePlaybook)
+	if e.isPowerShell {
+		tmpl, err = tmpl.Parse(powerShellAnsiblePlaybook)
+	} else {
+		tmpl, err = tmpl.Parse(shellAnsiblePlaybook)
+	}
 	if err != nil {
 		err = errors.Wrap(err, "Failed to Generate ansible playbook")
 		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
@@ -39,6 +39,7 @@ import (
 	"github.com/ystia/yorc/v4/prov"
 	"github.com/ystia/yorc/v4/prov/operations"
 	yorc_testutil "github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/tosca/types"
 )
 
 // From now only WorkingDirectory is necessary for those tests
@@ -74,6 +75,180 @@ func TestTemplates(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestGenerateContainerHostConnection(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		host *hostConnection
+		want string
+	}{
+		{"DockerLocal", &hostConnection{host: "8f3a", connectionType: "docker"}, "8f3a ansible_connection=docker\n"},
+		{"DockerRemote", &hostConnection{host: "8f3a", connectionType: "docker", dockerHost: "tcp://10.0.0.2:2376", user: "app"}, "8f3a ansible_connection=docker ansible_docker_extra_args=\"-H=tcp://10.0.0.2:2376\" ansible_user=app\n"},
+		{"Podman", &hostConnection{host: "8f3a", connectionType: "podman", dockerHost: "unix:///run/podman/podman.sock"}, "8f3a ansible_connection=podman\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executionCommon{cfg: GetConfig()}
+			var buffer bytes.Buffer
+			err := e.generateHostConnection(context.Background(), &buffer, tt.host)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
+
+func TestPowerShellTemplates(t *testing.T) {
+	t.Parallel()
+	ec := &executionCommon{
+		NodeName:               "Welcome",
+		operation:              prov.Operation{Name: "standard.start"},
+		Artifacts:              map[string]string{"scripts": "my_scripts"},
+		OverlayPath:            "/some/local/path",
+		VarInputsNames:         []string{"INSTANCE", "PORT"},
+		Outputs:                map[string]string{"MY_OUTPUT": "MY_OUTPUT"},
+		OperationRemoteBaseDir: ".yorc/path/on/remote",
+		Windows:                true,
+	}
+
+	e := &executionScript{
+		executionCommon: ec,
+		isPowerShell:    true,
+	}
+
+	for _, tmplContent := range []string{powerShellAnsiblePlaybook, powerShellCustomWrapper} {
+		tmpl := template.New("execTest")
+		tmpl = tmpl.Delims("[[[", "]]]")
+		tmpl = tmpl.Funcs(getExecutionScriptTemplateFnMap(ec, "", func() string { return "" }))
+		tmpl, err := tmpl.Parse(tmplContent)
+		require.NoError(t, err)
+		var buffer bytes.Buffer
+		err = tmpl.Execute(&buffer, e)
+		require.NoError(t, err)
+	}
+}
+
+func TestExecutableTemplates(t *testing.T) {
+	t.Parallel()
+	ec := &executionCommon{
+		NodeName:               "Welcome",
+		operation:              prov.Operation{Name: "standard.start"},
+		Primary:                "scripts/start.rb",
+		BasePrimary:            "start.rb",
+		VarInputsNames:         []string{"INSTANCE", "PORT"},
+		Outputs:                map[string]string{"MY_OUTPUT_0": "MY_OUTPUT_0"},
+		HaveOutput:             true,
+		OperationRemoteBaseDir: ".yorc/path/on/remote",
+		OperationRemotePath:    ".yorc/path/on/remote/start",
+	}
+
+	e := &executionScript{
+		executionCommon: ec,
+		isExecutable:    true,
+		Interpreter:     "ruby -W0",
+	}
+
+	tmpl := template.New("execTest")
+	tmpl = tmpl.Delims("[[[", "]]]")
+	tmpl = tmpl.Funcs(getExecutionScriptTemplateFnMap(ec, "", func() string { return "" }))
+	tmpl, err := tmpl.Parse(executableCustomWrapper)
+	require.NoError(t, err)
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, e)
+	require.NoError(t, err)
+	assert.Contains(t, buffer.String(), `export YORC_OUTPUTS_FILE="$HOME/.yorc/path/on/remote/start/outputs.env"`)
+	assert.Contains(t, buffer.String(), `echo MY_OUTPUT_0,\"$MY_OUTPUT\" >> $HOME/.yorc/path/on/remote/start/out.csv`)
+	assert.Contains(t, buffer.String(), `ruby -W0 "$HOME/.yorc/path/on/remote/start/start.rb"`)
+}
+
+func TestGetExecutableInterpreter(t *testing.T) {
+	t.Parallel()
+	cfg := config.Configuration{Ansible: config.Ansible{ExecutableArtifacts: []config.ExecutableArtifact{
+		{ArtifactType: "tosca.artifacts.Implementation.Ruby", Interpreter: "ruby"},
+		{ArtifactType: "my.artifacts.Binary"},
+	}}}
+	tests := []struct {
+		name            string
+		artifactType    string
+		wantInterpreter string
+	}{
+		{"MappedToInterpreter", "tosca.artifacts.Implementation.Ruby", "ruby"},
+		{"MappedWithoutInterpreter", "my.artifacts.Binary", ""},
+		{"GenericExecutable", implementationArtifactExecutable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interpreter, isExecutable, err := getExecutableInterpreter(context.Background(), cfg, "dep", tt.artifactType)
+			require.NoError(t, err)
+			assert.True(t, isExecutable)
+			assert.Equal(t, tt.wantInterpreter, interpreter)
+		})
+	}
+}
+
+func TestGenerateWinRMHostConnection(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		host *hostConnection
+		want string
+	}{
+		{"NTLMDefaultPort", &hostConnection{host: "10.0.0.2", port: 22, user: "Administrator", password: "secret", connectionType: winRMProtocol, winRM: &winRMConnection{transport: winRMTransportNTLM}},
+			"10.0.0.2 ansible_connection=winrm ansible_user=Administrator ansible_port=5986 ansible_winrm_transport=ntlm ansible_winrm_server_cert_validation=ignore ansible_password=secret\n"},
+		{"KerberosCustomPort", &hostConnection{host: "10.0.0.2", port: 5985, user: "admin@EXAMPLE.COM", password: "secret", connectionType: winRMProtocol, winRM: &winRMConnection{transport: winRMTransportKerberos}},
+			"10.0.0.2 ansible_connection=winrm ansible_user=admin@EXAMPLE.COM ansible_port=5985 ansible_winrm_transport=kerberos ansible_winrm_server_cert_validation=ignore ansible_password=secret\n"},
+		{"Certificate", &hostConnection{host: "10.0.0.2", user: "admin", connectionType: winRMProtocol, winRM: &winRMConnection{transport: winRMTransportCertificate, certificatePath: "/tmp/cert.pem", keyPath: "/tmp/key.pem"}},
+			"10.0.0.2 ansible_connection=winrm ansible_user=admin ansible_port=5986 ansible_winrm_transport=certificate ansible_winrm_server_cert_validation=ignore ansible_winrm_cert_pem=/tmp/cert.pem ansible_winrm_cert_key_pem=/tmp/key.pem\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executionCommon{cfg: GetConfig()}
+			var buffer bytes.Buffer
+			err := e.generateHostConnection(context.Background(), &buffer, tt.host)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
+
+func TestSetWinRMConnection(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		password      string
+		credentials   *types.Credential
+		wantTransport string
+		wantKey       string
+		wantErr       bool
+	}{
+		{"DefaultTokenType", "secret", &types.Credential{}, winRMTransportNTLM, "", false},
+		{"PasswordTokenType", "secret", &types.Credential{TokenType: "password"}, winRMTransportNTLM, "", false},
+		{"Kerberos", "secret", &types.Credential{TokenType: "Kerberos"}, winRMTransportKerberos, "", false},
+		{"Certificate", "/etc/certs/cert.pem", &types.Credential{TokenType: "certificate", Keys: map[string]string{"1": "/etc/certs/key.pem", "0": ""}}, winRMTransportCertificate, "/etc/certs/key.pem", false},
+		{"CertificateWithoutKey", "/etc/certs/cert.pem", &types.Credential{TokenType: "certificate"}, "", "", true},
+		{"CertificateWithoutCertificate", "", &types.Credential{TokenType: "certificate", Keys: map[string]string{"0": "/etc/certs/key.pem"}}, "", "", true},
+		{"UnsupportedTokenType", "secret", &types.Credential{TokenType: "basic"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &hostConnection{password: tt.password}
+			err := setWinRMConnection(conn, tt.credentials)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, conn.winRM)
+			assert.Equal(t, winRMProtocol, conn.connectionType)
+			assert.Equal(t, tt.wantTransport, conn.winRM.transport)
+			assert.Equal(t, tt.wantKey, conn.winRM.key)
+			if tt.wantTransport == winRMTransportCertificate {
+				assert.Equal(t, tt.password, conn.winRM.certificate)
+				assert.Empty(t, conn.password)
+			}
+		})
+	}
+}
+
 func testExecution(t *testing.T, srv1 *testutil.TestServer) {
 	deploymentID := yorc_testutil.BuildDeploymentID(t)
 	err := deployments.StoreDeploymentDefinition(context.Background(), deploymentID, "testdata/execTemplate.yml")
@@ -718,10 +893,10 @@ func testExecutionGenerateAnsibleConfig(t *testing.T) {
 	cfgPath := path.Join(yorcConfig.WorkingDirectory, "ansible.cfg")
 	resultMap, content := readAnsibleConfigSettings(t, cfgPath)
 
-	// An additional entry for retry_files_save_path should be added to default
-	// values
+	// Additional entries for retry_files_save_path and callback_plugins should
+	// be added to default values
 	assert.Equal(t,
-		len(ansibleDefaultConfig[ansibleConfigDefaultsHeader])+1,
+		len(ansibleDefaultConfig[ansibleConfigDefaultsHeader])+2,
 		len(resultMap[ansibleConfigDefaultsHeader]),
 		"Missing entries in ansible config file, content: %q", content)
 
@@ -732,6 +907,8 @@ func testExecutionGenerateAnsibleConfig(t *testing.T) {
 	v, ok := resultMap[ansibleConfigDefaultsHeader]["retry_files_save_path"]
 	assert.True(t, ok, "Found no entry for retry_files_save_path in ansible config")
 	assert.Equal(t, tempdir, v, "Unexpected value for retry_files_save_path value")
+	assert.Equal(t, path.Join(tempdir, "callback_plugins")+":"+defaultAnsibleCallbackPluginsPath,
+		resultMap[ansibleConfigDefaultsHeader]["callback_plugins"], "Unexpected value for callback_plugins")
 
 	// Test enabling fact caching, it should add configuration settings
 	execution.CacheFacts = true
@@ -740,7 +917,7 @@ func testExecutionGenerateAnsibleConfig(t *testing.T) {
 	require.NoError(t, err, "Error generating ansible config file")
 	resultMap, content = readAnsibleConfigSettings(t, cfgPath)
 	assert.Equal(t,
-		initialConfigMapLength+len(ansibleFactCaching)+1,
+		initialConfigMapLength+len(ansibleFactCaching)+2,
 		len(resultMap[ansibleConfigDefaultsHeader]),
 		"Missing entries in ansible config file with fact caching, content: %q", content)
 
@@ -767,7 +944,7 @@ func testExecutionGenerateAnsibleConfig(t *testing.T) {
 	resultMap, content = readAnsibleConfigSettings(t, cfgPath)
 
 	assert.Equal(t,
-		initialConfigMapLength+2,
+		initialConfigMapLength+3,
 		len(resultMap[ansibleConfigDefaultsHeader]),
 		"Missing entries in ansible config file with user-defined values, content: %q", content)
 
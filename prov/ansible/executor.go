@@ -81,7 +81,31 @@ func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configu
 		}
 		return err
 	}
+	return e.execute(ctx, conf, taskID, deploymentID, nodeName, operation, exec)
+}
+
+func (e *defaultExecutor) ExecOperationInCheckMode(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
+	exec, err := newExecution(ctx, conf, taskID, deploymentID, nodeName, operation, e.cli)
+	if err != nil {
+		if IsOperationNotImplemented(err) {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Voluntary bypassing error: %s", err.Error())
+			return nil
+		}
+		return err
+	}
+	execAnsible, ok := exec.(*executionAnsible)
+	if !ok {
+		// Scripts can't tell what they would change without running
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Operation %q of node %q implemented by %q is not supported in check mode, skipping it", operation.Name, nodeName, operation.ImplementationArtifact)
+		return nil
+	}
+	execAnsible.CheckMode = true
+	// Outputs of a playbook run in check mode are not reliable, they should not update attributes
+	execAnsible.HaveOutput = false
+	return e.execute(ctx, conf, taskID, deploymentID, nodeName, operation, exec)
+}
 
+func (e *defaultExecutor) execute(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, exec execution) error {
 	instances, err := tasks.GetInstances(ctx, taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
@@ -94,7 +118,7 @@ func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configu
 		return nil
 	}
 	if !IsRetriable(err) {
-		logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed", operation.Name, nodeName)
+		logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed: %v", operation.Name, nodeName, err)
 		return err
 	}
 
@@ -109,13 +133,13 @@ func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configu
 				return nil
 			}
 			if !IsRetriable(err) {
-				logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed", operation.Name, nodeName)
+				logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed: %v", operation.Name, nodeName, err)
 				return err
 			}
 		}
 		logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Giving up retries for Ansible error: '%v' (%d/%d)", err, conf.Ansible.ConnectionRetries, conf.Ansible.ConnectionRetries)
 	}
-	logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed", operation.Name, nodeName)
+	logForAllInstances(ctx, deploymentID, instances, events.LogLevelERROR, "Ansible execution for operation %q on node %q failed: %v", operation.Name, nodeName, err)
 	return err
 }
 
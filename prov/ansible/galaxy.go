@@ -0,0 +1,368 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// galaxyRequirementsArtifactName is the name of the TOSCA artifact that may declare
+// the roles and collections required by a playbook
+const galaxyRequirementsArtifactName = "requirements"
+
+// galaxyRequirementsFileNames are the names of requirements files looked up next to a playbook
+var galaxyRequirementsFileNames = []string{"requirements.yml", "requirements.yaml"}
+
+const (
+	defaultAnsibleRolesPath       = "~/.ansible/roles:/usr/share/ansible/roles:/etc/ansible/roles"
+	defaultAnsibleCollectionsPath = "~/.ansible/collections:/usr/share/ansible/collections"
+)
+
+// galaxyRequirements are the roles and collections declared in a requirements file.
+//
+// Entries are kept as generic maps as they are given back to ansible-galaxy.
+type galaxyRequirements struct {
+	Roles       []map[string]interface{} `yaml:"roles,omitempty"`
+	Collections []map[string]interface{} `yaml:"collections,omitempty"`
+}
+
+// loadGalaxyRequirements reads a requirements file, either a list of roles or
+// a map defining roles and collections lists
+func loadGalaxyRequirements(filePath string) (*galaxyRequirements, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read Ansible requirements file %q", filePath)
+	}
+	var raw interface{}
+	if err = yaml.Unmarshal(content, &raw); err != nil {
+		return nil, errors.Wrapf(err, "invalid Ansible requirements file %q", filePath)
+	}
+	reqs := new(galaxyRequirements)
+	switch v := raw.(type) {
+	case nil:
+	case []interface{}:
+		reqs.Roles, err = toRequirementsEntries(v, "src")
+	case map[interface{}]interface{}:
+		for key, entries := range v {
+			list, ok := entries.([]interface{})
+			if !ok && entries != nil {
+				return nil, errors.Errorf("invalid Ansible requirements file %q: %v should be a list", filePath, key)
+			}
+			switch key {
+			case "roles":
+				reqs.Roles, err = toRequirementsEntries(list, "src")
+			case "collections":
+				reqs.Collections, err = toRequirementsEntries(list, "name")
+			}
+			if err != nil {
+				break
+			}
+		}
+	default:
+		err = errors.New("expecting a list of roles or a map of roles and collections")
+	}
+	return reqs, errors.Wrapf(err, "invalid Ansible requirements file %q", filePath)
+}
+
+// toRequirementsEntries converts requirements entries to maps, an entry defined as a simple string
+// being the value of the given key
+func toRequirementsEntries(list []interface{}, stringKey string) ([]map[string]interface{}, error) {
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		switch v := item.(type) {
+		case string:
+			result = append(result, map[string]interface{}{stringKey: v})
+		case map[interface{}]interface{}:
+			entry := make(map[string]interface{}, len(v))
+			for k, val := range v {
+				entry[fmt.Sprint(k)] = val
+			}
+			result = append(result, entry)
+		default:
+			return nil, errors.Errorf("unexpected requirement %v", item)
+		}
+	}
+	return result, nil
+}
+
+func requirementValue(entry map[string]interface{}, key string) string {
+	if v, ok := entry[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// useMirror replaces requirements by the matching archives of the given mirror directory.
+//
+// Roles are looked up as <name>-<version>.tar.gz or <name>.tar.gz, collections as
+// <namespace>-<name>-<version>.tar.gz. When no exact version is specified, the archive having
+// the highest semantic version matching the version constraint, if any, is used.
+// An error is returned if a requirement is not found in the mirror.
+func (reqs *galaxyRequirements) useMirror(mirrorDir string) error {
+	for i, role := range reqs.Roles {
+		name := requirementValue(role, "name")
+		if name == "" {
+			name = requirementValue(role, "src")
+		}
+		var candidates []string
+		if version := requirementValue(role, "version"); version != "" {
+			candidates = append(candidates, fmt.Sprintf("%s-%s.tar.gz", name, version))
+		}
+		candidates = append(candidates, name+".tar.gz")
+		var archive string
+		for _, c := range candidates {
+			if _, err := os.Stat(filepath.Join(mirrorDir, c)); err == nil {
+				archive = filepath.Join(mirrorDir, c)
+				break
+			}
+		}
+		if archive == "" && requirementValue(role, "version") == "" {
+			var err error
+			archive, err = findHighestMirrorArchive(mirrorDir, name+"-", "")
+			if err != nil {
+				return errors.Wrapf(err, "failed to look for role %q in Ansible Galaxy mirror directory %q", name, mirrorDir)
+			}
+		}
+		if archive == "" {
+			return errors.Errorf("role %q not found in Ansible Galaxy mirror directory %q", name, mirrorDir)
+		}
+		reqs.Roles[i] = map[string]interface{}{"src": archive, "name": name}
+	}
+	for i, collection := range reqs.Collections {
+		name := requirementValue(collection, "name")
+		prefix := strings.Replace(name, ".", "-", 1) + "-"
+		version := requirementValue(collection, "version")
+		var archive string
+		if version != "" && version != "*" && !strings.ContainsAny(version, "<>=!,") {
+			if _, err := os.Stat(filepath.Join(mirrorDir, prefix+version+".tar.gz")); err == nil {
+				archive = filepath.Join(mirrorDir, prefix+version+".tar.gz")
+			}
+		} else {
+			var err error
+			archive, err = findHighestMirrorArchive(mirrorDir, prefix, version)
+			if err != nil {
+				return errors.Wrapf(err, "failed to look for collection %q in Ansible Galaxy mirror directory %q", name, mirrorDir)
+			}
+		}
+		if archive == "" {
+			return errors.Errorf("collection %q not found in Ansible Galaxy mirror directory %q", name, mirrorDir)
+		}
+		reqs.Collections[i] = map[string]interface{}{"name": archive}
+	}
+	return nil
+}
+
+// findHighestMirrorArchive returns the archive of the mirror directory named <prefix><version>.tar.gz
+// having the highest semantic version matching the given constraint, or an empty string if there is none.
+//
+// The constraint uses the ansible-galaxy syntax, a comma-separated list of comparisons like ">=1.0,<2.0".
+// Archives which version suffix is not a semantic version are ignored.
+func findHighestMirrorArchive(mirrorDir, prefix, constraint string) (string, error) {
+	versionRange, err := parseGalaxyVersionConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	archives, err := filepath.Glob(filepath.Join(mirrorDir, prefix+"*.tar.gz"))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	var result string
+	var highest semver.Version
+	for _, archive := range archives {
+		v, err := semver.ParseTolerant(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(archive), prefix), ".tar.gz"))
+		if err != nil {
+			continue
+		}
+		if (versionRange == nil || versionRange(v)) && (result == "" || v.GT(highest)) {
+			result = archive
+			highest = v
+		}
+	}
+	return result, nil
+}
+
+// parseGalaxyVersionConstraint converts an ansible-galaxy version constraint to a semantic versions range,
+// a nil range matching any version
+func parseGalaxyVersionConstraint(constraint string) (semver.Range, error) {
+	if constraint == "" || constraint == "*" {
+		return nil, nil
+	}
+	comparisons := make([]string, 0)
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		version := strings.TrimLeft(part, "<>=!")
+		op := part[:len(part)-len(version)]
+		v, err := semver.ParseTolerant(version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid version constraint %q", constraint)
+		}
+		comparisons = append(comparisons, op+v.String())
+	}
+	versionRange, err := semver.ParseRange(strings.Join(comparisons, " "))
+	return versionRange, errors.Wrapf(err, "invalid version constraint %q", constraint)
+}
+
+// findGalaxyRequirementsFile returns the path of the requirements file of the playbook,
+// declared as an artifact or located next to the playbook, or an empty string if there is none
+func (e *executionAnsible) findGalaxyRequirementsFile() string {
+	if art, ok := e.Artifacts[galaxyRequirementsArtifactName]; ok {
+		return filepath.Join(e.OverlayPath, art)
+	}
+	for _, name := range galaxyRequirementsFileNames {
+		reqFile := filepath.Join(filepath.Dir(e.PlaybookPath), name)
+		if _, err := os.Stat(reqFile); err == nil {
+			return reqFile
+		}
+	}
+	return ""
+}
+
+// installGalaxyRequirements installs roles and collections required by the playbook, if any,
+// and sets the environment allowing ansible-playbook to find them.
+//
+// Requirements are installed once per deployment in a cache directory named after
+// the hash of their definition.
+func (e *executionAnsible) installGalaxyRequirements(ctx context.Context) error {
+	e.playbookEnv = nil
+	reqFile := e.findGalaxyRequirementsFile()
+	if reqFile == "" {
+		return nil
+	}
+	reqs, err := loadGalaxyRequirements(reqFile)
+	if err != nil {
+		return err
+	}
+	if len(reqs.Roles) == 0 && len(reqs.Collections) == 0 {
+		return nil
+	}
+	if e.cfg.Ansible.GalaxyMirrorDir != "" {
+		if err = reqs.useMirror(e.cfg.Ansible.GalaxyMirrorDir); err != nil {
+			return err
+		}
+	}
+	content, err := yaml.Marshal(reqs)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate Ansible requirements file")
+	}
+	cacheRoot, err := filepath.Abs(filepath.Join(e.cfg.WorkingDirectory, "deployments", e.deploymentID, "ansible", "galaxy"))
+	if err != nil {
+		return err
+	}
+	cacheDir := filepath.Join(cacheRoot, fmt.Sprintf("%x", sha256.Sum256(content)))
+	if _, err = os.Stat(cacheDir); os.IsNotExist(err) {
+		if err = e.runGalaxyInstall(ctx, reqs, filepath.Dir(reqFile), cacheRoot, cacheDir); err != nil {
+			return err
+		}
+	} else {
+		log.Debugf("Using Ansible requirements cached in %q", cacheDir)
+	}
+
+	rolesPath := defaultAnsibleRolesPath
+	collectionsPath := defaultAnsibleCollectionsPath
+	if v, ok := e.cfg.Ansible.Config[ansibleConfigDefaultsHeader]["roles_path"]; ok {
+		rolesPath = v
+	}
+	if v, ok := e.cfg.Ansible.Config[ansibleConfigDefaultsHeader]["collections_paths"]; ok {
+		collectionsPath = v
+	}
+	e.playbookEnv = append(e.playbookEnv,
+		"ANSIBLE_ROLES_PATH="+filepath.Join(cacheDir, "roles")+":"+rolesPath,
+		"ANSIBLE_COLLECTIONS_PATHS="+filepath.Join(cacheDir, "collections")+":"+collectionsPath)
+	return nil
+}
+
+// runGalaxyInstall installs requirements in a temporary directory renamed into cacheDir
+// once done, so that concurrent executions never use a partially installed cache
+func (e *executionAnsible) runGalaxyInstall(ctx context.Context, reqs *galaxyRequirements, workDir, cacheRoot, cacheDir string) error {
+	if err := os.MkdirAll(cacheRoot, 0775); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", cacheRoot)
+	}
+	tmpDir, err := ioutil.TempDir(cacheRoot, ".install-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory in %q", cacheRoot)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf(
+		"Installing %d Ansible role(s) and %d collection(s) required by node %q operation %q",
+		len(reqs.Roles), len(reqs.Collections), e.NodeName, e.operation.Name)
+
+	if len(reqs.Roles) > 0 {
+		rolesFile := filepath.Join(tmpDir, "roles.yml")
+		if err = writeYAMLFile(rolesFile, reqs.Roles); err != nil {
+			return err
+		}
+		err = e.runGalaxyCommand(ctx, workDir, "install", "-r", rolesFile, "-p", filepath.Join(tmpDir, "roles"))
+		if err != nil {
+			return errors.Wrap(err, "failed to install Ansible roles")
+		}
+	}
+	if len(reqs.Collections) > 0 {
+		collectionsFile := filepath.Join(tmpDir, "collections.yml")
+		if err = writeYAMLFile(collectionsFile, galaxyRequirements{Collections: reqs.Collections}); err != nil {
+			return err
+		}
+		err = e.runGalaxyCommand(ctx, workDir, "collection", "install", "-r", collectionsFile, "-p", filepath.Join(tmpDir, "collections"))
+		if err != nil {
+			return errors.Wrap(err, "failed to install Ansible collections")
+		}
+	}
+
+	if err = os.Rename(tmpDir, cacheDir); err != nil {
+		if _, statErr := os.Stat(cacheDir); statErr == nil {
+			// Installed meanwhile by a concurrent execution
+			return nil
+		}
+		return errors.Wrapf(err, "failed to move Ansible requirements to %q", cacheDir)
+	}
+	return nil
+}
+
+func (e *executionAnsible) runGalaxyCommand(ctx context.Context, workDir string, args ...string) error {
+	cmd := executil.Command(ctx, e.ansibleCommand("ansible-galaxy"), args...)
+	cmd.Dir = workDir
+	if env := e.virtualenvEnv(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	outBuf := events.NewBufferedLogEntryWriter()
+	cmd.Stdout = outBuf
+	cmd.Stderr = outBuf
+	quit := make(chan bool)
+	defer close(quit)
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).RunBufferedRegistration(outBuf, quit)
+	return cmd.Run()
+}
+
+func writeYAMLFile(filePath string, v interface{}) error {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate file %q", filePath)
+	}
+	return errors.Wrapf(ioutil.WriteFile(filePath, content, 0664), "failed to write file %q", filePath)
+}
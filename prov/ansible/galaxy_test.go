@@ -0,0 +1,107 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGalaxyRequirements(t *testing.T) {
+	reqs, err := loadGalaxyRequirements("testdata/galaxy/requirements.yml")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"src": "geerlingguy.java"},
+		{"src": "geerlingguy.docker"},
+		{"name": "nginx", "src": "https://github.com/example/ansible-role-nginx.git", "version": "v1.2.0"},
+	}, reqs.Roles)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "community.general"},
+		{"name": "ansible.posix", "version": "1.1.1"},
+		{"name": "community.crypto", "version": ">=1.0,<2.0"},
+	}, reqs.Collections)
+
+	reqs, err = loadGalaxyRequirements("testdata/galaxy/roles_list.yml")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"src": "geerlingguy.apache", "version": "3.1.0"}}, reqs.Roles)
+	assert.Len(t, reqs.Collections, 0)
+}
+
+func TestGalaxyRequirementsUseMirror(t *testing.T) {
+	mirrorDir, err := ioutil.TempDir("", "galaxy-mirror")
+	require.NoError(t, err)
+	defer os.RemoveAll(mirrorDir)
+	for _, f := range []string{"geerlingguy.java.tar.gz", "geerlingguy.docker-4.9.0.tar.gz", "geerlingguy.docker-4.10.0.tar.gz",
+		"nginx-v1.2.0.tar.gz", "community-general-1.0.0.tar.gz", "community-general-1.3.0.tar.gz", "community-general-1.10.0.tar.gz",
+		"ansible-posix-1.1.1.tar.gz", "community-crypto-1.9.0.tar.gz", "community-crypto-1.10.0.tar.gz", "community-crypto-2.0.0.tar.gz"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(mirrorDir, f), []byte{}, 0644))
+	}
+
+	reqs, err := loadGalaxyRequirements("testdata/galaxy/requirements.yml")
+	require.NoError(t, err)
+	require.NoError(t, reqs.useMirror(mirrorDir))
+	assert.Equal(t, []map[string]interface{}{
+		{"src": filepath.Join(mirrorDir, "geerlingguy.java.tar.gz"), "name": "geerlingguy.java"},
+		{"src": filepath.Join(mirrorDir, "geerlingguy.docker-4.10.0.tar.gz"), "name": "geerlingguy.docker"},
+		{"src": filepath.Join(mirrorDir, "nginx-v1.2.0.tar.gz"), "name": "nginx"},
+	}, reqs.Roles)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": filepath.Join(mirrorDir, "community-general-1.10.0.tar.gz")},
+		{"name": filepath.Join(mirrorDir, "ansible-posix-1.1.1.tar.gz")},
+		{"name": filepath.Join(mirrorDir, "community-crypto-1.10.0.tar.gz")},
+	}, reqs.Collections)
+
+	reqs, err = loadGalaxyRequirements("testdata/galaxy/roles_list.yml")
+	require.NoError(t, err)
+	assert.Error(t, reqs.useMirror(mirrorDir), "expecting an error for a role missing from the mirror")
+}
+
+func TestParseGalaxyVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint  string
+		matching    []string
+		notMatching []string
+		wantErr     bool
+	}{
+		{"", []string{"0.1.0", "3.2.1"}, nil, false},
+		{"*", []string{"0.1.0", "3.2.1"}, nil, false},
+		{"1.2.0", []string{"1.2.0"}, []string{"1.2.1"}, false},
+		{"==1.2", []string{"1.2.0"}, []string{"1.3.0"}, false},
+		{"!=1.2.0", []string{"1.1.0", "1.3.0"}, []string{"1.2.0"}, false},
+		{">=2.0,<3.0", []string{"2.0.0", "2.10.1"}, []string{"1.9.0", "3.0.0"}, false},
+		{">1.0, <=1.5", []string{"1.0.1", "1.5.0"}, []string{"1.0.0", "1.5.1"}, false},
+		{">=latest", nil, nil, true},
+	}
+	for _, tt := range tests {
+		versionRange, err := parseGalaxyVersionConstraint(tt.constraint)
+		if tt.wantErr {
+			assert.Error(t, err, "constraint %q", tt.constraint)
+			continue
+		}
+		require.NoError(t, err, "constraint %q", tt.constraint)
+		for _, v := range tt.matching {
+			assert.True(t, versionRange == nil || versionRange(semver.MustParse(v)), "%s should match %q", v, tt.constraint)
+		}
+		for _, v := range tt.notMatching {
+			assert.False(t, versionRange(semver.MustParse(v)), "%s should not match %q", v, tt.constraint)
+		}
+	}
+}
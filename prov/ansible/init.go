@@ -19,6 +19,8 @@ import "github.com/ystia/yorc/v4/registry"
 const (
 	implementationArtifactBash         = "tosca.artifacts.Implementation.Bash"
 	implementationArtifactPython       = "tosca.artifacts.Implementation.Python"
+	implementationArtifactPowerShell   = "tosca.artifacts.Implementation.PowerShell"
+	implementationArtifactExecutable   = "yorc.artifacts.Implementation.Executable"
 	implementationArtifactAnsible      = "tosca.artifacts.Implementation.Ansible"
 	implementationArtifactAnsibleAlien = "org.alien4cloud.artifacts.AnsiblePlaybook"
 )
@@ -30,6 +32,8 @@ func init() {
 		[]string{
 			implementationArtifactBash,
 			implementationArtifactPython,
+			implementationArtifactPowerShell,
+			implementationArtifactExecutable,
 			implementationArtifactAnsible,
 			implementationArtifactAnsibleAlien,
 		}, executor, registry.BuiltinOrigin)
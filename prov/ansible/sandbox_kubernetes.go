@@ -0,0 +1,248 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/stringutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+const (
+	sandboxTypeKubernetes          = "kubernetes"
+	kubernetesSandboxContainerName = "sandbox"
+	kubernetesSandboxRecipesVolume = "recipes"
+)
+
+// kubernetesSandboxPollInterval is the interval between checks of the sandbox pod status while waiting for it to run
+var kubernetesSandboxPollInterval = 2 * time.Second
+
+func isKubernetesSandbox(sandboxCfg *config.DockerSandbox) bool {
+	return sandboxCfg != nil && strings.ToLower(sandboxCfg.Type) == sandboxTypeKubernetes
+}
+
+func getKubernetesSandboxNamespace(sandboxCfg *config.DockerSandbox) string {
+	if sandboxCfg.Namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return sandboxCfg.Namespace
+}
+
+// newKubernetesSandboxClientSet connects to the Kubernetes cluster defined by the kubeconfig option of the sandbox,
+// or to the cluster in which Yorc runs if this option is not set
+func newKubernetesSandboxClientSet(sandboxCfg *config.DockerSandbox) (kubernetes.Interface, error) {
+	var conf *rest.Config
+	var err error
+	if sandboxCfg.KubeConfig == "" {
+		conf, err = rest.InClusterConfig()
+	} else {
+		conf, err = clientcmd.BuildConfigFromFlags("", sandboxCfg.KubeConfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build Kubernetes configuration for the sandbox of orchestrator-hosted operations")
+	}
+	clientset, err := kubernetes.NewForConfig(conf)
+	return clientset, errors.Wrap(err, "failed to create Kubernetes clientset for the sandbox of orchestrator-hosted operations")
+}
+
+// newKubernetesSandboxPod returns the definition of a sandbox pod.
+//
+// If a recipes volume claim is configured, the recipe directory is mounted at the same path than on the Yorc host
+// so that paths of the generated recipe remain valid inside the sandbox.
+func newKubernetesSandboxPod(sandboxCfg *config.DockerSandbox, deploymentID, recipePath, workingDirectory string) (*corev1.Pod, error) {
+	container := corev1.Container{
+		Name:    kubernetesSandboxContainerName,
+		Image:   sandboxCfg.Image,
+		Command: sandboxCfg.Entrypoint,
+		Args:    sandboxCfg.Command,
+	}
+	if len(sandboxCfg.Command) == 0 && len(sandboxCfg.Entrypoint) == 0 {
+		container.Command = []string{"python"}
+		container.Args = []string{"-c", "import time;time.sleep(31536000);"}
+	}
+	for _, env := range sandboxCfg.Env {
+		nameValue := strings.SplitN(env, "=", 2)
+		envVar := corev1.EnvVar{Name: nameValue[0]}
+		if len(nameValue) == 2 {
+			envVar.Value = nameValue[1]
+		}
+		container.Env = append(container.Env, envVar)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      stringutil.UniqueTimestampedName("yorc-sandbox-", ""),
+			Namespace: getKubernetesSandboxNamespace(sandboxCfg),
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "yorc-sandbox",
+				"app.kubernetes.io/managed-by": "yorc",
+			},
+			Annotations: map[string]string{
+				"yorc.ystia.org/deployment-id": deploymentID,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: sandboxCfg.ServiceAccount,
+		},
+	}
+
+	if sandboxCfg.RecipesVolumeClaim != "" {
+		workingDirectory, err := filepath.Abs(workingDirectory)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to resolve Yorc working directory")
+		}
+		subPath, err := filepath.Rel(workingDirectory, recipePath)
+		if err != nil || strings.HasPrefix(subPath, "..") {
+			return nil, errors.Errorf("recipe directory %q is not in the Yorc working directory %q stored on the volume claim %q", recipePath, workingDirectory, sandboxCfg.RecipesVolumeClaim)
+		}
+		pod.Spec.Volumes = []corev1.Volume{
+			{
+				Name: kubernetesSandboxRecipesVolume,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: sandboxCfg.RecipesVolumeClaim},
+				},
+			},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{Name: kubernetesSandboxRecipesVolume, MountPath: recipePath, SubPath: subPath},
+		}
+	}
+	pod.Spec.Containers = []corev1.Container{container}
+	return pod, nil
+}
+
+func createKubernetesSandbox(ctx context.Context, clientset kubernetes.Interface, sandboxCfg *config.DockerSandbox, deploymentID, recipePath, workingDirectory string) (string, error) {
+	// check context is cancelable
+	if ctx.Done() == nil {
+		return "", errors.New("should provide a cancelable context for creating a Kubernetes sandbox")
+	}
+
+	if sandboxCfg.Image == "" {
+		return "", errors.New("Kubernetes sandbox for orchestrator-hosted operation misconfigured, image option is missing")
+	}
+
+	pod, err := newKubernetesSandboxPod(sandboxCfg, deploymentID, recipePath, workingDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Creating Kubernetes sandbox pod %q from image: %s", pod.Name, sandboxCfg.Image)
+	pod, err = clientset.CoreV1().Pods(pod.Namespace).Create(pod)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to create Kubernetes sandbox %q", sandboxCfg.Image)
+	}
+	go deleteKubernetesSandboxOnContextCancellation(ctx, clientset, deploymentID, pod.Namespace, pod.Name)
+
+	err = waitForKubernetesSandbox(ctx, clientset, pod.Namespace, pod.Name)
+	if err != nil {
+		return "", err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Kubernetes sandbox pod %q started", pod.Name)
+	go streamKubernetesSandboxLogs(ctx, clientset, deploymentID, pod.Namespace, pod.Name)
+	return pod.Name, nil
+}
+
+// waitForKubernetesSandbox waits for the sandbox pod to run, failing if it terminates or if its image can't be pulled
+func waitForKubernetesSandbox(ctx context.Context, clientset kubernetes.Interface, namespace, podName string) error {
+	err := wait.PollImmediateUntil(kubernetesSandboxPollInterval, func() (bool, error) {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get status of Kubernetes sandbox pod %q", podName)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return true, nil
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return false, errors.Errorf("Kubernetes sandbox pod %q terminated with phase %q: %s", podName, pod.Status.Phase, pod.Status.Message)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting == nil {
+				continue
+			}
+			switch status.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError":
+				return false, errors.Errorf("Kubernetes sandbox pod %q failed to start: %s %s", podName, status.State.Waiting.Reason, status.State.Waiting.Message)
+			}
+		}
+		return false, nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("cancelled while waiting for Kubernetes sandbox pod %q to run", podName)
+	}
+	return err
+}
+
+// streamKubernetesSandboxLogs publishes the logs of the sandbox pod until it is deleted
+func streamKubernetesSandboxLogs(ctx context.Context, clientset kubernetes.Interface, deploymentID, namespace, podName string) {
+	logs, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{Container: kubernetesSandboxContainerName, Follow: true}).Stream()
+	if err != nil {
+		log.Debugf("Failed to stream logs of Kubernetes sandbox pod %q: %v", podName, err)
+		return
+	}
+	defer logs.Close()
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("[sandbox %s] %s", podName, scanner.Text())
+	}
+}
+
+func deleteKubernetesSandboxOnContextCancellation(ctx context.Context, clientset kubernetes.Interface, deploymentID, namespace, podName string) {
+	<-ctx.Done()
+	gracePeriod := int64(10)
+	propagation := metav1.DeletePropagationBackground
+	err := clientset.CoreV1().Pods(namespace).Delete(podName, &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation})
+	if err != nil {
+		log.Printf("Failed to delete Kubernetes sandbox pod %v", err)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to delete your Kubernetes pod execution sandbox %q in namespace %q. Please report this to your system administrator.", podName, namespace)
+		return
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Kubernetes sandbox pod %q removed", podName)
+}
+
+// generateKubernetesSandboxConnection starts a sandbox pod and connects Ansible to it using the kubectl connection plugin
+func (e *executionCommon) generateKubernetesSandboxConnection(ctx context.Context, buffer *bytes.Buffer, sandboxCfg *config.DockerSandbox) error {
+	clientset, err := newKubernetesSandboxClientSet(sandboxCfg)
+	if err != nil {
+		return err
+	}
+	podName, err := createKubernetesSandbox(ctx, clientset, sandboxCfg, e.deploymentID, e.recipePath, e.cfg.WorkingDirectory)
+	if err != nil {
+		return err
+	}
+	buffer.WriteString(fmt.Sprintf(" ansible_connection=kubectl ansible_kubectl_pod=%s ansible_kubectl_namespace=%s ansible_kubectl_container=%s",
+		podName, getKubernetesSandboxNamespace(sandboxCfg), kubernetesSandboxContainerName))
+	if sandboxCfg.KubeConfig != "" {
+		buffer.WriteString(fmt.Sprintf(" ansible_kubectl_kubeconfig=%s", sandboxCfg.KubeConfig))
+	}
+	return nil
+}
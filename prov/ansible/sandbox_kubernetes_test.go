@@ -0,0 +1,100 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestNewKubernetesSandboxPod(t *testing.T) {
+	t.Parallel()
+	sandboxCfg := &config.DockerSandbox{
+		Type:               "kubernetes",
+		Image:              "python:3-slim",
+		Env:                []string{"HTTP_PROXY=http://proxy:3128", "EMPTY"},
+		Namespace:          "sandboxes",
+		RecipesVolumeClaim: "yorc-work",
+	}
+	pod, err := newKubernetesSandboxPod(sandboxCfg, "dep1", "/var/yorc/work/deployments/dep1/ansible/t1/Node/standard.create", "/var/yorc/work")
+	require.NoError(t, err)
+	assert.Equal(t, "sandboxes", pod.Namespace)
+	assert.Equal(t, "dep1", pod.Annotations["yorc.ystia.org/deployment-id"])
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	require.Len(t, pod.Spec.Containers, 1)
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "python:3-slim", container.Image)
+	assert.Equal(t, []string{"python"}, container.Command)
+	assert.Equal(t, []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "EMPTY"}}, container.Env)
+	require.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, "yorc-work", pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	require.Len(t, container.VolumeMounts, 1)
+	assert.Equal(t, "/var/yorc/work/deployments/dep1/ansible/t1/Node/standard.create", container.VolumeMounts[0].MountPath)
+	assert.Equal(t, "deployments/dep1/ansible/t1/Node/standard.create", container.VolumeMounts[0].SubPath)
+
+	sandboxCfg = &config.DockerSandbox{Type: "kubernetes", Image: "python:3-slim", Command: []string{"sleep", "3600"}}
+	pod, err = newKubernetesSandboxPod(sandboxCfg, "dep1", "/tmp/recipe", "/var/yorc/work")
+	require.NoError(t, err)
+	assert.Equal(t, metav1.NamespaceDefault, pod.Namespace)
+	assert.Nil(t, pod.Spec.Containers[0].Command)
+	assert.Equal(t, []string{"sleep", "3600"}, pod.Spec.Containers[0].Args)
+	assert.Len(t, pod.Spec.Volumes, 0)
+
+	sandboxCfg.RecipesVolumeClaim = "yorc-work"
+	_, err = newKubernetesSandboxPod(sandboxCfg, "dep1", "/tmp/recipe", "/var/yorc/work")
+	assert.Error(t, err, "expecting an error for a recipe outside of the working directory")
+}
+
+func TestWaitForKubernetesSandbox(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		status  corev1.PodStatus
+		wantErr bool
+	}{
+		{"Running", corev1.PodStatus{Phase: corev1.PodRunning}, false},
+		{"Failed", corev1.PodStatus{Phase: corev1.PodFailed, Message: "evicted"}, true},
+		{"ImagePullError", corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+		}}, true},
+		{"Pending", corev1.PodStatus{Phase: corev1.PodPending}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Namespace: "ns"},
+				Status:     tt.status,
+			})
+			// Pending pods never run so the wait is stopped by the context
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := waitForKubernetesSandbox(ctx, clientset, "ns", "sandbox")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
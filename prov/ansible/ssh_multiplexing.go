@@ -0,0 +1,120 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/sshutil"
+)
+
+const ansibleConfigSSHConnectionHeader = "ssh_connection"
+
+// controlMastersDirsMaxAge is the duration after which directories of control
+// sockets without master connection are removed
+const controlMastersDirsMaxAge = 24 * time.Hour
+
+// isSSHMultiplexingEnabled returns true if Ansible runs should share OpenSSH
+// master connections to remote hosts
+func (e *executionCommon) isSSHMultiplexingEnabled() bool {
+	return !e.isOrchestratorOperation && e.cfg.Ansible.UseOpenSSH && e.cfg.Ansible.SSHControlPersist > 0
+}
+
+// sshMultiplexingArgs returns OpenSSH options opening a master connection if
+// none exists yet and keeping it open in the background once idle
+func (e *executionCommon) sshMultiplexingArgs() string {
+	return fmt.Sprintf("-o ControlMaster=auto -o ControlPersist=%ds",
+		int(math.Ceil(e.cfg.Ansible.SSHControlPersist.Seconds())))
+}
+
+// sshControlPath returns the control socket of the master connection to a host
+// shared by all operations of the task and by the SSH clients of Yorc, or an
+// empty string if SSH multiplexing is disabled
+func (e *executionCommon) sshControlPath(user, host string, port int) string {
+	if !e.isSSHMultiplexingEnabled() {
+		return ""
+	}
+	return sshutil.ControlPath(sshutil.ControlMastersDir(e.taskID), user, host, port)
+}
+
+// sshBastionMultiplexingArgs returns OpenSSH options of the ProxyCommand going
+// through a bastion host so that connections to the bastion are multiplexed too
+func (e *executionCommon) sshBastionMultiplexingArgs(bastion *sshutil.BastionHostConfig) string {
+	if !e.isSSHMultiplexingEnabled() {
+		return ""
+	}
+	var port int
+	fmt.Sscanf(bastion.Port, "%d", &port)
+	return fmt.Sprintf("%s -o ControlPath=%s ", e.sshMultiplexingArgs(), e.sshControlPath(bastion.User, bastion.Host, port))
+}
+
+// setSSHMultiplexingConfig adds to the Ansible configuration the ssh connection
+// settings sharing master connections between Ansible runs of the task
+func (e *executionCommon) setSSHMultiplexingConfig(ansibleConfig map[string]map[string]string) error {
+	if !e.isSSHMultiplexingEnabled() {
+		return nil
+	}
+	sshutil.PruneControlMastersDirs(controlMastersDirsMaxAge)
+	controlPathDir := sshutil.ControlMastersDir(e.taskID)
+	if err := os.MkdirAll(controlPathDir, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create SSH control path directory %q", controlPathDir)
+	}
+	if _, ok := ansibleConfig[ansibleConfigSSHConnectionHeader]; !ok {
+		ansibleConfig[ansibleConfigSSHConnectionHeader] = make(map[string]string)
+	}
+	// Keep Ansible default compression option
+	ansibleConfig[ansibleConfigSSHConnectionHeader]["ssh_args"] = "-C " + e.sshMultiplexingArgs()
+	// Control paths are set per host in the inventory, those settings only
+	// apply to hosts without explicit control path
+	ansibleConfig[ansibleConfigSSHConnectionHeader]["control_path_dir"] = controlPathDir
+	// %C is a hash of the local host, remote host, port and user
+	ansibleConfig[ansibleConfigSSHConnectionHeader]["control_path"] = "%(directory)s/%%C"
+	return nil
+}
+
+// startSSHMultiplexingMetrics reports per host master connections already open
+// when an Ansible run starts as reused, the returned function reports master
+// connections opened by the run once it is done
+func (e *executionCommon) startSSHMultiplexingMetrics() func() {
+	if !e.isSSHMultiplexingEnabled() {
+		return func() {}
+	}
+	// several instances may be hosted on the same host
+	hosts := make(map[string]string, len(e.hosts))
+	for _, host := range e.hosts {
+		if host.controlPath != "" {
+			hosts[host.controlPath] = host.host
+		}
+	}
+	openedBefore := make(map[string]bool, len(hosts))
+	for controlPath, host := range hosts {
+		openedBefore[controlPath] = sshutil.IsControlMasterOpen(controlPath)
+		if openedBefore[controlPath] {
+			sshutil.ReportControlMasterUse(host, true)
+		}
+	}
+	return func() {
+		for controlPath, host := range hosts {
+			if !openedBefore[controlPath] && sshutil.IsControlMasterOpen(controlPath) {
+				sshutil.ReportControlMasterUse(host, false)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+)
+
+func TestSSHMultiplexingConfig(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "TestSSHMultiplexingConfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+	defaultRootDir := sshutil.ControlMastersRootDir
+	sshutil.ControlMastersRootDir = filepath.Join(tempdir, "ssh")
+	defer func() { sshutil.ControlMastersRootDir = defaultRootDir }()
+
+	e := &executionCommon{
+		taskID: "d8c3bba3-1c0b-4e2e-9c70-8c2a0d4a6e19",
+		cfg: config.Configuration{Ansible: config.Ansible{
+			UseOpenSSH:        true,
+			SSHControlPersist: 90 * time.Second,
+		}},
+	}
+	require.NoError(t, e.generateAnsibleConfigurationFile("ansiblePath", tempdir))
+	resultMap, content := readAnsibleConfigSettings(t, filepath.Join(tempdir, "ansible.cfg"))
+	controlPathDir := sshutil.ControlMastersDir(e.taskID)
+	assert.DirExists(t, controlPathDir)
+	assert.Equal(t, "-C -o ControlMaster=auto -o ControlPersist=90s", resultMap[ansibleConfigSSHConnectionHeader]["ssh_args"], content)
+	assert.Equal(t, controlPathDir, resultMap[ansibleConfigSSHConnectionHeader]["control_path_dir"], content)
+	assert.Equal(t, "%(directory)s/%%C", resultMap[ansibleConfigSSHConnectionHeader]["control_path"], content)
+	bastion := &sshutil.BastionHostConfig{Host: "bastion", Port: "2222", User: "admin"}
+	assert.Equal(t, "-o ControlMaster=auto -o ControlPersist=90s -o ControlPath="+sshutil.ControlPath(controlPathDir, "admin", "bastion", 2222)+" ",
+		e.sshBastionMultiplexingArgs(bastion))
+
+	// User-defined settings take precedence
+	e.cfg.Ansible.Config = map[string]map[string]string{
+		ansibleConfigSSHConnectionHeader: {"ssh_args": "-o ControlMaster=no"},
+	}
+	require.NoError(t, e.generateAnsibleConfigurationFile("ansiblePath", tempdir))
+	resultMap, content = readAnsibleConfigSettings(t, filepath.Join(tempdir, "ansible.cfg"))
+	assert.Equal(t, "-o ControlMaster=no", resultMap[ansibleConfigSSHConnectionHeader]["ssh_args"], content)
+
+	// Multiplexing is only available with OpenSSH
+	e.cfg.Ansible.Config = nil
+	e.cfg.Ansible.UseOpenSSH = false
+	require.NoError(t, e.generateAnsibleConfigurationFile("ansiblePath", tempdir))
+	resultMap, content = readAnsibleConfigSettings(t, filepath.Join(tempdir, "ansible.cfg"))
+	_, ok := resultMap[ansibleConfigSSHConnectionHeader]
+	assert.False(t, ok, content)
+	assert.Equal(t, "", e.sshBastionMultiplexingArgs(bastion))
+	assert.Equal(t, "", e.sshControlPath("admin", "bastion", 2222))
+}
+
+func TestGenerateHostConnectionWithSSHMultiplexing(t *testing.T) {
+	e := &executionCommon{
+		taskID: "t1",
+		cfg: config.Configuration{Ansible: config.Ansible{
+			UseOpenSSH:        true,
+			SSHControlPersist: time.Minute,
+		}},
+	}
+	controlPathDir := sshutil.ControlMastersDir(e.taskID)
+
+	host := &hostConnection{host: "10.0.0.1", user: "centos", password: "secret"}
+	var buffer bytes.Buffer
+	require.NoError(t, e.generateHostConnection(context.Background(), &buffer, host))
+	assert.Equal(t, sshutil.ControlPath(controlPathDir, "centos", "10.0.0.1", 22), host.controlPath)
+	assert.Contains(t, buffer.String(), "ansible_ssh_common_args='-o ConnectionAttempts=20 -o ControlPath="+host.controlPath+"'")
+
+	host = &hostConnection{host: "10.0.0.2", port: 2222, user: "centos", password: "secret",
+		bastion: &sshutil.BastionHostConfig{Host: "bastion", User: "admin"}}
+	buffer.Reset()
+	require.NoError(t, e.generateHostConnection(context.Background(), &buffer, host))
+	assert.Equal(t, sshutil.ControlPath(controlPathDir, "centos", "10.0.0.2", 2222), host.controlPath)
+	assert.Contains(t, buffer.String(), "ControlPath="+sshutil.ControlPath(controlPathDir, "admin", "bastion", 22)+
+		" -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -p 22 admin@bastion\" -o ControlPath="+host.controlPath+"'")
+}
@@ -0,0 +1,281 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/log"
+)
+
+const taskResultsCallbackName = "yorc_task_results"
+
+// defaultAnsibleCallbackPluginsPath is the Ansible default value of the callback_plugins setting
+const defaultAnsibleCallbackPluginsPath = "~/.ansible/plugins/callback:/usr/share/ansible/plugins/callback"
+
+// taskResultsFDEnvVar is the environment variable giving to the callback plugin
+// the file descriptor where task results should be written
+const taskResultsFDEnvVar = "YORC_TASK_RESULTS_FD"
+
+// taskResultsCallbackPlugin is an Ansible notification callback plugin writing
+// a JSON record for each task result on a file descriptor provided by Yorc.
+// It doesn't need to be whitelisted and so doesn't change the stdout callback output.
+const taskResultsCallbackPlugin = `from __future__ import (absolute_import, division, print_function)
+__metaclass__ = type
+
+import json
+import os
+import time
+
+from ansible.module_utils._text import to_text
+from ansible.plugins.callback import CallbackBase
+
+
+class CallbackModule(CallbackBase):
+    CALLBACK_VERSION = 2.0
+    CALLBACK_TYPE = 'notification'
+    CALLBACK_NAME = '` + taskResultsCallbackName + `'
+    CALLBACK_NEEDS_WHITELIST = False
+    CALLBACK_NEEDS_ENABLED = False
+
+    def __init__(self):
+        super(CallbackModule, self).__init__()
+        self._out = None
+        self._start = {}
+        fd = os.environ.get('` + taskResultsFDEnvVar + `')
+        if fd:
+            try:
+                self._out = os.fdopen(int(fd), 'w')
+            except (OSError, ValueError):
+                self._out = None
+
+    def v2_playbook_on_task_start(self, task, is_conditional):
+        self._start[task._uuid] = time.time()
+
+    def v2_playbook_on_handler_task_start(self, task):
+        self._start[task._uuid] = time.time()
+
+    def _record(self, result, status):
+        if self._out is None:
+            return
+        task = result._task
+        res = result._result
+        start = self._start.get(task._uuid)
+        record = {
+            'host': result._host.get_name(),
+            'task': task.get_name(),
+            'status': status,
+            'duration': time.time() - start if start else 0,
+        }
+        facts = res.get('ansible_facts')
+        if facts and task.action not in ('setup', 'gather_facts'):
+            record['facts'] = facts
+        if status in ('failed', 'unreachable'):
+            msg = res.get('msg', '')
+            if res.get('stderr'):
+                msg = '%s: %s' % (to_text(msg), to_text(res.get('stderr')))
+            record['msg'] = to_text(msg)
+        try:
+            self._out.write(json.dumps(record, default=to_text) + '\n')
+            self._out.flush()
+        except (IOError, OSError, TypeError, ValueError):
+            pass
+
+    def v2_runner_on_ok(self, result):
+        self._record(result, 'changed' if result._result.get('changed', False) else 'ok')
+
+    def v2_runner_on_failed(self, result, ignore_errors=False):
+        self._record(result, 'ignored' if ignore_errors else 'failed')
+
+    def v2_runner_on_skipped(self, result):
+        self._record(result, 'skipped')
+
+    def v2_runner_on_unreachable(self, result):
+        self._record(result, 'unreachable')
+
+    def v2_on_file_diff(self, result):
+        if self._out is None or 'diff' not in result._result:
+            return
+        diff = self._get_diff(result._result['diff'])
+        if not diff:
+            return
+        record = {
+            'host': result._host.get_name(),
+            'task': result._task.get_name(),
+            'status': 'diff',
+            'diff': diff,
+        }
+        try:
+            self._out.write(json.dumps(record, default=to_text) + '\n')
+            self._out.flush()
+        except (IOError, OSError, TypeError, ValueError):
+            pass
+`
+
+// taskResultsDrainTimeout is the maximum time spent reading task results once
+// ansible-playbook exited, in case the results pipe is held open by a remaining child process
+const taskResultsDrainTimeout = 5 * time.Second
+
+// taskResult is a record written by the task results callback plugin
+type taskResult struct {
+	Host     string                 `json:"host"`
+	Task     string                 `json:"task"`
+	Status   string                 `json:"status"`
+	Duration float64                `json:"duration"`
+	Facts    map[string]interface{} `json:"facts,omitempty"`
+	Msg      string                 `json:"msg,omitempty"`
+	// Diff is set on records of status "diff", reporting changes of a task in diff mode
+	Diff string `json:"diff,omitempty"`
+}
+
+// writeTaskResultsCallbackPlugin writes the task results callback plugin in the
+// callback plugins directory of a recipe
+func writeTaskResultsCallbackPlugin(ansibleRecipePath string) error {
+	pluginsDir := filepath.Join(ansibleRecipePath, "callback_plugins")
+	if err := os.MkdirAll(pluginsDir, 0775); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", pluginsDir)
+	}
+	pluginFile := filepath.Join(pluginsDir, taskResultsCallbackName+".py")
+	return errors.Wrapf(ioutil.WriteFile(pluginFile, []byte(taskResultsCallbackPlugin), 0664), "failed to write file %q", pluginFile)
+}
+
+// taskResultsCollector reads task results written by the callback plugin, publishes them
+// as events and keeps failed ones to report them as the execution error
+type taskResultsCollector struct {
+	ctx          context.Context
+	deploymentID string
+	nodeName     string
+	hosts        map[string]*hostConnection
+	reader       *os.File
+	writer       *os.File
+	done         chan struct{}
+	lock         sync.Mutex
+	failures     []taskResult
+}
+
+// newTaskResultsCollector creates the pipe on which the callback plugin writes results.
+// Its writer end has to be passed to ansible-playbook as file descriptor 3.
+func newTaskResultsCollector(ctx context.Context, deploymentID, nodeName string, hosts map[string]*hostConnection) (*taskResultsCollector, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Ansible task results pipe")
+	}
+	return &taskResultsCollector{
+		ctx:          ctx,
+		deploymentID: deploymentID,
+		nodeName:     nodeName,
+		hosts:        hosts,
+		reader:       r,
+		writer:       w,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// start starts reading results, it has to be called once ansible-playbook is started
+func (c *taskResultsCollector) start() {
+	// ansible-playbook got its own copy
+	c.writer.Close()
+	go func() {
+		defer close(c.done)
+		c.read(c.reader)
+	}()
+}
+
+// stop waits for all results to be read, it has to be called once ansible-playbook exited
+func (c *taskResultsCollector) stop() {
+	c.reader.SetReadDeadline(time.Now().Add(taskResultsDrainTimeout))
+	<-c.done
+	c.reader.Close()
+}
+
+func (c *taskResultsCollector) read(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var res taskResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			log.Debugf("Ignoring invalid Ansible task result %q: %v", scanner.Text(), err)
+			continue
+		}
+		c.publish(res)
+	}
+	if err := scanner.Err(); err != nil && !os.IsTimeout(err) {
+		log.Debugf("Failed to read Ansible task results: %v", err)
+	}
+}
+
+func (c *taskResultsCollector) publish(res taskResult) {
+	if res.Status == "diff" {
+		c.logDiff(res)
+		return
+	}
+	if res.Status == "failed" || res.Status == "unreachable" {
+		c.lock.Lock()
+		c.failures = append(c.failures, res)
+		c.lock.Unlock()
+	}
+	_, err := events.PublishAndLogAnsibleTaskResult(c.ctx, c.deploymentID, &events.AnsibleTaskResult{
+		NodeName:     c.nodeName,
+		InstanceName: getInstanceIDForHost(res.Host, c.hosts),
+		HostName:     res.Host,
+		TaskName:     res.Task,
+		Status:       res.Status,
+		Duration:     time.Duration(res.Duration * float64(time.Second)).Round(time.Millisecond),
+		Facts:        res.Facts,
+		Message:      res.Msg,
+	})
+	if err != nil {
+		log.Printf("Failed to publish Ansible task result for deployment %q: %v", c.deploymentID, err)
+	}
+}
+
+// logDiff logs changes reported by a task in diff mode, those are not task results
+// and so are not published as events
+func (c *taskResultsCollector) logDiff(res taskResult) {
+	logCtx := events.AddLogOptionalFields(c.ctx, events.LogOptionalFields{
+		events.InstanceID:      getInstanceIDForHost(res.Host, c.hosts),
+		events.HostName:        res.Host,
+		events.AnsibleTaskName: res.Task,
+	})
+	events.WithContextOptionalFields(logCtx).NewLogEntry(events.LogLevelINFO, c.deploymentID).Registerf("Ansible task %q changes on host %q:\n%s", res.Task, res.Host, res.Diff)
+}
+
+// failuresDescription returns a description of failed tasks, or an empty string if no task failed
+func (c *taskResultsCollector) failuresDescription() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	descriptions := make([]string, 0, len(c.failures))
+	for _, f := range c.failures {
+		d := fmt.Sprintf("task %q %s on host %q", f.Task, f.Status, f.Host)
+		if f.Msg != "" {
+			d += ": " + f.Msg
+		}
+		descriptions = append(descriptions, d)
+	}
+	return strings.Join(descriptions, "; ")
+}
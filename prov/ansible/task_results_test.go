@@ -0,0 +1,73 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func testTaskResultsCollector(t *testing.T) {
+	file, err := os.Open("testdata/task_results.jsonl")
+	require.NoError(t, err)
+	defer file.Close()
+
+	deploymentID := testutil.BuildDeploymentID(t)
+	hosts := map[string]*hostConnection{
+		"Instance_0": {host: "10.0.0.132", instanceID: "0"},
+		"Instance_1": {host: "10.0.0.133", instanceID: "1"},
+	}
+	ctx := events.NewContext(context.Background(), events.LogOptionalFields{events.NodeID: "node"})
+	c := &taskResultsCollector{ctx: ctx, deploymentID: deploymentID, nodeName: "node", hosts: hosts}
+	c.read(file)
+
+	assert.Equal(t, `task "Start component" failed on host "10.0.0.133": non-zero return code: port already in use`,
+		c.failuresDescription())
+
+	evts, _, err := events.StatusEvents(ctx, deploymentID, 0, 5*time.Second)
+	require.NoError(t, err)
+	require.Len(t, evts, 3)
+	var evt map[string]string
+	require.NoError(t, json.Unmarshal(evts[1], &evt))
+	assert.Equal(t, events.StatusChangeTypeAnsibleTask.String(), evt[events.EType.String()])
+	assert.Equal(t, "node", evt[events.ENodeID.String()])
+	assert.Equal(t, "1", evt[events.EInstanceID.String()])
+	assert.Equal(t, "10.0.0.133", evt[events.EHostName.String()])
+	assert.Equal(t, "Set component facts", evt[events.EAnsibleTaskName.String()])
+	assert.Equal(t, "ok", evt[events.EStatus.String()])
+	assert.Equal(t, "10ms", evt[events.EDuration.String()])
+	assert.Equal(t, `{"component_port":8080}`, evt[events.EFacts.String()])
+
+	logs, _, err := events.LogsEvents(ctx, deploymentID, 0, 5*time.Second)
+	require.NoError(t, err)
+	logMap, err := getLogMap(logs, map[string]string{"level": "ERROR", "host": "10.0.0.133"}, "Start component")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", logMap[events.AnsibleTaskStatus.String()])
+	assert.Equal(t, "1.5s", logMap[events.Duration.String()])
+
+	logMap, err = getLogMap(logs, map[string]string{"level": "INFO", "host": "10.0.0.133"}, "Configure component")
+	require.NoError(t, err)
+	assert.Equal(t, "1", logMap[events.InstanceID.String()])
+	assert.Contains(t, logMap["content"], "+port=8080")
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// Topology template metadata selecting the Ansible virtualenv of a deployment
+const (
+	topologyMetadataAnsibleVersion         = "yorc.ansible.version"
+	topologyMetadataAnsiblePipRequirements = "yorc.ansible.pip_requirements"
+)
+
+// Location properties selecting the Ansible virtualenv of nodes hosted on the location
+const (
+	locationPropertyAnsibleVersion         = "ansible_version"
+	locationPropertyAnsiblePipRequirements = "ansible_pip_requirements"
+)
+
+// virtualenvCompleteMarker is the file created once a virtualenv is fully installed
+const virtualenvCompleteMarker = ".yorc-complete"
+
+// virtualenvLocks serialize the installation of a given virtualenv
+var virtualenvLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+// virtualenvSpec defines the Ansible version and the Python packages installed in a virtualenv
+type virtualenvSpec struct {
+	ansibleVersion  string
+	pipRequirements []string
+}
+
+func virtualenvSpecFromMetadata(version, requirements string) virtualenvSpec {
+	spec := virtualenvSpec{ansibleVersion: strings.TrimSpace(version)}
+	for _, req := range strings.Split(requirements, ",") {
+		if req = strings.TrimSpace(req); req != "" {
+			spec.pipRequirements = append(spec.pipRequirements, req)
+		}
+	}
+	return spec
+}
+
+func virtualenvSpecFromLocation(props config.DynamicMap) virtualenvSpec {
+	spec := virtualenvSpec{ansibleVersion: strings.TrimSpace(props.GetString(locationPropertyAnsibleVersion))}
+	for _, req := range props.GetStringSlice(locationPropertyAnsiblePipRequirements) {
+		if req = strings.TrimSpace(req); req != "" {
+			spec.pipRequirements = append(spec.pipRequirements, req)
+		}
+	}
+	return spec
+}
+
+func (s virtualenvSpec) isEmpty() bool {
+	return s.ansibleVersion == "" && len(s.pipRequirements) == 0
+}
+
+// packages returns the pip packages to install, an Ansible version without
+// comparison operator being an exact version
+func (s virtualenvSpec) packages() []string {
+	ansible := "ansible"
+	if s.ansibleVersion != "" {
+		if strings.ContainsAny(s.ansibleVersion[:1], "<>=!~") {
+			ansible += s.ansibleVersion
+		} else {
+			ansible += "==" + s.ansibleVersion
+		}
+	}
+	reqs := make([]string, len(s.pipRequirements))
+	copy(reqs, s.pipRequirements)
+	sort.Strings(reqs)
+	return append([]string{ansible}, reqs...)
+}
+
+// hash identifies virtualenvs installing the same packages
+func (s virtualenvSpec) hash() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(s.packages(), "\n"))))
+}
+
+// getVirtualenvSpec returns the virtualenv defined for the deployment by its topology metadata
+// or, if not defined, by the location of the hosts of the node
+func (e *executionCommon) getVirtualenvSpec(ctx context.Context) (virtualenvSpec, error) {
+	_, version, err := deployments.GetTopologyMetadata(ctx, e.deploymentID, topologyMetadataAnsibleVersion)
+	if err != nil {
+		return virtualenvSpec{}, err
+	}
+	_, requirements, err := deployments.GetTopologyMetadata(ctx, e.deploymentID, topologyMetadataAnsiblePipRequirements)
+	if err != nil {
+		return virtualenvSpec{}, err
+	}
+	spec := virtualenvSpecFromMetadata(version, requirements)
+	if !spec.isEmpty() || e.isOrchestratorOperation {
+		return spec, nil
+	}
+
+	locationName, err := e.getHostsLocationName(ctx)
+	if err != nil || locationName == "" {
+		return spec, err
+	}
+	locationMgr, err := locations.GetManager(e.cfg)
+	if err != nil {
+		return spec, err
+	}
+	locs, err := locationMgr.GetLocations()
+	if err != nil {
+		return spec, err
+	}
+	for _, loc := range locs {
+		if loc.Name == locationName {
+			return virtualenvSpecFromLocation(loc.Properties), nil
+		}
+	}
+	return spec, nil
+}
+
+// getHostsLocationName returns the location defined in the metadata of the node or of the nodes hosting it
+func (e *executionCommon) getHostsLocationName(ctx context.Context) (string, error) {
+	nodeName := e.NodeName
+	for nodeName != "" {
+		found, locationName, err := deployments.GetNodeMetadata(ctx, e.deploymentID, nodeName, tosca.MetadataLocationNameKey)
+		if err != nil || found {
+			return locationName, err
+		}
+		nodeName, err = deployments.GetHostedOnNode(ctx, e.deploymentID, nodeName)
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// setupVirtualenv installs, if not already done, the virtualenv selected for the operation
+// and uses it to run Ansible.
+//
+// Virtualenvs are shared by deployments using the same specification and are installed in place,
+// as they can't be moved once created.
+func (e *executionCommon) setupVirtualenv(ctx context.Context) error {
+	spec, err := e.getVirtualenvSpec(ctx)
+	if err != nil || spec.isEmpty() {
+		return err
+	}
+	virtualenvsRoot, err := filepath.Abs(filepath.Join(e.cfg.WorkingDirectory, "ansible", "virtualenvs"))
+	if err != nil {
+		return err
+	}
+	hash := spec.hash()
+	virtualenv := filepath.Join(virtualenvsRoot, hash)
+
+	virtualenvLocks.Lock()
+	lock, ok := virtualenvLocks.locks[hash]
+	if !ok {
+		lock = new(sync.Mutex)
+		virtualenvLocks.locks[hash] = lock
+	}
+	virtualenvLocks.Unlock()
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err = os.Stat(filepath.Join(virtualenv, virtualenvCompleteMarker)); err == nil {
+		log.Debugf("Using Ansible virtualenv %q", virtualenv)
+		e.virtualenv = virtualenv
+		return nil
+	}
+
+	// Removing a partially installed virtualenv
+	if err = os.RemoveAll(virtualenv); err != nil {
+		return errors.Wrapf(err, "failed to remove directory %q", virtualenv)
+	}
+	if err = os.MkdirAll(virtualenvsRoot, 0775); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", virtualenvsRoot)
+	}
+	packages := spec.packages()
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf(
+		"Installing an Ansible virtualenv with packages %s", strings.Join(packages, " "))
+	if err = e.runVirtualenvCommand(ctx, "python3", "-m", "venv", virtualenv); err != nil {
+		return errors.Wrap(err, "failed to create Ansible virtualenv")
+	}
+	if err = e.runVirtualenvCommand(ctx, filepath.Join(virtualenv, "bin", "pip"), append([]string{"install"}, packages...)...); err != nil {
+		return errors.Wrapf(err, "failed to install packages %s in Ansible virtualenv", strings.Join(packages, " "))
+	}
+	err = ioutil.WriteFile(filepath.Join(virtualenv, virtualenvCompleteMarker), []byte(strings.Join(packages, "\n")+"\n"), 0664)
+	if err != nil {
+		return errors.Wrap(err, "failed to write Ansible virtualenv marker file")
+	}
+	e.virtualenv = virtualenv
+	return nil
+}
+
+func (e *executionCommon) runVirtualenvCommand(ctx context.Context, name string, args ...string) error {
+	cmd := executil.Command(ctx, name, args...)
+	outBuf := events.NewBufferedLogEntryWriter()
+	cmd.Stdout = outBuf
+	cmd.Stderr = outBuf
+	quit := make(chan bool)
+	defer close(quit)
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).RunBufferedRegistration(outBuf, quit)
+	return cmd.Run()
+}
+
+// ansibleCommand returns the path of an Ansible command, within the virtualenv if one is used
+func (e *executionCommon) ansibleCommand(name string) string {
+	if e.virtualenv == "" {
+		return name
+	}
+	return filepath.Join(e.virtualenv, "bin", name)
+}
+
+// virtualenvEnv returns the environment variables activating the virtualenv if one is used
+func (e *executionCommon) virtualenvEnv() []string {
+	if e.virtualenv == "" {
+		return nil
+	}
+	return []string{
+		"VIRTUAL_ENV=" + e.virtualenv,
+		"PATH=" + filepath.Join(e.virtualenv, "bin") + string(os.PathListSeparator) + os.Getenv("PATH"),
+	}
+}
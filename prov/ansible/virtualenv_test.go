@@ -0,0 +1,63 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestVirtualenvSpec(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		spec         virtualenvSpec
+		wantEmpty    bool
+		wantPackages []string
+	}{
+		{"Empty", virtualenvSpecFromMetadata("", " , "), true, []string{"ansible"}},
+		{"ExactVersion", virtualenvSpecFromMetadata("2.9.27", ""), false, []string{"ansible==2.9.27"}},
+		{"VersionConstraint", virtualenvSpecFromMetadata(">=2.10,<2.11", "netaddr, jmespath==0.10.0"), false, []string{"ansible>=2.10,<2.11", "jmespath==0.10.0", "netaddr"}},
+		{"RequirementsOnly", virtualenvSpecFromLocation(config.DynamicMap{"ansible_pip_requirements": []string{"pywinrm", "boto3"}}), false, []string{"ansible", "boto3", "pywinrm"}},
+		{"LocationVersion", virtualenvSpecFromLocation(config.DynamicMap{"ansible_version": "2.9.27"}), false, []string{"ansible==2.9.27"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantEmpty, tt.spec.isEmpty())
+			assert.Equal(t, tt.wantPackages, tt.spec.packages())
+		})
+	}
+
+	// Specs installing the same packages share the same virtualenv
+	assert.Equal(t, virtualenvSpecFromMetadata("2.9.27", "b,a").hash(), virtualenvSpecFromMetadata("2.9.27", "a, b").hash())
+	assert.NotEqual(t, virtualenvSpecFromMetadata("2.9.27", "a").hash(), virtualenvSpecFromMetadata("2.9.26", "a").hash())
+}
+
+func TestVirtualenvCommands(t *testing.T) {
+	t.Parallel()
+	e := &executionCommon{}
+	assert.Equal(t, "ansible-playbook", e.ansibleCommand("ansible-playbook"))
+	assert.Nil(t, e.virtualenvEnv())
+
+	e.virtualenv = "/var/yorc/ansible/virtualenvs/abc"
+	assert.Equal(t, "/var/yorc/ansible/virtualenvs/abc/bin/ansible-playbook", e.ansibleCommand("ansible-playbook"))
+	env := e.virtualenvEnv()
+	assert.Len(t, env, 2)
+	assert.Equal(t, "VIRTUAL_ENV=/var/yorc/ansible/virtualenvs/abc", env[0])
+	assert.Contains(t, env[1], "PATH=/var/yorc/ansible/virtualenvs/abc/bin:")
+}
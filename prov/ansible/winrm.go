@@ -0,0 +1,158 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/tosca/types"
+)
+
+const (
+	winRMProtocol = "winrm"
+	// defaultWinRMPort is the WinRM HTTPS listener port, used when the endpoint
+	// port is not set or is left to the SSH default one
+	defaultWinRMPort = 5986
+)
+
+// WinRM transports supported for Windows hosts, selected by the token_type of credentials
+const (
+	winRMTransportNTLM        = "ntlm"
+	winRMTransportKerberos    = "kerberos"
+	winRMTransportCertificate = "certificate"
+)
+
+// winRMConnection holds the settings of a connection to a Windows host through WinRM
+type winRMConnection struct {
+	transport string
+	// certificate and key are either a path or the PEM content of the client
+	// certificate and its private key, used by the certificate transport
+	certificate string
+	key         string
+	// certificatePath and keyPath are the files actually given to Ansible
+	certificatePath string
+	keyPath         string
+}
+
+func isWinRMProtocol(protocol string) bool {
+	return strings.ToLower(protocol) == winRMProtocol
+}
+
+// setWinRMConnection sets a WinRM connection on a host according to its credentials.
+//
+// The token_type of credentials selects the transport: "password" or "ntlm" (the default)
+// and "kerberos" use the token as password, while "certificate" uses the token as the
+// client certificate and the first key of credentials as its private key.
+func setWinRMConnection(conn *hostConnection, credentials *types.Credential) error {
+	winRM := &winRMConnection{}
+	switch strings.ToLower(credentials.TokenType) {
+	case "", "password", winRMTransportNTLM:
+		winRM.transport = winRMTransportNTLM
+	case winRMTransportKerberos:
+		winRM.transport = winRMTransportKerberos
+	case winRMTransportCertificate:
+		winRM.transport = winRMTransportCertificate
+		if conn.password == "" {
+			return errors.New("a client certificate is required as token for the WinRM certificate authentication")
+		}
+		winRM.certificate = conn.password
+		conn.password = ""
+		keyNames := make([]string, 0, len(credentials.Keys))
+		for name, key := range credentials.Keys {
+			if key != "" {
+				keyNames = append(keyNames, name)
+			}
+		}
+		if len(keyNames) == 0 {
+			return errors.New("a client private key is required for the WinRM certificate authentication")
+		}
+		sort.Strings(keyNames)
+		winRM.key = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.key", credentials.Keys[keyNames[0]]).(string)
+	default:
+		return errors.Errorf("unsupported token type %q for the WinRM protocol, expecting one of %q, %q or %q",
+			credentials.TokenType, winRMTransportNTLM, winRMTransportKerberos, winRMTransportCertificate)
+	}
+	conn.connectionType = winRMProtocol
+	conn.winRM = winRM
+	return nil
+}
+
+// writeCertificateFiles writes in the recipe directory the client certificate and key
+// provided as content, those provided as a path are used as is
+func (w *winRMConnection) writeCertificateFiles(ansibleRecipePath, instanceName string) error {
+	if w.transport != winRMTransportCertificate {
+		return nil
+	}
+	var err error
+	w.certificatePath, err = pemFile(w.certificate, filepath.Join(ansibleRecipePath, instanceName+"-winrm-cert.pem"))
+	if err != nil {
+		return err
+	}
+	w.keyPath, err = pemFile(w.key, filepath.Join(ansibleRecipePath, instanceName+"-winrm-key.pem"))
+	return err
+}
+
+func pemFile(value, fileName string) (string, error) {
+	if !strings.Contains(value, "-----BEGIN") {
+		return value, nil
+	}
+	err := ioutil.WriteFile(fileName, []byte(value), 0600)
+	return fileName, errors.Wrapf(err, "failed to write file %q", fileName)
+}
+
+// resolveWindowsHosts checks whether operations target Windows hosts, in which case
+// internal plays use Windows modules
+func (e *executionCommon) resolveWindowsHosts(nodeName string) error {
+	var nbWindowsHosts int
+	for _, host := range e.hosts {
+		if host.winRM != nil {
+			nbWindowsHosts++
+		}
+	}
+	if nbWindowsHosts != 0 && nbWindowsHosts != len(e.hosts) {
+		return errors.Errorf("hosts of node %q mix Windows hosts reached through WinRM with other hosts, this is not supported", nodeName)
+	}
+	e.Windows = nbWindowsHosts != 0
+	return nil
+}
+
+func (e *executionCommon) generateWinRMHostConnection(buffer *bytes.Buffer, host *hostConnection) {
+	buffer.WriteString(" ansible_connection=winrm")
+	if host.user != "" {
+		buffer.WriteString(fmt.Sprintf(" ansible_user=%s", host.user))
+	}
+	port := host.port
+	if port == 0 || port == 22 {
+		port = defaultWinRMPort
+	}
+	buffer.WriteString(fmt.Sprintf(" ansible_port=%d", port))
+	buffer.WriteString(fmt.Sprintf(" ansible_winrm_transport=%s", host.winRM.transport))
+	// As for SSH host keys, certificates of hosts are not checked
+	buffer.WriteString(" ansible_winrm_server_cert_validation=ignore")
+	if host.winRM.transport == winRMTransportCertificate {
+		buffer.WriteString(fmt.Sprintf(" ansible_winrm_cert_pem=%s ansible_winrm_cert_key_pem=%s", host.winRM.certificatePath, host.winRM.keyPath))
+	} else if host.password != "" {
+		// TODO use ansible vault
+		buffer.WriteString(fmt.Sprintf(" ansible_password=%s", host.password))
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+// The aim of this function is to run all package tests with consul server dependency with only one consul server start
+func TestRunConsulDockerPackageTests(t *testing.T) {
+	cfg := testutil.SetupTestConfig(t)
+	srv, _ := testutil.NewTestConsulInstance(t, &cfg)
+	defer func() {
+		srv.Stop()
+		os.RemoveAll(cfg.WorkingDirectory)
+	}()
+
+	t.Run("dockerProvider", func(t *testing.T) {
+		t.Run("createContainer", func(t *testing.T) {
+			testCreateContainer(t, cfg)
+		})
+		t.Run("createContainerWithSSH", func(t *testing.T) {
+			testCreateContainerWithSSH(t, cfg)
+		})
+		t.Run("removeContainer", func(t *testing.T) {
+			testRemoveContainer(t, cfg)
+		})
+		t.Run("createNetwork", func(t *testing.T) {
+			testCreateNetwork(t, cfg)
+		})
+		t.Run("createVolume", func(t *testing.T) {
+			testCreateVolume(t, cfg)
+		})
+	})
+}
+
+func loadTestYaml(t *testing.T) string {
+	deploymentID := path.Base(t.Name())
+	yamlName := "testdata/" + deploymentID + ".yaml"
+	err := deployments.StoreDeploymentDefinition(context.Background(), deploymentID, yamlName)
+	require.NoError(t, err, "Failed to parse "+yamlName+" definition")
+	return deploymentID
+}
+
+// mockEngine is a fake container engine API recording the requests it receives
+type mockEngine struct {
+	lock      sync.Mutex
+	requests  []string
+	bodies    map[string][]byte
+	responses map[string]*mockResponse
+}
+
+type mockResponse struct {
+	status int
+	body   string
+}
+
+func newMockEngine() *mockEngine {
+	return &mockEngine{
+		bodies:    make(map[string][]byte),
+		responses: make(map[string]*mockResponse),
+	}
+}
+
+// on defines the response to requests which method and path suffix (without the API version) match
+func (m *mockEngine) on(method, pathSuffix string, status int, body string) {
+	m.responses[method+" "+pathSuffix] = &mockResponse{status: status, body: body}
+}
+
+func (m *mockEngine) do(r *http.Request) (*http.Response, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	p := r.URL.Path
+	if i := strings.Index(p, "/v1."); i >= 0 {
+		p = p[i:]
+		p = p[strings.Index(p[1:], "/")+1:]
+	}
+	key := r.Method + " " + p
+	m.requests = append(m.requests, key)
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		m.bodies[key] = b
+	}
+	resp, ok := m.responses[key]
+	if !ok {
+		resp = &mockResponse{status: http.StatusNotFound, body: `{"message":"not found"}`}
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(resp.body))),
+	}, nil
+}
+
+func (m *mockEngine) newOperationParameters(t *testing.T, cfg config.Configuration, deploymentID, nodeName string, locationProps config.DynamicMap) operationParameters {
+	hc := &http.Client{
+		Transport: &http.Transport{},
+	}
+	cli, err := client.NewClient("tcp://somewhere:42/api", "1.25", hc, nil)
+	require.NoError(t, err)
+	hc.Transport = testutil.NewMockClient(m.do).Transport
+	return operationParameters{
+		cfg:           cfg,
+		cli:           cli,
+		locationProps: locationProps,
+		deploymentID:  deploymentID,
+		nodeName:      nodeName,
+	}
+}
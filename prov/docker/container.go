@@ -0,0 +1,271 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+)
+
+const (
+	pullAlways       = "Always"
+	pullIfNotPresent = "IfNotPresent"
+	pullNever        = "Never"
+)
+
+const (
+	connectionTypeExec = "exec"
+	connectionTypeSSH  = "ssh"
+)
+
+func createContainer(ctx context.Context, op operationParameters, instanceName string) error {
+	image, err := deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "image", true)
+	if err != nil {
+		return err
+	}
+	err = pullImage(ctx, op, image)
+	if err != nil {
+		return err
+	}
+
+	cc := &container.Config{
+		Image:  image,
+		Labels: getLabels(op.deploymentID, op.nodeName),
+	}
+	command, err := getStringListProperty(ctx, op.deploymentID, op.nodeName, "command")
+	if err != nil {
+		return err
+	}
+	if len(command) > 0 {
+		cc.Cmd = strslice.StrSlice(command)
+	}
+	entrypoint, err := getStringListProperty(ctx, op.deploymentID, op.nodeName, "entrypoint")
+	if err != nil {
+		return err
+	}
+	if len(entrypoint) > 0 {
+		cc.Entrypoint = strslice.StrSlice(entrypoint)
+	}
+	env, err := getStringMapProperty(ctx, op.deploymentID, op.nodeName, "env")
+	if err != nil {
+		return err
+	}
+	for k, v := range env {
+		cc.Env = append(cc.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	// Keep a stable order
+	sort.Strings(cc.Env)
+
+	hc := &container.HostConfig{}
+	hc.Privileged, err = deployments.GetBooleanNodeProperty(ctx, op.deploymentID, op.nodeName, "privileged")
+	if err != nil {
+		return err
+	}
+	hc.Binds, err = getVolumeBinds(ctx, op, instanceName)
+	if err != nil {
+		return err
+	}
+
+	networks, err := getNetworks(ctx, op)
+	if err != nil {
+		return err
+	}
+	var nc *network.NetworkingConfig
+	if len(networks) > 0 {
+		hc.NetworkMode = container.NetworkMode(networks[0])
+		nc = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				networks[0]: {},
+			},
+		}
+	}
+
+	name := getResourceName(op.cfg, op.deploymentID, op.nodeName, instanceName)
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Creating container %q from image %q", name, image)
+	createResp, err := op.cli.ContainerCreate(ctx, cc, hc, nc, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create container %q", name)
+	}
+	err = deployments.SetInstanceAttribute(ctx, op.deploymentID, op.nodeName, instanceName, "container_id", createResp.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i < len(networks); i++ {
+		err = op.cli.NetworkConnect(ctx, networks[i], createResp.ID, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect container %q to network %q", name, networks[i])
+		}
+	}
+
+	err = op.cli.ContainerStart(ctx, createResp.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to start container %q", name)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Container %q started with id %q", name, createResp.ID)
+
+	inspect, err := op.cli.ContainerInspect(ctx, createResp.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to inspect container %q", name)
+	}
+	return setContainerAttributes(ctx, op, instanceName, inspect, networks)
+}
+
+func pullImage(ctx context.Context, op operationParameters, image string) error {
+	pullPolicy, err := deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "pull_policy", false)
+	if err != nil {
+		return err
+	}
+	switch pullPolicy {
+	case pullNever:
+		return nil
+	case "", pullIfNotPresent:
+		_, _, err = op.cli.ImageInspectWithRaw(ctx, image)
+		if err == nil {
+			return nil
+		}
+		if !client.IsErrImageNotFound(err) {
+			return errors.Wrapf(err, "failed to inspect image %q", image)
+		}
+	case pullAlways:
+	default:
+		return errors.Errorf("unsupported pull policy %q, expecting one of %q, %q or %q", pullPolicy, pullAlways, pullIfNotPresent, pullNever)
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Pulling image: %s", image)
+	pullResp, err := op.cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	if pullResp != nil {
+		ioutil.ReadAll(pullResp)
+		pullResp.Close()
+	}
+	return errors.Wrapf(err, "failed to pull image %q", image)
+}
+
+// getNetworks returns the names of networks the container should be connected to
+// the first one being the primary network
+func getNetworks(ctx context.Context, op operationParameters) ([]string, error) {
+	networkReqs, err := deployments.GetRequirementsByTypeForNode(ctx, op.deploymentID, op.nodeName, "network")
+	if err != nil {
+		return nil, err
+	}
+	var networks []string
+	for _, networkReq := range networkReqs {
+		networkName, err := deployments.LookupInstanceAttributeValue(ctx, op.deploymentID, networkReq.Node, "0", "network_name")
+		if err != nil {
+			return nil, err
+		}
+		if networkName == "" {
+			return nil, errors.Errorf("network %q is not created yet", networkReq.Node)
+		}
+		networks = append(networks, networkName)
+	}
+	if len(networks) == 0 {
+		if defaultNetwork := op.locationProps.GetString("network"); defaultNetwork != "" {
+			networks = append(networks, defaultNetwork)
+		}
+	}
+	return networks, nil
+}
+
+func getVolumeBinds(ctx context.Context, op operationParameters, instanceName string) ([]string, error) {
+	storageReqs, err := deployments.GetRequirementsByTypeForNode(ctx, op.deploymentID, op.nodeName, "local_storage")
+	if err != nil {
+		return nil, err
+	}
+	var binds []string
+	for _, storageReq := range storageReqs {
+		volumeName, err := deployments.LookupInstanceAttributeValue(ctx, op.deploymentID, storageReq.Node, instanceName, "volume_id")
+		if err != nil {
+			return nil, err
+		}
+		mountPath, err := deployments.GetRelationshipPropertyValueFromRequirement(ctx, op.deploymentID, op.nodeName, storageReq.Index, "location")
+		if err != nil {
+			return nil, err
+		}
+		if volumeName == "" || mountPath == nil || mountPath.RawString() == "" {
+			return nil, errors.Errorf("missing volume id or mount location for storage %q", storageReq.Node)
+		}
+		binds = append(binds, fmt.Sprintf("%s:%s", volumeName, mountPath.RawString()))
+	}
+	return binds, nil
+}
+
+func setContainerAttributes(ctx context.Context, op operationParameters, instanceName string, inspect types.ContainerJSON, networks []string) error {
+	var ipAddress string
+	if inspect.NetworkSettings != nil {
+		ipAddress = inspect.NetworkSettings.IPAddress
+		if len(networks) > 0 {
+			if endpoint, ok := inspect.NetworkSettings.Networks[networks[0]]; ok && endpoint != nil {
+				ipAddress = endpoint.IPAddress
+			}
+		}
+	}
+
+	connectionType, err := deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "connection_type", false)
+	if err != nil {
+		return err
+	}
+	switch connectionType {
+	case "", connectionTypeExec:
+		if connectionType, err = getEngine(op.locationProps); err != nil {
+			return err
+		}
+	case connectionTypeSSH:
+	default:
+		return errors.Errorf("unsupported connection type %q, expecting %q or %q", connectionType, connectionTypeExec, connectionTypeSSH)
+	}
+
+	attrs := map[string]string{
+		"ip_address":      ipAddress,
+		"private_address": ipAddress,
+		"connection_type": connectionType,
+		"docker_host":     op.locationProps.GetString("host"),
+	}
+	for k, v := range attrs {
+		err = deployments.SetInstanceAttribute(ctx, op.deploymentID, op.nodeName, instanceName, k, v)
+		if err != nil {
+			return err
+		}
+	}
+	return deployments.SetInstanceCapabilityAttribute(ctx, op.deploymentID, op.nodeName, instanceName, "endpoint", "ip_address", ipAddress)
+}
+
+func removeContainer(ctx context.Context, op operationParameters, instanceName string) error {
+	containerID, err := deployments.GetInstanceAttributeValue(ctx, op.deploymentID, op.nodeName, instanceName, "container_id")
+	if err != nil || containerID == nil || containerID.RawString() == "" {
+		return err
+	}
+	// Remove errors don't tell if the container was not found, so check it first
+	_, err = op.cli.ContainerInspect(ctx, containerID.RawString())
+	if client.IsErrContainerNotFound(err) {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, op.deploymentID).Registerf("Container %q already removed", containerID.RawString())
+		return nil
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Removing container %q", containerID.RawString())
+	err = op.cli.ContainerRemove(ctx, containerID.RawString(), types.ContainerRemoveOptions{Force: true})
+	return errors.Wrapf(err, "failed to remove container %q", containerID.RawString())
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+const containerInspectResponse = `{
+  "Id": "c0ffee",
+  "NetworkSettings": {
+    "IPAddress": "",
+    "Networks": {
+      "backend": {"IPAddress": "10.10.0.3"},
+      "front": {"IPAddress": "172.28.0.2"}
+    }
+  }
+}`
+
+func assertInstanceAttribute(t *testing.T, deploymentID, nodeName, instanceName, attribute, expected string) {
+	t.Helper()
+	value, err := deployments.GetInstanceAttributeValue(context.Background(), deploymentID, nodeName, instanceName, attribute)
+	require.NoError(t, err)
+	require.NotNil(t, value, "attribute %s not set", attribute)
+	assert.Equal(t, expected, value.RawString(), "unexpected value for attribute %s", attribute)
+}
+
+func testCreateContainer(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	ctx := context.Background()
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "FrontNet", "0", "network_name", "front"))
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "BackNet", "0", "network_name", "backend"))
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "Data", "0", "volume_id", "data-vol"))
+
+	engine := newMockEngine()
+	engine.on(http.MethodPost, "/images/create", http.StatusOK, "{}")
+	engine.on(http.MethodPost, "/containers/create", http.StatusCreated, `{"Id": "c0ffee"}`)
+	engine.on(http.MethodPost, "/networks/backend/connect", http.StatusOK, "")
+	engine.on(http.MethodPost, "/containers/c0ffee/start", http.StatusNoContent, "")
+	engine.on(http.MethodGet, "/containers/c0ffee/json", http.StatusOK, containerInspectResponse)
+
+	op := engine.newOperationParameters(t, cfg, deploymentID, "Container", config.DynamicMap{"host": "tcp://somewhere:42/api"})
+	err := createContainer(ctx, op, "0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"GET /images/python:3.7-slim/json",
+		"POST /images/create",
+		"POST /containers/create",
+		"POST /networks/backend/connect",
+		"POST /containers/c0ffee/start",
+		"GET /containers/c0ffee/json",
+	}, engine.requests)
+
+	var body struct {
+		container.Config
+		HostConfig       *container.HostConfig
+		NetworkingConfig *network.NetworkingConfig
+	}
+	require.NoError(t, json.Unmarshal(engine.bodies["POST /containers/create"], &body))
+	assert.Equal(t, "python:3.7-slim", body.Image)
+	assert.Equal(t, []string{"sleep", "infinity"}, []string(body.Cmd))
+	assert.Equal(t, []string{"APP_ENV=test", "LANG=C.UTF-8"}, body.Env)
+	assert.Equal(t, map[string]string{labelDeploymentID: deploymentID, labelNodeName: "Container"}, body.Labels)
+	require.NotNil(t, body.HostConfig)
+	assert.True(t, body.HostConfig.Privileged)
+	assert.Equal(t, []string{"data-vol:/data"}, body.HostConfig.Binds)
+	assert.Equal(t, container.NetworkMode("front"), body.HostConfig.NetworkMode)
+	require.NotNil(t, body.NetworkingConfig)
+	assert.Contains(t, body.NetworkingConfig.EndpointsConfig, "front")
+
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "container_id", "c0ffee")
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "ip_address", "172.28.0.2")
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "private_address", "172.28.0.2")
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "connection_type", engineDocker)
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "docker_host", "tcp://somewhere:42/api")
+	ipAddress, err := deployments.GetInstanceCapabilityAttributeValue(ctx, deploymentID, "Container", "0", "endpoint", "ip_address")
+	require.NoError(t, err)
+	require.NotNil(t, ipAddress)
+	assert.Equal(t, "172.28.0.2", ipAddress.RawString())
+}
+
+func testCreateContainerWithSSH(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	engine := newMockEngine()
+	engine.on(http.MethodPost, "/containers/create", http.StatusCreated, `{"Id": "c0ffee"}`)
+	engine.on(http.MethodPost, "/containers/c0ffee/start", http.StatusNoContent, "")
+	engine.on(http.MethodGet, "/containers/c0ffee/json", http.StatusOK, `{"Id": "c0ffee", "NetworkSettings": {"IPAddress": "172.17.0.5"}}`)
+
+	op := engine.newOperationParameters(t, cfg, deploymentID, "Container", config.DynamicMap{"engine": enginePodman})
+	err := createContainer(context.Background(), op, "0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /containers/create",
+		"POST /containers/c0ffee/start",
+		"GET /containers/c0ffee/json",
+	}, engine.requests, "image should not be pulled with the Never pull policy")
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "ip_address", "172.17.0.5")
+	assertInstanceAttribute(t, deploymentID, "Container", "0", "connection_type", connectionTypeSSH)
+}
+
+func testRemoveContainer(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	ctx := context.Background()
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "Container", "0", "container_id", "c0ffee"))
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "Container", "1", "container_id", "deadbeef"))
+
+	engine := newMockEngine()
+	engine.on(http.MethodGet, "/containers/c0ffee/json", http.StatusOK, `{"Id": "c0ffee"}`)
+	engine.on(http.MethodDelete, "/containers/c0ffee", http.StatusNoContent, "")
+	op := engine.newOperationParameters(t, cfg, deploymentID, "Container", config.DynamicMap{})
+
+	err := removeContainer(ctx, op, "0")
+	require.NoError(t, err)
+	err = removeContainer(ctx, op, "1")
+	require.NoError(t, err, "removing an already removed container should not fail")
+
+	assert.Equal(t, []string{
+		"GET /containers/c0ffee/json",
+		"DELETE /containers/c0ffee",
+		"GET /containers/deadbeef/json",
+	}, engine.requests)
+}
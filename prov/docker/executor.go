@@ -0,0 +1,117 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const infrastructureType = "docker"
+
+type defaultExecutor struct {
+}
+
+// operationParameters holds what is needed to manage the instances of a node
+type operationParameters struct {
+	cfg           config.Configuration
+	cli           *client.Client
+	locationProps config.DynamicMap
+	deploymentID  string
+	nodeName      string
+}
+
+type instanceFunc func(ctx context.Context, op operationParameters, instanceName string) error
+
+func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	instances, err := tasks.GetInstances(ctx, taskID, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	var locationProps config.DynamicMap
+	locationMgr, err := locations.GetManager(cfg)
+	if err == nil {
+		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
+	}
+	if err != nil {
+		return err
+	}
+
+	cli, err := getClient(locationProps)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	var create, remove instanceFunc
+	switch nodeType {
+	case "yorc.nodes.docker.Container":
+		create, remove = createContainer, removeContainer
+	case "yorc.nodes.docker.Network":
+		create, remove = createNetwork, removeNetwork
+	case "yorc.nodes.docker.Volume":
+		create, remove = createVolume, removeVolume
+	default:
+		return errors.Errorf("Unsupported node type '%s' for node '%s' in deployment '%s'", nodeType, nodeName, deploymentID)
+	}
+
+	op := operationParameters{
+		cfg:           cfg,
+		cli:           cli,
+		locationProps: locationProps,
+		deploymentID:  deploymentID,
+		nodeName:      nodeName,
+	}
+	switch strings.ToLower(delegateOperation) {
+	case "install":
+		return execOnInstances(ctx, op, instances, create, tosca.NodeStateCreating, tosca.NodeStateStarted)
+	case "uninstall":
+		return execOnInstances(ctx, op, instances, remove, tosca.NodeStateDeleting, tosca.NodeStateDeleted)
+	}
+	return errors.Errorf("Unsupported operation %q", delegateOperation)
+}
+
+func execOnInstances(ctx context.Context, op operationParameters, instances []string, f instanceFunc, transitionalState, finalState tosca.NodeState) error {
+	for _, instance := range instances {
+		instanceCtx := events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instance})
+		err := deployments.SetInstanceStateWithContextualLogs(instanceCtx, op.deploymentID, op.nodeName, instance, transitionalState)
+		if err != nil {
+			return err
+		}
+		if err = f(instanceCtx, op, instance); err != nil {
+			return err
+		}
+		err = deployments.SetInstanceStateWithContextualLogs(instanceCtx, op.deploymentID, op.nodeName, instance, finalState)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
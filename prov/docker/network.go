@@ -0,0 +1,99 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+)
+
+func createNetwork(ctx context.Context, op operationParameters, instanceName string) error {
+	networkName, err := deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "network_name", false)
+	if err != nil {
+		return err
+	}
+
+	var networkID string
+	if networkName != "" {
+		// Use an existing network
+		resource, err := op.cli.NetworkInspect(ctx, networkName, false)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find network %q", networkName)
+		}
+		networkID = resource.ID
+	} else {
+		networkName = getResourceName(op.cfg, op.deploymentID, op.nodeName, instanceName)
+		options := types.NetworkCreate{
+			CheckDuplicate: true,
+			Labels:         getLabels(op.deploymentID, op.nodeName),
+		}
+		if options.Driver, err = deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "driver", false); err != nil {
+			return err
+		}
+		if options.Internal, err = deployments.GetBooleanNodeProperty(ctx, op.deploymentID, op.nodeName, "internal"); err != nil {
+			return err
+		}
+		ipamConfig := network.IPAMConfig{}
+		if ipamConfig.Subnet, err = deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "cidr", false); err != nil {
+			return err
+		}
+		if ipamConfig.Gateway, err = deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "gateway_ip", false); err != nil {
+			return err
+		}
+		if ipamConfig.Subnet != "" {
+			options.IPAM = &network.IPAM{Config: []network.IPAMConfig{ipamConfig}}
+		}
+
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Creating docker network %q", networkName)
+		resp, err := op.cli.NetworkCreate(ctx, networkName, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create network %q", networkName)
+		}
+		networkID = resp.ID
+	}
+
+	err = deployments.SetInstanceAttribute(ctx, op.deploymentID, op.nodeName, instanceName, "network_id", networkID)
+	if err != nil {
+		return err
+	}
+	return deployments.SetInstanceAttribute(ctx, op.deploymentID, op.nodeName, instanceName, "network_name", networkName)
+}
+
+func removeNetwork(ctx context.Context, op operationParameters, instanceName string) error {
+	existingName, err := deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "network_name", false)
+	if err != nil || existingName != "" {
+		// Existing networks are not removed
+		return err
+	}
+	networkID, err := deployments.GetInstanceAttributeValue(ctx, op.deploymentID, op.nodeName, instanceName, "network_id")
+	if err != nil || networkID == nil || networkID.RawString() == "" {
+		return err
+	}
+	_, err = op.cli.NetworkInspect(ctx, networkID.RawString(), false)
+	if client.IsErrNetworkNotFound(err) {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, op.deploymentID).Registerf("Docker network %q already removed", networkID.RawString())
+		return nil
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Removing docker network %q", networkID.RawString())
+	err = op.cli.NetworkRemove(ctx, networkID.RawString())
+	return errors.Wrapf(err, "failed to remove network %q", networkID.RawString())
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func testCreateNetwork(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	ctx := context.Background()
+
+	engine := newMockEngine()
+	engine.on(http.MethodPost, "/networks/create", http.StatusCreated, `{"Id": "n3tw0rk"}`)
+	engine.on(http.MethodGet, "/networks/backend", http.StatusOK, `{"Id": "b4ck3nd", "Name": "backend"}`)
+	engine.on(http.MethodGet, "/networks/n3tw0rk", http.StatusOK, `{"Id": "n3tw0rk"}`)
+	engine.on(http.MethodDelete, "/networks/n3tw0rk", http.StatusNoContent, "")
+
+	op := engine.newOperationParameters(t, cfg, deploymentID, "Network", config.DynamicMap{})
+	require.NoError(t, createNetwork(ctx, op, "0"))
+
+	var body types.NetworkCreateRequest
+	require.NoError(t, json.Unmarshal(engine.bodies["POST /networks/create"], &body))
+	networkName := getResourceName(cfg, deploymentID, "Network", "0")
+	assert.Equal(t, networkName, body.Name)
+	assert.Equal(t, "bridge", body.Driver)
+	require.NotNil(t, body.IPAM)
+	require.Len(t, body.IPAM.Config, 1)
+	assert.Equal(t, "172.28.0.0/16", body.IPAM.Config[0].Subnet)
+	assert.Equal(t, "172.28.0.1", body.IPAM.Config[0].Gateway)
+	assertInstanceAttribute(t, deploymentID, "Network", "0", "network_id", "n3tw0rk")
+	assertInstanceAttribute(t, deploymentID, "Network", "0", "network_name", networkName)
+
+	existingOp := engine.newOperationParameters(t, cfg, deploymentID, "ExistingNetwork", config.DynamicMap{})
+	require.NoError(t, createNetwork(ctx, existingOp, "0"))
+	assertInstanceAttribute(t, deploymentID, "ExistingNetwork", "0", "network_id", "b4ck3nd")
+	assertInstanceAttribute(t, deploymentID, "ExistingNetwork", "0", "network_name", "backend")
+
+	require.NoError(t, removeNetwork(ctx, op, "0"))
+	require.NoError(t, removeNetwork(ctx, existingOp, "0"))
+	assert.Equal(t, []string{
+		"POST /networks/create",
+		"GET /networks/backend",
+		"GET /networks/n3tw0rk",
+		"DELETE /networks/n3tw0rk",
+	}, engine.requests, "only created networks should be removed")
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+const (
+	engineDocker = "docker"
+	enginePodman = "podman"
+)
+
+// Labels set on resources created by Yorc
+const (
+	labelDeploymentID = "io.ystia.yorc.deployment"
+	labelNodeName     = "io.ystia.yorc.node"
+)
+
+// getClient returns a client of the Docker API endpoint defined by the host property of a location.
+// Podman exposes a compatible API, its socket can be used as host.
+// If no host is defined, the client is configured from the environment as the docker CLI is.
+func getClient(locationProps config.DynamicMap) (*client.Client, error) {
+	host := locationProps.GetString("host")
+	if host == "" {
+		cli, err := client.NewEnvClient()
+		return cli, errors.Wrap(err, "failed to create docker client from environment")
+	}
+	cli, err := client.NewClient(host, locationProps.GetString("api_version"), nil, nil)
+	return cli, errors.Wrapf(err, "failed to create docker client for host %q", host)
+}
+
+// getEngine returns the container engine of a location, which defines the Ansible connection plugin used to
+// execute operations in containers
+func getEngine(locationProps config.DynamicMap) (string, error) {
+	engine := locationProps.GetStringOrDefault("engine", engineDocker)
+	if engine != engineDocker && engine != enginePodman {
+		return "", errors.Errorf("unsupported container engine %q, expecting %q or %q", engine, engineDocker, enginePodman)
+	}
+	return engine, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// getResourceName returns the name of a container, network or volume created for the given node instance
+func getResourceName(cfg config.Configuration, deploymentID, nodeName, instanceName string) string {
+	b := sha1.Sum([]byte(deploymentID))
+	name := fmt.Sprintf("%s%x-%s-%s", cfg.ResourcesPrefix, b[0:3], nodeName, instanceName)
+	return invalidNameChars.ReplaceAllString(name, "-")
+}
+
+func getLabels(deploymentID, nodeName string) map[string]string {
+	return map[string]string{
+		labelDeploymentID: deploymentID,
+		labelNodeName:     nodeName,
+	}
+}
+
+// getStringMapProperty returns the values of a node property of type map of strings
+func getStringMapProperty(ctx context.Context, deploymentID, nodeName, propertyName string) (map[string]string, error) {
+	val, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, propertyName)
+	if err != nil || val == nil || val.RawString() == "" {
+		return nil, err
+	}
+	m, ok := val.Value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("failed to retrieve %s map from Tosca Value: not expected type", propertyName)
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = fmt.Sprint(v)
+	}
+	return result, nil
+}
+
+// getStringListProperty returns the values of a node property of type list of strings
+func getStringListProperty(ctx context.Context, deploymentID, nodeName, propertyName string) ([]string, error) {
+	val, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, propertyName)
+	if err != nil || val == nil || val.RawString() == "" {
+		return nil, err
+	}
+	list, ok := val.Value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("failed to retrieve %s list from Tosca Value: not expected type", propertyName)
+	}
+	result := make([]string, len(list))
+	for i, v := range list {
+		result[i] = fmt.Sprint(v)
+	}
+	return result, nil
+}
+
+// getProvidedID returns the identifier of an existing resource provided for an instance through a comma-separated
+// list of identifiers
+func getProvidedID(ctx context.Context, deploymentID, nodeName, propertyName string, instanceName string) (string, error) {
+	ids, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, propertyName, false)
+	if err != nil || ids == "" {
+		return "", err
+	}
+	var index int
+	if _, err = fmt.Sscanf(instanceName, "%d", &index); err != nil {
+		return "", errors.Wrapf(err, "unexpected instance name %q", instanceName)
+	}
+	tab := strings.Split(ids, ",")
+	if len(tab) > index {
+		return strings.TrimSpace(tab[index]), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+)
+
+func createVolume(ctx context.Context, op operationParameters, instanceName string) error {
+	volumeName, err := getProvidedID(ctx, op.deploymentID, op.nodeName, "volume_id", instanceName)
+	if err != nil {
+		return err
+	}
+
+	if volumeName != "" {
+		// Use an existing volume
+		if _, err = op.cli.VolumeInspect(ctx, volumeName); err != nil {
+			return errors.Wrapf(err, "failed to find volume %q", volumeName)
+		}
+	} else {
+		options := volumetypes.VolumesCreateBody{
+			Name:   getResourceName(op.cfg, op.deploymentID, op.nodeName, instanceName),
+			Labels: getLabels(op.deploymentID, op.nodeName),
+		}
+		if options.Driver, err = deployments.GetStringNodeProperty(ctx, op.deploymentID, op.nodeName, "driver", false); err != nil {
+			return err
+		}
+		if options.DriverOpts, err = getStringMapProperty(ctx, op.deploymentID, op.nodeName, "driver_opts"); err != nil {
+			return err
+		}
+
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Creating docker volume %q", options.Name)
+		volume, err := op.cli.VolumeCreate(ctx, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create volume %q", options.Name)
+		}
+		volumeName = volume.Name
+	}
+
+	return deployments.SetInstanceAttribute(ctx, op.deploymentID, op.nodeName, instanceName, "volume_id", volumeName)
+}
+
+func removeVolume(ctx context.Context, op operationParameters, instanceName string) error {
+	providedName, err := getProvidedID(ctx, op.deploymentID, op.nodeName, "volume_id", instanceName)
+	if err != nil || providedName != "" {
+		// Existing volumes are not removed
+		return err
+	}
+	deletable, err := deployments.GetBooleanNodeProperty(ctx, op.deploymentID, op.nodeName, "deletable")
+	if err != nil || !deletable {
+		return err
+	}
+	volumeName, err := deployments.GetInstanceAttributeValue(ctx, op.deploymentID, op.nodeName, instanceName, "volume_id")
+	if err != nil || volumeName == nil || volumeName.RawString() == "" {
+		return err
+	}
+	_, err = op.cli.VolumeInspect(ctx, volumeName.RawString())
+	if client.IsErrVolumeNotFound(err) {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, op.deploymentID).Registerf("Docker volume %q already removed", volumeName.RawString())
+		return nil
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, op.deploymentID).Registerf("Removing docker volume %q", volumeName.RawString())
+	err = op.cli.VolumeRemove(ctx, volumeName.RawString(), false)
+	return errors.Wrapf(err, "failed to remove volume %q", volumeName.RawString())
+}
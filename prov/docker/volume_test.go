@@ -0,0 +1,64 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func testCreateVolume(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	ctx := context.Background()
+	volumeName := getResourceName(cfg, deploymentID, "Volume", "0")
+
+	engine := newMockEngine()
+	engine.on(http.MethodPost, "/volumes/create", http.StatusCreated, `{"Name": "`+volumeName+`", "Driver": "local"}`)
+	engine.on(http.MethodGet, "/volumes/shared-data", http.StatusOK, `{"Name": "shared-data"}`)
+	engine.on(http.MethodGet, "/volumes/"+volumeName, http.StatusOK, `{"Name": "`+volumeName+`"}`)
+	engine.on(http.MethodDelete, "/volumes/"+volumeName, http.StatusNoContent, "")
+
+	op := engine.newOperationParameters(t, cfg, deploymentID, "Volume", config.DynamicMap{})
+	require.NoError(t, createVolume(ctx, op, "0"))
+
+	var body volumetypes.VolumesCreateBody
+	require.NoError(t, json.Unmarshal(engine.bodies["POST /volumes/create"], &body))
+	assert.Equal(t, volumeName, body.Name)
+	assert.Equal(t, "local", body.Driver)
+	assert.Equal(t, map[string]string{"type": "tmpfs", "device": "tmpfs"}, body.DriverOpts)
+	assertInstanceAttribute(t, deploymentID, "Volume", "0", "volume_id", volumeName)
+
+	existingOp := engine.newOperationParameters(t, cfg, deploymentID, "ExistingVolume", config.DynamicMap{})
+	require.NoError(t, createVolume(ctx, existingOp, "0"))
+	assertInstanceAttribute(t, deploymentID, "ExistingVolume", "0", "volume_id", "shared-data")
+
+	require.NoError(t, removeVolume(ctx, op, "0"))
+	require.NoError(t, removeVolume(ctx, existingOp, "0"))
+	assert.Equal(t, []string{
+		"POST /volumes/create",
+		"GET /volumes/shared-data",
+		"GET /volumes/" + volumeName,
+		"DELETE /volumes/" + volumeName,
+	}, engine.requests, "only created volumes should be removed")
+}
@@ -317,6 +317,14 @@ func (e *defaultExecutor) updateConnectionSettings(ctx context.Context, op opera
 			"0": host.Connection.PrivateKey,
 		},
 	}
+	if host.Connection.IsWinRM() {
+		// Operations executors select the WinRM transport from the token type
+		credentials.Protocol = host.Connection.Protocol
+		credentials.TokenType = host.Connection.AuthType
+		if strings.ToLower(host.Connection.AuthType) == "certificate" {
+			credentials.Token = host.Connection.Certificate
+		}
+	}
 
 	var credentialsMap map[string]interface{}
 	err = mapstructure.Decode(credentials, &credentialsMap)
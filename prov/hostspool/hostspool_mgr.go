@@ -160,13 +160,18 @@ func (cm *consulManager) getAddOperations(
 	if conn.Password == "" && conn.PrivateKey == "" {
 		return nil, errors.WithStack(badRequestError{`at least "password" or "private_key" is required for a host pool connection`})
 	}
+	if err := checkConnectionProtocol(conn); err != nil {
+		return nil, err
+	}
 
 	user := conn.User
 	if user == "" {
 		user = "root"
 	}
 	port := conn.Port
-	if port == 0 {
+	if port == 0 && conn.IsWinRM() {
+		port = defaultWinRMPort
+	} else if port == 0 {
 		port = 22
 	}
 	host := conn.Host
@@ -210,6 +215,21 @@ func (cm *consulManager) getAddOperations(
 			Key:   path.Join(hostKVPrefix, "connection", "port"),
 			Value: []byte(strconv.FormatUint(port, 10)),
 		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "protocol"),
+			Value: []byte(conn.Protocol),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "auth_type"),
+			Value: []byte(conn.AuthType),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "certificate"),
+			Value: []byte(conn.Certificate),
+		},
 	}
 
 	if message != "" {
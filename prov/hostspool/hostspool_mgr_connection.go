@@ -15,6 +15,8 @@
 package hostspool
 
 import (
+	"fmt"
+	"net"
 	"path"
 	"strconv"
 	"strings"
@@ -30,6 +32,11 @@ import (
 
 const hostConnectionErrorMessage = "failed to connect to host"
 
+const (
+	winRMProtocol    = "winrm"
+	defaultWinRMPort = 5986
+)
+
 func (cm *consulManager) UpdateConnection(locationName, hostname string, conn Connection) error {
 	return cm.updateConnectionWait(locationName, hostname, conn, maxWaitTimeSeconds*time.Second)
 }
@@ -70,6 +77,49 @@ func (cm *consulManager) updateConnectionWait(locationName, hostname string, con
 			Value: []byte(conn.Host),
 		})
 	}
+	if conn.Protocol != "" || conn.AuthType != "" || conn.Certificate != "" {
+		// Check the updated connection settings merged with registered ones
+		updatedConn, err := cm.GetHostConnection(locationName, hostname)
+		if err != nil {
+			return err
+		}
+		if conn.Protocol != "" {
+			updatedConn.Protocol = conn.Protocol
+		}
+		if conn.AuthType != "" {
+			updatedConn.AuthType = conn.AuthType
+		}
+		if conn.Certificate != "" {
+			updatedConn.Certificate = conn.Certificate
+		}
+		if conn.PrivateKey != "" {
+			updatedConn.PrivateKey = conn.PrivateKey
+		}
+		if err = checkConnectionProtocol(updatedConn); err != nil {
+			return err
+		}
+	}
+	if conn.Protocol != "" {
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "protocol"),
+			Value: []byte(conn.Protocol),
+		})
+	}
+	if conn.AuthType != "" {
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "auth_type"),
+			Value: []byte(conn.AuthType),
+		})
+	}
+	if conn.Certificate != "" {
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(hostKVPrefix, "connection", "certificate"),
+			Value: []byte(conn.Certificate),
+		})
+	}
 	if conn.PrivateKey != "" {
 		if conn.PrivateKey == "-" {
 			ok, err := cm.DoesHostHasConnectionPassword(locationName, hostname)
@@ -138,6 +188,27 @@ func (cm *consulManager) updateConnectionWait(locationName, hostname string, con
 	return nil
 }
 
+// checkConnectionProtocol checks the protocol of a connection and its WinRM authentication settings
+func checkConnectionProtocol(conn Connection) error {
+	switch strings.ToLower(conn.Protocol) {
+	case "", "ssh":
+		return nil
+	case winRMProtocol:
+	default:
+		return errors.WithStack(badRequestError{fmt.Sprintf(`unsupported connection protocol %q, expecting "ssh" or "winrm"`, conn.Protocol)})
+	}
+	switch strings.ToLower(conn.AuthType) {
+	case "", "ntlm", "kerberos":
+	case "certificate":
+		if conn.Certificate == "" || conn.PrivateKey == "" {
+			return errors.WithStack(badRequestError{`"certificate" and "private_key" are required for the WinRM certificate authentication`})
+		}
+	default:
+		return errors.WithStack(badRequestError{fmt.Sprintf(`unsupported WinRM authentication type %q, expecting one of "ntlm", "kerberos" or "certificate"`, conn.AuthType)})
+	}
+	return nil
+}
+
 func (cm *consulManager) DoesHostHasConnectionPrivateKey(locationName, hostname string) (bool, error) {
 	c, err := cm.GetHostConnection(locationName, hostname)
 	if err != nil {
@@ -197,6 +268,28 @@ func (cm *consulManager) GetHostConnection(locationName, hostname string) (Conne
 		conn.PrivateKey = string(kvp.Value)
 		conn.PrivateKey = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.PrivateKey", conn.PrivateKey).(string)
 	}
+	kvp, _, err = kv.Get(path.Join(connKVPrefix, "protocol"), nil)
+	if err != nil {
+		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil {
+		conn.Protocol = string(kvp.Value)
+	}
+	kvp, _, err = kv.Get(path.Join(connKVPrefix, "auth_type"), nil)
+	if err != nil {
+		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil {
+		conn.AuthType = string(kvp.Value)
+	}
+	kvp, _, err = kv.Get(path.Join(connKVPrefix, "certificate"), nil)
+	if err != nil {
+		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil {
+		conn.Certificate = string(kvp.Value)
+		conn.Certificate = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.Certificate", conn.Certificate).(string)
+	}
 	kvp, _, err = kv.Get(path.Join(connKVPrefix, "port"), nil)
 	if err != nil {
 		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
@@ -217,6 +310,14 @@ func (cm *consulManager) checkConnection(locationName, hostname string) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to connect to host %q", hostname)
 	}
+	if conn.IsWinRM() {
+		// Executing commands through WinRM is left to Ansible, only check that the WinRM listener is reachable
+		c, err := net.DialTimeout("tcp", net.JoinHostPort(conn.Host, strconv.FormatUint(conn.Port, 10)), cm.cfg.SSHConnectionTimeout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to host %q", hostname)
+		}
+		return c.Close()
+	}
 	conf, err := getSSHConfig(cm.cfg, conn)
 	if err != nil {
 		return errors.Wrapf(err, "failed to connect to host %q", hostname)
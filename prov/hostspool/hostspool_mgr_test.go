@@ -1511,3 +1511,31 @@ func testConsulManagerAddLabelsWithAllocation(t *testing.T, cc *api.Client, cfg
 	require.Equal(t, "node_test", allocatedHost.Allocations[0].NodeName)
 	assert.Equal(t, expectedLabels, allocatedHost.Labels, "labels have not been updated after apply")
 }
+
+func TestCheckConnectionProtocol(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		conn    Connection
+		wantErr bool
+	}{
+		{"DefaultSSH", Connection{Password: "pass"}, false},
+		{"SSH", Connection{Protocol: "ssh", PrivateKey: "key"}, false},
+		{"WinRMDefaultAuth", Connection{Protocol: "winrm", Password: "pass"}, false},
+		{"WinRMKerberos", Connection{Protocol: "WinRM", AuthType: "kerberos", Password: "pass"}, false},
+		{"WinRMCertificate", Connection{Protocol: "winrm", AuthType: "certificate", Certificate: "cert", PrivateKey: "key"}, false},
+		{"WinRMCertificateWithoutCertificate", Connection{Protocol: "winrm", AuthType: "certificate", PrivateKey: "key"}, true},
+		{"WinRMUnknownAuth", Connection{Protocol: "winrm", AuthType: "basic", Password: "pass"}, true},
+		{"UnknownProtocol", Connection{Protocol: "telnet", Password: "pass"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkConnectionProtocol(tt.conn)
+			if tt.wantErr {
+				assert.True(t, IsBadRequestError(err), "expecting a bad request error, got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
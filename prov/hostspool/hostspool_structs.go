@@ -65,9 +65,7 @@ func (hs *HostStatus) UnmarshalJSON(b []byte) error {
 	return errors.Wrap(err, "failed to parse HostStatus from JSON input")
 }
 
-// TODO support winrm for windows hosts
-
-// A Connection holds info used to connect to a host using SSH
+// A Connection holds info used to connect to a host using SSH, or WinRM for Windows hosts
 type Connection struct {
 	// The User that we should use for the connection. Defaults to root.
 	User string `json:"user,omitempty" yaml:"user,omitempty"`
@@ -78,8 +76,20 @@ type Connection struct {
 	PrivateKey string `json:"private_key,omitempty"  yaml:"private_key,omitempty" mapstructure:"private_key"`
 	// The address of the Host to connect to. Defaults to the hostname specified during the registration.
 	Host string `json:"host,omitempty" yaml:"host,omitempty"`
-	// The Port to connect to. Defaults to 22 if set to 0.
+	// The Port to connect to. Defaults to 22 if set to 0, or to 5986 for WinRM connections.
 	Port uint64 `json:"port,omitempty" yaml:"port,omitempty"`
+	// The Protocol used to connect to the host, either ssh (the default) or winrm
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	// The AuthType of WinRM connections, either ntlm (the default), kerberos or certificate.
+	// For the certificate authentication, PrivateKey is the key of the client Certificate.
+	AuthType string `json:"auth_type,omitempty" yaml:"auth_type,omitempty" mapstructure:"auth_type"`
+	// The client Certificate of WinRM connections using the certificate authentication
+	Certificate string `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+}
+
+// IsWinRM returns true if the host is connected using WinRM
+func (conn Connection) IsWinRM() bool {
+	return strings.ToLower(conn.Protocol) == winRMProtocol
 }
 
 // String allows to stringify a connection
@@ -92,7 +102,15 @@ func (conn Connection) String() string {
 		key = "private key: " + conn.PrivateKey + ", "
 	}
 
-	return "user: " + conn.User + ", " + pass + key + "host: " + conn.Host + ", " + "port: " + strconv.FormatUint(conn.Port, 10)
+	var winRM string
+	if conn.IsWinRM() {
+		winRM = ", protocol: " + conn.Protocol + ", auth type: " + conn.AuthType
+		if conn.Certificate != "" {
+			winRM += ", certificate: " + conn.Certificate
+		}
+	}
+
+	return "user: " + conn.User + ", " + pass + key + "host: " + conn.Host + ", " + "port: " + strconv.FormatUint(conn.Port, 10) + winRM
 }
 
 // A Pool holds information on a hosts pool
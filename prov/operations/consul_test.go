@@ -42,6 +42,9 @@ func TestRunConsulTasksPackageTests(t *testing.T) {
 	t.Run("TestGetOverlayPath", func(t *testing.T) {
 		testGetOverlayPath(t)
 	})
+	t.Run("TestFetchOperationRemoteArtifacts", func(t *testing.T) {
+		testFetchOperationRemoteArtifacts(t)
+	})
 
 }
 
@@ -0,0 +1,313 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// Types of repositories from which artifacts are downloaded by Yorc
+const (
+	// RepositoryTypeHTTP is the type of repositories serving artifacts files over HTTP or HTTPS
+	RepositoryTypeHTTP = "http"
+	// RepositoryTypeGit is the type of Git repositories, artifacts files are paths in the repository
+	RepositoryTypeGit = "git"
+	// RepositoryTypeS3 is the type of S3-compatible object storage repositories, artifacts files are objects keys
+	RepositoryTypeS3 = "s3"
+	// RepositoryTypeMaven is the type of Maven repositories, artifacts files are Maven coordinates
+	RepositoryTypeMaven = "maven"
+)
+
+// remoteArtifactsDir is the directory of the deployment overlay where
+// artifacts downloaded from repositories are cached
+const remoteArtifactsDir = ".remote_artifacts"
+
+// remoteArtifactsLocks prevents concurrent operations from downloading the same artifact
+var remoteArtifactsLocks sync.Map
+
+func getRemoteRepositoryType(repositoryType string) (string, bool) {
+	switch t := strings.ToLower(repositoryType); t {
+	case RepositoryTypeHTTP, "https":
+		return RepositoryTypeHTTP, true
+	case RepositoryTypeGit, RepositoryTypeS3, RepositoryTypeMaven:
+		return t, true
+	}
+	return "", false
+}
+
+// GetRemoteArtifactPath returns the path, relative to the deployment overlay, where an artifact
+// hosted in a repository is cached once downloaded.
+//
+// false is returned if the artifact is not hosted in a repository of a type from which Yorc
+// downloads artifacts (like Docker registries).
+func GetRemoteArtifactPath(ctx context.Context, deploymentID string, artifact tosca.ArtifactDefinition) (bool, string, error) {
+	if artifact.Repository == "" {
+		return false, "", nil
+	}
+	repositoryType, err := deployments.GetRepositoryTypeFromName(ctx, deploymentID, artifact.Repository)
+	if err != nil {
+		return false, "", err
+	}
+	repositoryType, ok := getRemoteRepositoryType(repositoryType)
+	if !ok {
+		return false, "", nil
+	}
+	p, err := remoteArtifactRelativePath(artifact.Repository, repositoryType, artifact.File)
+	return true, p, err
+}
+
+func remoteArtifactRelativePath(repositoryName, repositoryType, file string) (string, error) {
+	if repositoryType == RepositoryTypeMaven {
+		var err error
+		file, err = mavenArtifactPath(file)
+		if err != nil {
+			return "", err
+		}
+	}
+	// Artifacts files should not go out of the repository cache directory
+	return path.Join(remoteArtifactsDir, repositoryName, path.Clean("/"+file)), nil
+}
+
+// FetchRemoteArtifact downloads an artifact hosted in a repository, if it is not already cached in the
+// deployment overlay, and verifies its checksum if any.
+//
+// Like GetRemoteArtifactPath, it returns false if the artifact is not downloaded by Yorc, and the path
+// of the artifact relative to the deployment overlay otherwise.
+func FetchRemoteArtifact(ctx context.Context, overlayPath, deploymentID string, artifact tosca.ArtifactDefinition) (bool, string, error) {
+	isRemote, relPath, err := GetRemoteArtifactPath(ctx, deploymentID, artifact)
+	if err != nil || !isRemote {
+		return isRemote, relPath, err
+	}
+	repositoryType, err := deployments.GetRepositoryTypeFromName(ctx, deploymentID, artifact.Repository)
+	if err != nil {
+		return true, "", err
+	}
+	repositoryType, _ = getRemoteRepositoryType(repositoryType)
+	artifactPath := filepath.Join(overlayPath, relPath)
+
+	// A Git repository is cloned once for all its artifacts
+	lockPath := artifactPath
+	if repositoryType == RepositoryTypeGit {
+		lockPath = filepath.Join(overlayPath, remoteArtifactsDir, artifact.Repository)
+	}
+	l, _ := remoteArtifactsLocks.LoadOrStore(lockPath, new(sync.Mutex))
+	lock := l.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if repositoryType == RepositoryTypeGit {
+		if _, err = os.Stat(lockPath); err == nil {
+			return true, relPath, checkDownloadedArtifact(artifactPath, artifact)
+		}
+	} else if _, err = os.Stat(artifactPath); err == nil {
+		if err = verifyArtifactChecksum(artifactPath, artifact); err == nil {
+			return true, relPath, nil
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Cached artifact %q of repository %q will be downloaded again: %v", artifact.File, artifact.Repository, err)
+	}
+
+	repositoryURL, err := deployments.GetRepositoryURLFromName(ctx, deploymentID, artifact.Repository)
+	if err != nil {
+		return true, "", err
+	}
+	credential, err := deployments.GetRepositoryCredentialFromName(ctx, deploymentID, artifact.Repository)
+	if err != nil {
+		return true, "", err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Downloading artifact %q from %s repository %q", artifact.File, repositoryType, artifact.Repository)
+	if repositoryType == RepositoryTypeGit {
+		err = cloneGitRepository(ctx, repositoryURL, credential, lockPath)
+	} else {
+		err = downloadArtifactFile(ctx, repositoryType, repositoryURL, credential, artifact, artifactPath)
+	}
+	if err != nil {
+		return true, "", errors.Wrapf(err, "failed to download artifact %q from repository %q", artifact.File, artifact.Repository)
+	}
+	return true, relPath, checkDownloadedArtifact(artifactPath, artifact)
+}
+
+func checkDownloadedArtifact(artifactPath string, artifact tosca.ArtifactDefinition) error {
+	if _, err := os.Stat(artifactPath); err != nil {
+		return errors.Wrapf(err, "artifact %q not found in repository %q", artifact.File, artifact.Repository)
+	}
+	return verifyArtifactChecksum(artifactPath, artifact)
+}
+
+// FetchRemoteArtifacts downloads artifacts hosted in repositories among the given definitions
+// and updates the path of the corresponding artifacts in the given map of artifacts files.
+func FetchRemoteArtifacts(ctx context.Context, overlayPath, deploymentID string, definitions map[string]tosca.ArtifactDefinition, artifacts map[string]string) error {
+	for name, artifact := range definitions {
+		isRemote, relPath, err := FetchRemoteArtifact(ctx, overlayPath, deploymentID, artifact)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch artifact %q", name)
+		}
+		if isRemote {
+			artifacts[name] = relPath
+		}
+	}
+	return nil
+}
+
+// downloadArtifactFile downloads an artifact file in a temporary file renamed once
+// complete, so that a partially downloaded artifact is never used
+func downloadArtifactFile(ctx context.Context, repositoryType, repositoryURL string, credential *tosca.Credential, artifact tosca.ArtifactDefinition, artifactPath string) error {
+	if err := os.MkdirAll(filepath.Dir(artifactPath), 0775); err != nil {
+		return errors.Wrap(err, "failed to create artifacts cache directory")
+	}
+	f, err := ioutil.TempFile(filepath.Dir(artifactPath), ".download-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create artifact file")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	switch repositoryType {
+	case RepositoryTypeHTTP:
+		err = httpGetFile(ctx, strings.TrimSuffix(repositoryURL, "/")+"/"+strings.TrimPrefix(artifact.File, "/"), credential, f)
+	case RepositoryTypeMaven:
+		err = mavenGetFile(ctx, repositoryURL, credential, artifact, f)
+	case RepositoryTypeS3:
+		err = s3GetFile(ctx, repositoryURL, credential, artifact.File, f)
+	default:
+		err = errors.Errorf("unsupported repository type %q", repositoryType)
+	}
+	if err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write artifact file")
+	}
+	return errors.Wrap(os.Rename(f.Name(), artifactPath), "failed to write artifact file")
+}
+
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToUpper(strings.Replace(algorithm, "-", "", -1)) {
+	case "", "SHA256":
+		return sha256.New(), nil
+	case "SHA1":
+		return sha1.New(), nil
+	case "SHA512":
+		return sha512.New(), nil
+	case "MD5":
+		return md5.New(), nil
+	}
+	return nil, errors.Errorf("unsupported checksum algorithm %q", algorithm)
+}
+
+func fileChecksum(filePath, algorithm string) (string, error) {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open artifact file")
+	}
+	defer f.Close()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", errors.Wrap(err, "failed to read artifact file")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyArtifactChecksum checks the checksum of an artifact file if the artifact
+// definition specifies one, SHA-256 being the default algorithm
+func verifyArtifactChecksum(filePath string, artifact tosca.ArtifactDefinition) error {
+	if artifact.Checksum == "" {
+		return nil
+	}
+	checksum, err := fileChecksum(filePath, artifact.ChecksumAlgorithm)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(checksum, strings.TrimSpace(artifact.Checksum)) {
+		return errors.Errorf("checksum mismatch for artifact %q: expected %q, got %q", artifact.File, artifact.Checksum, checksum)
+	}
+	return nil
+}
+
+// FetchOperationRemoteArtifacts downloads the implementation artifact of an operation and the
+// artifacts hosted in repositories of the node on which it runs, and of the relationship type
+// for relationship operations. The path of those artifacts in the given map of artifacts files
+// are updated.
+//
+// Like FetchRemoteArtifact, it returns false if the implementation artifact is not downloaded
+// by Yorc, and the path of this artifact relative to the deployment overlay otherwise.
+func FetchOperationRemoteArtifacts(ctx context.Context, overlayPath, deploymentID, nodeName string, operation prov.Operation, artifacts map[string]string) (bool, string, error) {
+	operationType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return false, "", err
+	}
+	var relationshipType string
+	if operation.RelOp.IsRelationshipOperation {
+		relationshipType, err = deployments.GetRelationshipForRequirement(ctx, deploymentID, nodeName, operation.RelOp.RequirementIndex)
+		if err != nil {
+			return false, "", err
+		}
+		operationType = relationshipType
+	}
+	implementation, err := deployments.GetOperationImplementation(ctx, deploymentID, operation.ImplementedInNodeTemplate, operationType, operation.Name)
+	if err != nil {
+		return false, "", err
+	}
+	var isRemote bool
+	var implementationPath string
+	if implementation != nil {
+		isRemote, implementationPath, err = FetchRemoteArtifact(ctx, overlayPath, deploymentID, implementation.Artifact)
+		if err != nil {
+			return false, "", err
+		}
+	}
+
+	artifactsNode := nodeName
+	if IsRelationshipTargetNodeOperation(operation) {
+		artifactsNode = operation.RelOp.TargetNodeName
+	}
+	definitions, err := deployments.GetArtifactsDefinitionsForNode(ctx, deploymentID, artifactsNode)
+	if err != nil {
+		return false, "", err
+	}
+	if operation.RelOp.IsRelationshipOperation {
+		relDefinitions, err := deployments.GetArtifactsDefinitionsForType(ctx, deploymentID, relationshipType)
+		if err != nil {
+			return false, "", err
+		}
+		for name, artifact := range relDefinitions {
+			definitions[name] = artifact
+		}
+	}
+	return isRemote, implementationPath, FetchRemoteArtifacts(ctx, overlayPath, deploymentID, definitions, artifacts)
+}
@@ -0,0 +1,285 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/awsutil"
+	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const defaultS3Region = "us-east-1"
+
+// httpGet sends a GET request authenticated by the repository credential if any, using
+// the basic authentication scheme if a user is defined or a bearer token otherwise
+func httpGet(ctx context.Context, rawURL string, credential *tosca.Credential, prepare func(*http.Request) error) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid artifact URL %q", rawURL)
+	}
+	if prepare != nil {
+		if err = prepare(req); err != nil {
+			return nil, err
+		}
+	} else if credential != nil && credential.User != "" {
+		req.SetBasicAuth(credential.User, credential.Token)
+	} else if credential != nil && credential.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+credential.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	return resp, errors.Wrapf(err, "failed to get %q", redactURL(req.URL))
+}
+
+// redactURL removes credentials from an URL to be used in errors
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
+
+func httpGetFile(ctx context.Context, rawURL string, credential *tosca.Credential, w io.Writer) error {
+	return httpGetFileWithRequest(ctx, rawURL, credential, nil, w)
+}
+
+func httpGetFileWithRequest(ctx context.Context, rawURL string, credential *tosca.Credential, prepare func(*http.Request) error, w io.Writer) error {
+	resp, err := httpGet(ctx, rawURL, credential, prepare)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to get %q: %s", redactURL(resp.Request.URL), resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return errors.Wrapf(err, "failed to get %q", redactURL(resp.Request.URL))
+}
+
+// mavenArtifactPath returns the path of an artifact in a Maven repository from its
+// coordinates groupId:artifactId:version[:packaging[:classifier]], the default
+// packaging being jar.
+//
+// Files without coordinates are considered as paths in the repository.
+func mavenArtifactPath(file string) (string, error) {
+	if !strings.Contains(file, ":") {
+		return file, nil
+	}
+	coordinates := strings.Split(file, ":")
+	if len(coordinates) < 3 || len(coordinates) > 5 {
+		return "", errors.Errorf("invalid Maven coordinates %q, expecting groupId:artifactId:version[:packaging[:classifier]]", file)
+	}
+	for _, c := range coordinates {
+		if c == "" || strings.Contains(c, "/") {
+			return "", errors.Errorf("invalid Maven coordinates %q", file)
+		}
+	}
+	groupID, artifactID, version := coordinates[0], coordinates[1], coordinates[2]
+	packaging := "jar"
+	if len(coordinates) > 3 {
+		packaging = coordinates[3]
+	}
+	fileName := artifactID + "-" + version
+	if len(coordinates) > 4 {
+		fileName += "-" + coordinates[4]
+	}
+	return strings.Join([]string{strings.Replace(groupID, ".", "/", -1), artifactID, version, fileName + "." + packaging}, "/"), nil
+}
+
+// mavenGetFile downloads an artifact from a Maven repository. If the artifact
+// definition has no checksum, the SHA-1 checksum published in the repository
+// is checked when available.
+func mavenGetFile(ctx context.Context, repositoryURL string, credential *tosca.Credential, artifact tosca.ArtifactDefinition, w io.Writer) error {
+	artifactPath, err := mavenArtifactPath(artifact.File)
+	if err != nil {
+		return err
+	}
+	artifactURL := strings.TrimSuffix(repositoryURL, "/") + "/" + strings.TrimPrefix(artifactPath, "/")
+	h := sha1.New()
+	if err = httpGetFile(ctx, artifactURL, credential, io.MultiWriter(w, h)); err != nil {
+		return err
+	}
+	if artifact.Checksum != "" {
+		return nil
+	}
+
+	resp, err := httpGet(ctx, artifactURL+".sha1", credential, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to get checksum of %q: %s", artifactURL, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get checksum of %q", artifactURL)
+	}
+	// Checksum files may contain the file name after the checksum
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return errors.Errorf("empty checksum file for %q", artifactURL)
+	}
+	if checksum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(fields[0], checksum) {
+		return errors.Errorf("SHA-1 checksum mismatch for %q: expected %q, got %q", artifactURL, fields[0], checksum)
+	}
+	return nil
+}
+
+// s3ObjectURL returns the URL of an object of an S3-compatible repository.
+//
+// Repositories URLs are either s3://bucket[/prefix] for AWS S3 or
+// http(s)://endpoint/bucket[/prefix] for other S3-compatible storages, objects
+// being addressed in path-style.
+func s3ObjectURL(repositoryURL, key, region string) (*url.URL, error) {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid S3 repository URL %q", repositoryURL)
+	}
+	var objectPath string
+	switch u.Scheme {
+	case "s3":
+		objectPath = "/" + u.Host + u.Path
+		u.Scheme = "https"
+		u.Host = "s3.amazonaws.com"
+		if region != defaultS3Region {
+			u.Host = fmt.Sprintf("s3.%s.amazonaws.com", region)
+		}
+	case "http", "https":
+		objectPath = u.Path
+	default:
+		return nil, errors.Errorf("invalid S3 repository URL %q, expecting s3, http or https scheme", repositoryURL)
+	}
+	u.Path = strings.TrimSuffix(objectPath, "/") + "/" + strings.TrimPrefix(key, "/")
+	u.RawPath = awsutil.URIEncode(u.Path)
+	return u, nil
+}
+
+// s3GetFile downloads an object from an S3-compatible repository.
+//
+// The user and token of the repository credential are the access key ID and
+// the secret access key. The region and an optional session token are given
+// by the region and session_token keys of the credential.
+func s3GetFile(ctx context.Context, repositoryURL string, credential *tosca.Credential, key string, w io.Writer) error {
+	region := defaultS3Region
+	if r := credential.Keys["region"]; r != "" {
+		region = r
+	}
+	u, err := s3ObjectURL(repositoryURL, key, region)
+	if err != nil {
+		return err
+	}
+	return httpGetFileWithRequest(ctx, u.String(), credential, func(req *http.Request) error {
+		// Go would escape the path differently
+		req.URL.RawPath = u.RawPath
+		if credential.User != "" {
+			awsutil.SignS3Request(req, credential.User, credential.Token, credential.Keys["session_token"], region, time.Now())
+		}
+		return nil
+	}, w)
+}
+
+// gitAskPassScript answers Git credentials prompts with the credentials given
+// in environment variables, so that they appear neither in commands arguments
+// nor on disk
+const gitAskPassScript = `#!/bin/sh
+case "$1" in
+Username*) echo "$YORC_GIT_USERNAME" ;;
+*) echo "$YORC_GIT_PASSWORD" ;;
+esac
+`
+
+// cloneGitRepository makes a shallow clone of a Git repository. A branch or tag
+// may be given as fragment of the repository URL.
+//
+// Credentials are given to Git through a GIT_ASKPASS script.
+func cloneGitRepository(ctx context.Context, repositoryURL string, credential *tosca.Credential, dest string) error {
+	var ref string
+	if i := strings.LastIndex(repositoryURL, "#"); i >= 0 {
+		repositoryURL, ref = repositoryURL[:i], repositoryURL[i+1:]
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0775); err != nil {
+		return errors.Wrap(err, "failed to create artifacts cache directory")
+	}
+	tmpDir, err := ioutil.TempDir(filepath.Dir(dest), ".clone-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create artifacts cache directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", repositoryURL, tmpDir)
+	cmd := executil.Command(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if credential != nil && credential.Token != "" {
+		user := credential.User
+		if user == "" {
+			user = "oauth2"
+		}
+		askPass, err := writeGitAskPassScript()
+		if err != nil {
+			return err
+		}
+		defer os.Remove(askPass)
+		cmd.Env = append(cmd.Env,
+			"GIT_ASKPASS="+askPass,
+			"YORC_GIT_USERNAME="+user,
+			"YORC_GIT_PASSWORD="+credential.Token,
+		)
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err = cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to clone Git repository %q: %s", repositoryURL, output.String())
+	}
+	return errors.Wrap(os.Rename(tmpDir, dest), "failed to create artifacts cache directory")
+}
+
+func writeGitAskPassScript() (string, error) {
+	f, err := ioutil.TempFile("", "yorc-git-askpass-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create Git credentials helper")
+	}
+	defer f.Close()
+	if _, err = f.WriteString(gitAskPassScript); err == nil {
+		err = f.Chmod(0700)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", errors.Wrap(err, "failed to create Git credentials helper")
+	}
+	return f.Name(), nil
+}
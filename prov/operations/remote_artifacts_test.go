@@ -0,0 +1,247 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func TestMavenArtifactPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"Jar", "org.ystia:yorc:4.0.0", "org/ystia/yorc/4.0.0/yorc-4.0.0.jar", false},
+		{"Packaging", "org.ystia:yorc:4.0.0:tgz", "org/ystia/yorc/4.0.0/yorc-4.0.0.tgz", false},
+		{"Classifier", "org.ystia:yorc:4.0.0:zip:dist", "org/ystia/yorc/4.0.0/yorc-4.0.0-dist.zip", false},
+		{"Path", "org/ystia/yorc/4.0.0/yorc-4.0.0.pom", "org/ystia/yorc/4.0.0/yorc-4.0.0.pom", false},
+		{"MissingVersion", "org.ystia:yorc", "", true},
+		{"EmptyPart", "org.ystia::4.0.0", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mavenArtifactPath(tt.file)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemoteArtifactRelativePath(t *testing.T) {
+	p, err := remoteArtifactRelativePath("repo", RepositoryTypeHTTP, "../../../etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, ".remote_artifacts/repo/etc/passwd", p)
+	p, err = remoteArtifactRelativePath("repo", RepositoryTypeMaven, "org.ystia:yorc:4.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, ".remote_artifacts/repo/org/ystia/yorc/4.0.0/yorc-4.0.0.jar", p)
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	u, err := s3ObjectURL("s3://bucket/prefix/", "dir/my file.tgz", defaultS3Region)
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.amazonaws.com/bucket/prefix/dir/my%20file.tgz", u.String())
+	u, err = s3ObjectURL("s3://bucket", "file.tgz", "eu-west-3")
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.eu-west-3.amazonaws.com/bucket/file.tgz", u.String())
+	u, err = s3ObjectURL("http://minio:9000/bucket", "a+b.tgz", defaultS3Region)
+	require.NoError(t, err)
+	assert.Equal(t, "http://minio:9000/bucket/a%2Bb.tgz", u.String())
+	_, err = s3ObjectURL("ftp://bucket", "file.tgz", defaultS3Region)
+	assert.Error(t, err)
+}
+
+func TestVerifyArtifactChecksum(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "TestVerifyArtifactChecksum")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+	filePath := filepath.Join(tempdir, "artifact")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("hello"), 0600))
+
+	assert.NoError(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{}))
+	assert.NoError(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{Checksum: "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824"}))
+	assert.NoError(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{Checksum: "5d41402abc4b2a76b9719d911017c592", ChecksumAlgorithm: "MD5"}))
+	assert.NoError(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{Checksum: "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", ChecksumAlgorithm: "SHA-1"}))
+	assert.Error(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{Checksum: "5d41402abc4b2a76b9719d911017c592"}))
+	assert.Error(t, verifyArtifactChecksum(filePath, tosca.ArtifactDefinition{Checksum: "5d41402abc4b2a76b9719d911017c592", ChecksumAlgorithm: "CRC32"}))
+}
+
+func TestDownloadArtifactFile(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "TestDownloadArtifactFile")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/app.tgz":
+			if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("hello"))
+		case "/maven/org/ystia/app/1.0/app-1.0.jar", "/maven/org/ystia/bad/1.0/bad-1.0.jar":
+			w.Write([]byte("hello"))
+		case "/maven/org/ystia/app/1.0/app-1.0.jar.sha1":
+			w.Write([]byte("aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d  app-1.0.jar"))
+		case "/maven/org/ystia/bad/1.0/bad-1.0.jar.sha1":
+			w.Write([]byte("0000000000000000000000000000000000000000"))
+		case "/bucket/prefix/app.tgz":
+			if r.Header.Get("Authorization") == "" || r.Header.Get("X-Amz-Date") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("hello"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	credential := &tosca.Credential{User: "user", Token: "secret"}
+	tests := []struct {
+		name           string
+		repositoryType string
+		repositoryURL  string
+		credential     *tosca.Credential
+		file           string
+		wantErr        bool
+	}{
+		{"HTTP", RepositoryTypeHTTP, ts.URL + "/files/", credential, "app.tgz", false},
+		{"HTTPUnauthorized", RepositoryTypeHTTP, ts.URL + "/files/", &tosca.Credential{}, "app.tgz", true},
+		{"HTTPNotFound", RepositoryTypeHTTP, ts.URL + "/files/", credential, "other.tgz", true},
+		{"Maven", RepositoryTypeMaven, ts.URL + "/maven", nil, "org.ystia:app:1.0", false},
+		{"MavenChecksumMismatch", RepositoryTypeMaven, ts.URL + "/maven", nil, "org.ystia:bad:1.0", true},
+		{"S3", RepositoryTypeS3, ts.URL + "/bucket/prefix", &tosca.Credential{User: "access", Token: "secret"}, "app.tgz", false},
+		{"S3Anonymous", RepositoryTypeS3, ts.URL + "/bucket/prefix", &tosca.Credential{}, "app.tgz", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifactPath := filepath.Join(tempdir, tt.name, "artifact")
+			err := downloadArtifactFile(context.Background(), tt.repositoryType, tt.repositoryURL, tt.credential, tosca.ArtifactDefinition{File: tt.file}, artifactPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				_, err = os.Stat(artifactPath)
+				assert.True(t, os.IsNotExist(err), "unexpected artifact file")
+				return
+			}
+			require.NoError(t, err)
+			content, err := ioutil.ReadFile(artifactPath)
+			require.NoError(t, err)
+			assert.Equal(t, "hello", string(content))
+		})
+	}
+}
+
+func TestCloneGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempdir, err := ioutil.TempDir("", "TestCloneGitRepository")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	repoDir := filepath.Join(tempdir, "origin")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "playbooks"), 0775))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoDir, "playbooks", "create.yml"), []byte("- hosts: all"), 0664))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	dest := filepath.Join(tempdir, "cache", "repo")
+	require.NoError(t, cloneGitRepository(context.Background(), repoDir+"#v1", nil, dest))
+	assert.FileExists(t, filepath.Join(dest, "playbooks", "create.yml"))
+
+	err = cloneGitRepository(context.Background(), repoDir+"#unknown", nil, filepath.Join(tempdir, "cache", "other"))
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(tempdir, "cache", "other"))
+	assert.True(t, os.IsNotExist(err), "unexpected clone directory")
+
+	askPass, err := writeGitAskPassScript()
+	require.NoError(t, err)
+	defer os.Remove(askPass)
+	for prompt, expected := range map[string]string{
+		"Username for 'https://git.example.com': ":      "user",
+		"Password for 'https://user@git.example.com': ": "token",
+	} {
+		cmd := exec.Command(askPass, prompt)
+		cmd.Env = []string{"YORC_GIT_USERNAME=user", "YORC_GIT_PASSWORD=token"}
+		out, err := cmd.Output()
+		require.NoError(t, err)
+		assert.Equal(t, expected+"\n", string(out))
+	}
+}
+
+func testFetchOperationRemoteArtifacts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "content of %s", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	tempdir, err := ioutil.TempDir("", "TestFetchOperationRemoteArtifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+	definition, err := ioutil.ReadFile("testdata/remote_artifacts.yaml")
+	require.NoError(t, err)
+	definitionPath := filepath.Join(tempdir, "remote_artifacts.yaml")
+	require.NoError(t, ioutil.WriteFile(definitionPath, bytes.Replace(definition, []byte("REPOSITORY_URL"), []byte(ts.URL+"/"), 1), 0664))
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	require.NoError(t, deployments.StoreDeploymentDefinition(context.Background(), deploymentID, definitionPath))
+
+	overlayPath := filepath.Join(tempdir, "overlay")
+	artifacts, err := deployments.GetFileArtifactsForNode(context.Background(), deploymentID, "Remote")
+	require.NoError(t, err)
+	op := prov.Operation{Name: "standard.create", ImplementedInType: "yorc.types.Remote"}
+	isRemote, primaryPath, err := FetchOperationRemoteArtifacts(context.Background(), overlayPath, deploymentID, "Remote", op, artifacts)
+	require.NoError(t, err)
+	assert.True(t, isRemote)
+	assert.Equal(t, ".remote_artifacts/binaries/scripts/create.sh", primaryPath)
+	content, err := ioutil.ReadFile(filepath.Join(overlayPath, primaryPath))
+	require.NoError(t, err)
+	assert.Equal(t, "content of /scripts/create.sh", string(content))
+
+	assert.Equal(t, ".remote_artifacts/binaries/app/app-1.0.tgz", artifacts["app"])
+	assert.FileExists(t, filepath.Join(overlayPath, artifacts["app"]))
+	assert.NotContains(t, artifacts["config"], ".remote_artifacts")
+}
@@ -33,6 +33,14 @@ type DelegateExecutor interface {
 	ExecDelegate(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error
 }
 
+// A DeploymentPurger is a DelegateExecutor storing data outside of the deployment that should be
+// removed when the deployment is purged
+//
+// PurgeNode removes the data stored for the given node, its resources being already destroyed.
+type DeploymentPurger interface {
+	PurgeNode(ctx context.Context, conf config.Configuration, deploymentID, nodeName string) error
+}
+
 // Operation represent a provisioning operation
 type Operation struct {
 	// The operation name
@@ -91,6 +99,14 @@ type OperationExecutor interface {
 	ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation Operation, stepName string) (*Action, time.Duration, error)
 }
 
+// CheckModeOperationExecutor is an OperationExecutor able to run operations in check mode
+//
+// ExecOperationInCheckMode reports the changes that ExecOperation would make without applying them.
+// It should neither update instances attributes nor operations outputs.
+type CheckModeOperationExecutor interface {
+	ExecOperationInCheckMode(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation Operation) error
+}
+
 // InfraUsageCollector is the interface for collecting information about infrastructure usage for a defined location
 //
 // GetUsageInfo returns data about infrastructure usage for defined infrastructure and location
@@ -99,6 +115,26 @@ type InfraUsageCollector interface {
 		params map[string]string) (map[string]interface{}, error)
 }
 
+// A CostEstimate is the estimated cost of the resources of a node
+type CostEstimate struct {
+	// Currency of costs, as defined by the price table of the location
+	Currency string `json:"currency,omitempty"`
+	// HourlyCost is the estimated cost of all resources for one hour
+	HourlyCost float64 `json:"hourly_cost"`
+	// UnpricedResources lists resources missing from the price table, not part of the hourly cost
+	UnpricedResources []string `json:"unpriced_resources,omitempty"`
+}
+
+// A CostEstimator is an InfraUsageCollector able to estimate the cost of the resources of a node
+// before they are created
+//
+// EstimateNodeCost returns the estimated cost of the given number of instances of a node,
+// or nil if costs can't be estimated for this node, for instance because no price table is defined
+// for its location.
+type CostEstimator interface {
+	EstimateNodeCost(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instancesCount int) (*CostEstimate, error)
+}
+
 // Action represents an executable action
 type Action struct {
 	ID             string
@@ -77,8 +77,17 @@ func TestRunConsulSlurmPackageTests(t *testing.T) {
 		t.Run("ExecutionCommonPrepareAndSubmitJob", func(t *testing.T) {
 			testExecutionCommonPrepareAndSubmitJob(t)
 		})
+		t.Run("ExecutionCommonResolveJobDependencies", func(t *testing.T) {
+			testExecutionCommonResolveJobDependencies(t)
+		})
+		t.Run("JobDependenciesWorkflow", func(t *testing.T) {
+			testJobDependenciesWorkflow(t)
+		})
 		t.Run("ActionOperatorAnalyzeJob", func(t *testing.T) {
 			testActionOperatorAnalyzeJob(t, srv, cfg)
 		})
+		t.Run("InfraUsageCollector", func(t *testing.T) {
+			testInfraUsageCollector(t, cfg)
+		})
 	})
 }
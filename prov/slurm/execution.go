@@ -48,6 +48,8 @@ import (
 const home = "~"
 const batchScript = "b-%s.batch"
 const srunCommand = "srun"
+const jobNodeType = "yorc.nodes.slurm.Job"
+const jobDependencyRelationship = "yorc.relationships.slurm.JobDependency"
 
 type execution interface {
 	resolveExecution(ctx context.Context) error
@@ -127,10 +129,15 @@ func newExecution(ctx context.Context, cfg config.Configuration, taskID, deploym
 		return nil, err
 	}
 	// Create sshClient using user credentials from credentials property if the are provided, or from yorc config otherwise
-	execCommon.client, err = getSSHClient(cfg, creds, locationProps)
+	sshClient, err := getSSHClient(cfg, creds, locationProps)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.Ansible.UseOpenSSH && cfg.Ansible.SSHControlPersist > 0 {
+		// Reuse the master connection opened by Ansible to the Slurm client node during this task if any
+		sshClient.ControlPath = sshutil.ControlPath(sshutil.ControlMastersDir(taskID), sshClient.Config.User, sshClient.Host, sshClient.Port)
+	}
+	execCommon.client = sshClient
 
 	if isSingularity {
 		execSingularity := &executionSingularity{executionCommon: execCommon}
@@ -260,6 +267,9 @@ func (e *executionCommon) buildJobMonitoringAction() *prov.Action {
 	data["nodeName"] = e.NodeName
 	data["workingDir"] = e.jobInfo.WorkingDir
 	data["artifacts"] = strings.Join(e.jobInfo.Artifacts, ",")
+	if e.jobInfo.Array != "" {
+		data["array"] = e.jobInfo.Array
+	}
 
 	return &prov.Action{ActionType: "job-monitoring", Data: data}
 }
@@ -375,6 +385,13 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 		e.jobInfo.Reservation = res.RawString()
 	}
 
+	// Job array
+	if arr, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "slurm_options", "array"); err != nil {
+		return err
+	} else if arr != nil && arr.RawString() != "" {
+		e.jobInfo.Array = arr.RawString()
+	}
+
 	// Execution options
 	eo, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "execution_options")
 	if err != nil {
@@ -407,6 +424,82 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 	if envFile != nil {
 		e.jobInfo.EnvFile = envFile.RawString()
 	}
+	return e.resolveJobDependencies(ctx)
+}
+
+// resolveJobDependencies translates relationships to other Slurm jobs into Slurm dependencies
+//
+// Only jobs that are still queued or running are considered, dependencies on terminated jobs are already satisfied.
+func (e *executionCommon) resolveJobDependencies(ctx context.Context) error {
+	reqIndexes, err := deployments.GetRequirementsIndexes(ctx, e.deploymentID, e.NodeName)
+	if err != nil {
+		return err
+	}
+	depTypes := make([]string, 0)
+	depJobIDs := make(map[string][]string)
+	for _, reqIndex := range reqIndexes {
+		targetNode, err := deployments.GetTargetNodeForRequirement(ctx, e.deploymentID, e.NodeName, reqIndex)
+		if err != nil {
+			return err
+		}
+		isJob, err := deployments.IsNodeDerivedFrom(ctx, e.deploymentID, targetNode, jobNodeType)
+		if err != nil {
+			return err
+		}
+		if !isJob {
+			continue
+		}
+		relType, err := deployments.GetRelationshipForRequirement(ctx, e.deploymentID, e.NodeName, reqIndex)
+		if err != nil {
+			return err
+		}
+		isDependsOn, err := deployments.IsTypeDerivedFrom(ctx, e.deploymentID, relType, "tosca.relationships.DependsOn")
+		if err != nil {
+			return err
+		}
+		if !isDependsOn {
+			continue
+		}
+		depType := "afterok"
+		isJobDep, err := deployments.IsTypeDerivedFrom(ctx, e.deploymentID, relType, jobDependencyRelationship)
+		if err != nil {
+			return err
+		}
+		if isJobDep {
+			dt, err := deployments.GetRelationshipPropertyValueFromRequirement(ctx, e.deploymentID, e.NodeName, reqIndex, "dependency_type")
+			if err != nil {
+				return err
+			}
+			if dt != nil && dt.RawString() != "" {
+				depType = dt.RawString()
+			}
+		}
+
+		// For now we consider only instance 0 (https://github.com/ystia/yorc/issues/670)
+		state, err := deployments.GetInstanceStateString(ctx, e.deploymentID, targetNode, "0")
+		if err != nil {
+			return err
+		}
+		if !isActiveJobState(state) {
+			log.Debugf("Job %q is in state %q, no Slurm dependency added for job %q", targetNode, state, e.NodeName)
+			continue
+		}
+		id, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, targetNode, "0", "job_id")
+		if err != nil {
+			return err
+		}
+		if id == nil || id.RawString() == "" {
+			continue
+		}
+		if _, ok := depJobIDs[depType]; !ok {
+			depTypes = append(depTypes, depType)
+		}
+		depJobIDs[depType] = append(depJobIDs[depType], id.RawString())
+	}
+
+	for _, depType := range depTypes {
+		e.jobInfo.Dependencies = append(e.jobInfo.Dependencies, depType+":"+strings.Join(depJobIDs[depType], ":"))
+	}
 	return nil
 }
 
@@ -435,6 +528,12 @@ func (e *executionCommon) buildJobOpts() string {
 	if e.jobInfo.Account != "" {
 		opts += fmt.Sprintf(" --account='%s'", e.jobInfo.Account)
 	}
+	if e.jobInfo.Array != "" {
+		opts += fmt.Sprintf(" --array='%s'", e.jobInfo.Array)
+	}
+	if len(e.jobInfo.Dependencies) > 0 {
+		opts += fmt.Sprintf(" --dependency='%s'", strings.Join(e.jobInfo.Dependencies, ","))
+	}
 	log.Debugf("opts=%q", opts)
 	return opts
 }
@@ -636,7 +735,7 @@ func (e *executionCommon) resolveOperation(ctx context.Context) error {
 	}
 
 	e.Primary = strings.TrimSpace(e.Primary)
-	if e.operation.ImplementedInType == "yorc.nodes.slurm.Job" && e.Primary == "embedded" {
+	if e.operation.ImplementedInType == jobNodeType && e.Primary == "embedded" {
 		e.Primary = ""
 	}
 
@@ -688,6 +787,17 @@ func (e *executionCommon) resolveArtifacts(ctx context.Context) error {
 	var err error
 	log.Debugf("Get artifacts for node:%q", e.NodeName)
 	e.Artifacts, err = deployments.GetFileArtifactsForNode(ctx, e.deploymentID, e.NodeName)
+	if err != nil {
+		return err
+	}
+	isRemote, primaryPath, err := operations.FetchOperationRemoteArtifacts(ctx, e.OverlayPath, e.deploymentID, e.NodeName, e.operation, e.Artifacts)
+	if err != nil {
+		return err
+	}
+	// The primary implementation is uploaded from the repository cache
+	if isRemote && !e.isSingularity && e.Primary != "" {
+		e.Primary = primaryPath
+	}
 	log.Debugf("Resolved artifacts: %v", e.Artifacts)
-	return err
+	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -31,11 +32,18 @@ import (
 	"github.com/ystia/yorc/v4/tosca"
 )
 
+const defaultContainerRuntime = "singularity"
+
 type executionSingularity struct {
 	*executionCommon
 	imageURI       string
 	commandOptions []string
 	debug          bool
+	runtime        string
+	bindMounts     []string
+	gpu            string
+	envFromInputs  bool
+	imageCache     *imageCache
 }
 
 func (e *executionSingularity) execute(ctx context.Context) error {
@@ -96,15 +104,24 @@ func (e *executionSingularity) execute(ctx context.Context) error {
 }
 
 func (e *executionSingularity) prepareAndSubmitSingularityJob(ctx context.Context) error {
-	var debug, inner string
+	var debug, inner, cacheScript string
 	if e.debug {
 		debug = "-d -v"
 	}
-	cmdOpts := strings.Join(e.commandOptions, " ")
+	image := e.imageURI
+	if e.imageCache != nil && isRemoteImage(e.imageURI) {
+		var err error
+		cacheScript, err = e.imageCache.script(e.runtime, e.imageURI)
+		if err != nil {
+			return err
+		}
+		image = `"$YORC_IMAGE"`
+	}
+	cmdOpts := e.buildCommandOptions()
 	if e.jobInfo.ExecutionOptions.Command != "" {
-		inner = fmt.Sprintf("srun singularity %s exec %s %s %s %s", debug, cmdOpts, e.imageURI, e.jobInfo.ExecutionOptions.Command, quoteArgs(e.jobInfo.ExecutionOptions.Args))
+		inner = fmt.Sprintf("%ssrun %s %s exec %s %s %s %s", cacheScript, e.runtime, debug, cmdOpts, image, e.jobInfo.ExecutionOptions.Command, quoteArgs(e.jobInfo.ExecutionOptions.Args))
 	} else {
-		inner = fmt.Sprintf("srun singularity %s run %s %s", debug, cmdOpts, e.imageURI)
+		inner = fmt.Sprintf("%ssrun %s %s run %s %s", cacheScript, e.runtime, debug, cmdOpts, image)
 	}
 	cmd, err := e.wrapCommand(inner)
 	if err != nil {
@@ -125,8 +142,11 @@ func (e *executionSingularity) resolveImageURI(ctx context.Context) error {
 		if err := e.buildImageURI(ctx, "shub://"); err != nil {
 			return err
 		}
+	// Sylabs library or OCI registry image
+	case strings.HasPrefix(e.Primary, "library://"), strings.HasPrefix(e.Primary, "oras://"):
+		e.imageURI = e.Primary
 	// File image
-	case strings.HasSuffix(e.Primary, ".simg") || strings.HasSuffix(e.Primary, ".img"):
+	case strings.HasSuffix(e.Primary, ".simg") || strings.HasSuffix(e.Primary, ".img") || strings.HasSuffix(e.Primary, ".sif"):
 		e.imageURI = e.Primary
 	default:
 		return errors.Errorf("Unable to resolve image URI from image with name:%q", e.Primary)
@@ -177,5 +197,64 @@ func (e *executionSingularity) getSingularityProps(ctx context.Context) error {
 	if e.debug, err = deployments.GetBooleanNodeProperty(ctx, e.deploymentID, e.NodeName, "singularity_debug"); err != nil {
 		return err
 	}
-	return nil
+
+	// Container runtime: node property first then location property
+	if r, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "container_runtime"); err != nil {
+		return err
+	} else if r != nil && r.RawString() != "" {
+		e.runtime = r.RawString()
+	} else {
+		e.runtime = e.locationProps.GetStringOrDefault("container_runtime", defaultContainerRuntime)
+	}
+	if e.runtime != "singularity" && e.runtime != "apptainer" {
+		return errors.Errorf("unsupported container runtime %q, expecting one of singularity or apptainer", e.runtime)
+	}
+
+	if b, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "bind_mounts"); err != nil {
+		return err
+	} else if b != nil && b.RawString() != "" {
+		if err = json.Unmarshal([]byte(b.RawString()), &e.bindMounts); err != nil {
+			return err
+		}
+	}
+
+	if g, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "gpu"); err != nil {
+		return err
+	} else if g != nil {
+		e.gpu = g.RawString()
+	}
+
+	if e.envFromInputs, err = deployments.GetBooleanNodeProperty(ctx, e.deploymentID, e.NodeName, "env_from_inputs"); err != nil {
+		return err
+	}
+
+	e.imageCache, err = newImageCache(e.locationProps)
+	return err
+}
+
+func (e *executionSingularity) buildCommandOptions() string {
+	opts := make([]string, 0)
+	for _, b := range e.bindMounts {
+		opts = append(opts, "--bind "+shellQuote(b))
+	}
+	switch e.gpu {
+	case "nvidia":
+		opts = append(opts, "--nv")
+	case "rocm":
+		opts = append(opts, "--rocm")
+	}
+	if e.envFromInputs {
+		names := make([]string, 0, len(e.jobInfo.Inputs))
+		for k := range e.jobInfo.Inputs {
+			if strings.TrimSpace(k) != "" {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			opts = append(opts, "--env "+shellQuote(k+"="+e.jobInfo.Inputs[k]))
+		}
+	}
+	opts = append(opts, e.commandOptions...)
+	return strings.Join(opts, " ")
 }
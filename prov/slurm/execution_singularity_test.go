@@ -0,0 +1,53 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_executionSingularity_buildCommandOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		e        *executionSingularity
+		expected string
+	}{
+		{"NoOptions", &executionSingularity{executionCommon: &executionCommon{jobInfo: &jobInfo{}}}, ""},
+		{"AllOptions", &executionSingularity{
+			executionCommon: &executionCommon{jobInfo: &jobInfo{Inputs: map[string]string{"B_INPUT": "b", "A_INPUT": "a"}}},
+			commandOptions:  []string{"--cleanenv"},
+			bindMounts:      []string{"/scratch:/data:ro", "/home"},
+			gpu:             "nvidia",
+			envFromInputs:   true,
+		}, "--bind '/scratch:/data:ro' --bind '/home' --nv --env 'A_INPUT=a' --env 'B_INPUT=b' --cleanenv"},
+		{"QuotedValues", &executionSingularity{
+			executionCommon: &executionCommon{jobInfo: &jobInfo{Inputs: map[string]string{"MSG": "it's $HOME'; rm -rf ~ #"}}},
+			bindMounts:      []string{"/data/o'brien"},
+			envFromInputs:   true,
+		}, `--bind '/data/o'\''brien' --env 'MSG=it'\''s $HOME'\''; rm -rf ~ #'`},
+		{"InputsNotPassed", &executionSingularity{
+			executionCommon: &executionCommon{jobInfo: &jobInfo{Inputs: map[string]string{"A_INPUT": "a"}}},
+			gpu:             "rocm",
+		}, "--rocm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.e.buildCommandOptions())
+		})
+	}
+}
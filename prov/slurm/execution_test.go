@@ -178,6 +178,11 @@ func testExecutionCommonPrepareAndSubmitJob(t *testing.T) {
 					}}},
 			regexp.MustCompile(`cat <<'EOF' > ~/b-[-a-f0-9]+.batch\n#!/bin/bash\n\nsrun --mpi=pmi2 test.mpi \nEOF\nsbatch -D ~ --job-name='MyJob' --ntasks=2 --nodes=4 ~/b-[-a-f0-9]+.batch; rm -f ~/b-[-a-f0-9]+.batch`),
 			false},
+		{"CheckArrayAndDependencies",
+			fields{config.Configuration{}, config.DynamicMap{}, deploymentID, "ClassificationJobUnit_Singularity", make([]*operations.EnvInput, 0), "primary.batch",
+				&jobInfo{Name: "MyJob", Nodes: 1, WorkingDir: home, Array: "0-15%4", Dependencies: []string{"afterok:41:43", "afterany:42"}}},
+			regexp.MustCompile("sbatch -D ~ --job-name='MyJob' --nodes=1 --array='0-15%4' --dependency='afterok:41:43,afterany:42' ~/primary.batch"),
+			false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -206,3 +211,77 @@ func testExecutionCommonPrepareAndSubmitJob(t *testing.T) {
 		})
 	}
 }
+
+func testExecutionCommonResolveJobDependencies(t *testing.T) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/job_dependencies.yaml")
+	require.NoError(t, err)
+
+	setJobState := func(nodeName, state, jobID string) {
+		err := deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, "0", state)
+		require.NoError(t, err)
+		err = deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, "0", "job_id", jobID)
+		require.NoError(t, err)
+	}
+	setJobState("Preprocess", "submitted", "41")
+	setJobState("Train", "COMPLETED", "42")
+	setJobState("Cleanup", "RUNNING", "43")
+
+	tests := []struct {
+		name                 string
+		nodeName             string
+		trainState           string
+		expectedDependencies []string
+	}{
+		{"NoDependencies", "Preprocess", "COMPLETED", nil},
+		{"JobDependencyRelationship", "Train", "COMPLETED", []string{"afterany:41"}},
+		{"TerminatedJobsAreIgnored", "Report", "COMPLETED", []string{"afterok:43"}},
+		{"DependsOnRelationships", "Report", "PENDING", []string{"afterok:42:43"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJobState("Train", tt.trainState, "42")
+			e := &executionCommon{
+				deploymentID: deploymentID,
+				NodeName:     tt.nodeName,
+				jobInfo:      &jobInfo{},
+			}
+			err := e.resolveJobDependencies(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDependencies, e.jobInfo.Dependencies)
+		})
+	}
+}
+
+func testJobDependenciesWorkflow(t *testing.T) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/job_dependencies.yaml")
+	require.NoError(t, err)
+
+	wf, err := deployments.GetWorkflow(ctx, deploymentID, "run")
+	require.NoError(t, err)
+	// Train has a native dependency on Preprocess: it is submitted as soon as Preprocess is submitted
+	assert.Contains(t, wf.Steps["Preprocess_submitted"].OnSuccess, "Train_submitting")
+	assert.NotContains(t, wf.Steps["Preprocess_executed"].OnSuccess, "Train_submitting")
+	// Report uses DependsOn relationships: it still waits for its targets to be executed
+	assert.Contains(t, wf.Steps["Train_executed"].OnSuccess, "Report_submitting")
+	assert.Contains(t, wf.Steps["Cleanup_executed"].OnSuccess, "Report_submitting")
+
+	// Run the workflow up to the submission of Train: Preprocess has just been submitted
+	err = deployments.SetInstanceAttribute(ctx, deploymentID, "Preprocess", "0", "job_id", "41")
+	require.NoError(t, err)
+	err = deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, "Preprocess", "0", "submitted")
+	require.NoError(t, err)
+
+	e := &executionCommon{
+		deploymentID: deploymentID,
+		NodeName:     "Train",
+		jobInfo:      &jobInfo{},
+	}
+	err = e.resolveJobDependencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"afterany:41"}, e.jobInfo.Dependencies)
+	assert.Contains(t, e.buildJobOpts(), "--dependency='afterany:41'")
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tosca"
 	"github.com/ystia/yorc/v4/tosca/types"
 )
 
@@ -39,6 +41,8 @@ const reSbatch = `Submitted batch job (\d+)`
 
 const invalidJob = "Invalid job id specified"
 
+const sacctFormat = "JobID,JobName,Account,State,ExitCode,Elapsed,ElapsedRaw,TotalCPU,CPUTimeRAW,NCPUS,NNodes,MaxRSS,Start,End"
+
 // getSSHClient returns a SSH client with slurm credentials from node or job configuration provided by the deployment,
 // or by the yorc slurm configuration
 func getSSHClient(cfg config.Configuration, credentials *types.Credential, locationProps config.DynamicMap) (*sshutil.SSHClient, error) {
@@ -92,6 +96,25 @@ func getSSHClient(cfg config.Configuration, credentials *types.Credential, locat
 	}, nil
 }
 
+// getLocationNameForNode returns the name of the location on which a node is deployed, that is the one provided
+// in the node template metadata or the first Slurm location otherwise
+func getLocationNameForNode(ctx context.Context, locationMgr locations.Manager, deploymentID, nodeName string) (string, error) {
+	found, locationName, err := deployments.GetNodeMetadata(ctx, deploymentID, nodeName, tosca.MetadataLocationNameKey)
+	if err != nil || found {
+		return locationName, err
+	}
+	locs, err := locationMgr.GetLocations()
+	if err != nil {
+		return "", err
+	}
+	for _, loc := range locs {
+		if loc.Type == infrastructureType {
+			return loc.Name, nil
+		}
+	}
+	return "", errors.Errorf("Found no location of type %q", infrastructureType)
+}
+
 // getUserCredentials returns user credentials from a node property, or a capability property.
 // the property name is provided by propertyName parameter, and its type is supposed to be tosca.datatypes.Credential
 func getUserCredentials(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName, capabilityName string) (*types.Credential, error) {
@@ -301,6 +324,41 @@ func parseJobInfo(r io.Reader) (map[string]string, error) {
 	return data, nil
 }
 
+// parseJobsInfo parses a scontrol output containing several job records separated by blank lines
+// as returned for job arrays
+func parseJobsInfo(r io.Reader) ([]map[string]string, error) {
+	jobs := make([]map[string]string, 0)
+	var record strings.Builder
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if record.Len() > 0 {
+				data, err := parseJobInfo(strings.NewReader(record.String()))
+				if err != nil {
+					return nil, err
+				}
+				jobs = append(jobs, data)
+				record.Reset()
+			}
+			continue
+		}
+		record.WriteString(line)
+		record.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "An error occurred scanning scontrol output")
+	}
+	if record.Len() > 0 {
+		data, err := parseJobInfo(strings.NewReader(record.String()))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, data)
+	}
+	return jobs, nil
+}
+
 func parseJobID(str string, regexp *regexp.Regexp) (string, error) {
 	subMatch := regexp.FindStringSubmatch(str)
 	if subMatch != nil && len(subMatch) == 2 {
@@ -340,19 +398,206 @@ func parseKeyValue(str string) (bool, string, string) {
 }
 
 func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error) {
+	out, err := showJob(client, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return parseJobInfo(strings.NewReader(out))
+}
+
+func showJob(client sshutil.Client, jobID string) (string, error) {
 	cmd := fmt.Sprintf("scontrol show job %s", jobID)
 	output, err := client.RunCommand(cmd)
 	out := strings.Trim(output, "\" \t\n\x00")
 	if err != nil {
 		if strings.Contains(out, invalidJob) {
-			return nil, &noJobFound{msg: err.Error()}
+			return "", &noJobFound{msg: err.Error()}
 		}
-		return nil, errors.Wrap(err, out)
+		return "", errors.Wrap(err, out)
+	}
+	if out == "" {
+		return "", &noJobFound{msg: fmt.Sprintf("no information found for job with id:%q", jobID)}
 	}
-	if out != "" {
-		return parseJobInfo(strings.NewReader(out))
+	return out, nil
+}
+
+func getArrayJobTasksInfo(client sshutil.Client, jobID string) ([]map[string]string, error) {
+	out, err := showJob(client, jobID)
+	if err != nil {
+		return nil, err
 	}
-	return nil, &noJobFound{msg: fmt.Sprintf("no information found for job with id:%q", jobID)}
+	return parseJobsInfo(strings.NewReader(out))
+}
+
+// arrayJobState returns the global state of a job array and the state of each of its tasks indexed by array task ID
+//
+// A job array is considered as running as long as one of its tasks is running, then pending as long as one of its
+// tasks is pending. It is completed if all its tasks are completed, otherwise the state of the first unsuccessful task is returned.
+func arrayJobState(tasks []map[string]string) (string, map[string]string) {
+	tasksStates := make(map[string]string, len(tasks))
+	var running, pending bool
+	var failedState string
+	for _, task := range tasks {
+		taskID, ok := task["ArrayTaskId"]
+		if !ok {
+			taskID = task["JobId"]
+		}
+		state := task["JobState"]
+		tasksStates[taskID] = state
+		switch state {
+		case "COMPLETED":
+		case "RUNNING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
+			running = true
+		case "PENDING":
+			pending = true
+		default:
+			if failedState == "" {
+				failedState = state
+			}
+		}
+	}
+	switch {
+	case running:
+		return "RUNNING", tasksStates
+	case pending:
+		return "PENDING", tasksStates
+	case failedState != "":
+		return failedState, tasksStates
+	}
+	return "COMPLETED", tasksStates
+}
+
+// getJobAccounting returns the accounting data of a job retrieved using sacct
+func getJobAccounting(client sshutil.Client, jobID string) (*jobAccounting, error) {
+	cmd := fmt.Sprintf("sacct -j %s --noheader --parsable2 --format=%s", jobID, sacctFormat)
+	output, err := client.RunCommand(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, output)
+	}
+	return parseJobAccounting(strings.NewReader(output))
+}
+
+// parseJobAccounting parses a sacct parsable output using the sacctFormat fields
+//
+// Allocations lines (one per task for job arrays) are aggregated: elapsed and CPU times are summed and the state
+// is the one of the first unsuccessful task if any. The maximum resident set size is computed from job steps lines.
+func parseJobAccounting(r io.Reader) (*jobAccounting, error) {
+	var acc *jobAccounting
+	var maxRSS uint64
+	var maxRSSStr string
+	nbFields := len(strings.Split(sacctFormat, ","))
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) != nbFields {
+			return nil, errors.Errorf("unexpected sacct output line %q, expecting %d fields", line, nbFields)
+		}
+		if strings.Contains(fields[0], ".") {
+			// Job step
+			rss, err := parseSlurmSize(fields[11])
+			if err != nil {
+				return nil, err
+			}
+			if rss > maxRSS {
+				maxRSS = rss
+				maxRSSStr = fields[11]
+			}
+			continue
+		}
+		elapsedRaw, err := parseSacctInt(fields[6])
+		if err != nil {
+			return nil, err
+		}
+		cpuTimeRaw, err := parseSacctInt(fields[8])
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			ncpus, err := parseSacctInt(fields[9])
+			if err != nil {
+				return nil, err
+			}
+			nnodes, err := parseSacctInt(fields[10])
+			if err != nil {
+				return nil, err
+			}
+			acc = &jobAccounting{
+				// Remove the array task ID if any
+				JobID:    strings.SplitN(fields[0], "_", 2)[0],
+				JobName:  fields[1],
+				Account:  fields[2],
+				State:    fields[3],
+				ExitCode: fields[4],
+				Elapsed:  fields[5],
+				TotalCPU: fields[7],
+				NCPUS:    int(ncpus),
+				NNodes:   int(nnodes),
+				Start:    fields[12],
+				End:      fields[13],
+			}
+		} else {
+			if acc.State == "COMPLETED" && fields[3] != "COMPLETED" {
+				acc.State = fields[3]
+			}
+			if acc.ExitCode == "0:0" && fields[4] != "0:0" {
+				acc.ExitCode = fields[4]
+			}
+			if fields[12] < acc.Start {
+				acc.Start = fields[12]
+			}
+			if fields[13] > acc.End {
+				acc.End = fields[13]
+			}
+		}
+		acc.ElapsedRaw += elapsedRaw
+		acc.CPUTimeRaw += cpuTimeRaw
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "An error occurred scanning sacct output")
+	}
+	if acc == nil {
+		return nil, &noJobFound{msg: "no accounting data found for job"}
+	}
+	acc.MaxRSS = maxRSSStr
+	return acc, nil
+}
+
+func parseSacctInt(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	return i, errors.Wrapf(err, "unexpected sacct numeric value %q", s)
+}
+
+// parseSlurmSize converts a Slurm size (ex: 1234K, 1.5G) into bytes, Slurm uses 1024 based units
+func parseSlurmSize(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	var mult float64 = 1
+	if i := strings.IndexByte("KMGTP", s[len(s)-1]); i >= 0 {
+		mult = float64(uint64(1) << (10 * uint(i+1)))
+		s = s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unexpected Slurm size value %q", s)
+	}
+	return uint64(f * mult), nil
+}
+
+// isActiveJobState checks if a job instance state corresponds to a job submitted and not yet terminated
+func isActiveJobState(state string) bool {
+	switch state {
+	case "submitted", "executing", "RUNNING", "PENDING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
+		return true
+	}
+	return false
 }
 
 func quoteArgs(t []string) string {
@@ -367,6 +612,11 @@ func quoteArgs(t []string) string {
 	return args
 }
 
+// shellQuote quotes a string to be used as a single word in a shell script, whatever the characters it contains
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
 // Convert scalar-unit size to Kib as K for Slurm
 func toSlurmMemFormat(memStr string) (string, error) {
 	mem, err := humanize.ParseBytes(memStr)
@@ -439,3 +439,108 @@ func TestToSlurmMemFormat(t *testing.T) {
 	}
 
 }
+
+func TestParseJobsInfo(t *testing.T) {
+	t.Parallel()
+	data, err := os.Open("testdata/scontrol_show_job_array.txt")
+	require.Nil(t, err, "unexpected error while opening test file")
+	jobs, err := parseJobsInfo(data)
+	require.Nil(t, err, "unexpected error while parsing jobs info")
+	require.Len(t, jobs, 3)
+	require.Equal(t, "1", jobs[0]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "RUNNING", jobs[0]["JobState"], "unexpected value for \"JobState\" key")
+	require.Equal(t, "0", jobs[1]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "COMPLETED", jobs[1]["JobState"], "unexpected value for \"JobState\" key")
+	require.Equal(t, "2-3", jobs[2]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "JobArrayTaskLimit", jobs[2]["Reason"], "unexpected value for \"Reason\" key")
+}
+
+func TestArrayJobState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		tasks         []map[string]string
+		expectedState string
+		expectedTasks map[string]string
+	}{
+		{"RunningAndPending", []map[string]string{{"ArrayTaskId": "0", "JobState": "COMPLETED"}, {"ArrayTaskId": "1", "JobState": "RUNNING"}, {"ArrayTaskId": "2-3", "JobState": "PENDING"}},
+			"RUNNING", map[string]string{"0": "COMPLETED", "1": "RUNNING", "2-3": "PENDING"}},
+		{"FailedAndPending", []map[string]string{{"ArrayTaskId": "0", "JobState": "FAILED"}, {"ArrayTaskId": "1-3", "JobState": "PENDING"}},
+			"PENDING", map[string]string{"0": "FAILED", "1-3": "PENDING"}},
+		{"Completed", []map[string]string{{"ArrayTaskId": "0", "JobState": "COMPLETED"}, {"ArrayTaskId": "1", "JobState": "COMPLETED"}},
+			"COMPLETED", map[string]string{"0": "COMPLETED", "1": "COMPLETED"}},
+		{"Failed", []map[string]string{{"ArrayTaskId": "0", "JobState": "COMPLETED"}, {"ArrayTaskId": "1", "JobState": "TIMEOUT"}, {"ArrayTaskId": "2", "JobState": "FAILED"}},
+			"TIMEOUT", map[string]string{"0": "COMPLETED", "1": "TIMEOUT", "2": "FAILED"}},
+		{"NotAnArray", []map[string]string{{"JobId": "42", "JobState": "COMPLETED"}},
+			"COMPLETED", map[string]string{"42": "COMPLETED"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, tasksStates := arrayJobState(tt.tasks)
+			assert.Equal(t, tt.expectedState, state)
+			assert.Equal(t, tt.expectedTasks, tasksStates)
+		})
+	}
+}
+
+func TestParseJobAccounting(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		file     string
+		expected *jobAccounting
+	}{
+		{"CompletedJob", "testdata/sacct_completed.txt", &jobAccounting{JobID: "6260", JobName: "test-salloc-Environment", Account: "acc_salloc",
+			State: "COMPLETED", ExitCode: "0:0", Elapsed: "00:02:05", ElapsedRaw: 125, TotalCPU: "00:01.332", CPUTimeRaw: 500, NCPUS: 4, NNodes: 1,
+			MaxRSS: "2.5M", Start: "2019-02-22T15:41:51", End: "2019-02-22T15:43:56"}},
+		{"JobArray", "testdata/sacct_array.txt", &jobAccounting{JobID: "6261", JobName: "test-array", Account: "acc_array",
+			State: "FAILED", ExitCode: "1:0", Elapsed: "00:00:30", ElapsedRaw: 40, TotalCPU: "00:00.100", CPUTimeRaw: 80, NCPUS: 2, NNodes: 1,
+			MaxRSS: "1.2M", Start: "2019-02-22T15:41:50", End: "2019-02-22T15:42:21"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.Open(tt.file)
+			require.NoError(t, err, "unexpected error while opening test file")
+			defer data.Close()
+			acc, err := parseJobAccounting(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, acc)
+		})
+	}
+}
+
+func TestParseJobAccountingErrors(t *testing.T) {
+	t.Parallel()
+	_, err := parseJobAccounting(strings.NewReader(""))
+	require.Error(t, err)
+	require.True(t, isNoJobFoundError(err), "expected no job found error")
+
+	_, err = parseJobAccounting(strings.NewReader("6260|test|COMPLETED"))
+	require.Error(t, err)
+}
+
+func TestParseSlurmSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		size     string
+		expected uint64
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"1532K", 1532 * 1024, false},
+		{"2.5M", 5 * 512 * 1024, false},
+		{"1G", 1024 * 1024 * 1024, false},
+		{"12X", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseSlurmSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSlurmSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
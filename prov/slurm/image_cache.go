@@ -0,0 +1,172 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+// defaultImageTagRefresh is the duration after which an image referenced by a tag is pulled again
+// when no images cache max age is defined
+const defaultImageTagRefresh = time.Hour
+
+// imageCacheScript is a bash snippet included in the generated batch script. It retrieves an image from the shared
+// images cache, pulling it first if needed, and sets the YORC_IMAGE variable to the cached image path.
+//
+// Images are stored in the blobs directory keyed by their sha256 digest, so they are shared between all images
+// references resolving to the same image. The refs directory maps images URIs to those blobs.
+// A ref expires max age after the image pull while a blob expires max age after its last use.
+//
+// Images pinned by digest are pulled once. Tags are mutable so images referenced by tag are pulled again once
+// their ref is older than the refresh duration, the pulled image replaces the cached one only if its content changed.
+//
+// Jobs hold a shared lock on the eviction lock file while resolving their image, then a shared lock on the image
+// until they end. Eviction only runs when no job is resolving its image and skips images locked by running jobs.
+const imageCacheScript = `YORC_CACHE_DIR={{.QuotedDir}}
+YORC_IMAGE_REF="$YORC_CACHE_DIR/refs/{{.RefKey}}"
+mkdir -p "$YORC_CACHE_DIR/blobs" "$YORC_CACHE_DIR/refs" "$YORC_CACHE_DIR/locks"
+exec 8>"$YORC_CACHE_DIR/locks/eviction.lock"
+flock -s 8
+exec 9>"$YORC_CACHE_DIR/locks/{{.RefKey}}.lock"
+flock 9
+yorc_pull_image() {
+    YORC_TMP_IMAGE="$YORC_CACHE_DIR/blobs/pull-$$-$(date +%s).sif"
+    {{.Runtime}} pull "$YORC_TMP_IMAGE" {{.QuotedImageURI}} || { rm -f "$YORC_TMP_IMAGE"; return 1; }
+    YORC_IMAGE_DIGEST=$(sha256sum "$YORC_TMP_IMAGE" | cut -d ' ' -f 1)
+    if [ -f "$YORC_CACHE_DIR/blobs/$YORC_IMAGE_DIGEST.sif" ]; then
+        rm -f "$YORC_TMP_IMAGE"
+    else
+        mv "$YORC_TMP_IMAGE" "$YORC_CACHE_DIR/blobs/$YORC_IMAGE_DIGEST.sif"
+    fi
+    ln -sfn "../blobs/$YORC_IMAGE_DIGEST.sif" "$YORC_IMAGE_REF"
+}
+{{- if .Pinned}}
+[ -f "$YORC_IMAGE_REF" ] || yorc_pull_image || exit 1
+{{- else}}
+[ -f "$YORC_IMAGE_REF" ] && [ -n "$(find "$YORC_IMAGE_REF" -mmin -{{.RefreshMinutes}})" ] || yorc_pull_image || exit 1
+{{- end}}
+YORC_IMAGE=$(readlink -f "$YORC_IMAGE_REF")
+touch "$YORC_IMAGE"
+exec 7<"$YORC_IMAGE"
+flock -s 7
+flock -u 9
+flock -u 8
+{{- if or .MaxAgeMinutes .MaxSize}}
+(
+    flock -n 8 || exit 0
+{{- if .MaxAgeMinutes}}
+    find "$YORC_CACHE_DIR/refs" -type l -mmin +{{.MaxAgeMinutes}} -delete
+    find "$YORC_CACHE_DIR/blobs" -name '*.sif' ! -name 'pull-*' -mmin +{{.MaxAgeMinutes}} -exec flock -n -x {} rm -f {} \;
+{{- end}}
+{{- if .MaxSize}}
+    YORC_CACHE_SIZE=$(du -sb "$YORC_CACHE_DIR/blobs" | cut -f 1)
+    ls -tr "$YORC_CACHE_DIR/blobs" | while IFS= read -r f; do
+        [ "$YORC_CACHE_SIZE" -le {{.MaxSize}} ] && break
+        case "$f" in pull-*) continue ;; *.sif) ;; *) continue ;; esac
+        f="$YORC_CACHE_DIR/blobs/$f"
+        YORC_BLOB_SIZE=$(stat -c %s "$f")
+        flock -n -x "$f" rm -f "$f" && YORC_CACHE_SIZE=$((YORC_CACHE_SIZE - YORC_BLOB_SIZE))
+    done
+{{- end}}
+    find "$YORC_CACHE_DIR/refs" -xtype l -delete
+) 8>"$YORC_CACHE_DIR/locks/eviction.lock"
+{{- end}}
+`
+
+// imageCache is a cache of container images located on the Slurm cluster shared filesystem
+type imageCache struct {
+	dir     string
+	maxAge  time.Duration
+	maxSize uint64
+}
+
+// newImageCache returns the images cache configured for a location or nil if images caching is not enabled
+func newImageCache(locationProps config.DynamicMap) (*imageCache, error) {
+	dir := strings.TrimSpace(locationProps.GetString("image_cache_directory"))
+	if dir == "" {
+		return nil, nil
+	}
+	ic := &imageCache{dir: strings.TrimSuffix(dir, "/"), maxAge: locationProps.GetDuration("image_cache_max_age")}
+	if maxSize := locationProps.GetString("image_cache_max_size"); maxSize != "" {
+		var err error
+		ic.maxSize, err = humanize.ParseBytes(maxSize)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid image_cache_max_size value %q", maxSize)
+		}
+	}
+	return ic, nil
+}
+
+// script returns a bash snippet retrieving the given image from the cache using the given container runtime
+func (ic *imageCache) script(runtime, imageURI string) (string, error) {
+	refresh := ic.maxAge
+	if refresh < time.Minute {
+		refresh = defaultImageTagRefresh
+	}
+	data := struct {
+		QuotedDir      string
+		RefKey         string
+		Runtime        string
+		QuotedImageURI string
+		Pinned         bool
+		RefreshMinutes int64
+		MaxAgeMinutes  int64
+		MaxSize        uint64
+	}{
+		QuotedDir:      shellQuote(ic.dir),
+		RefKey:         fmt.Sprintf("%x", sha256.Sum256([]byte(imageURI))),
+		Runtime:        runtime,
+		QuotedImageURI: shellQuote(imageURI),
+		Pinned:         isPinnedImage(imageURI),
+		RefreshMinutes: int64(refresh / time.Minute),
+		MaxAgeMinutes:  int64(ic.maxAge / time.Minute),
+		MaxSize:        ic.maxSize,
+	}
+	tmpl, err := template.New("imageCache").Parse(imageCacheScript)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse images cache script template")
+	}
+	var buffer bytes.Buffer
+	if err = tmpl.Execute(&buffer, data); err != nil {
+		return "", errors.Wrap(err, "failed to generate images cache script")
+	}
+	return buffer.String(), nil
+}
+
+// isRemoteImage checks if an image URI references an image that should be pulled from a registry
+func isRemoteImage(imageURI string) bool {
+	for _, prefix := range []string{"docker://", "shub://", "library://", "oras://"} {
+		if strings.HasPrefix(imageURI, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPinnedImage checks if an image URI references an immutable image by its digest rather than by a tag
+func isPinnedImage(imageURI string) bool {
+	// Docker and ORAS references use "@sha256:<digest>" while library references use ":sha256.<digest>"
+	return strings.Contains(imageURI, "@sha256:") || strings.Contains(imageURI, ":sha256.")
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestNewImageCache(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		locationProps config.DynamicMap
+		expected      *imageCache
+		wantErr       bool
+	}{
+		{"CacheDisabled", config.DynamicMap{}, nil, false},
+		{"CacheWithoutEviction", config.DynamicMap{"image_cache_directory": "/shared/images/"}, &imageCache{dir: "/shared/images"}, false},
+		{"CacheWithEviction", config.DynamicMap{"image_cache_directory": "/shared/images", "image_cache_max_age": "72h", "image_cache_max_size": "100 GB"},
+			&imageCache{dir: "/shared/images", maxAge: 72 * time.Hour, maxSize: 100000000000}, false},
+		{"InvalidMaxSize", config.DynamicMap{"image_cache_directory": "/shared/images", "image_cache_max_size": "big"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newImageCache(tt.locationProps)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newImageCache() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestImageCacheScript(t *testing.T) {
+	t.Parallel()
+	ic := &imageCache{dir: "/shared/images"}
+	script, err := ic.script("apptainer", "docker://alpine:3.12")
+	require.NoError(t, err)
+	assert.Contains(t, script, `YORC_CACHE_DIR='/shared/images'`)
+	assert.Contains(t, script, `apptainer pull "$YORC_TMP_IMAGE" 'docker://alpine:3.12'`)
+	assert.Contains(t, script, `[ -n "$(find "$YORC_IMAGE_REF" -mmin -60)" ] || yorc_pull_image || exit 1`, "tagged images should be pulled again once their ref expired")
+	assert.Contains(t, script, `YORC_IMAGE=$(readlink -f "$YORC_IMAGE_REF")`)
+	assert.Contains(t, script, "exec 7<\"$YORC_IMAGE\"\nflock -s 7", "images in use should be locked")
+	assert.NotContains(t, script, "flock -n 8", "no eviction policy configured")
+
+	// Same image URI gives the same ref key
+	other, err := ic.script("apptainer", "docker://alpine:3.12")
+	require.NoError(t, err)
+	assert.Equal(t, script, other)
+
+	ic = &imageCache{dir: "/shared/images", maxAge: 90 * time.Minute, maxSize: 1024}
+	script, err = ic.script("singularity", "library://alpine")
+	require.NoError(t, err)
+	assert.Contains(t, script, `singularity pull "$YORC_TMP_IMAGE" 'library://alpine'`)
+	assert.Contains(t, script, `find "$YORC_IMAGE_REF" -mmin -90`)
+	assert.Contains(t, script, "-type l -mmin +90 -delete")
+	assert.Contains(t, script, `! -name 'pull-*' -mmin +90 -exec flock -n -x {} rm -f {} \;`)
+	assert.Contains(t, script, `flock -n -x "$f" rm -f "$f"`)
+	assert.Contains(t, script, `[ "$YORC_CACHE_SIZE" -le 1024 ] && break`)
+	assert.Contains(t, script, `8>"$YORC_CACHE_DIR/locks/eviction.lock"`)
+
+	script, err = ic.script("apptainer", "docker://alpine@sha256:a15790640a6690aa1730c38cf0a440e2aa44aaca9b0e8931a9f2b0d7cc90fd65")
+	require.NoError(t, err)
+	assert.Contains(t, script, `[ -f "$YORC_IMAGE_REF" ] || yorc_pull_image || exit 1`, "pinned images should be pulled once")
+
+	ic = &imageCache{dir: "/shared/user's images"}
+	script, err = ic.script("apptainer", "docker://alpine")
+	require.NoError(t, err)
+	assert.Contains(t, script, `YORC_CACHE_DIR='/shared/user'\''s images'`)
+}
+
+func TestIsPinnedImage(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isPinnedImage("docker://alpine@sha256:a15790640a6690aa1730c38cf0a440e2aa44aaca9b0e8931a9f2b0d7cc90fd65"))
+	assert.True(t, isPinnedImage("library://sylabsed/examples/lolcow:sha256.3ba7d2b8c7f1e1a3f5bb2c1b18a4a6fd1d82ea0e4bb2db4bd0ba1b5e3d13d30c"))
+	assert.False(t, isPinnedImage("docker://alpine:latest"))
+	assert.False(t, isPinnedImage("docker://alpine"))
+}
+
+func TestIsRemoteImage(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isRemoteImage("docker://alpine"))
+	assert.True(t, isRemoteImage("shub://vsoch/hello-world"))
+	assert.True(t, isRemoteImage("library://alpine"))
+	assert.True(t, isRemoteImage("oras://registry.example.com/image:1.0"))
+	assert.False(t, isRemoteImage("/shared/images/image.sif"))
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+// infraUsageCollector reports resources consumed by deployments jobs on a Slurm location
+// based on the accounting data recorded at the end of each job
+type infraUsageCollector struct {
+}
+
+// GetUsageInfo returns for each deployment the number of jobs, their elapsed and CPU times and the consumed core-hours.
+//
+// Supported parameters are "deployment" to restrict the report to a given deployment
+// and "details" to include the accounting data of each job.
+func (c *infraUsageCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string,
+	params map[string]string) (map[string]interface{}, error) {
+	prefix := path.Join(consulutil.InfraUsagePrefix, infrastructureType)
+	if params["deployment"] != "" {
+		prefix = path.Join(prefix, params["deployment"])
+	}
+	kvs, err := consulutil.List(prefix + "/")
+	if err != nil {
+		return nil, err
+	}
+	var details bool
+	if params["details"] != "" {
+		details, err = strconv.ParseBool(params["details"])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid details parameter %q", params["details"])
+		}
+	}
+
+	usage := make(map[string]interface{})
+	for key, value := range kvs {
+		acc := new(jobAccounting)
+		err = json.Unmarshal(value, acc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal job accounting data stored in %q", key)
+		}
+		if acc.Location != locationName {
+			continue
+		}
+		deploymentID := path.Base(path.Dir(key))
+		du, ok := usage[deploymentID].(*deploymentUsage)
+		if !ok {
+			du = &deploymentUsage{}
+			usage[deploymentID] = du
+		}
+		du.Jobs++
+		du.ElapsedRaw += acc.ElapsedRaw
+		du.CPUTimeRaw += acc.CPUTimeRaw
+		du.CoreHours = float64(du.CPUTimeRaw) / 3600
+		if details {
+			du.Details = append(du.Details, acc)
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func testInfraUsageCollector(t *testing.T, cfg config.Configuration) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	otherDeploymentID := deploymentID + "-other"
+	ctx := context.Background()
+	for _, depID := range []string{deploymentID, otherDeploymentID} {
+		err := deployments.StoreDeploymentDefinition(ctx, depID, "testdata/jobMonitoringTest.yaml")
+		require.NoError(t, err)
+	}
+
+	sacctClient := func(file string) sshutil.Client {
+		return &sshutil.MockSSHClient{
+			MockRunCommand: func(cmd string) (string, error) {
+				content, err := ioutil.ReadFile(file)
+				require.NoError(t, err)
+				return string(content), nil
+			},
+		}
+	}
+
+	o := &actionOperator{}
+	o.storeJobAccounting(ctx, sacctClient("testdata/sacct_completed.txt"), deploymentID, "Job", "0", "testSlurmLocation", "6260")
+	o.storeJobAccounting(ctx, sacctClient("testdata/sacct_array.txt"), deploymentID, "Job", "0", "testSlurmLocation", "6261")
+	o.storeJobAccounting(ctx, sacctClient("testdata/sacct_completed.txt"), otherDeploymentID, "Job", "0", "otherLocation", "6260")
+
+	value, err := deployments.GetInstanceAttributeValue(ctx, deploymentID, "Job", "0", "accounting", "cpu_time_raw")
+	require.NoError(t, err)
+	require.NotNil(t, value)
+	assert.Equal(t, "80", value.RawString())
+
+	c := &infraUsageCollector{}
+	usage, err := c.GetUsageInfo(ctx, cfg, "", infrastructureType, "testSlurmLocation", map[string]string{"deployment": deploymentID})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	du, ok := usage[deploymentID].(*deploymentUsage)
+	require.True(t, ok, "unexpected usage type %T", usage[deploymentID])
+	assert.Equal(t, 2, du.Jobs)
+	assert.Equal(t, int64(580), du.CPUTimeRaw)
+	assert.Equal(t, int64(165), du.ElapsedRaw)
+	assert.InDelta(t, 580.0/3600, du.CoreHours, 0.0001)
+	assert.Len(t, du.Details, 0)
+
+	usage, err = c.GetUsageInfo(ctx, cfg, "", infrastructureType, "otherLocation", map[string]string{"details": "true"})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	du, ok = usage[otherDeploymentID].(*deploymentUsage)
+	require.True(t, ok, "unexpected usage type %T", usage[otherDeploymentID])
+	assert.Equal(t, 1, du.Jobs)
+	require.Len(t, du.Details, 1)
+	assert.Equal(t, "6260", du.Details[0].JobID)
+	assert.Equal(t, "Job", du.Details[0].NodeName)
+
+	_, err = c.GetUsageInfo(ctx, cfg, "", infrastructureType, "otherLocation", map[string]string{"details": "notabool"})
+	require.Error(t, err)
+}
@@ -34,4 +34,5 @@ func init() {
 		}, executor, registry.BuiltinOrigin)
 
 	reg.RegisterActionOperator([]string{"job-monitoring"}, &actionOperator{}, registry.BuiltinOrigin)
+	reg.RegisterInfraUsageCollector(infrastructureType, &infraUsageCollector{}, registry.BuiltinOrigin)
 }
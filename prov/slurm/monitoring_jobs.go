@@ -27,6 +27,7 @@ import (
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/sshutil"
 	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
@@ -50,6 +51,7 @@ type actionData struct {
 	taskID     string
 	workingDir string
 	artifacts  []string
+	array      string
 }
 
 func (o *actionOperator) ExecAction(ctx context.Context, cfg config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
@@ -113,12 +115,14 @@ func getMonitoringJobActionData(action *prov.Action) (*actionData, error) {
 	if ok {
 		actionData.artifacts = strings.Split(artifactsStr, ",")
 	}
+	// Check job array indexes (optional)
+	actionData.array = action.Data["array"]
 
 	return actionData, nil
 
 }
 
-func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClient sshutil.Client, deploymentID, nodeName string, action *prov.Action, keepArtifacts bool) (bool, error) {
+func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClient sshutil.Client, deploymentID, nodeName, locationName string, action *prov.Action, keepArtifacts bool) (bool, error) {
 	var (
 		err        error
 		deregister bool
@@ -146,6 +150,28 @@ func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClie
 		return true, errors.Wrapf(err, "failed to update job attributes with jobID: %q", actionData.jobID)
 	}
 
+	if actionData.array != "" {
+		tasks, err := getArrayJobTasksInfo(sshClient, actionData.jobID)
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to get job array tasks info with jobID:%q", actionData.jobID)
+		}
+		var tasksStates map[string]string
+		info["JobState"], tasksStates = arrayJobState(tasks)
+		err = deployments.SetInstanceAttributeComplex(ctx, deploymentID, nodeName, instanceName, "array_tasks_states", tasksStates)
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to update job array tasks states with jobID: %q", actionData.jobID)
+		}
+	}
+
+	if info["JobState"] == "PENDING" && info["Reason"] == "DependencyNeverSatisfied" {
+		// The job will never run as one of the jobs it depends on failed: cancel it instead of keeping it pending forever
+		if err = cancelJobID(actionData.jobID, sshClient); err != nil {
+			log.Printf("failed to cancel job %q which dependencies will never be satisfied: %+v", actionData.jobID, err)
+		}
+		deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, instanceName, "CANCELLED")
+		return true, errors.Errorf("job with ID:%q has been cancelled as its dependencies can never be satisfied", actionData.jobID)
+	}
+
 	var mess string
 	if info["Reason"] != "None" {
 		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Reason:%s, Execution Time:%s", info["JobName"], info["JobId"], info["JobState"], info["Reason"], info["RunTime"])
@@ -196,6 +222,10 @@ func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClie
 		err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q", actionData.jobID, info["JobState"])
 	}
 
+	if deregister {
+		o.storeJobAccounting(ctx, sshClient, deploymentID, nodeName, instanceName, locationName, actionData.jobID)
+	}
+
 	// cleanup except if error occurred or explicitly specified in config
 	if deregister && err == nil {
 		if !keepArtifacts {
@@ -213,10 +243,14 @@ func (o *actionOperator) monitorJob(ctx context.Context, cfg config.Configuratio
 	nodeName := action.Data["nodeName"]
 
 	var locationProps config.DynamicMap
+	var locationName string
 	locationMgr, err := locations.GetManager(cfg)
 	if err == nil {
 		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
 	}
+	if err == nil {
+		locationName, err = getLocationNameForNode(ctx, locationMgr, deploymentID, nodeName)
+	}
 	if err != nil {
 		return true, err
 	}
@@ -237,8 +271,49 @@ func (o *actionOperator) monitorJob(ctx context.Context, cfg config.Configuratio
 		return true, err
 	}
 
-	return o.analyzeJob(ctx, cc, sshClient, deploymentID, nodeName, action, locationProps.GetBool("keep_job_remote_artifacts"))
+	return o.analyzeJob(ctx, cc, sshClient, deploymentID, nodeName, locationName, action, locationProps.GetBool("keep_job_remote_artifacts"))
+
+}
+
+// storeJobAccounting retrieves the accounting data of a terminated job, sets them as the job instance accounting attribute
+// and records them for infrastructure usage reporting.
+//
+// Failures are only logged as accounting may not be enabled on the Slurm cluster.
+func (o *actionOperator) storeJobAccounting(ctx context.Context, sshClient sshutil.Client, deploymentID, nodeName, instanceName, locationName, jobID string) {
+	acc, err := getJobAccounting(sshClient, jobID)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to retrieve accounting data for job with ID:%q: %v", jobID, err)
+		return
+	}
+	acc.NodeName = nodeName
+	acc.Location = locationName
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf(
+		"Job accounting ID:%s, State:%s, ExitCode:%s, Elapsed:%s, CPU Time:%s (%.2f core-hours), Max RSS:%s",
+		acc.JobID, acc.State, acc.ExitCode, acc.Elapsed, acc.TotalCPU, float64(acc.CPUTimeRaw)/3600, acc.MaxRSS)
+
+	err = deployments.SetInstanceAttributeComplex(ctx, deploymentID, nodeName, instanceName, "accounting", map[string]string{
+		"job_id":       acc.JobID,
+		"state":        acc.State,
+		"exit_code":    acc.ExitCode,
+		"elapsed":      acc.Elapsed,
+		"elapsed_raw":  strconv.FormatInt(acc.ElapsedRaw, 10),
+		"total_cpu":    acc.TotalCPU,
+		"cpu_time_raw": strconv.FormatInt(acc.CPUTimeRaw, 10),
+		"ncpus":        strconv.Itoa(acc.NCPUS),
+		"nnodes":       strconv.Itoa(acc.NNodes),
+		"max_rss":      acc.MaxRSS,
+		"start":        acc.Start,
+		"end":          acc.End,
+	})
+	if err != nil {
+		log.Printf("failed to set accounting attribute for job %q: %+v", jobID, err)
+	}
 
+	err = consulutil.StoreConsulKeyWithJSONValue(path.Join(consulutil.InfraUsagePrefix, infrastructureType, deploymentID, acc.JobID), acc)
+	if err != nil {
+		log.Printf("failed to store accounting data for job %q: %+v", jobID, err)
+	}
 }
 
 func (o *actionOperator) removeArtifacts(actionData *actionData, sshClient sshutil.Client) {
@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	ctu "github.com/hashicorp/consul/testutil"
@@ -79,6 +80,14 @@ func testActionOperatorAnalyzeJob(t *testing.T, srv *ctu.TestServer, cfg config.
 			"taskID":     "t1",
 			"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
 		}}, keepArtifacts: false}, "scontrol_show_job_failed.txt", true, true},
+		{"MonitorRunningJobArray", args{deploymentID: deploymentID, nodeName: "Job", action: &prov.Action{ActionType: "job-monitoring", Data: map[string]string{
+			"nodeName":   "Job",
+			"jobID":      "6261",
+			"stepName":   "run",
+			"taskID":     "t1",
+			"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
+			"array":      "0-3%2",
+		}}, keepArtifacts: false}, "scontrol_show_job_array.txt", false, false},
 		{"JobNotFound", args{deploymentID: deploymentID, nodeName: "Job", action: &prov.Action{ActionType: "job-monitoring", Data: map[string]string{
 			"nodeName":   "Job",
 			"jobID":      "6260",
@@ -93,6 +102,11 @@ func testActionOperatorAnalyzeJob(t *testing.T, srv *ctu.TestServer, cfg config.
 
 			sshClient := &sshutil.MockSSHClient{
 				MockRunCommand: func(input string) (string, error) {
+					if strings.HasPrefix(input, "sacct ") {
+						testdataFileContent, err := ioutil.ReadFile(filepath.Join("testdata", "sacct_completed.txt"))
+						assert.NilError(t, err)
+						return string(testdataFileContent), nil
+					}
 					if tt.jobInfoFile != "" {
 						testdataFile := filepath.Join("testdata", tt.jobInfoFile)
 						testdataFileContent, err := ioutil.ReadFile(testdataFile)
@@ -103,7 +117,7 @@ func testActionOperatorAnalyzeJob(t *testing.T, srv *ctu.TestServer, cfg config.
 				},
 			}
 
-			got, err := o.analyzeJob(context.Background(), cc, sshClient, tt.args.deploymentID, tt.args.nodeName, tt.args.action, tt.args.keepArtifacts)
+			got, err := o.analyzeJob(context.Background(), cc, sshClient, tt.args.deploymentID, tt.args.nodeName, "testSlurmLocation", tt.args.action, tt.args.keepArtifacts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("actionOperator.analyzeJob() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -54,4 +54,35 @@ type jobInfo struct {
 	WorkingDir             string                      `json:"working_directory,omitempty"`
 	Artifacts              []string                    `json:"artifacts,omitempty"`
 	EnvFile                string                      `json:"env_file,omitempty"`
+	Array                  string                      `json:"array,omitempty"`
+	Dependencies           []string                    `json:"dependencies,omitempty"`
+}
+
+// jobAccounting holds Slurm accounting data (sacct) of a terminated job
+type jobAccounting struct {
+	JobID      string `json:"job_id"`
+	NodeName   string `json:"node_name"`
+	Location   string `json:"location,omitempty"`
+	JobName    string `json:"job_name,omitempty"`
+	Account    string `json:"account,omitempty"`
+	State      string `json:"state,omitempty"`
+	ExitCode   string `json:"exit_code,omitempty"`
+	Elapsed    string `json:"elapsed,omitempty"`
+	ElapsedRaw int64  `json:"elapsed_raw"`
+	TotalCPU   string `json:"total_cpu,omitempty"`
+	CPUTimeRaw int64  `json:"cpu_time_raw"`
+	NCPUS      int    `json:"ncpus"`
+	NNodes     int    `json:"nnodes"`
+	MaxRSS     string `json:"max_rss,omitempty"`
+	Start      string `json:"start,omitempty"`
+	End        string `json:"end,omitempty"`
+}
+
+// deploymentUsage sums up Slurm resources consumed by a deployment jobs
+type deploymentUsage struct {
+	Jobs       int              `json:"jobs"`
+	ElapsedRaw int64            `json:"elapsed_raw"`
+	CPUTimeRaw int64            `json:"cpu_time_raw"`
+	CoreHours  float64          `json:"core_hours"`
+	Details    []*jobAccounting `json:"details,omitempty"`
 }
@@ -44,6 +44,7 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 	instancesKey := path.Join(instancesPrefix, nodeName)
 
 	instance.Tags.Name = cfg.ResourcesPrefix + nodeName + "-" + instanceName
+	instance.Tags.Others = commons.AddResourceTags(cfg, deploymentID, nodeName, nil)
 
 	// image_id is mandatory
 	image, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "image_id")
@@ -75,18 +76,33 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 	}
 	instance.KeyName = keyName.RawString()
 
-	// security_groups needs to contain a least one occurrence
+	// Security groups are either existing ones referenced by name in security_groups
+	// or ones defined in the topology and bound through security_group requirements
 	secGroups, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "security_groups")
 	if err != nil {
 		return err
 	}
-	if secGroups == nil || secGroups.RawString() == "" {
-		return errors.Errorf("Missing mandatory parameter 'security_groups' node type for %s", nodeName)
+	if secGroups != nil && secGroups.RawString() != "" {
+		for _, secGroup := range strings.Split(strings.NewReplacer("\"", "", "'", "").Replace(secGroups.RawString()), ",") {
+			secGroup = strings.TrimSpace(secGroup)
+			instance.SecurityGroups = append(instance.SecurityGroups, secGroup)
+		}
+	}
+	instance.VPCSecurityGroupIDs, err = getSecurityGroupsIDs(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
 	}
 
-	for _, secGroup := range strings.Split(strings.NewReplacer("\"", "", "'", "").Replace(secGroups.RawString()), ",") {
-		secGroup = strings.TrimSpace(secGroup)
-		instance.SecurityGroups = append(instance.SecurityGroups, secGroup)
+	// Optional subnet in which the instance is created
+	hasSubnet, subnetNode, err := deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "network", "yorc.nodes.aws.Subnet")
+	if err != nil {
+		return err
+	}
+	if hasSubnet {
+		instance.SubnetID, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, subnetNode, "0", "subnet_id")
+		if err != nil {
+			return err
+		}
 	}
 
 	// Get connection info (user, private key)
@@ -141,17 +157,53 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 	if placementGroup != nil {
 		instance.PlacementGroup = placementGroup.RawString()
 	}
-	// Add the AWS instance
-	commons.AddResource(infrastructure, "aws_instance", instance.Tags.Name, &instance)
+
+	// Optional cloud-init configuration or script run at instance launch
+	if instance.UserData, err = commons.GetUserData(ctx, cfg, deploymentID, nodeName); err != nil {
+		return err
+	}
+
+	// Spot instances are requested instead of on-demand ones when enabled
+	spot, err := deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "spot")
+	if err != nil {
+		return err
+	}
+	resourceType := "aws_instance"
+	instanceIDAttr := "id"
+	if spot {
+		spotRequest := &SpotInstanceRequest{
+			ComputeInstance:               instance,
+			SpotType:                      "one-time",
+			InstanceInterruptionBehaviour: "terminate",
+			WaitForFulfillment:            true,
+		}
+		spotRequest.SpotPrice, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "spot_max_price", false)
+		if err != nil {
+			return err
+		}
+		resourceType = "aws_spot_instance_request"
+		instanceIDAttr = "spot_instance_id"
+		commons.AddResource(infrastructure, resourceType, instance.Tags.Name, spotRequest)
+	} else {
+		// Add the AWS instance
+		commons.AddResource(infrastructure, resourceType, instance.Tags.Name, &instance)
+	}
+	instanceRef := resourceType + "." + instance.Tags.Name
+	instanceID := fmt.Sprintf("${%s.%s}", instanceRef, instanceIDAttr)
 
 	// Provide private_address attribute output
 	privateIPKey := nodeName + "-" + instanceName + "-privateIP"
-	commons.AddOutput(infrastructure, privateIPKey, &commons.Output{Value: fmt.Sprintf("${aws_instance.%s.private_ip}", instance.Tags.Name)})
+	commons.AddOutput(infrastructure, privateIPKey, &commons.Output{Value: fmt.Sprintf("${%s.private_ip}", instanceRef)})
 	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = privateIPKey
 
+	// Provide instance_id attribute output
+	instanceIDKey := nodeName + "-" + instanceName + "-instanceID"
+	commons.AddOutput(infrastructure, instanceIDKey, &commons.Output{Value: instanceID})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/instance_id")] = instanceIDKey
+
 	// Provide specific DNS attribute attribute
 	publicDNSKey := nodeName + "-" + instanceName + "-publicDNS"
-	commons.AddOutput(infrastructure, publicDNSKey, &commons.Output{Value: fmt.Sprintf("${aws_instance.%s.public_dns}", instance.Tags.Name)})
+	commons.AddOutput(infrastructure, publicDNSKey, &commons.Output{Value: fmt.Sprintf("${%s.public_dns}", instanceRef)})
 	outputs[path.Join(instancesKey, instanceName, "/attributes/public_dns")] = publicDNSKey
 
 	// If any Elastic IP is provided without any network requirement, EIP is anyway associated to the compute instance
@@ -172,14 +224,14 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 			// Find the associated EIP in the list in function of the association index
 			ind := getEIPAssociationIndex(infrastructure, cfg.ResourcesPrefix+nodeName)
 			if ind < len(instance.ElasticIps) {
-				eipAssociationName = associateEIP(infrastructure, &instance, instance.ElasticIps[ind])
+				eipAssociationName = associateEIP(infrastructure, &instance, instanceID, instance.ElasticIps[ind])
 			} else {
 				// Not enough provided EIPs, rest of EIP is generated
-				eipAssociationName = associateEIP(infrastructure, &instance, "")
+				eipAssociationName = associateEIP(infrastructure, &instance, instanceID, "")
 			}
 		} else {
 			// No provided EIP
-			eipAssociationName = associateEIP(infrastructure, &instance, "")
+			eipAssociationName = associateEIP(infrastructure, &instance, instanceID, "")
 		}
 	}
 
@@ -188,7 +240,7 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 	if eipAssociationName != "" {
 		accessIP = fmt.Sprintf("${aws_eip_association.%s.public_ip}", eipAssociationName)
 	} else {
-		accessIP = fmt.Sprintf("${aws_instance.%s.public_ip}", instance.Tags.Name)
+		accessIP = fmt.Sprintf("${%s.public_ip}", instanceRef)
 	}
 
 	// public_address/ ip_address output attributes
@@ -205,10 +257,10 @@ func (g *awsGenerator) generateAWSInstance(ctx context.Context, cfg config.Confi
 		return err
 	}
 
-	return addAttachedDisks(ctx, cfg, deploymentID, nodeName, instanceName, instance.Tags.Name, infrastructure, outputs)
+	return addAttachedDisks(ctx, cfg, deploymentID, nodeName, instanceName, instanceID, infrastructure, outputs)
 }
 
-func addAttachedDisks(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, instanceName, instanceTagName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+func addAttachedDisks(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, instanceName, instanceID string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
 	storageReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "local_storage")
 	if err != nil {
 		return err
@@ -232,7 +284,7 @@ func addAttachedDisks(ctx context.Context, cfg config.Configuration, deploymentI
 
 		attachedDisk := &VolumeAttachment{
 			DeviceName: deviceName,
-			InstanceID: fmt.Sprintf("${aws_instance.%s.id}", instanceTagName),
+			InstanceID: instanceID,
 			VolumeID:   volumeID,
 		}
 
@@ -252,9 +304,9 @@ func addAttachedDisks(ctx context.Context, cfg config.Configuration, deploymentI
 	return nil
 }
 
-func associateEIP(infrastructure *commons.Infrastructure, instance *ComputeInstance, providedEIP string) string {
+func associateEIP(infrastructure *commons.Infrastructure, instance *ComputeInstance, instanceID, providedEIP string) string {
 	// Add the association EIP/Instance
-	eipAssociation := ElasticIPAssociation{InstanceID: fmt.Sprintf("${aws_instance.%s.id}", instance.Tags.Name)}
+	eipAssociation := ElasticIPAssociation{InstanceID: instanceID}
 	log.Printf("Adding ElasticIP association for instance name:%s", instance.Tags.Name)
 	if providedEIP != "" {
 		// Use existing EIP
@@ -264,7 +316,7 @@ func associateEIP(infrastructure *commons.Infrastructure, instance *ComputeInsta
 		// Add the EIP
 		log.Printf("Adding ElasticIP for instance name:%s", instance.Tags.Name)
 		elasticIPName := "EIP-" + instance.Tags.Name
-		elasticIP := ElasticIP{}
+		elasticIP := ElasticIP{Tags: instance.Tags.Others}
 		commons.AddResource(infrastructure, "aws_eip", elasticIPName, &elasticIP)
 
 		eipAssociation.AllocationID = fmt.Sprintf("${aws_eip.%s.id}", elasticIPName)
@@ -100,6 +100,7 @@ func testSimpleAWSInstance(t *testing.T, cfg config.Configuration) {
 	require.Equal(t, "t2.micro", compute.InstanceType)
 	require.Equal(t, "ComputeAWS-0", compute.Tags.Name)
 	require.Equal(t, "us-east-2c", compute.AvailabilityZone)
+	require.Equal(t, "#cloud-config\npackages:\n  - nginx\n", compute.UserData)
 	require.Equal(t, "myPlacement", compute.PlacementGroup)
 	require.Equal(t, true, compute.RootBlockDevice.DeleteOnTermination)
 	require.Len(t, compute.SecurityGroups, 1)
@@ -127,7 +128,8 @@ func testSimpleAWSInstance(t *testing.T, cfg config.Configuration) {
 
 	require.NotContains(t, infrastructure.Resource, "aws_eip_association")
 
-	require.Len(t, outputs, 6, "six outputs are expected")
+	require.Len(t, outputs, 7, "seven outputs are expected")
+	require.Contains(t, outputs, path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/", "ComputeAWS", "0", "attributes/instance_id"), "expected instance_id instance attribute output")
 	require.Contains(t, outputs, path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/", "ComputeAWS", "0", "attributes/public_address"), "expected public_address instance attribute output")
 	require.Contains(t, outputs, path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/", "ComputeAWS", "0", "attributes/public_dns"), "expected public_dns instance attribute output")
 	require.Contains(t, outputs, path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/", "ComputeAWS", "0", "attributes/public_ip_address"), "expected public_ip_address instance attribute output")
@@ -136,6 +138,38 @@ func testSimpleAWSInstance(t *testing.T, cfg config.Configuration) {
 	require.Contains(t, outputs, path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/", "ComputeAWS", "0", "/capabilities/endpoint/attributes/ip_address"), "expected capability endpoint ip_address instance attribute output")
 }
 
+func testSimpleAWSSpotInstance(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	g := awsGenerator{}
+	infrastructure := commons.Infrastructure{}
+	env := make([]string, 0)
+	outputs := make(map[string]string, 0)
+	err := g.generateAWSInstance(context.Background(), cfg, deploymentID, "ComputeAWS", "0", &infrastructure, outputs, &env)
+	require.Nil(t, err)
+
+	require.NotContains(t, infrastructure.Resource, "aws_instance")
+	require.Len(t, infrastructure.Resource["aws_spot_instance_request"], 1)
+	requestsMap := infrastructure.Resource["aws_spot_instance_request"].(map[string]interface{})
+	spotRequest, ok := requestsMap["ComputeAWS-0"].(*SpotInstanceRequest)
+	require.True(t, ok, "ComputeAWS-0 is not a SpotInstanceRequest")
+	assert.Equal(t, "ami-16dffe73", spotRequest.ImageID)
+	assert.Equal(t, "t2.micro", spotRequest.InstanceType)
+	assert.Equal(t, "0.0035", spotRequest.SpotPrice)
+	assert.Equal(t, "one-time", spotRequest.SpotType)
+	assert.Equal(t, "terminate", spotRequest.InstanceInterruptionBehaviour)
+	assert.True(t, spotRequest.WaitForFulfillment)
+
+	require.Contains(t, infrastructure.Output, "ComputeAWS-0-instanceID")
+	assert.Equal(t, "${aws_spot_instance_request.ComputeAWS-0.spot_instance_id}", infrastructure.Output["ComputeAWS-0-instanceID"].Value)
+	require.Contains(t, infrastructure.Output, "ComputeAWS-0-privateIP")
+	assert.Equal(t, "${aws_spot_instance_request.ComputeAWS-0.private_ip}", infrastructure.Output["ComputeAWS-0-privateIP"].Value)
+
+	interruptible, err := g.IsInterruptible(context.Background(), cfg, deploymentID, "ComputeAWS")
+	require.NoError(t, err)
+	assert.True(t, interruptible)
+}
+
 func testSimpleAWSInstanceWithPrivateKey(t *testing.T, cfg config.Configuration) {
 	t.Parallel()
 	deploymentID := loadTestYaml(t)
@@ -373,3 +407,24 @@ func testSimpleAWSInstanceWithPersistentDisk(t *testing.T, cfg config.Configurat
 	assert.Equal(t, "/dev/sdf", attachedDisk.DeviceName)
 
 }
+
+func testSimpleAWSInstanceWithSubnetAndSecurityGroup(t *testing.T, cfg config.Configuration, srv *testutil.TestServer) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/Subnet/0/attributes/subnet_id"):           []byte("subnet-1"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/SecGroup/0/attributes/security_group_id"): []byte("sg-1"),
+	})
+	g := awsGenerator{}
+	infrastructure := commons.Infrastructure{}
+	env := make([]string, 0)
+	err := g.generateAWSInstance(context.Background(), cfg, deploymentID, "ComputeAWS", "0", &infrastructure, make(map[string]string), &env)
+	require.NoError(t, err)
+
+	compute, ok := infrastructure.Resource["aws_instance"].(map[string]interface{})["ComputeAWS-0"].(*ComputeInstance)
+	require.True(t, ok, "ComputeAWS-0 is not a ComputeInstance")
+	assert.Len(t, compute.SecurityGroups, 0)
+	assert.Equal(t, []string{"sg-1"}, compute.VPCSecurityGroupIDs)
+	assert.Equal(t, "subnet-1", compute.SubnetID)
+	require.NotContains(t, infrastructure.Resource, "aws_eip_association")
+}
@@ -43,6 +43,9 @@ func TestRunConsulAWSPackageTests(t *testing.T) {
 		t.Run("simpleAWSInstanceWithPrivateKey", func(t *testing.T) {
 			testSimpleAWSInstanceWithPrivateKey(t, cfg)
 		})
+		t.Run("simpleAWSSpotInstance", func(t *testing.T) {
+			testSimpleAWSSpotInstance(t, cfg)
+		})
 		t.Run("simpleAWSInstanceFailed", func(t *testing.T) {
 			testSimpleAWSInstanceFailed(t, cfg)
 		})
@@ -76,5 +79,20 @@ func TestRunConsulAWSPackageTests(t *testing.T) {
 		t.Run("simpleAWSInstanceWithPersistentDisk", func(t *testing.T) {
 			testSimpleAWSInstanceWithPersistentDisk(t, cfg, srv)
 		})
+		t.Run("simpleAWSInstanceWithSubnetAndSecurityGroup", func(t *testing.T) {
+			testSimpleAWSInstanceWithSubnetAndSecurityGroup(t, cfg, srv)
+		})
+		t.Run("simpleVPC", func(t *testing.T) {
+			testSimpleVPC(t, cfg)
+		})
+		t.Run("simpleSubnet", func(t *testing.T) {
+			testSimpleSubnet(t, srv, cfg)
+		})
+		t.Run("simpleSecurityGroup", func(t *testing.T) {
+			testSimpleSecurityGroup(t, cfg)
+		})
+		t.Run("simpleLoadBalancer", func(t *testing.T) {
+			testSimpleLoadBalancer(t, srv, cfg)
+		})
 	})
 }
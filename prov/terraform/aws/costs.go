@@ -0,0 +1,82 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// GetPricedResources returns the resources billed for each instance of a node
+func (g *awsGenerator) GetPricedResources(ctx context.Context, deploymentID, nodeName string) ([]commons.PricedResource, error) {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	switch nodeType {
+	case "yorc.nodes.aws.Compute":
+		instanceType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "instance_type", false)
+		if err != nil {
+			return nil, err
+		}
+		spot, err := deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "spot")
+		if err != nil {
+			return nil, err
+		}
+		kind := commons.ComputeResourceKind
+		if spot {
+			kind = commons.SpotComputeResourceKind
+		}
+		resources := []commons.PricedResource{{Kind: kind, Type: instanceType}}
+
+		// An Elastic IP is associated to the instance if provided or if a public network is required
+		eips, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "elastic_ips", false)
+		if err != nil {
+			return nil, err
+		}
+		isElasticIP := eips != ""
+		if !isElasticIP {
+			isElasticIP, _, err = deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "network", "yorc.nodes.aws.PublicNetwork")
+			if err != nil {
+				return nil, err
+			}
+		}
+		if isElasticIP {
+			resources = append(resources, commons.PricedResource{Kind: commons.PublicIPResourceKind})
+		}
+		return resources, nil
+	case "yorc.nodes.aws.EBSVolume":
+		volumeType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "volume_type", false)
+		if err != nil {
+			return nil, err
+		}
+		if volumeType == "" {
+			volumeType = "standard"
+		}
+		return commons.GetStoragePricedResources(ctx, deploymentID, nodeName, volumeType)
+	case "yorc.nodes.aws.LoadBalancer":
+		lbType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "load_balancer_type", false)
+		if err != nil {
+			return nil, err
+		}
+		if lbType == "" {
+			lbType = "application"
+		}
+		return []commons.PricedResource{{Kind: commons.LoadBalancerResourceKind, Type: lbType}}, nil
+	}
+	return nil, nil
+}
@@ -21,6 +21,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/sizeutil"
@@ -28,7 +29,7 @@ import (
 	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
-func (g *awsGenerator) generateEBS(ctx context.Context, nodeParams nodeParams, instanceName string, instanceID int, outputs map[string]string) error {
+func (g *awsGenerator) generateEBS(ctx context.Context, cfg config.Configuration, nodeParams nodeParams, instanceName string, instanceID int, outputs map[string]string) error {
 	err := verifyThatNodeIsTypeOf(ctx, nodeParams, "yorc.nodes.aws.EBSVolume")
 	if err != nil {
 		return err
@@ -63,6 +64,8 @@ func (g *awsGenerator) generateEBS(ctx context.Context, nodeParams nodeParams, i
 		return err
 	}
 
+	ebs.Tags = commons.AddResourceTags(cfg, nodeParams.deploymentID, nodeParams.nodeName, ebs.Tags)
+
 	// Create the name for the resource
 	name := strings.ToLower(nodeParams.nodeName + "-" + instanceName)
 	commons.AddResource(nodeParams.infrastructure, "aws_ebs_volume", name, ebs)
@@ -37,7 +37,7 @@ func testSimpleEBS(t *testing.T, cfg config.Configuration) {
 		infrastructure: &infrastructure,
 	}
 
-	err := g.generateEBS(ctx, nodeParams, "0", 0, outputs)
+	err := g.generateEBS(ctx, cfg, nodeParams, "0", 0, outputs)
 	if err != nil {
 		panic(err)
 	}
@@ -60,9 +60,11 @@ func testSimpleEBS(t *testing.T, cfg config.Configuration) {
 	assert.Equal(t, "500", ebsvolume.IOPS)
 	assert.Equal(t, "standard", ebsvolume.Type)
 	assert.NotNil(t, ebsvolume.Tags, "volume tags are not expected to be null")
-	assert.Equal(t, 2, len(ebsvolume.Tags))
+	assert.Equal(t, 4, len(ebsvolume.Tags))
 	assert.Equal(t, "foo", ebsvolume.Tags["tag1"])
 	assert.Equal(t, "bar", ebsvolume.Tags["tag2"])
+	assert.Equal(t, deploymentID, ebsvolume.Tags[commons.DeploymentIDTagName])
+	assert.Equal(t, "EBSVolume", ebsvolume.Tags[commons.NodeNameTagName])
 }
 
 func testSimpleEBSWithVolumeID(t *testing.T, cfg config.Configuration) {
@@ -78,7 +80,7 @@ func testSimpleEBSWithVolumeID(t *testing.T, cfg config.Configuration) {
 		infrastructure: &infrastructure,
 	}
 
-	err := g.generateEBS(ctx, nodeParams, "0", 0, outputs)
+	err := g.generateEBS(ctx, cfg, nodeParams, "0", 0, outputs)
 	if err != nil {
 		panic(err)
 	}
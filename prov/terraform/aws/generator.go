@@ -19,14 +19,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"path"
 	"path/filepath"
 
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
-	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov/terraform/commons"
@@ -46,7 +44,6 @@ type nodeParams struct {
 func (g *awsGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, map[string]string, []string, commons.PostApplyCallback, error) {
 	log.Debugf("Generating infrastructure for deployment with id %s", deploymentID)
 
-	terraformStateKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-state", nodeName)
 	infrastructure := commons.Infrastructure{}
 
 	nodeParams := &nodeParams{
@@ -64,8 +61,11 @@ func (g *awsGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg co
 		return false, nil, nil, nil, err
 	}
 
-	// Remote Configuration for Terraform State to store it in the Consul KV store
-	infrastructure.Terraform = commons.GetBackendConfiguration(terraformStateKey, cfg)
+	// Remote Configuration for Terraform State to store it in the backend defined for the location
+	infrastructure.Terraform, err = commons.GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
 
 	cmdEnv := []string{
 		fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", locationProps.GetString("access_key")),
@@ -136,8 +136,28 @@ func (g *awsGenerator) generateInstances(ctx context.Context, cfg config.Configu
 			}
 		case "yorc.nodes.aws.PublicNetwork":
 			// Nothing to do
+		case "yorc.nodes.aws.VPC":
+			err = g.generateVPC(ctx, cfg, *nodeParams, outputs)
+			if err != nil {
+				return err
+			}
+		case "yorc.nodes.aws.Subnet":
+			err = g.generateSubnet(ctx, cfg, *nodeParams, outputs)
+			if err != nil {
+				return err
+			}
+		case "yorc.nodes.aws.SecurityGroup":
+			err = g.generateSecurityGroup(ctx, cfg, *nodeParams, outputs)
+			if err != nil {
+				return err
+			}
+		case "yorc.nodes.aws.LoadBalancer":
+			err = g.generateLoadBalancer(ctx, cfg, *nodeParams, outputs)
+			if err != nil {
+				return err
+			}
 		case "yorc.nodes.aws.EBSVolume":
-			err = g.generateEBS(ctx, *nodeParams, instanceName, instNb, outputs)
+			err = g.generateEBS(ctx, cfg, *nodeParams, instanceName, instNb, outputs)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,213 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// ec2Endpoint returns the EC2 API endpoint of a region, overridden in tests
+var ec2Endpoint = func(region string) string {
+	return fmt.Sprintf("https://ec2.%s.amazonaws.com/", region)
+}
+
+const ec2APIVersion = "2016-11-15"
+
+type describeInstancesResponse struct {
+	Reservations []struct {
+		Instances []struct {
+			InstanceID string `xml:"instanceId"`
+			State      struct {
+				Name string `xml:"name"`
+			} `xml:"instanceState"`
+		} `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+}
+
+type ec2ErrorResponse struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+}
+
+// IsInterruptible returns true for Computes requested as spot instances
+func (g *awsGenerator) IsInterruptible(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (bool, error) {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil || nodeType != "yorc.nodes.aws.Compute" {
+		return false, err
+	}
+	return deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "spot")
+}
+
+// GetInterruptedInstances returns the instances which EC2 instance is no longer pending or running.
+// A spot instance reclaimed by AWS is terminated, and is no more known by EC2 after a while.
+func (g *awsGenerator) GetInterruptedInstances(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instances []string) ([]string, error) {
+	locationProps, err := commons.GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	client := &ec2Client{
+		region:    locationProps.GetString("region"),
+		accessKey: locationProps.GetString("access_key"),
+		secretKey: locationProps.GetString("secret_key"),
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}
+
+	var interrupted []string
+	for _, instance := range instances {
+		ec2ID, err := deployments.GetInstanceAttributeValue(ctx, deploymentID, nodeName, instance, "instance_id")
+		if err != nil {
+			return nil, err
+		}
+		if ec2ID == nil || ec2ID.RawString() == "" {
+			continue
+		}
+		// Instances are described one by one as a single unknown ID fails the whole request
+		state, err := client.getInstanceState(ctx, ec2ID.RawString())
+		if err != nil {
+			return nil, err
+		}
+		if state != "pending" && state != "running" {
+			interrupted = append(interrupted, instance)
+		}
+	}
+	return interrupted, nil
+}
+
+// ec2Client is a minimal EC2 Query API client signing requests with AWS Signature Version 4
+type ec2Client struct {
+	region    string
+	accessKey string
+	secretKey string
+	http      *http.Client
+}
+
+// getInstanceState returns the state name of an EC2 instance, or an empty string if it does not exist
+func (c *ec2Client) getInstanceState(ctx context.Context, instanceID string) (string, error) {
+	params := url.Values{}
+	params.Set("Action", "DescribeInstances")
+	params.Set("Version", ec2APIVersion)
+	params.Set("InstanceId.1", instanceID)
+
+	req, err := c.newSignedRequest(ctx, params, time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to describe EC2 instance")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read EC2 response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := ec2ErrorResponse{}
+		if xml.Unmarshal(body, &errResp) == nil && len(errResp.Errors) > 0 {
+			if errResp.Errors[0].Code == "InvalidInstanceID.NotFound" {
+				return "", nil
+			}
+			return "", errors.Errorf("failed to describe EC2 instance %q: %s: %s", instanceID, errResp.Errors[0].Code, errResp.Errors[0].Message)
+		}
+		return "", errors.Errorf("failed to describe EC2 instance %q: %s", instanceID, resp.Status)
+	}
+
+	result := describeInstancesResponse{}
+	if err = xml.Unmarshal(body, &result); err != nil {
+		return "", errors.Wrap(err, "failed to parse EC2 response")
+	}
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.InstanceID == instanceID {
+				return instance.State.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (c *ec2Client) newSignedRequest(ctx context.Context, params url.Values, now time.Time) (*http.Request, error) {
+	endpoint, err := url.Parse(ec2Endpoint(c.region))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid EC2 endpoint")
+	}
+	// Signature requires spaces encoded as %20, url.Values encodes keys in sorted order
+	endpoint.RawQuery = strings.Replace(params.Encode(), "+", "%20", -1)
+	if endpoint.Path == "" {
+		endpoint.Path = "/"
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create EC2 request")
+	}
+	req = req.WithContext(ctx)
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	payloadHash := sha256.Sum256(nil)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		endpoint.EscapedPath(),
+		endpoint.RawQuery,
+		"host:" + endpoint.Host + "\n" + "x-amz-date:" + amzDate + "\n",
+		"host;x-amz-date",
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := strings.Join([]string{date, c.region, "ec2", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + c.secretKey)
+	for _, part := range []string{date, c.region, "ec2", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-date, Signature=%s", c.accessKey, scope, signature))
+	return req, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEC2ClientGetInstanceState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DescribeInstances", r.URL.Query().Get("Action"))
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Date"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=myAccessKey/"), "unexpected Authorization header %q", r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-2/ec2/aws4_request, SignedHeaders=host;x-amz-date, Signature=")
+
+		switch r.URL.Query().Get("InstanceId.1") {
+		case "i-running":
+			w.Write([]byte(`<DescribeInstancesResponse><reservationSet><item><instancesSet><item>
+<instanceId>i-running</instanceId><instanceState><code>16</code><name>running</name></instanceState>
+</item></instancesSet></item></reservationSet></DescribeInstancesResponse>`))
+		case "i-terminated":
+			w.Write([]byte(`<DescribeInstancesResponse><reservationSet><item><instancesSet><item>
+<instanceId>i-terminated</instanceId><instanceState><code>48</code><name>terminated</name></instanceState>
+</item></instancesSet></item></reservationSet></DescribeInstancesResponse>`))
+		case "i-unknown":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<Response><Errors><Error><Code>InvalidInstanceID.NotFound</Code><Message>not found</Message></Error></Errors></Response>`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`<Response><Errors><Error><Code>AuthFailure</Code><Message>denied</Message></Error></Errors></Response>`))
+		}
+	}))
+	defer server.Close()
+
+	oldEndpoint := ec2Endpoint
+	defer func() { ec2Endpoint = oldEndpoint }()
+	ec2Endpoint = func(region string) string { return server.URL }
+
+	client := &ec2Client{region: "us-east-2", accessKey: "myAccessKey", secretKey: "mySecretKey", http: &http.Client{Timeout: 5 * time.Second}}
+	tests := []struct {
+		instanceID string
+		want       string
+		wantErr    bool
+	}{
+		{"i-running", "running", false},
+		{"i-terminated", "terminated", false},
+		{"i-unknown", "", false},
+		{"i-denied", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.instanceID, func(t *testing.T) {
+			got, err := client.getInstanceState(context.Background(), tt.instanceID)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+const (
+	applicationLoadBalancer = "application"
+	networkLoadBalancer     = "network"
+)
+
+type lbListener struct {
+	port            int
+	protocol        string
+	targetPort      int
+	targetProtocol  string
+	healthCheckPath string
+	certificateARN  string
+}
+
+func (g *awsGenerator) generateLoadBalancer(ctx context.Context, cfg config.Configuration, nodeParams nodeParams, outputs map[string]string) error {
+	err := verifyThatNodeIsTypeOf(ctx, nodeParams, "yorc.nodes.aws.LoadBalancer")
+	if err != nil {
+		return err
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, nodeParams.deploymentID, "topology", "nodes", nodeParams.nodeName)
+
+	lb := &LoadBalancer{}
+	if lb.LoadBalancerType, err = deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "load_balancer_type", false); err != nil {
+		return err
+	}
+	if lb.LoadBalancerType == "" {
+		lb.LoadBalancerType = applicationLoadBalancer
+	}
+	if lb.LoadBalancerType != applicationLoadBalancer && lb.LoadBalancerType != networkLoadBalancer {
+		return errors.Errorf("unsupported load balancer type %q for node %q, expecting %q or %q", lb.LoadBalancerType, nodeParams.nodeName, applicationLoadBalancer, networkLoadBalancer)
+	}
+	if lb.Internal, err = deployments.GetBooleanNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "internal"); err != nil {
+		return err
+	}
+	if lb.Tags, err = getTags(ctx, cfg, nodeParams); err != nil {
+		return err
+	}
+
+	// Subnets of the load balancer, target groups are created in the VPC of these subnets
+	subnetReqs, err := deployments.GetRequirementsByTypeForNode(ctx, nodeParams.deploymentID, nodeParams.nodeName, "subnet")
+	if err != nil {
+		return err
+	}
+	if len(subnetReqs) == 0 {
+		return errors.Errorf("load balancer %q requires at least one subnet", nodeParams.nodeName)
+	}
+	for _, req := range subnetReqs {
+		subnetID, err := deployments.LookupInstanceAttributeValue(ctx, nodeParams.deploymentID, req.Node, "0", "subnet_id")
+		if err != nil {
+			return err
+		}
+		lb.Subnets = append(lb.Subnets, subnetID)
+	}
+	vpcID, err := deployments.LookupInstanceAttributeValue(ctx, nodeParams.deploymentID, subnetReqs[0].Node, "0", "vpc_id")
+	if err != nil {
+		return err
+	}
+
+	// Security groups are only supported by application load balancers
+	if lb.LoadBalancerType == applicationLoadBalancer {
+		if lb.SecurityGroups, err = getSecurityGroupsIDs(ctx, nodeParams.deploymentID, nodeParams.nodeName); err != nil {
+			return err
+		}
+	}
+
+	listeners, err := getLoadBalancerListeners(ctx, nodeParams, lb.LoadBalancerType)
+	if err != nil {
+		return err
+	}
+	targetReqs, err := deployments.GetRequirementsByTypeForNode(ctx, nodeParams.deploymentID, nodeParams.nodeName, "targets")
+	if err != nil {
+		return err
+	}
+
+	name := getResourceName(nodeParams)
+	log.Debugf("Add load balancer:%+v", lb)
+	commons.AddResource(nodeParams.infrastructure, "aws_lb", name, lb)
+
+	for i, listener := range listeners {
+		targetGroupName := fmt.Sprintf("%s-%d", name, i)
+		targetGroup := &LBTargetGroup{
+			Port:     listener.targetPort,
+			Protocol: listener.targetProtocol,
+			VPCID:    vpcID,
+			Tags:     lb.Tags,
+		}
+		if listener.healthCheckPath != "" {
+			targetGroup.HealthCheck = &LBHealthCheck{Path: listener.healthCheckPath, Protocol: listener.targetProtocol}
+		}
+		commons.AddResource(nodeParams.infrastructure, "aws_lb_target_group", targetGroupName, targetGroup)
+		targetGroupARN := fmt.Sprintf("${aws_lb_target_group.%s.arn}", targetGroupName)
+
+		commons.AddResource(nodeParams.infrastructure, "aws_lb_listener", targetGroupName, &LBListener{
+			LoadBalancerARN: fmt.Sprintf("${aws_lb.%s.arn}", name),
+			Port:            listener.port,
+			Protocol:        listener.protocol,
+			CertificateARN:  listener.certificateARN,
+			DefaultAction:   LBListenAction{Type: "forward", TargetGroupARN: targetGroupARN},
+		})
+
+		// Register instances of each target compute
+		for _, req := range targetReqs {
+			instances, err := deployments.GetNodeInstancesIds(ctx, nodeParams.deploymentID, req.Node)
+			if err != nil {
+				return err
+			}
+			for _, instanceName := range instances {
+				instanceID, err := deployments.LookupInstanceAttributeValue(ctx, nodeParams.deploymentID, req.Node, instanceName, "instance_id")
+				if err != nil {
+					return err
+				}
+				attachmentName := strings.ToLower(fmt.Sprintf("%s-%s-%s", targetGroupName, strings.Replace(req.Node, "_", "-", -1), instanceName))
+				commons.AddResource(nodeParams.infrastructure, "aws_lb_target_group_attachment", attachmentName, &LBTargetGroupAttachment{
+					TargetGroupARN: targetGroupARN,
+					TargetID:       instanceID,
+					Port:           listener.targetPort,
+				})
+			}
+		}
+	}
+
+	dnsNameKey := nodeParams.nodeName + "-dns-name"
+	commons.AddOutput(nodeParams.infrastructure, dnsNameKey, &commons.Output{Value: fmt.Sprintf("${aws_lb.%s.dns_name}", name)})
+	outputs[path.Join(nodeKey, "/attributes/dns_name")] = dnsNameKey
+	arnKey := nodeParams.nodeName + "-arn"
+	commons.AddOutput(nodeParams.infrastructure, arnKey, &commons.Output{Value: fmt.Sprintf("${aws_lb.%s.arn}", name)})
+	outputs[path.Join(nodeKey, "/attributes/arn")] = arnKey
+	return nil
+}
+
+func getLoadBalancerListeners(ctx context.Context, nodeParams nodeParams, lbType string) ([]lbListener, error) {
+	listenersValue, err := deployments.GetNodePropertyValue(ctx, nodeParams.deploymentID, nodeParams.nodeName, "listeners")
+	if err != nil {
+		return nil, err
+	}
+	if listenersValue == nil || listenersValue.RawString() == "" {
+		return nil, errors.Errorf("load balancer %q requires at least one listener", nodeParams.nodeName)
+	}
+	list, ok := listenersValue.Value.([]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve yorc.datatypes.aws.LoadBalancerListener Tosca Value: not expected type")
+	}
+
+	defaultProtocol := "HTTP"
+	if lbType == networkLoadBalancer {
+		defaultProtocol = "TCP"
+	}
+	listeners := make([]lbListener, 0, len(list))
+	for i := range list {
+		ind := strconv.Itoa(i)
+		var port, targetPort string
+		listener := lbListener{}
+		stringParams := []struct {
+			pAttr     *string
+			key       string
+			mandatory bool
+		}{
+			{&port, "port", true},
+			{&listener.protocol, "protocol", false},
+			{&targetPort, "target_port", false},
+			{&listener.targetProtocol, "target_protocol", false},
+			{&listener.healthCheckPath, "health_check_path", false},
+			{&listener.certificateARN, "certificate_arn", false},
+		}
+		for _, stringParam := range stringParams {
+			val, err := deployments.GetNodePropertyValue(ctx, nodeParams.deploymentID, nodeParams.nodeName, "listeners", ind, stringParam.key)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				*stringParam.pAttr = val.RawString()
+			}
+			if stringParam.mandatory && *stringParam.pAttr == "" {
+				return nil, errors.Errorf("missing mandatory %q value for listener %d of load balancer %q", stringParam.key, i, nodeParams.nodeName)
+			}
+		}
+
+		if listener.port, err = strconv.Atoi(port); err != nil {
+			return nil, errors.Wrapf(err, "invalid port value for listener %d of load balancer %q", i, nodeParams.nodeName)
+		}
+		listener.targetPort = listener.port
+		if targetPort != "" {
+			if listener.targetPort, err = strconv.Atoi(targetPort); err != nil {
+				return nil, errors.Wrapf(err, "invalid target_port value for listener %d of load balancer %q", i, nodeParams.nodeName)
+			}
+		}
+		listener.protocol = strings.ToUpper(listener.protocol)
+		if listener.protocol == "" {
+			listener.protocol = defaultProtocol
+		}
+		listener.targetProtocol = strings.ToUpper(listener.targetProtocol)
+		if listener.targetProtocol == "" {
+			listener.targetProtocol = listener.protocol
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// getSecurityGroupsIDs returns the IDs of security groups a node is bound to through security_group requirements
+func getSecurityGroupsIDs(ctx context.Context, deploymentID, nodeName string) ([]string, error) {
+	reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "security_group")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, req := range reqs {
+		id, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, req.Node, "0", "security_group_id")
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
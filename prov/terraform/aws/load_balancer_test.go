@@ -0,0 +1,84 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleLoadBalancer(t *testing.T, srv *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances")
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(instancesPrefix, "Subnet/0/attributes/subnet_id"):           []byte("subnet-1"),
+		path.Join(instancesPrefix, "Subnet/0/attributes/vpc_id"):              []byte("vpc-123456"),
+		path.Join(instancesPrefix, "SecGroup/0/attributes/security_group_id"): []byte("sg-1"),
+		path.Join(instancesPrefix, "ComputeAWS/0/attributes/instance_id"):     []byte("i-0"),
+		path.Join(instancesPrefix, "ComputeAWS/1/attributes/instance_id"):     []byte("i-1"),
+	})
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := awsGenerator{}
+	nodeParams := nodeParams{
+		deploymentID:   deploymentID,
+		nodeName:       "LB",
+		infrastructure: &infrastructure,
+	}
+
+	err := g.generateLoadBalancer(context.Background(), cfg, nodeParams, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate load balancer for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["aws_lb"], 1, "Expected one load balancer")
+	lb, ok := infrastructure.Resource["aws_lb"].(map[string]interface{})["lb"].(*LoadBalancer)
+	require.True(t, ok, "lb is not a LoadBalancer")
+	assert.Equal(t, "application", lb.LoadBalancerType)
+	assert.Equal(t, false, lb.Internal)
+	assert.Equal(t, []string{"subnet-1"}, lb.Subnets)
+	assert.Equal(t, []string{"sg-1"}, lb.SecurityGroups)
+
+	targetGroup, ok := infrastructure.Resource["aws_lb_target_group"].(map[string]interface{})["lb-0"].(*LBTargetGroup)
+	require.True(t, ok, "lb-0 is not a LBTargetGroup")
+	assert.Equal(t, &LBTargetGroup{Port: 8080, Protocol: "HTTP", VPCID: "vpc-123456", HealthCheck: &LBHealthCheck{Path: "/health", Protocol: "HTTP"}, Tags: lb.Tags}, targetGroup)
+
+	listener, ok := infrastructure.Resource["aws_lb_listener"].(map[string]interface{})["lb-0"].(*LBListener)
+	require.True(t, ok, "lb-0 is not a LBListener")
+	assert.Equal(t, 80, listener.Port)
+	assert.Equal(t, "HTTP", listener.Protocol)
+	assert.Equal(t, "${aws_lb.lb.arn}", listener.LoadBalancerARN)
+	assert.Equal(t, LBListenAction{Type: "forward", TargetGroupARN: "${aws_lb_target_group.lb-0.arn}"}, listener.DefaultAction)
+
+	attachmentsMap := infrastructure.Resource["aws_lb_target_group_attachment"].(map[string]interface{})
+	require.Len(t, attachmentsMap, 2)
+	for _, instance := range []string{"0", "1"} {
+		attachment, ok := attachmentsMap["lb-0-computeaws-"+instance].(*LBTargetGroupAttachment)
+		require.True(t, ok, "missing attachment for instance %s", instance)
+		assert.Equal(t, "i-"+instance, attachment.TargetID)
+		assert.Equal(t, 8080, attachment.Port)
+	}
+
+	dnsNameKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/LB/attributes/dns_name")]
+	assert.Equal(t, "${aws_lb.lb.dns_name}", infrastructure.Output[dnsNameKey].Value)
+}
@@ -14,21 +14,36 @@
 
 package aws
 
+import "encoding/json"
+
 // A ComputeInstance represent an AWS compute
 type ComputeInstance struct {
-	ImageID          string      `json:"ami,omitempty"`
-	InstanceType     string      `json:"instance_type,omitempty"`
-	AvailabilityZone string      `json:"availability_zone,omitempty"`
-	PlacementGroup   string      `json:"placement_group,omitempty"`
-	SecurityGroups   []string    `json:"security_groups,omitempty"`
-	KeyName          string      `json:"key_name,omitempty"`
-	Tags             Tags        `json:"tags,omitempty"`
-	ElasticIps       []string    `json:"-"`
-	RootBlockDevice  BlockDevice `json:"root_block_device,omitempty"`
+	ImageID             string      `json:"ami,omitempty"`
+	InstanceType        string      `json:"instance_type,omitempty"`
+	AvailabilityZone    string      `json:"availability_zone,omitempty"`
+	PlacementGroup      string      `json:"placement_group,omitempty"`
+	SecurityGroups      []string    `json:"security_groups,omitempty"`
+	VPCSecurityGroupIDs []string    `json:"vpc_security_group_ids,omitempty"`
+	SubnetID            string      `json:"subnet_id,omitempty"`
+	KeyName             string      `json:"key_name,omitempty"`
+	Tags                Tags        `json:"tags,omitempty"`
+	ElasticIps          []string    `json:"-"`
+	RootBlockDevice     BlockDevice `json:"root_block_device,omitempty"`
+	UserData            string      `json:"user_data,omitempty"`
 
 	Provisioners map[string]interface{} `json:"provisioner,omitempty"`
 }
 
+// A SpotInstanceRequest represents an AWS compute requested as a spot instance
+// see : https://www.terraform.io/docs/providers/aws/r/spot_instance_request.html
+type SpotInstanceRequest struct {
+	ComputeInstance
+	SpotPrice                     string `json:"spot_price,omitempty"`
+	SpotType                      string `json:"spot_type,omitempty"`
+	InstanceInterruptionBehaviour string `json:"instance_interruption_behaviour,omitempty"`
+	WaitForFulfillment            bool   `json:"wait_for_fulfillment"`
+}
+
 // A BlockDevice represents an AWS device block volume
 type BlockDevice struct {
 	DeleteOnTermination bool `json:"delete_on_termination"`
@@ -37,10 +52,25 @@ type BlockDevice struct {
 // Tags represent a mapping of tags assigned to the Instance.
 type Tags struct {
 	Name string `json:"Name,omitempty"`
+	// Others are the tags assigned in addition to the Name one
+	Others map[string]string `json:"-"`
+}
+
+// MarshalJSON marshals tags as a single mapping
+func (t Tags) MarshalJSON() ([]byte, error) {
+	tags := make(map[string]string, len(t.Others)+1)
+	for k, v := range t.Others {
+		tags[k] = v
+	}
+	if t.Name != "" {
+		tags["Name"] = t.Name
+	}
+	return json.Marshal(tags)
 }
 
 // ElasticIP represents the AWS Elastic IP resource
 type ElasticIP struct {
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // ElasticIPAssociation represents the ElasticIP/ComputeInstance association
@@ -71,3 +101,110 @@ type VolumeAttachment struct {
 	InstanceID string `json:"instance_id,omitempty"`
 	VolumeID   string `json:"volume_id,omitempty"`
 }
+
+// VPC represents an AWS Virtual Private Cloud
+// see : https://www.terraform.io/docs/providers/aws/r/vpc.html
+type VPC struct {
+	CIDRBlock          string            `json:"cidr_block,omitempty"`
+	InstanceTenancy    string            `json:"instance_tenancy,omitempty"`
+	EnableDNSSupport   bool              `json:"enable_dns_support"`
+	EnableDNSHostnames bool              `json:"enable_dns_hostnames"`
+	Tags               map[string]string `json:"tags,omitempty"`
+}
+
+// InternetGateway provides a way for instances of a VPC to reach the Internet
+// see : https://www.terraform.io/docs/providers/aws/r/internet_gateway.html
+type InternetGateway struct {
+	VPCID string            `json:"vpc_id,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// Route represents an entry of a VPC routing table
+// see : https://www.terraform.io/docs/providers/aws/r/route.html
+type Route struct {
+	RouteTableID         string `json:"route_table_id,omitempty"`
+	DestinationCIDRBlock string `json:"destination_cidr_block,omitempty"`
+	GatewayID            string `json:"gateway_id,omitempty"`
+}
+
+// Subnet represents a VPC subnet
+// see : https://www.terraform.io/docs/providers/aws/r/subnet.html
+type Subnet struct {
+	VPCID               string            `json:"vpc_id,omitempty"`
+	CIDRBlock           string            `json:"cidr_block,omitempty"`
+	AvailabilityZone    string            `json:"availability_zone,omitempty"`
+	MapPublicIPOnLaunch bool              `json:"map_public_ip_on_launch"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+// SecurityGroup represents a VPC security group
+// Rules are defined as separate SecurityGroupRule resources
+// see : https://www.terraform.io/docs/providers/aws/r/security_group.html
+type SecurityGroup struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	VPCID       string            `json:"vpc_id,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// SecurityGroupRule represents an ingress or egress rule of a security group
+// see : https://www.terraform.io/docs/providers/aws/r/security_group_rule.html
+type SecurityGroupRule struct {
+	Type            string   `json:"type"`
+	FromPort        int      `json:"from_port"`
+	ToPort          int      `json:"to_port"`
+	Protocol        string   `json:"protocol"`
+	CIDRBlocks      []string `json:"cidr_blocks,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	SecurityGroupID string   `json:"security_group_id"`
+}
+
+// LoadBalancer represents an Application or a Network Load Balancer
+// see : https://www.terraform.io/docs/providers/aws/r/lb.html
+type LoadBalancer struct {
+	LoadBalancerType string            `json:"load_balancer_type,omitempty"`
+	Internal         bool              `json:"internal"`
+	Subnets          []string          `json:"subnets,omitempty"`
+	SecurityGroups   []string          `json:"security_groups,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// LBTargetGroup represents a group of targets a load balancer routes requests to
+// see : https://www.terraform.io/docs/providers/aws/r/lb_target_group.html
+type LBTargetGroup struct {
+	Port        int               `json:"port"`
+	Protocol    string            `json:"protocol"`
+	VPCID       string            `json:"vpc_id,omitempty"`
+	HealthCheck *LBHealthCheck    `json:"health_check,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// LBHealthCheck defines how targets of a target group are checked
+type LBHealthCheck struct {
+	Path     string `json:"path,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// LBListener represents a load balancer listener forwarding requests to a target group
+// see : https://www.terraform.io/docs/providers/aws/r/lb_listener.html
+type LBListener struct {
+	LoadBalancerARN string         `json:"load_balancer_arn"`
+	Port            int            `json:"port"`
+	Protocol        string         `json:"protocol"`
+	CertificateARN  string         `json:"certificate_arn,omitempty"`
+	DefaultAction   LBListenAction `json:"default_action"`
+}
+
+// LBListenAction is the action of a listener
+type LBListenAction struct {
+	Type           string `json:"type"`
+	TargetGroupARN string `json:"target_group_arn"`
+}
+
+// LBTargetGroupAttachment registers an instance into a target group
+// see : https://www.terraform.io/docs/providers/aws/r/lb_target_group_attachment.html
+type LBTargetGroupAttachment struct {
+	TargetGroupARN string `json:"target_group_arn"`
+	TargetID       string `json:"target_id"`
+	Port           int    `json:"port,omitempty"`
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *awsGenerator) generateSecurityGroup(ctx context.Context, cfg config.Configuration, nodeParams nodeParams, outputs map[string]string) error {
+	err := verifyThatNodeIsTypeOf(ctx, nodeParams, "yorc.nodes.aws.SecurityGroup")
+	if err != nil {
+		return err
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, nodeParams.deploymentID, "topology", "nodes", nodeParams.nodeName)
+
+	secGroup := &SecurityGroup{Name: cfg.ResourcesPrefix + nodeParams.nodeName}
+	if secGroup.Description, err = deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "description", false); err != nil {
+		return err
+	}
+	if secGroup.VPCID, err = getVPCID(ctx, nodeParams); err != nil {
+		return err
+	}
+	if secGroup.Tags, err = getTags(ctx, cfg, nodeParams); err != nil {
+		return err
+	}
+
+	name := getResourceName(nodeParams)
+	log.Debugf("Add security group:%+v", secGroup)
+	commons.AddResource(nodeParams.infrastructure, "aws_security_group", name, secGroup)
+	secGroupID := fmt.Sprintf("${aws_security_group.%s.id}", name)
+
+	// Ingress rules allowing to reach endpoints of components hosted on computes using this security group
+	ingressCIDRBlocks, err := deployments.GetStringArrayNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "ingress_cidr_blocks")
+	if err != nil {
+		return err
+	}
+	endpointRules, err := commons.GetEndpointsPortRules(ctx, nodeParams.deploymentID, "security_group", nodeParams.nodeName)
+	if err != nil {
+		return err
+	}
+	for _, r := range endpointRules {
+		commons.AddResource(nodeParams.infrastructure, "aws_security_group_rule", fmt.Sprintf("%s-ingress-%s-%d", name, r.Protocol, r.Port), &SecurityGroupRule{
+			Type:            "ingress",
+			FromPort:        r.Port,
+			ToPort:          r.Port,
+			Protocol:        r.Protocol,
+			CIDRBlocks:      ingressCIDRBlocks,
+			SecurityGroupID: secGroupID,
+		})
+	}
+
+	// Rules explicitly defined on the security group
+	rules, err := getSecurityGroupRules(ctx, nodeParams)
+	if err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		rule.SecurityGroupID = secGroupID
+		commons.AddResource(nodeParams.infrastructure, "aws_security_group_rule", fmt.Sprintf("%s-rule-%d", name, i), rule)
+	}
+
+	// Outgoing traffic is allowed
+	commons.AddResource(nodeParams.infrastructure, "aws_security_group_rule", name+"-egress", &SecurityGroupRule{
+		Type:            "egress",
+		FromPort:        0,
+		ToPort:          0,
+		Protocol:        "-1",
+		CIDRBlocks:      []string{"0.0.0.0/0"},
+		SecurityGroupID: secGroupID,
+	})
+
+	secGroupIDKey := nodeParams.nodeName + "-security-group-id"
+	commons.AddOutput(nodeParams.infrastructure, secGroupIDKey, &commons.Output{Value: secGroupID})
+	outputs[path.Join(nodeKey, "/attributes/security_group_id")] = secGroupIDKey
+	return nil
+}
+
+func getSecurityGroupRules(ctx context.Context, nodeParams nodeParams) ([]*SecurityGroupRule, error) {
+	rulesValue, err := deployments.GetNodePropertyValue(ctx, nodeParams.deploymentID, nodeParams.nodeName, "rules")
+	if err != nil || rulesValue == nil || rulesValue.RawString() == "" {
+		return nil, err
+	}
+	list, ok := rulesValue.Value.([]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve yorc.datatypes.aws.SecurityGroupRule Tosca Value: not expected type")
+	}
+
+	rules := make([]*SecurityGroupRule, 0, len(list))
+	for i := range list {
+		ind := strconv.Itoa(i)
+		rule := &SecurityGroupRule{}
+		var fromPort, toPort, cidrBlocks string
+		stringParams := []struct {
+			pAttr     *string
+			key       string
+			mandatory bool
+		}{
+			{&rule.Type, "type", false},
+			{&rule.Protocol, "protocol", false},
+			{&rule.Description, "description", false},
+			{&fromPort, "from_port", true},
+			{&toPort, "to_port", false},
+			{&cidrBlocks, "cidr_blocks", false},
+		}
+		for _, stringParam := range stringParams {
+			val, err := deployments.GetNodePropertyValue(ctx, nodeParams.deploymentID, nodeParams.nodeName, "rules", ind, stringParam.key)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				*stringParam.pAttr = val.RawString()
+			}
+			if stringParam.mandatory && *stringParam.pAttr == "" {
+				return nil, errors.Errorf("missing mandatory %q value for rule %d of security group %q", stringParam.key, i, nodeParams.nodeName)
+			}
+		}
+
+		if rule.Type == "" {
+			rule.Type = "ingress"
+		}
+		if rule.Protocol == "" {
+			rule.Protocol = "tcp"
+		}
+		if rule.FromPort, err = strconv.Atoi(fromPort); err != nil {
+			return nil, errors.Wrapf(err, "invalid from_port value for rule %d of security group %q", i, nodeParams.nodeName)
+		}
+		rule.ToPort = rule.FromPort
+		if toPort != "" {
+			if rule.ToPort, err = strconv.Atoi(toPort); err != nil {
+				return nil, errors.Wrapf(err, "invalid to_port value for rule %d of security group %q", i, nodeParams.nodeName)
+			}
+		}
+		if cidrBlocks == "" {
+			cidrBlocks = "0.0.0.0/0"
+		}
+		for _, cidr := range strings.Split(cidrBlocks, ",") {
+			rule.CIDRBlocks = append(rule.CIDRBlocks, strings.TrimSpace(cidr))
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
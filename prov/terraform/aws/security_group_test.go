@@ -0,0 +1,71 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleSecurityGroup(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := awsGenerator{}
+	nodeParams := nodeParams{
+		deploymentID:   deploymentID,
+		nodeName:       "SecGroup",
+		infrastructure: &infrastructure,
+	}
+
+	err := g.generateSecurityGroup(context.Background(), cfg, nodeParams, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate security group for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["aws_security_group"], 1, "Expected one security group")
+	secGroup, ok := infrastructure.Resource["aws_security_group"].(map[string]interface{})["secgroup"].(*SecurityGroup)
+	require.True(t, ok, "secgroup is not a SecurityGroup")
+	assert.Equal(t, cfg.ResourcesPrefix+"SecGroup", secGroup.Name)
+	assert.Equal(t, "Managed by Yorc", secGroup.Description)
+	assert.Equal(t, "vpc-123456", secGroup.VPCID)
+
+	secGroupID := "${aws_security_group.secgroup.id}"
+	cidrBlocks := []string{"10.0.0.0/8", "192.168.0.0/16"}
+	expectedRules := map[string]*SecurityGroupRule{
+		"secgroup-ingress-tcp-22":   {Type: "ingress", FromPort: 22, ToPort: 22, Protocol: "tcp", CIDRBlocks: cidrBlocks, SecurityGroupID: secGroupID},
+		"secgroup-ingress-tcp-8080": {Type: "ingress", FromPort: 8080, ToPort: 8080, Protocol: "tcp", CIDRBlocks: cidrBlocks, SecurityGroupID: secGroupID},
+		"secgroup-ingress-udp-9000": {Type: "ingress", FromPort: 9000, ToPort: 9000, Protocol: "udp", CIDRBlocks: cidrBlocks, SecurityGroupID: secGroupID},
+		"secgroup-rule-0":           {Type: "ingress", FromPort: 8000, ToPort: 8010, Protocol: "tcp", CIDRBlocks: []string{"10.1.0.0/24"}, SecurityGroupID: secGroupID},
+		"secgroup-rule-1":           {Type: "egress", FromPort: 53, ToPort: 53, Protocol: "udp", CIDRBlocks: []string{"0.0.0.0/0"}, SecurityGroupID: secGroupID},
+		"secgroup-egress":           {Type: "egress", FromPort: 0, ToPort: 0, Protocol: "-1", CIDRBlocks: []string{"0.0.0.0/0"}, SecurityGroupID: secGroupID},
+	}
+	rulesMap := infrastructure.Resource["aws_security_group_rule"].(map[string]interface{})
+	require.Len(t, rulesMap, len(expectedRules))
+	for name, expected := range expectedRules {
+		require.Contains(t, rulesMap, name)
+		assert.Equal(t, expected, rulesMap[name], "unexpected rule %q", name)
+	}
+
+	secGroupIDKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/SecGroup/attributes/security_group_id")]
+	assert.Equal(t, secGroupID, infrastructure.Output[secGroupIDKey].Value)
+}
@@ -16,8 +16,13 @@ package aws
 
 import (
 	"context"
+	"strings"
+
 	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
 func verifyThatNodeIsTypeOf(ctx context.Context, nodeParams nodeParams, nodeType string) error {
@@ -30,3 +35,50 @@ func verifyThatNodeIsTypeOf(ctx context.Context, nodeParams nodeParams, nodeType
 	}
 	return nil
 }
+
+// getResourceName returns a Terraform resource name for a given node
+func getResourceName(nodeParams nodeParams) string {
+	return strings.Replace(strings.ToLower(nodeParams.nodeName), "_", "-", -1)
+}
+
+// getTags returns the tags to assign to a resource, from the tags property of the node.
+// A Name tag is added unless it is defined in this property, as well as the tags identifying the resource.
+func getTags(ctx context.Context, cfg config.Configuration, nodeParams nodeParams) (map[string]string, error) {
+	tags := make(map[string]string)
+	tagsVal, err := deployments.GetNodePropertyValue(ctx, nodeParams.deploymentID, nodeParams.nodeName, "tags")
+	if err != nil {
+		return nil, err
+	}
+	if tagsVal != nil && tagsVal.RawString() != "" {
+		d, ok := tagsVal.Value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("failed to retrieve tags map from Tosca Value: not expected type")
+		}
+		for k, v := range d {
+			v, ok := v.(string)
+			if !ok {
+				return nil, errors.Errorf("failed to retrieve string value from tags map from Tosca Value:%q not expected type", v)
+			}
+			tags[k] = v
+		}
+	}
+	if _, ok := tags["Name"]; !ok {
+		tags["Name"] = cfg.ResourcesPrefix + nodeParams.nodeName
+	}
+	return commons.AddResourceTags(cfg, nodeParams.deploymentID, nodeParams.nodeName, tags), nil
+}
+
+// getVPCID returns the VPC ID defined by the vpc_id property of a node or
+// the one of a VPC node this node depends on.
+// An empty string is returned if none is defined, meaning the default VPC is used.
+func getVPCID(ctx context.Context, nodeParams nodeParams) (string, error) {
+	vpcID, err := deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "vpc_id", false)
+	if err != nil || vpcID != "" {
+		return vpcID, err
+	}
+	hasDep, vpcNode, err := deployments.HasAnyRequirementFromNodeType(ctx, nodeParams.deploymentID, nodeParams.nodeName, "dependency", "yorc.nodes.aws.VPC")
+	if err != nil || !hasDep {
+		return "", err
+	}
+	return deployments.LookupInstanceAttributeValue(ctx, nodeParams.deploymentID, vpcNode, "0", "vpc_id")
+}
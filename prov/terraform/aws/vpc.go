@@ -0,0 +1,153 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *awsGenerator) generateVPC(ctx context.Context, cfg config.Configuration, nodeParams nodeParams, outputs map[string]string) error {
+	err := verifyThatNodeIsTypeOf(ctx, nodeParams, "yorc.nodes.aws.VPC")
+	if err != nil {
+		return err
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, nodeParams.deploymentID, "topology", "nodes", nodeParams.nodeName)
+	vpcIDKey := nodeParams.nodeName + "-vpc-id"
+
+	// Use existing VPC if defined with vpc_id
+	existingVPC, err := deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "vpc_id", false)
+	if err != nil {
+		return err
+	}
+	if existingVPC != "" {
+		commons.AddOutput(nodeParams.infrastructure, vpcIDKey, &commons.Output{Value: existingVPC})
+		outputs[path.Join(nodeKey, "/attributes/vpc_id")] = vpcIDKey
+		return nil
+	}
+
+	vpc := &VPC{}
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&vpc.CIDRBlock, "cidr_block", true},
+		{&vpc.InstanceTenancy, "instance_tenancy", false},
+	}
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+	boolParams := []struct {
+		pAttr        *bool
+		propertyName string
+	}{
+		{&vpc.EnableDNSSupport, "enable_dns_support"},
+		{&vpc.EnableDNSHostnames, "enable_dns_hostnames"},
+	}
+	for _, boolParam := range boolParams {
+		if *boolParam.pAttr, err = deployments.GetBooleanNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, boolParam.propertyName); err != nil {
+			return err
+		}
+	}
+	if vpc.Tags, err = getTags(ctx, cfg, nodeParams); err != nil {
+		return err
+	}
+
+	name := getResourceName(nodeParams)
+	log.Debugf("Add VPC:%+v", vpc)
+	commons.AddResource(nodeParams.infrastructure, "aws_vpc", name, vpc)
+
+	// An Internet gateway is required to access instances from outside the VPC
+	internetGateway, err := deployments.GetBooleanNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "internet_gateway")
+	if err != nil {
+		return err
+	}
+	if internetGateway {
+		commons.AddResource(nodeParams.infrastructure, "aws_internet_gateway", name, &InternetGateway{
+			VPCID: fmt.Sprintf("${aws_vpc.%s.id}", name),
+			Tags:  vpc.Tags,
+		})
+		commons.AddResource(nodeParams.infrastructure, "aws_route", name+"-internet-access", &Route{
+			RouteTableID:         fmt.Sprintf("${aws_vpc.%s.main_route_table_id}", name),
+			DestinationCIDRBlock: "0.0.0.0/0",
+			GatewayID:            fmt.Sprintf("${aws_internet_gateway.%s.id}", name),
+		})
+	}
+
+	commons.AddOutput(nodeParams.infrastructure, vpcIDKey, &commons.Output{Value: fmt.Sprintf("${aws_vpc.%s.id}", name)})
+	outputs[path.Join(nodeKey, "/attributes/vpc_id")] = vpcIDKey
+	return nil
+}
+
+func (g *awsGenerator) generateSubnet(ctx context.Context, cfg config.Configuration, nodeParams nodeParams, outputs map[string]string) error {
+	err := verifyThatNodeIsTypeOf(ctx, nodeParams, "yorc.nodes.aws.Subnet")
+	if err != nil {
+		return err
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, nodeParams.deploymentID, "topology", "nodes", nodeParams.nodeName)
+
+	subnet := &Subnet{}
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&subnet.CIDRBlock, "cidr_block", true},
+		{&subnet.AvailabilityZone, "availability_zone", false},
+	}
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+	if subnet.MapPublicIPOnLaunch, err = deployments.GetBooleanNodeProperty(ctx, nodeParams.deploymentID, nodeParams.nodeName, "map_public_ip_on_launch"); err != nil {
+		return err
+	}
+
+	// VPC is either set by user or retrieved via dependency relationship with VPC node
+	if subnet.VPCID, err = getVPCID(ctx, nodeParams); err != nil {
+		return err
+	}
+	if subnet.VPCID == "" {
+		return errors.Errorf("failed to retrieve the VPC of subnet %q: no vpc_id property and no dependency to a yorc.nodes.aws.VPC node", nodeParams.nodeName)
+	}
+	if subnet.Tags, err = getTags(ctx, cfg, nodeParams); err != nil {
+		return err
+	}
+
+	name := getResourceName(nodeParams)
+	log.Debugf("Add subnet:%+v", subnet)
+	commons.AddResource(nodeParams.infrastructure, "aws_subnet", name, subnet)
+
+	subnetIDKey := nodeParams.nodeName + "-subnet-id"
+	commons.AddOutput(nodeParams.infrastructure, subnetIDKey, &commons.Output{Value: fmt.Sprintf("${aws_subnet.%s.id}", name)})
+	outputs[path.Join(nodeKey, "/attributes/subnet_id")] = subnetIDKey
+	vpcIDKey := nodeParams.nodeName + "-vpc-id"
+	commons.AddOutput(nodeParams.infrastructure, vpcIDKey, &commons.Output{Value: subnet.VPCID})
+	outputs[path.Join(nodeKey, "/attributes/vpc_id")] = vpcIDKey
+	return nil
+}
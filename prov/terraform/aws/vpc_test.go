@@ -0,0 +1,96 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleVPC(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := awsGenerator{}
+	nodeParams := nodeParams{
+		deploymentID:   deploymentID,
+		nodeName:       "VPC",
+		infrastructure: &infrastructure,
+	}
+
+	err := g.generateVPC(context.Background(), cfg, nodeParams, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate VPC for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["aws_vpc"], 1, "Expected one VPC")
+	vpcsMap := infrastructure.Resource["aws_vpc"].(map[string]interface{})
+	require.Contains(t, vpcsMap, "vpc")
+	vpc, ok := vpcsMap["vpc"].(*VPC)
+	require.True(t, ok, "vpc is not a VPC")
+	assert.Equal(t, "10.0.0.0/16", vpc.CIDRBlock)
+	assert.Equal(t, true, vpc.EnableDNSSupport)
+	assert.Equal(t, true, vpc.EnableDNSHostnames)
+	assert.Equal(t, map[string]string{"env": "test", "Name": cfg.ResourcesPrefix + "VPC", commons.DeploymentIDTagName: deploymentID, commons.NodeNameTagName: "VPC"}, vpc.Tags)
+
+	require.Len(t, infrastructure.Resource["aws_internet_gateway"], 1, "Expected one Internet gateway")
+	routesMap := infrastructure.Resource["aws_route"].(map[string]interface{})
+	require.Contains(t, routesMap, "vpc-internet-access")
+	route := routesMap["vpc-internet-access"].(*Route)
+	assert.Equal(t, "${aws_vpc.vpc.main_route_table_id}", route.RouteTableID)
+	assert.Equal(t, "${aws_internet_gateway.vpc.id}", route.GatewayID)
+
+	vpcIDKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/VPC/attributes/vpc_id")]
+	require.Equal(t, "VPC-vpc-id", vpcIDKey)
+	assert.Equal(t, "${aws_vpc.vpc.id}", infrastructure.Output[vpcIDKey].Value)
+}
+
+func testSimpleSubnet(t *testing.T, srv *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/VPC/0/attributes/vpc_id"): []byte("vpc-123456"),
+	})
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := awsGenerator{}
+	nodeParams := nodeParams{
+		deploymentID:   deploymentID,
+		nodeName:       "Subnet",
+		infrastructure: &infrastructure,
+	}
+
+	err := g.generateSubnet(context.Background(), cfg, nodeParams, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate subnet for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["aws_subnet"], 1, "Expected one subnet")
+	subnet, ok := infrastructure.Resource["aws_subnet"].(map[string]interface{})["subnet"].(*Subnet)
+	require.True(t, ok, "subnet is not a Subnet")
+	assert.Equal(t, "vpc-123456", subnet.VPCID)
+	assert.Equal(t, "10.0.1.0/24", subnet.CIDRBlock)
+	assert.Equal(t, "us-east-2a", subnet.AvailabilityZone)
+	assert.Equal(t, true, subnet.MapPublicIPOnLaunch)
+
+	subnetIDKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/Subnet/attributes/subnet_id")]
+	assert.Equal(t, "${aws_subnet.subnet.id}", infrastructure.Output[subnetIDKey].Value)
+}
@@ -0,0 +1,302 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *azureGenerator) generateVirtualMachine(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName, instanceName string, instanceID int, infrastructure *commons.Infrastructure, outputs map[string]string, env *[]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.azure.Compute" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID,
+		"topology", "instances")
+	instancesKey := path.Join(instancesPrefix, nodeName)
+
+	vm := VirtualMachine{Name: getResourceName(cfg, deploymentID, nodeName, instanceName)}
+
+	// Getting string parameters
+	var imageID, osDiskSize string
+	vm.StorageOSDisk = OSDisk{
+		Name:         vm.Name + "-os-disk",
+		Caching:      "ReadWrite",
+		CreateOption: "FromImage",
+	}
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&vm.VMSize, "vm_size", true},
+		{&imageID, "image_id", false},
+		{&vm.StorageOSDisk.ManagedDiskType, "os_disk_type", false},
+		{&osDiskSize, "os_disk_size", false},
+	}
+
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName,
+			stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+	if osDiskSize != "" {
+		// Default size unit is MB
+		if vm.StorageOSDisk.DiskSizeGB, err = sizeutil.ConvertToGB(osDiskSize); err != nil {
+			return err
+		}
+	}
+
+	if vm.ResourceGroupName, err = getResourceGroup(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if vm.Location, err = getLocation(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if vm.Zones, err = getZones(ctx, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if vm.Tags, err = getTags(ctx, cfg, deploymentID, nodeName); err != nil {
+		return err
+	}
+
+	// Define the image either from its ID or from a Marketplace image reference
+	if imageID != "" {
+		vm.StorageImageReference = &ImageReference{ID: imageID}
+	} else {
+		imageRef := &ImageReference{}
+		imageParams := []struct {
+			pAttr    *string
+			property string
+		}{
+			{&imageRef.Publisher, "publisher"},
+			{&imageRef.Offer, "offer"},
+			{&imageRef.SKU, "sku"},
+			{&imageRef.Version, "version"},
+		}
+		for _, imageParam := range imageParams {
+			val, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "image", imageParam.property)
+			if err != nil {
+				return err
+			}
+			if val == nil || val.RawString() == "" {
+				return errors.Errorf("Expected an image_id or an image %s on %s", imageParam.property, nodeName)
+			}
+			*imageParam.pAttr = val.RawString()
+		}
+		vm.StorageImageReference = imageRef
+	}
+	vm.DeleteOSDiskOnTermination = true
+
+	// Get connection info (user, private key)
+	user, privateKey, err := commons.GetConnInfoFromEndpointCredentials(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	publicKey, err := privateKey.AuthorizedKey()
+	if err != nil {
+		return err
+	}
+	vm.OSProfile = OSProfile{ComputerName: vm.Name, AdminUsername: user}
+	// Custom data are base64 encoded by the Terraform provider and processed by cloud-init
+	if vm.OSProfile.CustomData, err = commons.GetUserData(ctx, cfg, deploymentID, nodeName); err != nil {
+		return err
+	}
+	vm.OSProfileLinuxConfig = LinuxConfig{
+		DisablePasswordAuthentication: true,
+		SSHKeys: []SSHKey{
+			{Path: fmt.Sprintf("/home/%s/.ssh/authorized_keys", user), KeyData: publicKey},
+		},
+	}
+
+	// Network interface definition
+	nic := &NetworkInterface{
+		Name:              vm.Name + "-nic",
+		Location:          vm.Location,
+		ResourceGroupName: vm.ResourceGroupName,
+		Tags:              vm.Tags,
+	}
+	ipConfig := IPConfiguration{Name: "primary", PrivateIPAddressAllocation: "Dynamic"}
+	if ipConfig.SubnetID, err = getSubnetID(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+
+	// Define an external access if there will be a public IP address
+	var noAddress bool
+	if noAddress, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "no_address"); err != nil {
+		return err
+	}
+	var publicAddress string
+	if !noAddress {
+		hasPublicIPReq, publicIPNode, err := deployments.HasAnyRequirementCapability(ctx, deploymentID, nodeName, "assignment", "yorc.capabilities.Assignable")
+		if err != nil {
+			return err
+		}
+		if hasPublicIPReq {
+			// Public IP Lookup
+			ipConfig.PublicIPAddressID, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, publicIPNode, instanceName, "public_ip_id")
+			if err != nil {
+				return err
+			}
+			publicAddress, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, publicIPNode, instanceName, "ip_address")
+			if err != nil {
+				return err
+			}
+		} else {
+			// Create a dedicated public IP address which is static to be known as soon as it is created
+			publicIP := &PublicIP{
+				Name:              vm.Name + "-public-ip",
+				Location:          vm.Location,
+				ResourceGroupName: vm.ResourceGroupName,
+				AllocationMethod:  "Static",
+				Tags:              vm.Tags,
+			}
+			commons.AddResource(infrastructure, "azurerm_public_ip", publicIP.Name, publicIP)
+			ipConfig.PublicIPAddressID = fmt.Sprintf("${azurerm_public_ip.%s.id}", publicIP.Name)
+			publicAddress = fmt.Sprintf("${azurerm_public_ip.%s.ip_address}", publicIP.Name)
+		}
+	}
+	nic.IPConfigurations = []IPConfiguration{ipConfig}
+	commons.AddResource(infrastructure, "azurerm_network_interface", nic.Name, nic)
+	vm.NetworkInterfaceIDs = []string{fmt.Sprintf("${azurerm_network_interface.%s.id}", nic.Name)}
+
+	// Add the virtual machine
+	commons.AddResource(infrastructure, "azurerm_virtual_machine", vm.Name, &vm)
+
+	// Attach managed disks
+	if err = addAttachedDisks(ctx, cfg, deploymentID, nodeName, instanceName, vm.Name, infrastructure, outputs); err != nil {
+		return err
+	}
+
+	vmIDKey := nodeName + "-" + instanceName + "-vmID"
+	commons.AddOutput(infrastructure, vmIDKey, &commons.Output{Value: fmt.Sprintf("${azurerm_virtual_machine.%s.id}", vm.Name)})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/vm_id")] = vmIDKey
+
+	// Define the private IP address using the value exported by Terraform
+	privateIP := fmt.Sprintf("${azurerm_network_interface.%s.private_ip_address}", nic.Name)
+
+	privateIPKey := nodeName + "-" + instanceName + "-privateIP"
+	commons.AddOutput(infrastructure, privateIPKey, &commons.Output{Value: privateIP})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = privateIPKey
+
+	// Define the public IP using the value exported by Terraform
+	// except if it was specified the instance shouldn't have a public address
+	var accessIP, accessIPKey string
+	if noAddress {
+		accessIP = privateIP
+		accessIPKey = privateIPKey
+	} else {
+		accessIP = publicAddress
+
+		publicIPKey := nodeName + "-" + instanceName + "-publicIP"
+		accessIPKey = publicIPKey
+		commons.AddOutput(infrastructure, publicIPKey, &commons.Output{Value: accessIP})
+
+		outputs[path.Join(instancesKey, instanceName, "/attributes/public_address")] = publicIPKey
+		outputs[path.Join(instancesKey, instanceName, "/attributes/public_ip_address")] = publicIPKey
+	}
+
+	// ip_adress attribute and endpoint capability
+	outputs[path.Join(instancesKey, instanceName, "/capabilities/endpoint/attributes/ip_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/attributes/ip_address")] = accessIPKey
+
+	// Add Connection check
+	return commons.AddConnectionCheckResource(ctx, deploymentID, nodeName, infrastructure, user, privateKey, accessIP, vm.Name, env)
+}
+
+// getSubnetID returns the ID of the subnet of a Compute, defined by a network relationship
+// to a subnet node or by the subnet_id property of its location
+func getSubnetID(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) (string, error) {
+	hasSubnet, subnetNode, err := deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "network", "yorc.nodes.azure.Subnet")
+	if err != nil {
+		return "", err
+	}
+	if hasSubnet {
+		return deployments.LookupInstanceAttributeValue(ctx, deploymentID, subnetNode, "0", "subnet_id")
+	}
+	subnetID := locationProps.GetString("subnet_id")
+	if subnetID == "" {
+		return "", errors.Errorf("no subnet defined for node %q, it should be connected to a yorc.nodes.azure.Subnet node or a default subnet_id should be set in the Azure location configuration", nodeName)
+	}
+	return subnetID, nil
+}
+
+func addAttachedDisks(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, instanceName, vmName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	storageReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "local_storage")
+	if err != nil {
+		return err
+	}
+	for i, storageReq := range storageReqs {
+		volumeNodeName := storageReq.Node
+		log.Debugf("Volume attachment required form Volume named %s", volumeNodeName)
+
+		volumeID, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, volumeNodeName, instanceName, "volume_id")
+		if err != nil {
+			return err
+		}
+
+		attachment := &DataDiskAttachment{
+			ManagedDiskID:    volumeID,
+			VirtualMachineID: fmt.Sprintf("${azurerm_virtual_machine.%s.id}", vmName),
+			LUN:              i,
+			Caching:          "ReadWrite",
+		}
+		lunValue, err := deployments.GetRelationshipPropertyValueFromRequirement(ctx, deploymentID, nodeName, storageReq.Index, "lun")
+		if err != nil {
+			return err
+		}
+		if lunValue != nil && lunValue.RawString() != "" {
+			if attachment.LUN, err = strconv.Atoi(lunValue.RawString()); err != nil {
+				return errors.Wrapf(err, "invalid lun %q for the attachment of %q to %q", lunValue.RawString(), volumeNodeName, nodeName)
+			}
+		}
+		cachingValue, err := deployments.GetRelationshipPropertyValueFromRequirement(ctx, deploymentID, nodeName, storageReq.Index, "caching")
+		if err != nil {
+			return err
+		}
+		if cachingValue != nil && cachingValue.RawString() != "" {
+			attachment.Caching = cachingValue.RawString()
+		}
+
+		attachName := getResourceName(cfg, deploymentID, volumeNodeName, instanceName, "to", nodeName, instanceName)
+		commons.AddResource(infrastructure, "azurerm_virtual_machine_data_disk_attachment", attachName, attachment)
+
+		// Azure Linux agent creates stable links to data disks according to their LUN
+		deviceKey := volumeNodeName + "-" + instanceName + "-device"
+		commons.AddOutput(infrastructure, deviceKey, &commons.Output{Value: fmt.Sprintf("/dev/disk/azure/scsi1/lun%d", attachment.LUN)})
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", volumeNodeName, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", nodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", volumeNodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+	}
+	return nil
+}
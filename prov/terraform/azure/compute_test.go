@@ -0,0 +1,222 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+var expectedKey []byte
+
+func init() {
+	var err error
+	expectedKey, err = ioutil.ReadFile("./testdata/mykey.pem")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func loadTestYaml(t *testing.T) string {
+	deploymentID := path.Base(t.Name())
+	yamlName := "testdata/" + deploymentID + ".yaml"
+	err := deployments.StoreDeploymentDefinition(context.Background(), deploymentID, yamlName)
+	require.NoError(t, err, "Failed to parse "+yamlName+" definition")
+	return deploymentID
+}
+
+func getVirtualMachine(t *testing.T, infrastructure *commons.Infrastructure, vmName string) *VirtualMachine {
+	require.Len(t, infrastructure.Resource["azurerm_virtual_machine"], 1, "Expected one virtual machine")
+	vmMap := infrastructure.Resource["azurerm_virtual_machine"].(map[string]interface{})
+	require.Contains(t, vmMap, vmName)
+	vm, ok := vmMap[vmName].(*VirtualMachine)
+	require.True(t, ok, "%s is not a VirtualMachine", vmName)
+	return vm
+}
+
+func getNetworkInterface(t *testing.T, infrastructure *commons.Infrastructure, nicName string) *NetworkInterface {
+	require.Len(t, infrastructure.Resource["azurerm_network_interface"], 1, "Expected one network interface")
+	nicMap := infrastructure.Resource["azurerm_network_interface"].(map[string]interface{})
+	require.Contains(t, nicMap, nicName)
+	nic, ok := nicMap[nicName].(*NetworkInterface)
+	require.True(t, ok, "%s is not a NetworkInterface", nicName)
+	require.Len(t, nic.IPConfigurations, 1)
+	return nic
+}
+
+func testSimpleVirtualMachine(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	g := azureGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", 0, &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual machine for %s", deploymentID)
+
+	vmName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	vm := getVirtualMachine(t, &infrastructure, vmName)
+	assert.Equal(t, "Standard_DS1_v2", vm.VMSize)
+	assert.Equal(t, "northeurope", vm.Location)
+	assert.Equal(t, "yorc-rg", vm.ResourceGroupName)
+	assert.Equal(t, []string{"1"}, vm.Zones)
+	assert.Equal(t, map[string]string{"environment": "test", commons.DeploymentIDTagName: deploymentID, commons.NodeNameTagName: "Compute"}, vm.Tags)
+	assert.Equal(t, &ImageReference{Publisher: "OpenLogic", Offer: "CentOS", SKU: "7.7", Version: "latest"}, vm.StorageImageReference)
+	assert.Equal(t, "Premium_LRS", vm.StorageOSDisk.ManagedDiskType)
+	assert.Equal(t, 40, vm.StorageOSDisk.DiskSizeGB)
+	assert.Equal(t, "FromImage", vm.StorageOSDisk.CreateOption)
+	assert.True(t, vm.DeleteOSDiskOnTermination)
+	assert.Equal(t, "centos", vm.OSProfile.AdminUsername)
+	assert.Equal(t, vmName, vm.OSProfile.ComputerName)
+	assert.True(t, vm.OSProfileLinuxConfig.DisablePasswordAuthentication)
+	require.Len(t, vm.OSProfileLinuxConfig.SSHKeys, 1)
+	assert.Equal(t, "/home/centos/.ssh/authorized_keys", vm.OSProfileLinuxConfig.SSHKeys[0].Path)
+	assert.True(t, strings.HasPrefix(vm.OSProfileLinuxConfig.SSHKeys[0].KeyData, "ssh-rsa "), "Expected a public key in the authorized_keys format")
+
+	nic := getNetworkInterface(t, &infrastructure, vmName+"-nic")
+	assert.Equal(t, []string{fmt.Sprintf("${azurerm_network_interface.%s-nic.id}", vmName)}, vm.NetworkInterfaceIDs)
+	assert.Equal(t, "default-subnet-id", nic.IPConfigurations[0].SubnetID)
+	assert.Equal(t, fmt.Sprintf("${azurerm_public_ip.%s-public-ip.id}", vmName), nic.IPConfigurations[0].PublicIPAddressID)
+
+	require.Len(t, infrastructure.Resource["azurerm_public_ip"], 1, "Expected a public IP to be created")
+	publicIPMap := infrastructure.Resource["azurerm_public_ip"].(map[string]interface{})
+	require.Contains(t, publicIPMap, vmName+"-public-ip")
+	assert.Equal(t, "Static", publicIPMap[vmName+"-public-ip"].(*PublicIP).AllocationMethod)
+
+	require.Contains(t, infrastructure.Resource, "null_resource")
+	nullResources := infrastructure.Resource["null_resource"].(map[string]interface{})
+	require.Contains(t, nullResources, vmName+"-ConnectionCheck")
+	require.Len(t, env, 1)
+	assert.Equal(t, "TF_VAR_private_key="+string(expectedKey), env[0], "env var for private key expected")
+
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Compute", "0")
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/vm_id"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/private_address"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/public_address"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/ip_address"))
+	require.Equal(t, &commons.Output{Value: fmt.Sprintf("${azurerm_public_ip.%s-public-ip.ip_address}", vmName)}, infrastructure.Output[outputs[path.Join(instancesKey, "/attributes/ip_address")]])
+}
+
+func testSimpleVirtualMachineMissingImage(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	g := azureGenerator{}
+	env := make([]string, 0)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", 0, &infrastructure, make(map[string]string), &env)
+	require.Error(t, err, "Expected missing image error, but had no error")
+	assert.Contains(t, err.Error(), "Expected an image_id or an image publisher")
+}
+
+func testSimpleVirtualMachineWithNetworkAndPublicIP(t *testing.T, srv1 *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	// Simulate the subnet and public IP attributes registration
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Subnet/0/attributes/subnet_id"):      []byte("my-subnet-id"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/PublicIP/0/attributes/public_ip_id"): []byte("my-public-ip-id"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/PublicIP/0/attributes/ip_address"):   []byte("1.2.3.4"),
+	})
+
+	infrastructure := commons.Infrastructure{}
+	g := azureGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", 0, &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual machine for %s", deploymentID)
+
+	vmName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	vm := getVirtualMachine(t, &infrastructure, vmName)
+	assert.Equal(t, "other-rg", vm.ResourceGroupName)
+	assert.Equal(t, &ImageReference{ID: "/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/images/centos"}, vm.StorageImageReference)
+
+	nic := getNetworkInterface(t, &infrastructure, vmName+"-nic")
+	assert.Equal(t, "my-subnet-id", nic.IPConfigurations[0].SubnetID)
+	assert.Equal(t, "my-public-ip-id", nic.IPConfigurations[0].PublicIPAddressID)
+	assert.NotContains(t, infrastructure.Resource, "azurerm_public_ip")
+
+	ipAddressKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Compute", "0", "/attributes/ip_address")]
+	require.Equal(t, &commons.Output{Value: "1.2.3.4"}, infrastructure.Output[ipAddressKey])
+}
+
+func testSimpleVirtualMachineWithManagedDisk(t *testing.T, srv1 *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	// Simulate the managed disks "volume_id" attribute registration
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Disk1/0/attributes/volume_id"): []byte("disk1-id"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Disk2/0/attributes/volume_id"): []byte("disk2-id"),
+	})
+
+	infrastructure := commons.Infrastructure{}
+	g := azureGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", 0, &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual machine for %s", deploymentID)
+
+	resourcePrefix := getResourcesPrefix(cfg, deploymentID)
+	vmName := resourcePrefix + "compute-0"
+	getVirtualMachine(t, &infrastructure, vmName)
+
+	// No public address
+	nic := getNetworkInterface(t, &infrastructure, vmName+"-nic")
+	assert.Equal(t, "", nic.IPConfigurations[0].PublicIPAddressID)
+	assert.NotContains(t, infrastructure.Resource, "azurerm_public_ip")
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances")
+	assert.NotContains(t, outputs, path.Join(instancesKey, "Compute", "0", "/attributes/public_address"))
+	assert.Equal(t, outputs[path.Join(instancesKey, "Compute", "0", "/attributes/private_address")], outputs[path.Join(instancesKey, "Compute", "0", "/attributes/ip_address")])
+
+	require.Len(t, infrastructure.Resource["azurerm_virtual_machine_data_disk_attachment"], 2, "Expected two disk attachments")
+	attachments := infrastructure.Resource["azurerm_virtual_machine_data_disk_attachment"].(map[string]interface{})
+	tests := []struct {
+		disk     string
+		index    string
+		volumeID string
+		lun      int
+		caching  string
+	}{
+		{"Disk1", "0", "disk1-id", 0, "ReadWrite"},
+		{"Disk2", "1", "disk2-id", 5, "None"},
+	}
+	for _, tt := range tests {
+		attachmentName := fmt.Sprintf("%s%s-0-to-compute-0", resourcePrefix, strings.ToLower(tt.disk))
+		require.Contains(t, attachments, attachmentName)
+		attachment := attachments[attachmentName].(*DataDiskAttachment)
+		assert.Equal(t, tt.volumeID, attachment.ManagedDiskID)
+		assert.Equal(t, fmt.Sprintf("${azurerm_virtual_machine.%s.id}", vmName), attachment.VirtualMachineID)
+		assert.Equal(t, tt.lun, attachment.LUN)
+		assert.Equal(t, tt.caching, attachment.Caching)
+
+		expectedDevice := &commons.Output{Value: fmt.Sprintf("/dev/disk/azure/scsi1/lun%d", tt.lun)}
+		assert.Equal(t, expectedDevice, infrastructure.Output[outputs[path.Join(instancesKey, tt.disk, "0", "attributes/device")]])
+		assert.Equal(t, expectedDevice, infrastructure.Output[outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", "Compute", tt.index, "0", "attributes/device")]])
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+var testLocationProperties config.DynamicMap
+
+// The aim of this function is to run all package tests with consul server dependency with only one consul server start
+func TestRunConsulAzurePackageTests(t *testing.T) {
+	cfg := testutil.SetupTestConfig(t)
+	srv, _ := testutil.NewTestConsulInstance(t, &cfg)
+	defer func() {
+		srv.Stop()
+		os.RemoveAll(cfg.WorkingDirectory)
+	}()
+
+	testLocationProperties = config.DynamicMap{
+		"subscription_id": "my-subscription",
+		"location":        "northeurope",
+		"resource_group":  "yorc-rg",
+		"subnet_id":       "default-subnet-id",
+	}
+
+	t.Run("azureProvider", func(t *testing.T) {
+		t.Run("simpleVirtualMachine", func(t *testing.T) {
+			testSimpleVirtualMachine(t, cfg)
+		})
+		t.Run("simpleVirtualMachineMissingImage", func(t *testing.T) {
+			testSimpleVirtualMachineMissingImage(t, cfg)
+		})
+		t.Run("simpleVirtualMachineWithNetworkAndPublicIP", func(t *testing.T) {
+			testSimpleVirtualMachineWithNetworkAndPublicIP(t, srv, cfg)
+		})
+		t.Run("simpleVirtualMachineWithManagedDisk", func(t *testing.T) {
+			testSimpleVirtualMachineWithManagedDisk(t, srv, cfg)
+		})
+		t.Run("simpleVirtualNetwork", func(t *testing.T) {
+			testSimpleVirtualNetwork(t, srv, cfg)
+		})
+		t.Run("simplePublicIP", func(t *testing.T) {
+			testSimplePublicIP(t, cfg)
+		})
+		t.Run("simpleManagedDisk", func(t *testing.T) {
+			testSimpleManagedDisk(t, cfg)
+		})
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *azureGenerator) generateManagedDisk(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName, instanceName string, instanceID int, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.azure.ManagedDisk" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID,
+		"topology", "instances")
+	instancesKey := path.Join(instancesPrefix, nodeName)
+
+	disk := &ManagedDisk{
+		Name:         getResourceName(cfg, deploymentID, nodeName, instanceName),
+		CreateOption: "Empty",
+	}
+	var size, volumes string
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&volumes, "volume_id", false},
+		{&disk.StorageAccountType, "storage_account_type", true},
+		{&disk.SourceResourceID, "snapshot_id", false},
+		{&size, "size", false},
+	}
+
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName,
+			stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+
+	var volumeID string
+	if volumes != "" {
+		tabVol := strings.Split(volumes, ",")
+		if len(tabVol) > instanceID {
+			volumeID = strings.TrimSpace(tabVol[instanceID])
+		}
+	}
+
+	// Add Azure managed disk resource if not any volume ID is provided
+	if volumeID == "" {
+		if disk.SourceResourceID != "" {
+			disk.CreateOption = "Copy"
+		}
+		if size != "" {
+			// Default size unit is MB
+			log.Debugf("Initial size property value (default is MB): %q", size)
+			disk.DiskSizeGB, err = sizeutil.ConvertToGB(size)
+			if err != nil {
+				return err
+			}
+			log.Debugf("Computed size (in GB): %d", disk.DiskSizeGB)
+		} else if disk.SourceResourceID == "" {
+			return errors.Errorf("size is mandatory to create the managed disk %q from scratch", nodeName)
+		}
+		if disk.ResourceGroupName, err = getResourceGroup(ctx, locationProps, deploymentID, nodeName); err != nil {
+			return err
+		}
+		if disk.Location, err = getLocation(ctx, locationProps, deploymentID, nodeName); err != nil {
+			return err
+		}
+		if disk.Zones, err = getZones(ctx, deploymentID, nodeName); err != nil {
+			return err
+		}
+		if disk.Tags, err = getTags(ctx, cfg, deploymentID, nodeName); err != nil {
+			return err
+		}
+		commons.AddResource(infrastructure, "azurerm_managed_disk", disk.Name, disk)
+		volumeID = fmt.Sprintf("${azurerm_managed_disk.%s.id}", disk.Name)
+	}
+
+	// Provide output for attribute volume_id
+	volumeKey := nodeName + "-" + instanceName + "-volume"
+	commons.AddOutput(infrastructure, volumeKey, &commons.Output{Value: volumeID})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/volume_id")] = volumeKey
+	return nil
+}
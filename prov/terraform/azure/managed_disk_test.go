@@ -0,0 +1,70 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleManagedDisk(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	resourcePrefix := getResourcesPrefix(cfg, deploymentID)
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances")
+	g := azureGenerator{}
+
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	for i, instanceName := range []string{"0", "1"} {
+		err := g.generateManagedDisk(context.Background(), cfg, testLocationProperties, deploymentID, "Disk", instanceName, i, &infrastructure, outputs)
+		require.NoError(t, err, "Unexpected error attempting to generate managed disk for %s", deploymentID)
+	}
+	require.Len(t, infrastructure.Resource["azurerm_managed_disk"], 2, "Expected two managed disks")
+	diskMap := infrastructure.Resource["azurerm_managed_disk"].(map[string]interface{})
+	for i := 0; i < 2; i++ {
+		diskName := fmt.Sprintf("%sdisk-%d", resourcePrefix, i)
+		require.Contains(t, diskMap, diskName)
+		disk, ok := diskMap[diskName].(*ManagedDisk)
+		require.True(t, ok, "%s is not a ManagedDisk", diskName)
+		assert.Equal(t, "StandardSSD_LRS", disk.StorageAccountType)
+		assert.Equal(t, "Empty", disk.CreateOption)
+		assert.Equal(t, 20, disk.DiskSizeGB)
+		assert.Equal(t, []string{"2"}, disk.Zones)
+		assert.Equal(t, "westeurope", disk.Location)
+		assert.Equal(t, "yorc-rg", disk.ResourceGroupName)
+		assert.Equal(t, map[string]string{"owner": "yorc", commons.DeploymentIDTagName: deploymentID, commons.NodeNameTagName: "Disk"}, disk.Tags)
+		assert.Equal(t, &commons.Output{Value: fmt.Sprintf("${azurerm_managed_disk.%s.id}", diskName)}, infrastructure.Output[outputs[path.Join(instancesKey, "Disk", fmt.Sprint(i), "/attributes/volume_id")]])
+	}
+
+	// Existing disks
+	infrastructure = commons.Infrastructure{}
+	outputs = make(map[string]string)
+	for i, instanceName := range []string{"0", "1"} {
+		err := g.generateManagedDisk(context.Background(), cfg, testLocationProperties, deploymentID, "ExistingDisk", instanceName, i, &infrastructure, outputs)
+		require.NoError(t, err, "Unexpected error attempting to generate managed disk for %s", deploymentID)
+		assert.Equal(t, &commons.Output{Value: fmt.Sprintf("disk-id-%d", i)}, infrastructure.Output[outputs[path.Join(instancesKey, "ExistingDisk", instanceName, "/attributes/volume_id")]])
+	}
+	assert.NotContains(t, infrastructure.Resource, "azurerm_managed_disk")
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+const defaultAddressSpace = "10.0.0.0/16"
+
+func (g *azureGenerator) generateVirtualNetwork(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.azure.VirtualNetwork" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
+
+	// Use existing virtual network if defined with network_name
+	networkName, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "network_name", false)
+	if err != nil {
+		return err
+	}
+	if networkName != "" {
+		networkKey := nodeName + "-network"
+		commons.AddOutput(infrastructure, networkKey, &commons.Output{Value: networkName})
+		outputs[path.Join(nodeKey, "/attributes/network_name")] = networkKey
+		return nil
+	}
+
+	network := &VirtualNetwork{Name: getResourceName(cfg, deploymentID, nodeName)}
+	if network.ResourceGroupName, err = getResourceGroup(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if network.Location, err = getLocation(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if network.AddressSpace, err = getStringListProperty(ctx, deploymentID, nodeName, "address_space"); err != nil {
+		return err
+	}
+	if len(network.AddressSpace) == 0 {
+		network.AddressSpace = []string{defaultAddressSpace}
+	}
+	if network.DNSServers, err = getStringListProperty(ctx, deploymentID, nodeName, "dns_servers"); err != nil {
+		return err
+	}
+	if network.Tags, err = getTags(ctx, cfg, deploymentID, nodeName); err != nil {
+		return err
+	}
+
+	log.Debugf("Add virtual network:%+v", network)
+	commons.AddResource(infrastructure, "azurerm_virtual_network", network.Name, network)
+
+	// Provide outputs for network_name and network_id
+	networkKey := nodeName + "-network"
+	commons.AddOutput(infrastructure, networkKey, &commons.Output{Value: fmt.Sprintf("${azurerm_virtual_network.%s.name}", network.Name)})
+	outputs[path.Join(nodeKey, "/attributes/network_name")] = networkKey
+	networkIDKey := nodeName + "-network-id"
+	commons.AddOutput(infrastructure, networkIDKey, &commons.Output{Value: fmt.Sprintf("${azurerm_virtual_network.%s.id}", network.Name)})
+	outputs[path.Join(nodeKey, "/attributes/network_id")] = networkIDKey
+	return nil
+}
+
+func (g *azureGenerator) generateSubnet(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.azure.Subnet" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
+
+	// Use existing subnet if defined with subnet_id
+	subnetID, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "subnet_id", false)
+	if err != nil {
+		return err
+	}
+	if subnetID != "" {
+		subnetKey := nodeName + "-subnet-id"
+		commons.AddOutput(infrastructure, subnetKey, &commons.Output{Value: subnetID})
+		outputs[path.Join(nodeKey, "/attributes/subnet_id")] = subnetKey
+		return nil
+	}
+
+	subnet := &Subnet{Name: getResourceName(cfg, deploymentID, nodeName)}
+	if subnet.ResourceGroupName, err = getResourceGroup(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if subnet.AddressPrefix, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "cidr", true); err != nil {
+		return err
+	}
+
+	// Virtual network is either set by user or retrieved via dependency relationship with a virtual network node
+	if subnet.VirtualNetworkName, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "virtual_network_name", false); err != nil {
+		return err
+	}
+	if subnet.VirtualNetworkName == "" {
+		hasDep, networkNode, err := deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "dependency", "yorc.nodes.azure.VirtualNetwork")
+		if err != nil {
+			return err
+		}
+		if !hasDep {
+			return errors.Errorf("no virtual network defined for subnet %q, either set its virtual_network_name property or add a dependency to a yorc.nodes.azure.VirtualNetwork node", nodeName)
+		}
+		subnet.VirtualNetworkName, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, networkNode, "0", "network_name")
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Add subnet:%+v", subnet)
+	commons.AddResource(infrastructure, "azurerm_subnet", subnet.Name, subnet)
+
+	// Provide outputs for subnet_id and network_name
+	subnetKey := nodeName + "-subnet-id"
+	commons.AddOutput(infrastructure, subnetKey, &commons.Output{Value: fmt.Sprintf("${azurerm_subnet.%s.id}", subnet.Name)})
+	outputs[path.Join(nodeKey, "/attributes/subnet_id")] = subnetKey
+	networkKey := nodeName + "-network"
+	commons.AddOutput(infrastructure, networkKey, &commons.Output{Value: subnet.VirtualNetworkName})
+	outputs[path.Join(nodeKey, "/attributes/network_name")] = networkKey
+	return nil
+}
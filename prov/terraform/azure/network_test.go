@@ -0,0 +1,86 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleVirtualNetwork(t *testing.T, srv1 *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	resourcePrefix := getResourcesPrefix(cfg, deploymentID)
+	nodesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes")
+	g := azureGenerator{}
+
+	// New virtual network
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	err := g.generateVirtualNetwork(context.Background(), cfg, testLocationProperties, deploymentID, "Network", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual network for %s", deploymentID)
+	networkName := resourcePrefix + "network"
+	require.Len(t, infrastructure.Resource["azurerm_virtual_network"], 1, "Expected one virtual network")
+	networkMap := infrastructure.Resource["azurerm_virtual_network"].(map[string]interface{})
+	require.Contains(t, networkMap, networkName)
+	network := networkMap[networkName].(*VirtualNetwork)
+	assert.Equal(t, "yorc-rg", network.ResourceGroupName)
+	assert.Equal(t, "northeurope", network.Location)
+	assert.Equal(t, []string{"10.1.0.0/16"}, network.AddressSpace)
+	assert.Equal(t, []string{"10.1.0.4"}, network.DNSServers)
+	require.Contains(t, outputs, path.Join(nodesKey, "Network", "/attributes/network_name"))
+	require.Contains(t, outputs, path.Join(nodesKey, "Network", "/attributes/network_id"))
+
+	// Existing virtual network
+	infrastructure = commons.Infrastructure{}
+	outputs = make(map[string]string)
+	err = g.generateVirtualNetwork(context.Background(), cfg, testLocationProperties, deploymentID, "ExistingNetwork", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual network for %s", deploymentID)
+	assert.Len(t, infrastructure.Resource, 0, "Expected no resource for an existing network")
+	assert.Equal(t, &commons.Output{Value: "existing-vnet"}, infrastructure.Output[outputs[path.Join(nodesKey, "ExistingNetwork", "/attributes/network_name")]])
+
+	// Subnet of the virtual network
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Network/0/attributes/network_name"): []byte(networkName),
+	})
+	infrastructure = commons.Infrastructure{}
+	outputs = make(map[string]string)
+	err = g.generateSubnet(context.Background(), cfg, testLocationProperties, deploymentID, "Subnet", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate subnet for %s", deploymentID)
+	subnetName := resourcePrefix + "subnet"
+	require.Len(t, infrastructure.Resource["azurerm_subnet"], 1, "Expected one subnet")
+	subnetMap := infrastructure.Resource["azurerm_subnet"].(map[string]interface{})
+	require.Contains(t, subnetMap, subnetName)
+	subnet := subnetMap[subnetName].(*Subnet)
+	assert.Equal(t, "yorc-rg", subnet.ResourceGroupName)
+	assert.Equal(t, networkName, subnet.VirtualNetworkName)
+	assert.Equal(t, "10.1.1.0/24", subnet.AddressPrefix)
+	assert.Equal(t, &commons.Output{Value: fmt.Sprintf("${azurerm_subnet.%s.id}", subnetName)}, infrastructure.Output[outputs[path.Join(nodesKey, "Subnet", "/attributes/subnet_id")]])
+
+	// Subnet without virtual network
+	err = g.generateSubnet(context.Background(), cfg, testLocationProperties, deploymentID, "OrphanSubnet", &commons.Infrastructure{}, make(map[string]string))
+	require.Error(t, err, "Expected an error for a subnet without virtual network")
+	assert.Contains(t, err.Error(), "no virtual network defined")
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimplePublicIP(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := azureGenerator{}
+	for i, instanceName := range []string{"0", "1"} {
+		err := g.generatePublicIP(context.Background(), cfg, testLocationProperties, deploymentID, "PublicIP", instanceName, i, &infrastructure, outputs)
+		require.NoError(t, err, "Unexpected error attempting to generate public IP for %s", deploymentID)
+	}
+
+	resourcePrefix := getResourcesPrefix(cfg, deploymentID)
+	require.Len(t, infrastructure.Resource["azurerm_public_ip"], 2, "Expected two public IPs")
+	publicIPMap := infrastructure.Resource["azurerm_public_ip"].(map[string]interface{})
+	for i, domainNameLabel := range []string{"yorc", "yorc-1"} {
+		publicIPName := fmt.Sprintf("%spublicip-%d", resourcePrefix, i)
+		require.Contains(t, publicIPMap, publicIPName)
+		publicIP, ok := publicIPMap[publicIPName].(*PublicIP)
+		require.True(t, ok, "%s is not a PublicIP", publicIPName)
+		assert.Equal(t, "Static", publicIP.AllocationMethod)
+		assert.Equal(t, "Standard", publicIP.SKU)
+		assert.Equal(t, domainNameLabel, publicIP.DomainNameLabel)
+		assert.Equal(t, []string{"1"}, publicIP.Zones)
+		assert.Equal(t, "northeurope", publicIP.Location)
+		assert.Equal(t, "yorc-rg", publicIP.ResourceGroupName)
+		assert.Equal(t, map[string]string{"owner": "yorc", commons.DeploymentIDTagName: deploymentID, commons.NodeNameTagName: "PublicIP"}, publicIP.Tags)
+
+		instanceKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "PublicIP", fmt.Sprint(i))
+		assert.Equal(t, &commons.Output{Value: fmt.Sprintf("${azurerm_public_ip.%s.ip_address}", publicIPName)}, infrastructure.Output[outputs[path.Join(instanceKey, "/attributes/ip_address")]])
+		assert.Equal(t, &commons.Output{Value: fmt.Sprintf("${azurerm_public_ip.%s.id}", publicIPName)}, infrastructure.Output[outputs[path.Join(instanceKey, "/attributes/public_ip_id")]])
+	}
+}
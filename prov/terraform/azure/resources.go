@@ -0,0 +1,152 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+// VirtualMachine represents an Azure Virtual Machine
+// See https://www.terraform.io/docs/providers/azurerm/r/virtual_machine.html
+// for the latest documentation.
+type VirtualMachine struct {
+	Name                         string            `json:"name"`
+	Location                     string            `json:"location"`
+	ResourceGroupName            string            `json:"resource_group_name"`
+	NetworkInterfaceIDs          []string          `json:"network_interface_ids"`
+	VMSize                       string            `json:"vm_size"`
+	Zones                        []string          `json:"zones,omitempty"`
+	DeleteOSDiskOnTermination    bool              `json:"delete_os_disk_on_termination"`
+	DeleteDataDisksOnTermination bool              `json:"delete_data_disks_on_termination"`
+	StorageImageReference        *ImageReference   `json:"storage_image_reference,omitempty"`
+	StorageOSDisk                OSDisk            `json:"storage_os_disk"`
+	OSProfile                    OSProfile         `json:"os_profile"`
+	OSProfileLinuxConfig         LinuxConfig       `json:"os_profile_linux_config"`
+	Tags                         map[string]string `json:"tags,omitempty"`
+}
+
+// ImageReference defines the image used to create a Virtual Machine,
+// either from its ID or from the publisher, offer, SKU and version of a Marketplace image
+type ImageReference struct {
+	ID        string `json:"id,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Offer     string `json:"offer,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// OSDisk represents the operating system disk of a Virtual Machine
+type OSDisk struct {
+	Name            string `json:"name"`
+	Caching         string `json:"caching,omitempty"`
+	CreateOption    string `json:"create_option"`
+	ManagedDiskType string `json:"managed_disk_type,omitempty"`
+	DiskSizeGB      int    `json:"disk_size_gb,omitempty"`
+}
+
+// OSProfile defines the computer name and administrator account of a Virtual Machine
+type OSProfile struct {
+	ComputerName  string `json:"computer_name"`
+	AdminUsername string `json:"admin_username"`
+	CustomData    string `json:"custom_data,omitempty"`
+}
+
+// LinuxConfig defines the settings of a Linux Virtual Machine
+type LinuxConfig struct {
+	DisablePasswordAuthentication bool     `json:"disable_password_authentication"`
+	SSHKeys                       []SSHKey `json:"ssh_keys,omitempty"`
+}
+
+// SSHKey is a public key allowed to connect to a Virtual Machine
+type SSHKey struct {
+	Path    string `json:"path"`
+	KeyData string `json:"key_data"`
+}
+
+// NetworkInterface represents an Azure Network Interface
+// See https://www.terraform.io/docs/providers/azurerm/r/network_interface.html
+// for the latest documentation.
+type NetworkInterface struct {
+	Name              string            `json:"name"`
+	Location          string            `json:"location"`
+	ResourceGroupName string            `json:"resource_group_name"`
+	IPConfigurations  []IPConfiguration `json:"ip_configuration"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+// IPConfiguration defines an IP address of a Network Interface
+type IPConfiguration struct {
+	Name                       string `json:"name"`
+	SubnetID                   string `json:"subnet_id,omitempty"`
+	PrivateIPAddressAllocation string `json:"private_ip_address_allocation"`
+	PublicIPAddressID          string `json:"public_ip_address_id,omitempty"`
+}
+
+// PublicIP represents an Azure Public IP address
+// See https://www.terraform.io/docs/providers/azurerm/r/public_ip.html
+// for the latest documentation.
+type PublicIP struct {
+	Name              string            `json:"name"`
+	Location          string            `json:"location"`
+	ResourceGroupName string            `json:"resource_group_name"`
+	AllocationMethod  string            `json:"allocation_method"`
+	SKU               string            `json:"sku,omitempty"`
+	DomainNameLabel   string            `json:"domain_name_label,omitempty"`
+	Zones             []string          `json:"zones,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+// VirtualNetwork represents an Azure Virtual Network
+// See https://www.terraform.io/docs/providers/azurerm/r/virtual_network.html
+// for the latest documentation.
+type VirtualNetwork struct {
+	Name              string            `json:"name"`
+	Location          string            `json:"location"`
+	ResourceGroupName string            `json:"resource_group_name"`
+	AddressSpace      []string          `json:"address_space"`
+	DNSServers        []string          `json:"dns_servers,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+// Subnet represents a subnet of an Azure Virtual Network
+// See https://www.terraform.io/docs/providers/azurerm/r/subnet.html
+// for the latest documentation.
+type Subnet struct {
+	Name               string `json:"name"`
+	ResourceGroupName  string `json:"resource_group_name"`
+	VirtualNetworkName string `json:"virtual_network_name"`
+	AddressPrefix      string `json:"address_prefix"`
+}
+
+// ManagedDisk represents an Azure Managed Disk
+// See https://www.terraform.io/docs/providers/azurerm/r/managed_disk.html
+// for the latest documentation.
+type ManagedDisk struct {
+	Name               string            `json:"name"`
+	Location           string            `json:"location"`
+	ResourceGroupName  string            `json:"resource_group_name"`
+	StorageAccountType string            `json:"storage_account_type"`
+	CreateOption       string            `json:"create_option"`
+	DiskSizeGB         int               `json:"disk_size_gb,omitempty"`
+	SourceResourceID   string            `json:"source_resource_id,omitempty"`
+	Zones              []string          `json:"zones,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
+}
+
+// DataDiskAttachment attaches a Managed Disk to a Virtual Machine
+// See https://www.terraform.io/docs/providers/azurerm/r/virtual_machine_data_disk_attachment.html
+// for the latest documentation.
+type DataDiskAttachment struct {
+	ManagedDiskID    string `json:"managed_disk_id"`
+	VirtualMachineID string `json:"virtual_machine_id"`
+	LUN              int    `json:"lun"`
+	Caching          string `json:"caching"`
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func getResourcesPrefix(cfg config.Configuration, deploymentID string) string {
+	b := sha1.Sum([]byte(deploymentID))
+	return fmt.Sprintf("%s%x-", cfg.ResourcesPrefix, b[0:3])
+}
+
+// getResourceName returns the name of an Azure resource created for the given node (and instance if any).
+// Names are made of alphanumeric characters and hyphens, which are accepted by all Azure resources types.
+func getResourceName(cfg config.Configuration, deploymentID, nodeName string, suffixes ...string) string {
+	name := getResourcesPrefix(cfg, deploymentID) + strings.Join(append([]string{nodeName}, suffixes...), "-")
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// getResourceGroup returns the resource group of a node, defined either by its resource_group property
+// or by the resource_group property of its location
+func getResourceGroup(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) (string, error) {
+	resourceGroup, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "resource_group", false)
+	if err != nil {
+		return "", err
+	}
+	if resourceGroup == "" {
+		resourceGroup = locationProps.GetString("resource_group")
+	}
+	if resourceGroup == "" {
+		return "", errors.Errorf("no resource group defined for node %q, it should be set either in the node resource_group property or in the Azure location configuration", nodeName)
+	}
+	return resourceGroup, nil
+}
+
+// getLocation returns the Azure region of a node, defined either by its location property
+// or by the location property of its Yorc location
+func getLocation(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) (string, error) {
+	location, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "location", false)
+	if err != nil {
+		return "", err
+	}
+	if location == "" {
+		location = locationProps.GetString("location")
+	}
+	if location == "" {
+		return "", errors.Errorf("no Azure location defined for node %q, it should be set either in the node location property or in the Azure location configuration", nodeName)
+	}
+	return location, nil
+}
+
+// getZones returns the availability zones defined by the zone property of a node
+func getZones(ctx context.Context, deploymentID, nodeName string) ([]string, error) {
+	zone, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "zone", false)
+	if err != nil || zone == "" {
+		return nil, err
+	}
+	return []string{zone}, nil
+}
+
+// getTags returns the tags to assign to a resource, from the tags property of a node
+// completed by the tags identifying the resource
+func getTags(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (map[string]string, error) {
+	tagsVal, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "tags")
+	if err != nil {
+		return nil, err
+	}
+	if tagsVal == nil || tagsVal.RawString() == "" {
+		return commons.AddResourceTags(cfg, deploymentID, nodeName, nil), nil
+	}
+	d, ok := tagsVal.Value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve tags map from Tosca Value: not expected type")
+	}
+	tags := make(map[string]string, len(d))
+	for k, v := range d {
+		v, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("failed to retrieve string value from tags map from Tosca Value:%q not expected type", v)
+		}
+		tags[k] = v
+	}
+	return commons.AddResourceTags(cfg, deploymentID, nodeName, tags), nil
+}
+
+// getStringListProperty returns the values of a node property of type list of strings
+func getStringListProperty(ctx context.Context, deploymentID, nodeName, propertyName string) ([]string, error) {
+	val, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, propertyName)
+	if err != nil || val == nil || val.RawString() == "" {
+		return nil, err
+	}
+	list, ok := val.Value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("failed to retrieve %s list from Tosca Value: not expected type", propertyName)
+	}
+	result := make([]string, len(list))
+	for i, v := range list {
+		if result[i], ok = v.(string); !ok {
+			return nil, errors.Errorf("failed to retrieve string value from %s list from Tosca Value:%v not expected type", propertyName, v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+const (
+	// BackendLocationProperty is the location property defining the Terraform backend
+	// used to store states of resources created on this location
+	BackendLocationProperty = "terraform_backend"
+	// BackendConfigLocationProperty is the location property holding the settings of the Terraform backend
+	BackendConfigLocationProperty = "terraform_backend_config"
+
+	// ConsulBackend stores Terraform states in the Consul KV store (default)
+	ConsulBackend = "consul"
+	// S3Backend stores Terraform states in an S3-compatible object store
+	S3Backend = "s3"
+	// HTTPBackend stores Terraform states using a REST client
+	HTTPBackend = "http"
+	// LocalBackend stores Terraform states on the Yorc server filesystem
+	LocalBackend = "local"
+
+	defaultS3KeyPrefix = "yorc"
+	stateFileName      = "terraform.tfstate"
+)
+
+// GetBackendConfigurationForNode returns the Terraform Backend configuration
+// used to store the state of a given node.
+//
+// The backend type is defined by the terraform_backend location property and its settings
+// by the terraform_backend_config location property. The state location within the backend
+// is computed from the deployment ID and the node name, so that each node gets its own state
+// and its own lock. The Consul KV store is used if no backend is defined for the location.
+func GetBackendConfigurationForNode(cfg config.Configuration, deploymentID, nodeName string, locationProps config.DynamicMap) (map[string]interface{}, error) {
+	backendType := strings.ToLower(locationProps.GetStringOrDefault(BackendLocationProperty, ConsulBackend))
+	var settings map[string]interface{}
+	if locationProps.IsSet(BackendConfigLocationProperty) {
+		var err error
+		settings, err = cast.ToStringMapE(locationProps.Get(BackendConfigLocationProperty))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %q location property, expecting a map", BackendConfigLocationProperty)
+		}
+	}
+	// Work on a copy as location properties may be cached
+	backendConfig := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		backendConfig[k] = v
+	}
+	stateKey := path.Join(deploymentID, nodeName)
+
+	switch backendType {
+	case ConsulBackend:
+		consulConfig := GetBackendConfiguration(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-state", nodeName), cfg)
+		// Settings defined on the location override the ones of the Yorc Consul agent
+		defaultConfig := consulConfig["backend"].(map[string]interface{})[ConsulBackend].(map[string]interface{})
+		for k, v := range backendConfig {
+			if k != "path" {
+				defaultConfig[k] = v
+			}
+		}
+		return consulConfig, nil
+	case S3Backend:
+		if cast.ToString(backendConfig["bucket"]) == "" {
+			return nil, errors.Errorf("missing mandatory %q setting for the %q Terraform backend", "bucket", S3Backend)
+		}
+		keyPrefix := defaultS3KeyPrefix
+		if prefix, ok := backendConfig["key_prefix"]; ok {
+			keyPrefix = cast.ToString(prefix)
+			delete(backendConfig, "key_prefix")
+		}
+		backendConfig["key"] = path.Join(keyPrefix, stateKey, stateFileName)
+	case HTTPBackend:
+		address := strings.TrimSuffix(cast.ToString(backendConfig["address"]), "/")
+		if address == "" {
+			return nil, errors.Errorf("missing mandatory %q setting for the %q Terraform backend", "address", HTTPBackend)
+		}
+		backendConfig["address"] = address + "/" + stateKey
+		// Locking is done on the state address unless specific lock addresses are provided
+		for _, lockSetting := range []string{"lock_address", "unlock_address"} {
+			lockAddress := strings.TrimSuffix(cast.ToString(backendConfig[lockSetting]), "/")
+			if lockAddress == "" {
+				backendConfig[lockSetting] = backendConfig["address"]
+			} else {
+				backendConfig[lockSetting] = lockAddress + "/" + stateKey
+			}
+		}
+	case LocalBackend:
+		dir := cast.ToString(backendConfig["directory"])
+		if dir == "" {
+			dir = filepath.Join(cfg.WorkingDirectory, "terraform-states")
+		}
+		delete(backendConfig, "directory")
+		backendConfig["path"] = filepath.Join(dir, deploymentID, nodeName, stateFileName)
+	default:
+		return nil, errors.Errorf("unsupported Terraform backend %q, supported backends are %q, %q, %q and %q", backendType, ConsulBackend, S3Backend, HTTPBackend, LocalBackend)
+	}
+
+	return map[string]interface{}{
+		"backend": map[string]interface{}{
+			backendType: backendConfig,
+		},
+	}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestGetBackendConfigurationForNode(t *testing.T) {
+	t.Parallel()
+	cfg := config.Configuration{WorkingDirectory: "/var/yorc/work", Consul: config.Consul{Address: "consul.example.com:8500"}}
+	tests := []struct {
+		name          string
+		locationProps config.DynamicMap
+		want          map[string]interface{}
+		wantErr       bool
+	}{
+		{"DefaultToConsul", config.DynamicMap{}, map[string]interface{}{
+			"consul": map[string]interface{}{
+				"path":      "_yorc/deployments/dep/terraform-state/Compute",
+				"address":   "consul.example.com:8500",
+				"scheme":    "http",
+				"ca_file":   "",
+				"cert_file": "",
+				"key_file":  "",
+			}}, false},
+		{"ConsulOverrides", config.DynamicMap{"terraform_backend": "consul", "terraform_backend_config": map[string]interface{}{"address": "other:8500", "path": "ignored"}}, map[string]interface{}{
+			"consul": map[string]interface{}{
+				"path":      "_yorc/deployments/dep/terraform-state/Compute",
+				"address":   "other:8500",
+				"scheme":    "http",
+				"ca_file":   "",
+				"cert_file": "",
+				"key_file":  "",
+			}}, false},
+		{"S3", config.DynamicMap{"terraform_backend": "s3", "terraform_backend_config": map[string]interface{}{"bucket": "states", "endpoint": "https://minio.example.com", "dynamodb_table": "locks"}}, map[string]interface{}{
+			"s3": map[string]interface{}{
+				"bucket":         "states",
+				"endpoint":       "https://minio.example.com",
+				"dynamodb_table": "locks",
+				"key":            "yorc/dep/Compute/terraform.tfstate",
+			}}, false},
+		{"S3WithPrefix", config.DynamicMap{"terraform_backend": "S3", "terraform_backend_config": map[string]interface{}{"bucket": "states", "key_prefix": "prod"}}, map[string]interface{}{
+			"s3": map[string]interface{}{
+				"bucket": "states",
+				"key":    "prod/dep/Compute/terraform.tfstate",
+			}}, false},
+		{"S3MissingBucket", config.DynamicMap{"terraform_backend": "s3"}, nil, true},
+		{"HTTP", config.DynamicMap{"terraform_backend": "http", "terraform_backend_config": map[string]interface{}{"address": "https://states.example.com/"}}, map[string]interface{}{
+			"http": map[string]interface{}{
+				"address":        "https://states.example.com/dep/Compute",
+				"lock_address":   "https://states.example.com/dep/Compute",
+				"unlock_address": "https://states.example.com/dep/Compute",
+			}}, false},
+		{"HTTPWithLockAddresses", config.DynamicMap{"terraform_backend": "http", "terraform_backend_config": map[string]interface{}{"address": "https://states.example.com", "lock_address": "https://locks.example.com", "unlock_address": "https://locks.example.com", "lock_method": "POST"}}, map[string]interface{}{
+			"http": map[string]interface{}{
+				"address":        "https://states.example.com/dep/Compute",
+				"lock_address":   "https://locks.example.com/dep/Compute",
+				"unlock_address": "https://locks.example.com/dep/Compute",
+				"lock_method":    "POST",
+			}}, false},
+		{"HTTPMissingAddress", config.DynamicMap{"terraform_backend": "http"}, nil, true},
+		{"Local", config.DynamicMap{"terraform_backend": "local"}, map[string]interface{}{
+			"local": map[string]interface{}{
+				"path": "/var/yorc/work/terraform-states/dep/Compute/terraform.tfstate",
+			}}, false},
+		{"LocalWithDirectory", config.DynamicMap{"terraform_backend": "local", "terraform_backend_config": map[string]interface{}{"directory": "/shared/states"}}, map[string]interface{}{
+			"local": map[string]interface{}{
+				"path": "/shared/states/dep/Compute/terraform.tfstate",
+			}}, false},
+		{"UnsupportedBackend", config.DynamicMap{"terraform_backend": "etcd"}, nil, true},
+		{"InvalidBackendConfig", config.DynamicMap{"terraform_backend": "s3", "terraform_backend_config": "bucket=states"}, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := GetBackendConfigurationForNode(cfg, "dep", "Compute", tt.locationProps)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"backend": tt.want}, got)
+		})
+	}
+}
@@ -47,4 +47,10 @@ func TestRunConsulTerraformCommonsPackageTests(t *testing.T) {
 	t.Run("AddConnectionCheckResource", func(t *testing.T) {
 		testAddConnectionCheckResource(t, client.KV())
 	})
+	t.Run("userDataProperty", func(t *testing.T) {
+		testGetUserDataFromProperty(t, client.KV(), cfg)
+	})
+	t.Run("userDataArtifact", func(t *testing.T) {
+		testGetUserDataFromArtifact(t, client.KV(), cfg)
+	})
 }
@@ -0,0 +1,315 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// PriceTableLocationProperty is the location property defining the path of the price table file
+// used to estimate the cost of resources created on this location
+const PriceTableLocationProperty = "price_table"
+
+// Kinds of priced resources, which are the sections of a price table
+const (
+	// ComputeResourceKind is the kind of compute instances, priced by instance type
+	ComputeResourceKind = "compute"
+	// SpotComputeResourceKind is the kind of spot or preemptible compute instances, priced by instance type.
+	// The compute price is used if no spot price is defined.
+	SpotComputeResourceKind = "compute_spot"
+	// StorageResourceKind is the kind of block storage volumes, priced per GB by volume type
+	StorageResourceKind = "storage"
+	// PublicIPResourceKind is the kind of public IP addresses
+	PublicIPResourceKind = "public_ip"
+	// DatabaseResourceKind is the kind of managed database instances, priced by instance type
+	DatabaseResourceKind = "database"
+	// LoadBalancerResourceKind is the kind of load balancers, priced by load balancer type
+	LoadBalancerResourceKind = "load_balancer"
+)
+
+// defaultPriceKey is the key of the price used for resources which type is not in the price table
+const defaultPriceKey = "default"
+
+// A PricedResource is a resource of a node instance billed according to a price table
+type PricedResource struct {
+	// Kind is the kind of resource, one of the price table sections
+	Kind string
+	// Type is the type of resource, for instance the instance type of a compute
+	Type string
+	// Quantity is the number of billed units, like the size in GB of a volume. 1 if not set.
+	Quantity float64
+}
+
+func (r PricedResource) String() string {
+	if r.Type == "" {
+		return r.Kind
+	}
+	return r.Kind + ":" + r.Type
+}
+
+// A ResourcesPricer is a Generator able to list the billed resources created for each instance of a node
+type ResourcesPricer interface {
+	GetPricedResources(ctx context.Context, deploymentID, nodeName string) ([]PricedResource, error)
+}
+
+// A PriceTable defines the hourly prices of resources
+//
+// Prices are grouped by resource kind, then by resource type:
+//
+//	currency: USD
+//	compute:
+//	  t2.micro: 0.0116
+//	  default: 0.1
+//	storage:
+//	  default: 0.00014
+//	public_ip:
+//	  default: 0.005
+type PriceTable struct {
+	Currency string                        `yaml:"currency,omitempty"`
+	Prices   map[string]map[string]float64 `yaml:",inline"`
+}
+
+// LoadPriceTable reads a price table from a YAML or JSON file
+func LoadPriceTable(filePath string) (*PriceTable, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read price table %q", filePath)
+	}
+	pt := new(PriceTable)
+	err = yaml.Unmarshal(content, pt)
+	return pt, errors.Wrapf(err, "invalid price table %q", filePath)
+}
+
+// GetHourlyPrice returns the hourly price of a resource, and false if it is not defined by the price table
+func (pt *PriceTable) GetHourlyPrice(res PricedResource) (float64, bool) {
+	quantity := res.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	kinds := []string{res.Kind}
+	if res.Kind == SpotComputeResourceKind {
+		kinds = append(kinds, ComputeResourceKind)
+	}
+	for _, kind := range kinds {
+		prices := pt.Prices[kind]
+		if price, ok := prices[res.Type]; ok && res.Type != "" {
+			return price * quantity, true
+		}
+		if price, ok := prices[defaultPriceKey]; ok {
+			return price * quantity, true
+		}
+	}
+	return 0, false
+}
+
+// GetStoragePricedResources returns the priced resources of a block storage node which size is defined
+// by its size property. Existing volumes referenced by the volume_id property are not priced.
+func GetStoragePricedResources(ctx context.Context, deploymentID, nodeName, volumeType string) ([]PricedResource, error) {
+	volumeID, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "volume_id", false)
+	if err != nil || volumeID != "" {
+		return nil, err
+	}
+	size, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "size", false)
+	if err != nil || size == "" {
+		return nil, err
+	}
+	sizeGB, err := sizeutil.ConvertToGB(size)
+	if err != nil {
+		return nil, err
+	}
+	return []PricedResource{{Kind: StorageResourceKind, Type: volumeType, Quantity: float64(sizeGB)}}, nil
+}
+
+func getLocationPriceTable(locationProps config.DynamicMap) (*PriceTable, error) {
+	filePath := locationProps.GetString(PriceTableLocationProperty)
+	if filePath == "" {
+		return nil, nil
+	}
+	return LoadPriceTable(filePath)
+}
+
+// NewCostInfraUsageCollector returns an infrastructure usage collector reporting the estimated hourly cost
+// of deployments resources on a location, based on the price table defined for this location.
+//
+// The returned collector is also a prov.CostEstimator, allowing to estimate costs before deploying.
+func NewCostInfraUsageCollector(infraType string, pricer ResourcesPricer) prov.InfraUsageCollector {
+	return &costCollector{infraType: infraType, pricer: pricer}
+}
+
+type costCollector struct {
+	infraType string
+	pricer    ResourcesPricer
+}
+
+type nodeCost struct {
+	Instances int `json:"instances"`
+	*prov.CostEstimate
+}
+
+type deploymentCost struct {
+	Currency   string               `json:"currency,omitempty"`
+	HourlyCost float64              `json:"hourly_cost"`
+	Nodes      map[string]*nodeCost `json:"nodes"`
+}
+
+// EstimateNodeCost returns the estimated cost of instances of a node, using the price table of its location
+func (c *costCollector) EstimateNodeCost(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instancesCount int) (*prov.CostEstimate, error) {
+	locationProps, err := GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := getLocationPriceTable(locationProps)
+	if err != nil || pt == nil {
+		return nil, err
+	}
+	return c.estimate(ctx, pt, deploymentID, nodeName, instancesCount)
+}
+
+func (c *costCollector) estimate(ctx context.Context, pt *PriceTable, deploymentID, nodeName string, instancesCount int) (*prov.CostEstimate, error) {
+	resources, err := c.pricer.GetPricedResources(ctx, deploymentID, nodeName)
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+	estimate := &prov.CostEstimate{Currency: pt.Currency}
+	for _, res := range resources {
+		price, ok := pt.GetHourlyPrice(res)
+		if !ok {
+			estimate.UnpricedResources = append(estimate.UnpricedResources, res.String())
+			continue
+		}
+		estimate.HourlyCost += price * float64(instancesCount)
+	}
+	return estimate, nil
+}
+
+// GetUsageInfo returns for each deployment the estimated hourly cost of its resources created on the location.
+//
+// The "deployment" parameter allows to restrict the report to a given deployment.
+func (c *costCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string,
+	params map[string]string) (map[string]interface{}, error) {
+	locationMgr, err := locations.GetManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	locationProps, err := locationMgr.GetLocationProperties(locationName, c.infraType)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := getLocationPriceTable(locationProps)
+	if err != nil {
+		return nil, err
+	}
+	if pt == nil {
+		return nil, errors.Errorf("no %s property defined for location %q", PriceTableLocationProperty, locationName)
+	}
+	// Nodes without location in their metadata are created on the first location of their infrastructure type
+	locs, err := locationMgr.GetLocations()
+	if err != nil {
+		return nil, err
+	}
+	var defaultLocation string
+	for _, loc := range locs {
+		if loc.Type == c.infraType {
+			defaultLocation = loc.Name
+			break
+		}
+	}
+
+	deploymentIDs := []string{params["deployment"]}
+	if params["deployment"] == "" {
+		deploymentIDs, err = deployments.GetDeploymentsIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	usage := make(map[string]interface{})
+	for _, deploymentID := range deploymentIDs {
+		nodes, err := deployments.GetNodes(ctx, deploymentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, nodeName := range nodes {
+			infraType, err := deployments.GetNodeInfrastructureType(ctx, deploymentID, nodeName)
+			if err != nil {
+				return nil, err
+			}
+			if infraType != c.infraType {
+				continue
+			}
+			found, nodeLocation, err := deployments.GetNodeMetadata(ctx, deploymentID, nodeName, tosca.MetadataLocationNameKey)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				nodeLocation = defaultLocation
+			}
+			if nodeLocation != locationName {
+				continue
+			}
+			count, err := countCreatedInstances(ctx, deploymentID, nodeName)
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				continue
+			}
+			estimate, err := c.estimate(ctx, pt, deploymentID, nodeName, count)
+			if err != nil {
+				return nil, err
+			}
+			if estimate == nil {
+				continue
+			}
+			dc, ok := usage[deploymentID].(*deploymentCost)
+			if !ok {
+				dc = &deploymentCost{Currency: pt.Currency, Nodes: make(map[string]*nodeCost)}
+				usage[deploymentID] = dc
+			}
+			dc.Nodes[nodeName] = &nodeCost{Instances: count, CostEstimate: estimate}
+			dc.HourlyCost += estimate.HourlyCost
+		}
+	}
+	return usage, nil
+}
+
+// countCreatedInstances returns the number of instances of a node which resources are created
+func countCreatedInstances(ctx context.Context, deploymentID, nodeName string) (int, error) {
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for _, instance := range instances {
+		state, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instance)
+		if err != nil {
+			return 0, err
+		}
+		if state != tosca.NodeStateInitial && state != tosca.NodeStateDeleted {
+			count++
+		}
+	}
+	return count, nil
+}
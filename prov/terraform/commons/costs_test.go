@@ -0,0 +1,54 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceTableGetHourlyPrice(t *testing.T) {
+	pt, err := LoadPriceTable("testdata/priceTable.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", pt.Currency)
+
+	tests := []struct {
+		name      string
+		res       PricedResource
+		wantPrice float64
+		wantFound bool
+	}{
+		{"ComputeType", PricedResource{Kind: ComputeResourceKind, Type: "t2.micro"}, 0.0116, true},
+		{"ComputeDefault", PricedResource{Kind: ComputeResourceKind, Type: "m5.large"}, 0.1, true},
+		{"SpotType", PricedResource{Kind: SpotComputeResourceKind, Type: "t2.micro"}, 0.0035, true},
+		{"SpotFallbackOnCompute", PricedResource{Kind: SpotComputeResourceKind, Type: "m5.large"}, 0.1, true},
+		{"StorageQuantity", PricedResource{Kind: StorageResourceKind, Type: "gp2", Quantity: 20}, 0.002, true},
+		{"Unpriced", PricedResource{Kind: PublicIPResourceKind}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, found := pt.GetHourlyPrice(tt.res)
+			assert.Equal(t, tt.wantFound, found)
+			assert.InDelta(t, tt.wantPrice, price, 1e-9)
+		})
+	}
+}
+
+func TestLoadPriceTableMissingFile(t *testing.T) {
+	_, err := LoadPriceTable("testdata/doesNotExist.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+// SSHPort is always opened by security groups or firewalls generated from endpoints
+// as Yorc connects to computes through SSH
+const SSHPort = 22
+
+// A PortRule is a port to open on a given transport protocol (tcp or udp)
+type PortRule struct {
+	Port     int
+	Protocol string
+}
+
+// GetEndpointsPortRules returns the ports to open to reach the endpoints exposed by nodes
+// referencing a given target node through requirements of the given type (for example
+// computes bound to a security group) and by components hosted on these nodes.
+//
+// The SSH port is always part of the returned rules as soon as a node references the target node.
+// Rules are sorted by port then protocol.
+func GetEndpointsPortRules(ctx context.Context, deploymentID, requirementType, targetNodeName string) ([]PortRule, error) {
+	nodes, err := deployments.GetNodes(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	rulesSet := make(map[PortRule]struct{})
+	for _, node := range nodes {
+		reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, node, requirementType)
+		if err != nil {
+			return nil, err
+		}
+		referencesTarget := false
+		for _, req := range reqs {
+			referencesTarget = referencesTarget || req.Node == targetNodeName
+		}
+		if !referencesTarget {
+			continue
+		}
+
+		rulesSet[PortRule{Port: SSHPort, Protocol: "tcp"}] = struct{}{}
+		hostedNodes, err := deployments.GetNodesHostedOn(ctx, deploymentID, node)
+		if err != nil {
+			return nil, err
+		}
+		for _, endpointNode := range append([]string{node}, hostedNodes...) {
+			if err = addNodeEndpointsPortRules(ctx, deploymentID, endpointNode, rulesSet); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rules := make([]PortRule, 0, len(rulesSet))
+	for r := range rulesSet {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Port != rules[j].Port {
+			return rules[i].Port < rules[j].Port
+		}
+		return rules[i].Protocol < rules[j].Protocol
+	})
+	return rules, nil
+}
+
+func addNodeEndpointsPortRules(ctx context.Context, deploymentID, nodeName string, rulesSet map[PortRule]struct{}) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	capabilities, err := deployments.GetCapabilitiesOfType(ctx, deploymentID, nodeType, "tosca.capabilities.Endpoint")
+	if err != nil {
+		return err
+	}
+	for _, capability := range capabilities {
+		portValue, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, capability, "port")
+		if err != nil {
+			return err
+		}
+		if portValue == nil || portValue.RawString() == "" {
+			continue
+		}
+		port, err := strconv.Atoi(portValue.RawString())
+		if err != nil {
+			return errors.Wrapf(err, "invalid port %q for capability %q of node %q", portValue.RawString(), capability, nodeName)
+		}
+		protocolValue, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, capability, "protocol")
+		if err != nil {
+			return err
+		}
+		// Endpoints protocols are application protocols (http, https,...) relying on TCP, except UDP
+		protocol := "tcp"
+		if protocolValue != nil && strings.ToLower(protocolValue.RawString()) == "udp" {
+			protocol = "udp"
+		}
+		rulesSet[PortRule{Port: port, Protocol: protocol}] = struct{}{}
+	}
+	return nil
+}
@@ -43,6 +43,15 @@ type Generator interface {
 	GenerateTerraformInfraForNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, map[string]string, []string, PostApplyCallback, error)
 }
 
+// An InterruptionChecker is a Generator of an infrastructure able to reclaim instances, like spot or preemptible instances.
+// It allows to detect instances interrupted by the infrastructure.
+type InterruptionChecker interface {
+	// IsInterruptible returns true if instances of the given node may be reclaimed by the infrastructure
+	IsInterruptible(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (bool, error)
+	// GetInterruptedInstances returns the given instances of a node that were reclaimed by the infrastructure
+	GetInterruptedInstances(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instances []string) ([]string, error)
+}
+
 // PreDestroyInfraCallback is a function that is call before destroying an infrastructure. If it returns false the node will not be destroyed.
 type PreDestroyInfraCallback func(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, error)
 
@@ -0,0 +1,323 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/awsutil"
+	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// PullState returns the Terraform state stored for a given node in the backend
+// configured for the node location.
+func PullState(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) ([]byte, error) {
+	var state []byte
+	err := withStateWorkingDirectory(ctx, cfg, deploymentID, nodeName, func(dir string) error {
+		cmd := executil.Command(ctx, "terraform", "state", "pull")
+		cmd.Dir = dir
+		stderr := new(bytes.Buffer)
+		cmd.Stderr = stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return errors.Wrapf(err, "failed to pull Terraform state of node %q: %s", nodeName, stderr.String())
+		}
+		state = out
+		return nil
+	})
+	return state, err
+}
+
+// PushState stores the given Terraform state for a node in the backend configured for the node location.
+//
+// Unless force is set, Terraform refuses to push a state with a different lineage or a lower serial
+// than the stored one.
+func PushState(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, state []byte, force bool) error {
+	if !json.Valid(state) {
+		return invalidStateError{"invalid Terraform state, expecting a JSON document"}
+	}
+	return withStateWorkingDirectory(ctx, cfg, deploymentID, nodeName, func(dir string) error {
+		stateFile := filepath.Join(dir, "import.tfstate")
+		if err := ioutil.WriteFile(stateFile, state, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write file %q", stateFile)
+		}
+		args := []string{"state", "push"}
+		if force {
+			args = append(args, "-force")
+		}
+		cmd := executil.Command(ctx, "terraform", append(args, stateFile)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil && isStateRejected(string(out)) {
+			return invalidStateError{fmt.Sprintf("Terraform state of node %q rejected: %s", nodeName, strings.TrimSpace(string(out)))}
+		}
+		return errors.Wrapf(err, "failed to push Terraform state of node %q: %s", nodeName, string(out))
+	})
+}
+
+type invalidStateError struct {
+	msg string
+}
+
+func (e invalidStateError) Error() string {
+	return e.msg
+}
+
+// IsInvalidStateError checks if an error is due to a pushed state that is not valid or conflicts with the stored one
+func IsInvalidStateError(err error) bool {
+	_, ok := errors.Cause(err).(invalidStateError)
+	return ok
+}
+
+// isStateRejected checks if the output of a failed "terraform state push" reports a state that conflicts
+// with the stored one, rather than an error accessing the backend
+func isStateRejected(output string) bool {
+	output = strings.ToLower(output)
+	return strings.Contains(output, "lineage") || strings.Contains(output, "serial") || strings.Contains(output, "decoding state file")
+}
+
+// DeleteState removes the Terraform state stored for a given node from the backend configured for the node location.
+//
+// It is called when purging a deployment, once its resources are destroyed. Deleting a state that does not exist
+// is not an error.
+func DeleteState(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) error {
+	locationProps, err := GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	backend, err := GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return err
+	}
+	for backendType, settings := range backend["backend"].(map[string]interface{}) {
+		settings := settings.(map[string]interface{})
+		switch backendType {
+		case ConsulBackend:
+			err = deleteConsulState(settings)
+		case S3Backend:
+			err = deleteS3State(ctx, settings)
+		case HTTPBackend:
+			err = deleteHTTPState(ctx, settings)
+		case LocalBackend:
+			err = deleteLocalState(settings)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete Terraform state of node %q from %q backend", nodeName, backendType)
+		}
+	}
+	return nil
+}
+
+func deleteConsulState(settings map[string]interface{}) error {
+	consulConfig := api.DefaultConfig()
+	consulConfig.Address = cast.ToString(settings["address"])
+	if scheme := cast.ToString(settings["scheme"]); scheme != "" {
+		consulConfig.Scheme = scheme
+	}
+	if token := cast.ToString(settings["access_token"]); token != "" {
+		consulConfig.Token = token
+	}
+	if dc := cast.ToString(settings["datacenter"]); dc != "" {
+		consulConfig.Datacenter = dc
+	}
+	consulConfig.TLSConfig.CAFile = cast.ToString(settings["ca_file"])
+	consulConfig.TLSConfig.CertFile = cast.ToString(settings["cert_file"])
+	consulConfig.TLSConfig.KeyFile = cast.ToString(settings["key_file"])
+	client, err := api.NewClient(consulConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Consul client")
+	}
+	statePath := cast.ToString(settings["path"])
+	// Locks and chunks of large states are stored under the state path
+	if _, err = client.KV().DeleteTree(statePath+"/", nil); err != nil {
+		return err
+	}
+	_, err = client.KV().Delete(statePath, nil)
+	return err
+}
+
+func deleteS3State(ctx context.Context, settings map[string]interface{}) error {
+	bucket := cast.ToString(settings["bucket"])
+	key := cast.ToString(settings["key"])
+	region := cast.ToString(settings["region"])
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	// As Terraform does, fall back to credentials defined in the environment
+	accessKey, secretKey, token := cast.ToString(settings["access_key"]), cast.ToString(settings["secret_key"]), cast.ToString(settings["token"])
+	if accessKey == "" {
+		accessKey, secretKey, token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if accessKey == "" || secretKey == "" {
+		return errors.New("deleting states requires access_key and secret_key to be defined in the backend configuration or in the environment")
+	}
+
+	var u *url.URL
+	if endpoint := cast.ToString(settings["endpoint"]); endpoint != "" {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+		var err error
+		u, err = url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + bucket)
+		if err != nil {
+			return errors.Wrapf(err, "invalid S3 endpoint %q", endpoint)
+		}
+	} else {
+		u = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region)}
+	}
+	u.Path += "/" + key
+	u.RawPath = awsutil.URIEncode(u.Path)
+	req, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	// Go would escape the path differently
+	req.URL.RawPath = u.RawPath
+	awsutil.SignS3Request(req, accessKey, secretKey, token, region, time.Now())
+	return sendStateDeletionRequest(ctx, req)
+}
+
+func deleteHTTPState(ctx context.Context, settings map[string]interface{}) error {
+	req, err := http.NewRequest(http.MethodDelete, cast.ToString(settings["address"]), nil)
+	if err != nil {
+		return err
+	}
+	if user := cast.ToString(settings["username"]); user != "" {
+		req.SetBasicAuth(user, cast.ToString(settings["password"]))
+	}
+	return sendStateDeletionRequest(ctx, req)
+}
+
+func sendStateDeletionRequest(ctx context.Context, req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return errors.Errorf("unexpected HTTP status %q deleting state at %s://%s%s", resp.Status, req.URL.Scheme, req.URL.Host, req.URL.Path)
+	}
+	return nil
+}
+
+func deleteLocalState(settings map[string]interface{}) error {
+	// States are stored in <directory>/<deploymentID>/<nodeName>/terraform.tfstate
+	nodeDir := filepath.Dir(cast.ToString(settings["path"]))
+	if err := os.RemoveAll(nodeDir); err != nil {
+		return err
+	}
+	// Only removed once states of all the deployment nodes are deleted
+	os.Remove(filepath.Dir(nodeDir))
+	return nil
+}
+
+// withStateWorkingDirectory initializes a temporary Terraform working directory
+// containing only the backend configuration of the given node and runs fn into it.
+func withStateWorkingDirectory(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, fn func(dir string) error) error {
+	locationProps, err := GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	backend, err := GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return err
+	}
+	jsonInfra, err := json.MarshalIndent(Infrastructure{Terraform: backend}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to generate JSON of terraform backend description")
+	}
+
+	baseDir := filepath.Join(cfg.WorkingDirectory, "deployments", deploymentID, "terraform")
+	if err = os.MkdirAll(baseDir, 0775); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", baseDir)
+	}
+	dir, err := ioutil.TempDir(baseDir, "state-"+nodeName+"-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create Terraform state working directory in %q", baseDir)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Debugf("Failed to remove Terraform state working directory %q: %v", dir, err)
+		}
+	}()
+	if err = ioutil.WriteFile(filepath.Join(dir, "backend.tf.json"), jsonInfra, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write file %q", filepath.Join(dir, "backend.tf.json"))
+	}
+
+	cmd := executil.Command(ctx, "terraform", "init", "-input=false")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to initialize Terraform backend for node %q: %s", nodeName, string(out))
+	}
+	return fn(dir)
+}
+
+// GetNodeLocationProperties returns the properties of the location of a node.
+//
+// The location is the one referenced in the node metadata if any, otherwise the first location
+// of the infrastructure type of the node is used, as done by generators.
+func GetNodeLocationProperties(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (config.DynamicMap, error) {
+	locationMgr, err := locations.GetManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	found, locationName, err := deployments.GetNodeMetadata(ctx, deploymentID, nodeName, tosca.MetadataLocationNameKey)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		locs, err := locationMgr.GetLocations()
+		if err != nil {
+			return nil, err
+		}
+		for _, loc := range locs {
+			if loc.Name == locationName {
+				return loc.Properties, nil
+			}
+		}
+		return nil, errors.Errorf("no such location %q for node %q", locationName, nodeName)
+	}
+
+	infraType, err := deployments.GetNodeInfrastructureType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if infraType == "" {
+		return nil, errors.Errorf("failed to find the location of node %q", nodeName)
+	}
+	return locationMgr.GetPropertiesForFirstLocationOfType(infraType)
+}
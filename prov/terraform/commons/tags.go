@@ -0,0 +1,82 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+const (
+	// DeploymentIDTagName is the name of the tag identifying the deployment of a resource
+	DeploymentIDTagName = "yorc_deployment_id"
+	// NodeNameTagName is the name of the tag identifying the node of a resource
+	NodeNameTagName = "yorc_node"
+)
+
+// AddResourceTags adds to the given tags of a resource the tags identifying its deployment and node,
+// and the tags defined by the resource_tags Terraform configuration option.
+//
+// Tags already defined, for instance by the tags property of the node, are kept unchanged.
+// The returned map is created if the given one is nil.
+func AddResourceTags(cfg config.Configuration, deploymentID, nodeName string, tags map[string]string) map[string]string {
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	setDefault := func(k, v string) {
+		if _, ok := tags[k]; !ok {
+			tags[k] = v
+		}
+	}
+	setDefault(DeploymentIDTagName, deploymentID)
+	setDefault(NodeNameTagName, nodeName)
+	for k, v := range cfg.Terraform.ResourceTags {
+		setDefault(k, v)
+	}
+	return tags
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// ToLabels converts tags into labels, as used by Google Cloud, which keys and values
+// may only contain lowercase letters, digits, underscores and dashes, up to 63 characters.
+// Keys have to start with a letter.
+func ToLabels(tags map[string]string) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	labels := make(map[string]string, len(tags))
+	for k, v := range tags {
+		k = toLabelString(k)
+		if k == "" {
+			continue
+		}
+		if k[0] < 'a' || k[0] > 'z' {
+			k = toLabelString("k" + k)
+		}
+		labels[k] = toLabelString(v)
+	}
+	return labels
+}
+
+func toLabelString(s string) string {
+	s = invalidLabelChars.ReplaceAllString(strings.ToLower(s), "_")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return s
+}
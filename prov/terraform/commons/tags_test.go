@@ -0,0 +1,48 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestAddResourceTags(t *testing.T) {
+	cfg := config.Configuration{Terraform: config.Terraform{ResourceTags: map[string]string{"owner": "team-a", NodeNameTagName: "ignored"}}}
+
+	tags := AddResourceTags(cfg, "myDep", "Compute", nil)
+	assert.Equal(t, map[string]string{DeploymentIDTagName: "myDep", NodeNameTagName: "Compute", "owner": "team-a"}, tags)
+
+	tags = AddResourceTags(cfg, "myDep", "Compute", map[string]string{"owner": "team-b"})
+	assert.Equal(t, "team-b", tags["owner"], "tags defined on the resource should be kept")
+}
+
+func TestToLabels(t *testing.T) {
+	assert.Nil(t, ToLabels(nil))
+	labels := ToLabels(map[string]string{
+		"Owner":                 "Team.A",
+		"1st":                   "v",
+		strings.Repeat("a", 70): "v",
+	})
+	assert.Equal(t, map[string]string{
+		"owner":                 "team_a",
+		"k1st":                  "v",
+		strings.Repeat("a", 63): "v",
+	}, labels)
+}
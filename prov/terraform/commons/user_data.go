@@ -0,0 +1,72 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+const (
+	// UserDataPropertyName is the name of the Compute property defining instances user data
+	UserDataPropertyName = "user_data"
+	// UserDataArtifactName is the name of the Compute artifact defining instances user data
+	UserDataArtifactName = "user_data"
+
+	cloudConfigHeader = "#cloud-config"
+)
+
+// GetUserData returns the user data passed to instances of a Compute node at boot time.
+//
+// User data may be a cloud-init configuration or a script. They are taken from the user_data property
+// of the node, TOSCA functions of this property being resolved, or else from the content of its user_data artifact.
+// An empty string is returned if none of them is defined.
+func GetUserData(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (string, error) {
+	userData, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, UserDataPropertyName)
+	if err != nil {
+		return "", err
+	}
+	if userData != nil && userData.RawString() != "" {
+		return userData.RawString(), nil
+	}
+
+	artifacts, err := deployments.GetFileArtifactsForNode(ctx, deploymentID, nodeName)
+	if err != nil {
+		return "", err
+	}
+	artifact, ok := artifacts[UserDataArtifactName]
+	if !ok || artifact == "" {
+		return "", nil
+	}
+	// Artifacts paths are relative to the root of the deployment archive
+	artifactPath := filepath.Join(cfg.WorkingDirectory, "deployments", deploymentID, "overlay", artifact)
+	b, err := ioutil.ReadFile(artifactPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read user data artifact %q of node %q", artifact, nodeName)
+	}
+	return string(b), nil
+}
+
+// IsCloudConfig returns true if user data are a cloud-init configuration rather than a script
+func IsCloudConfig(userData string) bool {
+	return strings.HasPrefix(strings.TrimSpace(userData), cloudConfigHeader)
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commons
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func testGetUserDataFromProperty(t *testing.T, kv *api.KV, cfg config.Configuration) {
+	deploymentID := loadTestYaml(t, kv)
+	userData, err := GetUserData(context.Background(), cfg, deploymentID, "Compute")
+	require.NoError(t, err)
+	assert.Equal(t, "#cloud-config\nhostname: myhost", userData)
+	assert.True(t, IsCloudConfig(userData))
+}
+
+func testGetUserDataFromArtifact(t *testing.T, kv *api.KV, cfg config.Configuration) {
+	deploymentID := loadTestYaml(t, kv)
+	script := "#!/bin/bash\necho hello\n"
+	scriptsDir := filepath.Join(cfg.WorkingDirectory, "deployments", deploymentID, "overlay", "scripts")
+	require.NoError(t, os.MkdirAll(scriptsDir, 0775))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(scriptsDir, "init.sh"), []byte(script), 0664))
+
+	userData, err := GetUserData(context.Background(), cfg, deploymentID, "Compute")
+	require.NoError(t, err)
+	assert.Equal(t, script, userData)
+	assert.False(t, IsCloudConfig(userData))
+
+	userData, err = GetUserData(context.Background(), cfg, deploymentID, "NoUserData")
+	require.NoError(t, err)
+	assert.Equal(t, "", userData)
+}
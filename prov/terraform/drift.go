@@ -0,0 +1,387 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+	"github.com/ystia/yorc/v4/registry"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const (
+	driftDetectionActionType = "terraform-drift-detection"
+
+	driftIntervalLocationProperty  = "terraform_drift_detection_interval"
+	driftReconcileLocationProperty = "terraform_drift_reconcile"
+
+	driftStatusAttribute      = "terraform_drift_status"
+	driftedResourcesAttribute = "terraform_drifted_resources"
+
+	driftStatusInSync  = "in_sync"
+	driftStatusDrifted = "drifted"
+
+	// terraform plan -detailed-exitcode exit code when changes are planned
+	planExitCodeChanges = 2
+)
+
+// driftResult is the result of a drift check on a node
+type driftResult struct {
+	Status    string   `json:"status"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+type driftDetectionOperator struct{}
+
+func driftPrefix(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-drift")
+}
+
+// registerDriftDetection adds a node to the nodes checked for drift within its deployment
+// and schedules the deployment drift detection action if not already done.
+//
+// Drift detection is enabled by the terraform_drift_detection_interval property of the node location.
+func registerDriftDetection(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) error {
+	locationProps, err := commons.GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	interval := locationProps.GetDuration(driftIntervalLocationProperty)
+	if interval <= 0 {
+		return nil
+	}
+
+	prefix := driftPrefix(deploymentID)
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return err
+	}
+	lock, err := consulutil.AcquireLock(cc, path.Join(prefix, ".lock"), 0)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Stored under lock so that the action can't deregister itself in the meantime
+	if err = consulutil.StoreConsulKeyAsString(path.Join(prefix, "nodes", nodeName), ""); err != nil {
+		return err
+	}
+
+	kvp, _, err := cc.KV().Get(path.Join(prefix, "actionID"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		// Already scheduled for this deployment
+		return nil
+	}
+	id, err := scheduling.RegisterAction(cc, deploymentID, interval, &prov.Action{ActionType: driftDetectionActionType})
+	if err != nil {
+		return err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Terraform drift detection scheduled every %s", interval)
+	return consulutil.StoreConsulKeyAsString(path.Join(prefix, "actionID"), id)
+}
+
+// unregisterDriftDetection removes a node from the nodes checked for drift.
+// The drift detection action deregisters itself once no node remains.
+func unregisterDriftDetection(deploymentID, nodeName string) error {
+	return consulutil.Delete(path.Join(driftPrefix(deploymentID), "nodes", nodeName), false)
+}
+
+func (o *driftDetectionOperator) ExecAction(ctx context.Context, cfg config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	exists, err := deployments.DoesDeploymentExists(ctx, deploymentID)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+	prefix := driftPrefix(deploymentID)
+	nodes, err := consulutil.GetKeys(path.Join(prefix, "nodes"))
+	if err != nil {
+		return false, err
+	}
+	if len(nodes) == 0 {
+		cc, err := cfg.GetConsulClient()
+		if err != nil {
+			return false, err
+		}
+		lock, err := consulutil.AcquireLock(cc, path.Join(prefix, ".lock"), 0)
+		if err != nil {
+			return false, err
+		}
+		defer lock.Unlock()
+		// A node may have been registered since nodes were listed
+		nodes, err = consulutil.GetKeys(path.Join(prefix, "nodes"))
+		if err != nil || len(nodes) > 0 {
+			return false, err
+		}
+		return true, consulutil.Delete(path.Join(prefix, "actionID"), false)
+	}
+
+	for _, nodeKey := range nodes {
+		nodeName := path.Base(nodeKey)
+		nodeCtx := events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName})
+		err = o.checkNodeDrift(nodeCtx, cfg, taskID, deploymentID, nodeName)
+		if err != nil {
+			// Keep on checking other nodes, next checks may succeed
+			log.Debugf("%+v", err)
+			events.WithContextOptionalFields(nodeCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to check Terraform drift of node %q: %v", nodeName, err)
+		}
+	}
+	return false, nil
+}
+
+func (o *driftDetectionOperator) checkNodeDrift(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName string) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	delegate, err := registry.GetRegistry().GetDelegateExecutor(nodeType)
+	if err != nil {
+		return err
+	}
+	e, ok := delegate.(*defaultExecutor)
+	if !ok {
+		log.Debugf("Node %q of deployment %q is not managed by Terraform, skipping drift detection", nodeName, deploymentID)
+		return nil
+	}
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	started, err := areInstancesStarted(ctx, deploymentID, nodeName, instances)
+	if err != nil || !started {
+		// An operation is in progress on this node, check it later
+		return err
+	}
+
+	infrastructurePath := filepath.Join(cfg.WorkingDirectory, "deployments", deploymentID, "terraform", taskID, nodeName)
+	if err = os.MkdirAll(infrastructurePath, 0775); err != nil {
+		return errors.Wrapf(err, "Failed to create infrastructure working directory %q", infrastructurePath)
+	}
+	defer func() {
+		if !cfg.Terraform.KeepGeneratedFiles {
+			os.RemoveAll(infrastructurePath)
+		}
+	}()
+
+	ctx, stopSSHAgent, err := startSSHAgent(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stopSSHAgent()
+
+	infraGenerated, outputs, env, cb, err := e.generator.GenerateTerraformInfraForNode(ctx, cfg, deploymentID, nodeName, infrastructurePath)
+	defer func() {
+		if cb != nil {
+			cb()
+		}
+	}()
+	if err != nil || !infraGenerated {
+		return err
+	}
+
+	result, err := e.planInfrastructure(ctx, cfg, deploymentID, nodeName, infrastructurePath, env)
+	if err != nil {
+		return err
+	}
+	if result.Status == driftStatusDrifted {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Terraform drift detected on node %q for resources: %v", nodeName, result.Resources)
+		locationProps, err := commons.GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+		if err != nil {
+			return err
+		}
+		if locationProps.GetBool(driftReconcileLocationProperty) {
+			reconciled, err := e.reconcileInfrastructure(ctx, cfg, deploymentID, nodeName, instances, infrastructurePath, outputs, env)
+			if err != nil {
+				return err
+			}
+			if reconciled {
+				result = &driftResult{Status: driftStatusInSync}
+			}
+		}
+	}
+	return storeDriftResult(ctx, deploymentID, nodeName, instances, result)
+}
+
+// reconcileInfrastructure applies the infrastructure of a drifted node.
+//
+// The deployment is flagged as processing a blocking operation during the apply so that no task
+// can be submitted on it. The reconciliation is postponed to next checks if a task is already
+// running on the deployment or if another blocking operation is in progress.
+func (e *defaultExecutor) reconcileInfrastructure(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instances []string, infrastructurePath string, outputs map[string]string, env []string) (bool, error) {
+	hasBlocking, err := deployments.HasBlockingOperationOnDeploymentFlag(ctx, deploymentID)
+	if err != nil || hasBlocking {
+		log.Debugf("A blocking operation is in progress on deployment %q, reconciliation of node %q postponed", deploymentID, nodeName)
+		return false, err
+	}
+	if err = deployments.AddBlockingOperationOnDeploymentFlag(ctx, deploymentID); err != nil {
+		return false, err
+	}
+	defer deployments.RemoveBlockingOperationOnDeploymentFlag(ctx, deploymentID)
+
+	// Tasks registered before the flag was set are still able to run
+	taskList, err := deployments.GetDeploymentTaskList(ctx, deploymentID)
+	if err != nil {
+		return false, err
+	}
+	hasLivingTask, livingTaskID, _, err := tasks.HasLivingTasks(taskList, []tasks.TaskType{tasks.TaskTypeQuery, tasks.TaskTypeAction})
+	if err != nil || hasLivingTask {
+		log.Debugf("Task %q is running on deployment %q, reconciliation of node %q postponed", livingTaskID, deploymentID, nodeName)
+		return false, err
+	}
+	// Instances may have been changed by a task which ended since the drift was detected
+	started, err := areInstancesStarted(ctx, deploymentID, nodeName, instances)
+	if err != nil || !started {
+		return false, err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Reconciling the infrastructure of node %q", nodeName)
+	err = e.applyInfrastructure(ctx, cfg, deploymentID, nodeName, infrastructurePath, outputs, env)
+	return err == nil, err
+}
+
+// areInstancesStarted checks that all given instances of a node are in the started state
+func areInstancesStarted(ctx context.Context, deploymentID, nodeName string, instances []string) (bool, error) {
+	for _, instance := range instances {
+		state, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instance)
+		if err != nil || state != tosca.NodeStateStarted {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// planInfrastructure runs a Terraform plan on an infrastructure to detect resources
+// that do not match anymore the applied infrastructure
+func (e *defaultExecutor) planInfrastructure(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string, env []string) (*driftResult, error) {
+	if err := e.remoteConfigInfrastructure(ctx, cfg, deploymentID, nodeName, infrastructurePath, env); err != nil {
+		return nil, err
+	}
+
+	cmd := executil.Command(ctx, "terraform", "plan", "-input=false", "-no-color", "-detailed-exitcode")
+	cmd.Dir = infrastructurePath
+	cmd.Env = mergeEnvironments(env)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return &driftResult{Status: driftStatusInSync}, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != planExitCodeChanges {
+		return nil, errors.Wrapf(err, "Failed to plan the infrastructure via terraform: %s", string(out))
+	}
+	return &driftResult{Status: driftStatusDrifted, Resources: parsePlanChanges(string(out))}, nil
+}
+
+// planResourceChangeRegexp matches resources changes in a Terraform plan output
+// like "  ~ openstack_compute_instance_v2.Compute-0" or "-/+ openstack_blockstorage_volume_v1.BS-0 (new resource required)"
+var planResourceChangeRegexp = regexp.MustCompile(`^\s{0,2}(-/\+|\+/-|\+|-|~|<=) (\S+)`)
+
+// parsePlanChanges returns the sorted addresses of resources having changes in a Terraform plan output
+func parsePlanChanges(plan string) []string {
+	resources := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(plan))
+	var inActions bool
+	for scanner.Scan() {
+		if !inActions {
+			// Skip the symbols legend
+			inActions = strings.HasPrefix(scanner.Text(), "Terraform will perform the following actions")
+			continue
+		}
+		matches := planResourceChangeRegexp.FindStringSubmatch(scanner.Text())
+		if matches == nil || matches[1] == "<=" {
+			// Data sources reads are not drifts
+			continue
+		}
+		resources = append(resources, matches[2])
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// storeDriftResult sets drift attributes on node instances when the drift status of the node changes
+func storeDriftResult(ctx context.Context, deploymentID, nodeName string, instances []string, result *driftResult) error {
+	resultKey := path.Join(driftPrefix(deploymentID), "nodes", nodeName)
+	kvp, _, err := consulutil.GetKV().Get(resultKey, nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil {
+		// Node has been uninstalled in the meantime
+		return nil
+	}
+	var previous driftResult
+	if len(kvp.Value) > 0 {
+		if err = json.Unmarshal(kvp.Value, &previous); err != nil {
+			log.Debugf("Ignoring invalid previous drift result for node %q: %v", nodeName, err)
+		}
+	}
+	if previous.Status == result.Status && stringSlicesEqual(previous.Resources, result.Resources) {
+		return nil
+	}
+
+	resources := result.Resources
+	if resources == nil {
+		resources = make([]string, 0)
+	}
+	for _, instance := range instances {
+		err = deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, instance, driftStatusAttribute, result.Status)
+		if err != nil {
+			return err
+		}
+		err = deployments.SetInstanceAttributeComplex(ctx, deploymentID, nodeName, instance, driftedResourcesAttribute, resources)
+		if err != nil {
+			return err
+		}
+	}
+	if previous.Status == driftStatusDrifted && result.Status == driftStatusInSync {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Infrastructure of node %q is back in sync with Terraform", nodeName)
+	}
+	return consulutil.StoreConsulKeyWithJSONValue(resultKey, result)
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
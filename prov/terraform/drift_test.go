@@ -0,0 +1,64 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlanChanges(t *testing.T) {
+	t.Parallel()
+	plan := `Refreshing Terraform state in-memory prior to plan...
+The refreshed state will be used to calculate this plan, but will not be
+persisted to local or remote state storage.
+
+openstack_compute_instance_v2.Compute-0: Refreshing state... (ID: 3f5a7c2e)
+
+------------------------------------------------------------------------
+
+An execution plan has been generated and is shown below.
+Resource actions are indicated with the following symbols:
+  ~ update in-place
+-/+ destroy and then create replacement
+ <= read (data resources)
+
+Terraform will perform the following actions:
+
+ <= data.consul_keys.network
+      id: <computed>
+
+  ~ openstack_compute_instance_v2.Compute-0
+      name: "compute-0" => "Compute-0"
+
+-/+ openstack_blockstorage_volume_v1.BS-0 (new resource required)
+      id: "9b1f" => <computed> (forces new resource)
+      size: "20" => "10" (forces new resource)
+
+
+Plan: 1 to add, 1 to change, 1 to destroy.
+`
+	assert.Equal(t, []string{"openstack_blockstorage_volume_v1.BS-0", "openstack_compute_instance_v2.Compute-0"}, parsePlanChanges(plan))
+	assert.Len(t, parsePlanChanges("No changes. Infrastructure is up-to-date."), 0)
+}
+
+func TestStringSlicesEqual(t *testing.T) {
+	t.Parallel()
+	assert.True(t, stringSlicesEqual(nil, []string{}))
+	assert.True(t, stringSlicesEqual([]string{"a", "b"}, []string{"a", "b"}))
+	assert.False(t, stringSlicesEqual([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(t, stringSlicesEqual([]string{"a"}, []string{"a", "b"}))
+}
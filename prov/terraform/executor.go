@@ -48,6 +48,11 @@ func NewExecutor(generator commons.Generator, preDestroyCheck commons.PreDestroy
 	return &defaultExecutor{generator: generator, preDestroyCheck: preDestroyCheck}
 }
 
+// PurgeNode implements prov.DeploymentPurger by deleting the Terraform state of the node from its backend
+func (e *defaultExecutor) PurgeNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) error {
+	return commons.DeleteState(ctx, cfg, deploymentID, nodeName)
+}
+
 func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
 	instances, err := tasks.GetInstances(ctx, taskID, deploymentID, nodeName)
 	if err != nil {
@@ -87,25 +92,11 @@ func (e *defaultExecutor) installNode(ctx context.Context, cfg config.Configurat
 		}
 	}
 
-	if !cfg.DisableSSHAgent {
-		sshAgent, err := sshutil.NewSSHAgent(ctx)
-		if err != nil {
-			return err
-		}
-		ctx = commons.StoreSSHAgentInContext(ctx, sshAgent)
-		defer func() {
-			// Stop the sshAgent if used during provisioning
-			// Do not return any error if failure occured during this
-			err := sshAgent.RemoveAllKeys()
-			if err != nil {
-				log.Debugf("Warning: failed to remove all SSH agents keys due to error:%+v", err)
-			}
-			err = sshAgent.Stop()
-			if err != nil {
-				log.Debugf("Warning: failed to stop SSH agent due to error:%+v", err)
-			}
-		}()
+	ctx, stopSSHAgent, err := startSSHAgent(ctx, cfg)
+	if err != nil {
+		return err
 	}
+	defer stopSSHAgent()
 
 	infraGenerated, outputs, env, cb, err := e.generator.GenerateTerraformInfraForNode(ctx, cfg, deploymentID, nodeName, infrastructurePath)
 	// Execute callback if needed even if there is an error
@@ -128,6 +119,17 @@ func (e *defaultExecutor) installNode(ctx context.Context, cfg config.Configurat
 			return err
 		}
 	}
+	if infraGenerated {
+		// Drift detection is not critical for the deployment
+		if err = registerDriftDetection(ctx, cfg, deploymentID, nodeName); err != nil {
+			log.Debugf("%+v", err)
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to register Terraform drift detection for node %q: %v", nodeName, err)
+		}
+		if err = registerInterruptionDetection(ctx, cfg, e.generator, deploymentID, nodeName); err != nil {
+			log.Debugf("%+v", err)
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to register instances interruption detection for node %q: %v", nodeName, err)
+		}
+	}
 	return nil
 }
 
@@ -159,7 +161,34 @@ func (e *defaultExecutor) uninstallNode(ctx context.Context, cfg config.Configur
 			return err
 		}
 	}
-	return nil
+	if err = unregisterInterruptionDetection(ctx, deploymentID, nodeName, instances); err != nil {
+		return err
+	}
+	return unregisterDriftDetection(deploymentID, nodeName)
+}
+
+// startSSHAgent starts an SSH agent stored in the returned context unless disabled in configuration.
+// The returned function stops it.
+func startSSHAgent(ctx context.Context, cfg config.Configuration) (context.Context, func(), error) {
+	if cfg.DisableSSHAgent {
+		return ctx, func() {}, nil
+	}
+	sshAgent, err := sshutil.NewSSHAgent(ctx)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return commons.StoreSSHAgentInContext(ctx, sshAgent), func() {
+		// Stop the sshAgent if used during provisioning
+		// Do not return any error if failure occured during this
+		err := sshAgent.RemoveAllKeys()
+		if err != nil {
+			log.Debugf("Warning: failed to remove all SSH agents keys due to error:%+v", err)
+		}
+		err = sshAgent.Stop()
+		if err != nil {
+			log.Debugf("Warning: failed to stop SSH agent due to error:%+v", err)
+		}
+	}, nil
 }
 
 func (e *defaultExecutor) remoteConfigInfrastructure(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string, env []string) error {
@@ -188,10 +217,10 @@ func (e *defaultExecutor) remoteConfigInfrastructure(ctx context.Context, cfg co
 	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RunBufferedRegistration(out, quit)
 
 	if err := cmd.Start(); err != nil {
-		return errors.Wrap(err, "Failed to setup Consul remote backend for terraform")
+		return errors.Wrap(err, "Failed to setup remote backend for terraform")
 	}
 
-	return errors.Wrap(cmd.Wait(), "Failed to setup Consul remote backend for terraform")
+	return errors.Wrap(cmd.Wait(), "Failed to setup remote backend for terraform")
 }
 
 func (e *defaultExecutor) retrieveOutputs(ctx context.Context, infraPath string, outputs map[string]string) error {
@@ -282,7 +311,7 @@ func (e *defaultExecutor) storeOutputs(ctx context.Context, store consulutil.Con
 
 func (e *defaultExecutor) applyInfrastructure(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string, outputs map[string]string, env []string) error {
 
-	// Remote Configuration for Terraform State to store it in the Consul KV store
+	// Remote Configuration for Terraform State to store it in the backend defined for the location
 	if err := e.remoteConfigInfrastructure(ctx, cfg, deploymentID, nodeName, infrastructurePath, env); err != nil {
 		return err
 	}
@@ -0,0 +1,163 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+const defaultCloudSQLTier = "db-f1-micro"
+
+func (g *googleGenerator) generateCloudSQLInstance(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName, instanceName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.google.CloudSQLInstance" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	sqlInstance := &SQLDatabaseInstance{}
+	var diskSize, authorizedNetworks, userName, userPassword, backupStartTime string
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&sqlInstance.Name, "name", false},
+		{&sqlInstance.DatabaseVersion, "database_version", true},
+		{&sqlInstance.Region, "region", false},
+		{&sqlInstance.Project, "project", false},
+		{&sqlInstance.Settings.Tier, "tier", false},
+		{&sqlInstance.Settings.DiskType, "disk_type", false},
+		{&diskSize, "disk_size", false},
+		{&authorizedNetworks, "authorized_networks", false},
+		{&userName, "user_name", false},
+		{&userPassword, "user_password", false},
+		{&backupStartTime, "backup_start_time", false},
+	}
+
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName,
+			stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+
+	if sqlInstance.Name == "" {
+		sqlInstance.Name = getResourcesPrefix(cfg, deploymentID) + nodeName
+	}
+	// Must be a match of regex '[a-z][-a-z0-9]*'
+	sqlInstance.Name = strings.Replace(strings.ToLower(sqlInstance.Name), "_", "-", -1)
+
+	if sqlInstance.Region == "" {
+		sqlInstance.Region = locationProps.GetString("region")
+		if sqlInstance.Region == "" {
+			return errors.New("Region must be set for CloudSQLInstance node type or in google infrastructure config")
+		}
+	}
+	if sqlInstance.Settings.Tier == "" {
+		sqlInstance.Settings.Tier = defaultCloudSQLTier
+	}
+	if diskSize != "" {
+		// Default size unit is MB
+		sqlInstance.Settings.DiskSize, err = sizeutil.ConvertToGB(diskSize)
+		if err != nil {
+			return err
+		}
+	}
+	sqlInstance.Settings.DiskAutoresize, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "disk_autoresize")
+	if err != nil {
+		return err
+	}
+	sqlInstance.Settings.UserLabels, err = deployments.GetKeyValuePairsNodeProperty(ctx, deploymentID, nodeName, "labels")
+	if err != nil {
+		return err
+	}
+	sqlInstance.Settings.UserLabels = addResourceLabels(cfg, deploymentID, nodeName, sqlInstance.Settings.UserLabels)
+
+	sqlInstance.Settings.IPConfiguration.IPV4Enabled = true
+	sqlInstance.Settings.IPConfiguration.RequireSSL, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "require_ssl")
+	if err != nil {
+		return err
+	}
+	if authorizedNetworks != "" {
+		for _, network := range strings.Split(authorizedNetworks, ",") {
+			if network = strings.TrimSpace(network); network != "" {
+				sqlInstance.Settings.IPConfiguration.AuthorizedNetworks = append(sqlInstance.Settings.IPConfiguration.AuthorizedNetworks,
+					SQLAuthorizedNetwork{Value: network})
+			}
+		}
+	}
+
+	backupEnabled, err := deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "backup_enabled")
+	if err != nil {
+		return err
+	}
+	if backupEnabled {
+		sqlInstance.Settings.BackupConfiguration = &SQLBackupConfiguration{Enabled: true, StartTime: backupStartTime}
+	}
+
+	log.Debugf("Add Cloud SQL instance:%+v", sqlInstance)
+	commons.AddResource(infrastructure, "google_sql_database_instance", sqlInstance.Name, sqlInstance)
+	instanceRef := fmt.Sprintf("${google_sql_database_instance.%s.name}", sqlInstance.Name)
+
+	databases, err := deployments.GetStringArrayNodeProperty(ctx, deploymentID, nodeName, "databases")
+	if err != nil {
+		return err
+	}
+	for _, database := range databases {
+		sqlDatabase := &SQLDatabase{Name: database, Instance: instanceRef, Project: sqlInstance.Project}
+		commons.AddResource(infrastructure, "google_sql_database", sqlInstance.Name+"-"+database, sqlDatabase)
+	}
+
+	if userName != "" {
+		if userPassword == "" {
+			return errors.Errorf("user_password must be set with user_name for CloudSQLInstance %q", nodeName)
+		}
+		sqlUser := &SQLUser{Name: userName, Instance: instanceRef, Password: userPassword, Host: "%", Project: sqlInstance.Project}
+		if strings.HasPrefix(strings.ToUpper(sqlInstance.DatabaseVersion), "POSTGRES") {
+			// Host is only supported for MySQL
+			sqlUser.Host = ""
+		}
+		commons.AddResource(infrastructure, "google_sql_user", sqlInstance.Name+"-"+userName, sqlUser)
+	}
+
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
+	connectionNameKey := nodeName + "-connection-name"
+	commons.AddOutput(infrastructure, connectionNameKey, &commons.Output{
+		Value: fmt.Sprintf("${google_sql_database_instance.%s.connection_name}", sqlInstance.Name)})
+	outputs[path.Join(nodeKey, "/attributes/connection_name")] = connectionNameKey
+
+	ipAddressKey := nodeName + "-ip-address"
+	commons.AddOutput(infrastructure, ipAddressKey, &commons.Output{
+		Value: fmt.Sprintf("${google_sql_database_instance.%s.ip_address.0.ip_address}", sqlInstance.Name)})
+	outputs[path.Join(nodeKey, "/attributes/ip_address")] = ipAddressKey
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName)
+	outputs[path.Join(instancesKey, instanceName, "/capabilities/database_endpoint/attributes/ip_address")] = ipAddressKey
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleCloudSQLInstance(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := googleGenerator{}
+	err := g.generateCloudSQLInstance(context.Background(), cfg, testLocationProperties, deploymentID, "SQL_DB", "0", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate Cloud SQL instance for %s", deploymentID)
+
+	name := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + "sql-db")
+	require.Len(t, infrastructure.Resource["google_sql_database_instance"], 1, "Expected one Cloud SQL instance")
+	sqlInstance, ok := infrastructure.Resource["google_sql_database_instance"].(map[string]interface{})[name].(*SQLDatabaseInstance)
+	require.True(t, ok, "%s is not a SQLDatabaseInstance", name)
+	assert.Equal(t, &SQLDatabaseInstance{
+		Name:            name,
+		DatabaseVersion: "MYSQL_5_7",
+		Region:          "europe-west-1",
+		Settings: SQLSettings{
+			Tier:           "db-f1-micro",
+			DiskSize:       20,
+			DiskAutoresize: true,
+			UserLabels:     map[string]string{"key1": "value1", "yorc_deployment_id": strings.ToLower(deploymentID), "yorc_node": "sql_db"},
+			IPConfiguration: SQLIPConfiguration{
+				IPV4Enabled:        true,
+				AuthorizedNetworks: []SQLAuthorizedNetwork{{Value: "10.0.0.0/8"}, {Value: "192.168.1.0/24"}},
+			},
+			BackupConfiguration: &SQLBackupConfiguration{Enabled: true, StartTime: "02:00"},
+		},
+	}, sqlInstance)
+
+	instanceRef := "${google_sql_database_instance." + name + ".name}"
+	databases := infrastructure.Resource["google_sql_database"].(map[string]interface{})
+	require.Len(t, databases, 2)
+	assert.Equal(t, &SQLDatabase{Name: "db2", Instance: instanceRef}, databases[name+"-db2"])
+	users := infrastructure.Resource["google_sql_user"].(map[string]interface{})
+	require.Len(t, users, 1)
+	assert.Equal(t, &SQLUser{Name: "admin", Instance: instanceRef, Password: "secret", Host: "%"}, users[name+"-admin"])
+
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/SQL_DB")
+	assert.Equal(t, "SQL_DB-connection-name", outputs[path.Join(nodeKey, "attributes/connection_name")])
+	assert.Equal(t, "SQL_DB-ip-address", outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/SQL_DB/0/capabilities/database_endpoint/attributes/ip_address")])
+}
@@ -44,12 +44,104 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 		"topology", "instances")
 	instancesKey := path.Join(instancesPrefix, nodeName)
 
-	instance := ComputeInstance{}
+	instance, noAddress, err := buildComputeInstance(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
 
 	// Must be a match of regex '(?:[a-z](?:[-a-z0-9]{0,61}[a-z0-9])?)'
 	instance.Name = strings.ToLower(getResourcesPrefix(cfg, deploymentID) + nodeName + "-" + instanceName)
 	instance.Name = strings.Replace(instance.Name, "_", "-", -1)
 
+	// External IP address can be static if required
+	if !noAddress {
+		hasStaticAddressReq, addressNode, err := deployments.HasAnyRequirementCapability(ctx, deploymentID, nodeName, "assignment", "yorc.capabilities.Assignable")
+		if err != nil {
+			return err
+		}
+		if hasStaticAddressReq {
+			// Address Lookup
+			instance.NetworkInterfaces[0].AccessConfigs[0].NatIP, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, addressNode, instanceName, "ip_address")
+			if err != nil {
+				return err
+			}
+		}
+		// else the NAT IP is empty, which means an ephemeral external IP
+		// address will be assigned to the instance
+	}
+
+	// Get connection info (user, private key)
+	user, privateKey, err := commons.GetConnInfoFromEndpointCredentials(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	// Add additional Scratch disks
+	if instance.ScratchDisks, err = getScratchDisks(ctx, deploymentID, nodeName); err != nil {
+		return err
+	}
+
+	// Add the compute instance
+	commons.AddResource(infrastructure, "google_compute_instance", instance.Name, &instance)
+
+	// Attach Persistent disks
+	devices, err := addAttachedDisks(ctx, cfg, deploymentID, nodeName, instanceName, instance.Name, infrastructure, outputs)
+	if err != nil {
+		return err
+	}
+
+	// Define the private IP address using the value exported by Terraform
+	privateIP := fmt.Sprintf("${google_compute_instance.%s.network_interface.0.address}",
+		instance.Name)
+
+	privateIPKey := nodeName + "-" + instanceName + "-privateIP"
+	commons.AddOutput(infrastructure, privateIPKey, &commons.Output{Value: privateIP})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = privateIPKey
+
+	// Define the public IP using the value exported by Terraform
+	// except if it was specified the instance shouldn't have a public address
+	var accessIP, accessIPKey string
+	if noAddress {
+		accessIP = privateIP
+		accessIPKey = privateIPKey
+	} else {
+		accessIP = fmt.Sprintf("${google_compute_instance.%s.network_interface.0.access_config.0.assigned_nat_ip}",
+			instance.Name)
+
+		publicIPKey := nodeName + "-" + instanceName + "-publicIP"
+		accessIPKey = publicIPKey
+		commons.AddOutput(infrastructure, publicIPKey, &commons.Output{Value: accessIP})
+
+		outputs[path.Join(instancesKey, instanceName, "/attributes/public_address")] = publicIPKey
+		outputs[path.Join(instancesKey, instanceName, "/attributes/public_ip_address")] = publicIPKey
+	}
+
+	// ip_adress attribute and endpoint capability
+	outputs[path.Join(instancesKey, instanceName, "/capabilities/endpoint/attributes/ip_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/attributes/ip_address")] = accessIPKey
+
+	// Add Connection check
+	if err = commons.AddConnectionCheckResource(ctx, deploymentID, nodeName, infrastructure, user, privateKey, accessIP, instance.Name, env); err != nil {
+		return err
+	}
+
+	// Retrieve devices
+	if len(devices) > 0 {
+		if err = handleDeviceAttributes(ctx, cfg, infrastructure, &instance, devices, user, privateKey, accessIP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildComputeInstance returns a compute instance definition from the properties of a Compute node,
+// shared by standalone instances and instance templates of managed instance groups.
+// It also returns true if no external IP address should be assigned to instances.
+func buildComputeInstance(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (ComputeInstance, bool, error) {
+	var err error
+	instance := ComputeInstance{}
+
 	// Getting string parameters
 	var imageProject, imageFamily, image, serviceAccount string
 
@@ -70,7 +162,7 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 	for _, stringParam := range stringParams {
 		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName,
 			stringParam.propertyName, stringParam.mandatory); err != nil {
-			return err
+			return instance, false, err
 		}
 	}
 
@@ -84,7 +176,7 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 			bootImage = bootImage + "/" + imageFamily
 		} else {
 			// Unexpected image project without a family or image
-			return errors.Errorf("Exepected an image or family for image project %s on %s", imageProject, nodeName)
+			return instance, false, errors.Errorf("Exepected an image or family for image project %s on %s", imageProject, nodeName)
 		}
 	} else if image != "" {
 		bootImage = image
@@ -101,26 +193,26 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 	// Network definition
 	var noAddress bool
 	if noAddress, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "no_address"); err != nil {
-		return err
+		return instance, false, err
 	}
 
 	// Define if a private network access is required
 	var netInterfaces []NetworkInterface
 	reqPrivateNetwork, _, err := deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "network", "yorc.nodes.google.PrivateNetwork")
 	if err != nil {
-		return err
+		return instance, false, err
 	}
 	// Check for subnet otherwise
 	if !reqPrivateNetwork {
 		reqPrivateNetwork, _, err = deployments.HasAnyRequirementFromNodeType(ctx, deploymentID, nodeName, "network", "yorc.nodes.google.Subnetwork")
 		if err != nil {
-			return err
+			return instance, false, err
 		}
 	}
 	if reqPrivateNetwork {
 		netInterfaces, err = addPrivateNetworkInterfaces(ctx, deploymentID, nodeName)
 		if err != nil {
-			return err
+			return instance, false, err
 		}
 	} else {
 		// Create a default private network interface
@@ -129,30 +221,14 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 
 	// Define an external access if there will be an external IP address
 	if !noAddress {
-		hasStaticAddressReq, addressNode, err := deployments.HasAnyRequirementCapability(ctx, deploymentID, nodeName, "assignment", "yorc.capabilities.Assignable")
-		if err != nil {
-			return err
-		}
-		var externalAddress string
-		// External IP address can be static if required
-		if hasStaticAddressReq {
-			// Address Lookup
-			externalAddress, err = deployments.LookupInstanceAttributeValue(ctx, deploymentID, addressNode, instanceName, "ip_address")
-			if err != nil {
-				return err
-			}
-		}
-		// else externalAddress is empty, which means an ephemeral external IP
-		// address will be assigned to the instance
-		accessConfig := AccessConfig{NatIP: externalAddress}
-		netInterfaces[0].AccessConfigs = []AccessConfig{accessConfig}
+		netInterfaces[0].AccessConfigs = []AccessConfig{{}}
 	}
 	instance.NetworkInterfaces = netInterfaces
 
 	// Scheduling definition
 	var preemptible bool
 	if preemptible, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "preemptible"); err != nil {
-		return err
+		return instance, false, err
 	}
 
 	if preemptible {
@@ -162,7 +238,7 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 	// Get list of strings parameters
 	var scopes []string
 	if scopes, err = deployments.GetStringArrayNodeProperty(ctx, deploymentID, nodeName, "scopes"); err != nil {
-		return err
+		return instance, false, err
 	}
 
 	if serviceAccount != "" || len(scopes) > 0 {
@@ -175,104 +251,74 @@ func (g *googleGenerator) generateComputeInstance(ctx context.Context, cfg confi
 	}
 
 	if instance.Tags, err = deployments.GetStringArrayNodeProperty(ctx, deploymentID, nodeName, "tags"); err != nil {
-		return err
+		return instance, false, err
 	}
+	// Firewalls defined in the topology apply to instances having their target tag
+	firewallTags, err := getFirewallsTargetTags(ctx, deploymentID, nodeName)
+	if err != nil {
+		return instance, false, err
+	}
+	instance.Tags = append(instance.Tags, firewallTags...)
 
 	// Get list of key/value pairs parameters
 	if instance.Labels, err = deployments.GetKeyValuePairsNodeProperty(ctx, deploymentID, nodeName, "labels"); err != nil {
-		return err
+		return instance, false, err
 	}
+	instance.Labels = addResourceLabels(cfg, deploymentID, nodeName, instance.Labels)
 
 	if instance.Metadata, err = deployments.GetKeyValuePairsNodeProperty(ctx, deploymentID, nodeName, "metadata"); err != nil {
-		return err
+		return instance, false, err
 	}
-
-	// Get connection info (user, private key)
-	user, privateKey, err := commons.GetConnInfoFromEndpointCredentials(ctx, deploymentID, nodeName)
+	userData, err := commons.GetUserData(ctx, cfg, deploymentID, nodeName)
 	if err != nil {
-		return err
+		return instance, false, err
+	}
+	if userData != "" {
+		if instance.Metadata == nil {
+			instance.Metadata = make(map[string]string)
+		}
+		// cloud-init reads its configuration from the user-data key while
+		// scripts are run by the Google guest environment
+		metadataKey := "user-data"
+		if _, ok := instance.Metadata["startup-script"]; !ok && !commons.IsCloudConfig(userData) {
+			metadataKey = "startup-script"
+		}
+		instance.Metadata[metadataKey] = userData
 	}
 
-	// Add additional Scratch disks
+	return instance, noAddress, nil
+}
+
+// getScratchDisks returns the scratch disks defined by the scratch_disks property of a Compute node
+func getScratchDisks(ctx context.Context, deploymentID, nodeName string) ([]ScratchDisk, error) {
 	scratchDisks, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "scratch_disks")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var result []ScratchDisk
 	if scratchDisks != nil && scratchDisks.RawString() != "" {
 		list, ok := scratchDisks.Value.([]interface{})
 		if !ok {
-			return errors.New("failed to retrieve scratch disk Tosca Value: not expected type")
+			return nil, errors.New("failed to retrieve scratch disk Tosca Value: not expected type")
 		}
-		instance.ScratchDisks = make([]ScratchDisk, 0)
+		result = make([]ScratchDisk, 0)
 		for _, n := range list {
 			v, ok := n.(map[string]interface{})
 			if !ok {
-				return errors.New("failed to retrieve scratch disk map: not expected type")
+				return nil, errors.New("failed to retrieve scratch disk map: not expected type")
 			}
 			for _, val := range v {
 				i, ok := val.(string)
 				if !ok {
-					return errors.New("failed to retrieve scratch disk interface value: not expected type")
+					return nil, errors.New("failed to retrieve scratch disk interface value: not expected type")
 				}
 				scratch := ScratchDisk{Interface: i}
-				instance.ScratchDisks = append(instance.ScratchDisks, scratch)
+				result = append(result, scratch)
 			}
 		}
 	}
-
-	// Add the compute instance
-	commons.AddResource(infrastructure, "google_compute_instance", instance.Name, &instance)
-
-	// Attach Persistent disks
-	devices, err := addAttachedDisks(ctx, cfg, deploymentID, nodeName, instanceName, instance.Name, infrastructure, outputs)
-	if err != nil {
-		return err
-	}
-
-	// Define the private IP address using the value exported by Terraform
-	privateIP := fmt.Sprintf("${google_compute_instance.%s.network_interface.0.address}",
-		instance.Name)
-
-	privateIPKey := nodeName + "-" + instanceName + "-privateIP"
-	commons.AddOutput(infrastructure, privateIPKey, &commons.Output{Value: privateIP})
-	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = privateIPKey
-
-	// Define the public IP using the value exported by Terraform
-	// except if it was specified the instance shouldn't have a public address
-	var accessIP, accessIPKey string
-	if noAddress {
-		accessIP = privateIP
-		accessIPKey = privateIPKey
-	} else {
-		accessIP = fmt.Sprintf("${google_compute_instance.%s.network_interface.0.access_config.0.assigned_nat_ip}",
-			instance.Name)
-
-		publicIPKey := nodeName + "-" + instanceName + "-publicIP"
-		accessIPKey = publicIPKey
-		commons.AddOutput(infrastructure, publicIPKey, &commons.Output{Value: accessIP})
-
-		outputs[path.Join(instancesKey, instanceName, "/attributes/public_address")] = publicIPKey
-		outputs[path.Join(instancesKey, instanceName, "/attributes/public_ip_address")] = publicIPKey
-	}
-
-	// ip_adress attribute and endpoint capability
-	outputs[path.Join(instancesKey, instanceName, "/capabilities/endpoint/attributes/ip_address")] = accessIPKey
-	outputs[path.Join(instancesKey, instanceName, "/attributes/ip_address")] = accessIPKey
-
-	// Add Connection check
-	if err = commons.AddConnectionCheckResource(ctx, deploymentID, nodeName, infrastructure, user, privateKey, accessIP, instance.Name, env); err != nil {
-		return err
-	}
-
-	// Retrieve devices
-	if len(devices) > 0 {
-		if err = handleDeviceAttributes(ctx, cfg, infrastructure, &instance, devices, user, privateKey, accessIP); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return result, nil
 }
 
 func handleDeviceAttributes(ctx context.Context, cfg config.Configuration, infrastructure *commons.Infrastructure, instance *ComputeInstance, devices []string, user string, privateKey *sshutil.PrivateKey, accessIP string) error {
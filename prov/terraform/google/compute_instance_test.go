@@ -22,6 +22,7 @@ import (
 	"github.com/ystia/yorc/v4/tosca"
 	"io/ioutil"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/testutil"
@@ -83,7 +84,8 @@ func testSimpleComputeInstance(t *testing.T, cfg config.Configuration) {
 	assert.Equal(t, "yorc@yorc.net", compute.ServiceAccounts[0].Email, "Unexpected Service Account")
 
 	assert.Equal(t, []string{"tag1", "tag2"}, compute.Tags)
-	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2"}, compute.Labels)
+	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2", "yorc_deployment_id": strings.ToLower(deploymentID), "yorc_node": "computeinstance"}, compute.Labels)
+	assert.Equal(t, map[string]string{"startup-script": "#!/bin/bash\nyum install -y nginx\n"}, compute.Metadata)
 
 	require.Contains(t, infrastructure.Resource, "null_resource")
 	require.Len(t, infrastructure.Resource["null_resource"], 1)
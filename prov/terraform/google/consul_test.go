@@ -70,5 +70,14 @@ func TestRunConsulGooglePackageTests(t *testing.T) {
 		t.Run("simpleComputeInstanceWithSimpleNetwork", func(t *testing.T) {
 			testSimpleComputeInstanceWithSimpleNetwork(t, srv, cfg)
 		})
+		t.Run("simpleFirewall", func(t *testing.T) {
+			testSimpleFirewall(t, cfg)
+		})
+		t.Run("simpleManagedInstanceGroup", func(t *testing.T) {
+			testSimpleManagedInstanceGroup(t, srv, cfg)
+		})
+		t.Run("simpleCloudSQLInstance", func(t *testing.T) {
+			testSimpleCloudSQLInstance(t, cfg)
+		})
 	})
 }
@@ -0,0 +1,72 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// GetPricedResources returns the resources billed for each instance of a node
+func (g *googleGenerator) GetPricedResources(ctx context.Context, deploymentID, nodeName string) ([]commons.PricedResource, error) {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	switch nodeType {
+	case "yorc.nodes.google.Compute":
+		machineType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "machine_type", false)
+		if err != nil {
+			return nil, err
+		}
+		preemptible, err := deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "preemptible")
+		if err != nil {
+			return nil, err
+		}
+		kind := commons.ComputeResourceKind
+		if preemptible {
+			kind = commons.SpotComputeResourceKind
+		}
+		return []commons.PricedResource{{Kind: kind, Type: machineType}}, nil
+	case "yorc.nodes.google.PersistentDisk":
+		diskType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "type", false)
+		if err != nil {
+			return nil, err
+		}
+		if diskType == "" {
+			diskType = "pd-standard"
+		}
+		return commons.GetStoragePricedResources(ctx, deploymentID, nodeName, diskType)
+	case "yorc.nodes.google.Address":
+		addressType, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "address_type", false)
+		if err != nil || strings.EqualFold(addressType, "INTERNAL") {
+			return nil, err
+		}
+		return []commons.PricedResource{{Kind: commons.PublicIPResourceKind}}, nil
+	case "yorc.nodes.google.CloudSQLInstance":
+		tier, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "tier", false)
+		if err != nil {
+			return nil, err
+		}
+		if tier == "" {
+			tier = defaultCloudSQLTier
+		}
+		return []commons.PricedResource{{Kind: commons.DatabaseResourceKind, Type: tier}}, nil
+	}
+	return nil, nil
+}
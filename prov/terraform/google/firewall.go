@@ -0,0 +1,164 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *googleGenerator) generateFirewall(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.google.Firewall" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
+
+	// Must be a match of regex '[a-z]([-a-z0-9]*[a-z0-9])?', the name is also used as target tag
+	name := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + nodeName)
+	name = strings.Replace(name, "_", "-", -1)
+	firewall := &Firewall{Name: name, TargetTags: []string{name}}
+	if firewall.Description, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "description", false); err != nil {
+		return err
+	}
+	if firewall.Network, err = getFirewallNetwork(ctx, deploymentID, nodeName); err != nil {
+		return err
+	}
+	if firewall.SourceRanges, err = deployments.GetStringArrayNodeProperty(ctx, deploymentID, nodeName, "source_ranges"); err != nil {
+		return err
+	}
+
+	// Allow rules to reach endpoints of components hosted on computes having the firewall target tag
+	endpointRules, err := commons.GetEndpointsPortRules(ctx, deploymentID, "firewall", nodeName)
+	if err != nil {
+		return err
+	}
+	portsByProtocol := make(map[string][]string)
+	var protocols []string
+	for _, r := range endpointRules {
+		if _, ok := portsByProtocol[r.Protocol]; !ok {
+			protocols = append(protocols, r.Protocol)
+		}
+		portsByProtocol[r.Protocol] = append(portsByProtocol[r.Protocol], strconv.Itoa(r.Port))
+	}
+	for _, protocol := range protocols {
+		firewall.Allow = append(firewall.Allow, AllowRule{Protocol: protocol, Ports: portsByProtocol[protocol]})
+	}
+
+	// Rules explicitly defined on the firewall
+	rules, err := getFirewallRules(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	firewall.Allow = append(firewall.Allow, rules...)
+	if len(firewall.Allow) == 0 {
+		// Nothing references this firewall yet, as Yorc connects to computes through SSH allow it
+		firewall.Allow = []AllowRule{{Protocol: "tcp", Ports: []string{strconv.Itoa(commons.SSHPort)}}}
+	}
+
+	log.Debugf("Add firewall:%+v", firewall)
+	commons.AddResource(infrastructure, "google_compute_firewall", name, firewall)
+
+	firewallNameKey := nodeName + "-firewall-name"
+	commons.AddOutput(infrastructure, firewallNameKey, &commons.Output{Value: fmt.Sprintf("${google_compute_firewall.%s.name}", name)})
+	outputs[path.Join(nodeKey, "/attributes/firewall_name")] = firewallNameKey
+	targetTagKey := nodeName + "-target-tag"
+	commons.AddOutput(infrastructure, targetTagKey, &commons.Output{Value: name})
+	outputs[path.Join(nodeKey, "/attributes/target_tag")] = targetTagKey
+	return nil
+}
+
+// getFirewallNetwork returns the network of a firewall, either the network of the private network
+// targeted by its network requirement or the one defined by its network property
+func getFirewallNetwork(ctx context.Context, deploymentID, nodeName string) (string, error) {
+	reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "network")
+	if err != nil {
+		return "", err
+	}
+	if len(reqs) > 0 {
+		return deployments.LookupInstanceAttributeValue(ctx, deploymentID, reqs[0].Node, "0", "network_name")
+	}
+	network, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "network", false)
+	if err != nil || network != "" {
+		return network, err
+	}
+	return "default", nil
+}
+
+func getFirewallRules(ctx context.Context, deploymentID, nodeName string) ([]AllowRule, error) {
+	rulesValue, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "rules")
+	if err != nil || rulesValue == nil || rulesValue.RawString() == "" {
+		return nil, err
+	}
+	list, ok := rulesValue.Value.([]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve yorc.datatypes.google.FirewallRule Tosca Value: not expected type")
+	}
+
+	rules := make([]AllowRule, 0, len(list))
+	for i := range list {
+		ind := strconv.Itoa(i)
+		protocol, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "rules", ind, "protocol")
+		if err != nil {
+			return nil, err
+		}
+		rule := AllowRule{Protocol: "tcp"}
+		if protocol != nil && protocol.RawString() != "" {
+			rule.Protocol = strings.ToLower(protocol.RawString())
+		}
+		ports, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "rules", ind, "ports")
+		if err != nil {
+			return nil, err
+		}
+		if ports != nil && ports.RawString() != "" {
+			for _, port := range strings.Split(ports.RawString(), ",") {
+				rule.Ports = append(rule.Ports, strings.TrimSpace(port))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getFirewallsTargetTags returns the target tags of firewalls a node is bound to through firewall requirements
+func getFirewallsTargetTags(ctx context.Context, deploymentID, nodeName string) ([]string, error) {
+	reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "firewall")
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, req := range reqs {
+		tag, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, req.Node, "0", "target_tag")
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
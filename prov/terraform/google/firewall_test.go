@@ -0,0 +1,62 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleFirewall(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := googleGenerator{}
+	err := g.generateFirewall(context.Background(), cfg, deploymentID, "Firewall", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate firewall for %s", deploymentID)
+
+	firewallName := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + "firewall")
+	require.Len(t, infrastructure.Resource["google_compute_firewall"], 1, "Expected one firewall")
+	firewall, ok := infrastructure.Resource["google_compute_firewall"].(map[string]interface{})[firewallName].(*Firewall)
+	require.True(t, ok, "%s is not a Firewall", firewallName)
+	assert.Equal(t, firewallName, firewall.Name)
+	assert.Equal(t, "mynetwork", firewall.Network)
+	assert.Equal(t, "the description", firewall.Description)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.0.0/16"}, firewall.SourceRanges)
+	assert.Equal(t, []string{firewallName}, firewall.TargetTags)
+	expectedRules := []AllowRule{
+		{Protocol: "tcp", Ports: []string{"22", "8080"}},
+		{Protocol: "udp", Ports: []string{"9000"}},
+		{Protocol: "tcp", Ports: []string{"8000-8010", "9090"}},
+		{Protocol: "icmp"},
+	}
+	assert.Equal(t, expectedRules, firewall.Allow)
+
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/Firewall")
+	assert.Equal(t, "Firewall-firewall-name", outputs[path.Join(nodeKey, "attributes/firewall_name")])
+	assert.Equal(t, "Firewall-target-tag", outputs[path.Join(nodeKey, "attributes/target_tag")])
+	require.Contains(t, infrastructure.Output, "Firewall-target-tag")
+	assert.Equal(t, firewallName, infrastructure.Output["Firewall-target-tag"].Value)
+}
@@ -19,7 +19,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"path"
 	"path/filepath"
 	"strings"
 
@@ -27,7 +26,6 @@ import (
 
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
-	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov/terraform/commons"
@@ -41,13 +39,9 @@ type googleGenerator struct {
 
 func (g *googleGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, map[string]string, []string, commons.PostApplyCallback, error) {
 	log.Debugf("Generating infrastructure for deployment with id %s", deploymentID)
-	terraformStateKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-state", nodeName)
 
 	infrastructure := commons.Infrastructure{}
 
-	// Remote Configuration for Terraform State to store it in the Consul KV store
-	infrastructure.Terraform = commons.GetBackendConfiguration(terraformStateKey, cfg)
-
 	// Define Terraform provider environment variables
 	var locationProps config.DynamicMap
 	locationMgr, err := locations.GetManager(cfg)
@@ -57,6 +51,12 @@ func (g *googleGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg
 	if err != nil {
 		return false, nil, nil, nil, err
 	}
+
+	// Remote Configuration for Terraform State to store it in the backend defined for the location
+	infrastructure.Terraform, err = commons.GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
 	var cmdEnv []string
 	configParams := []string{"application_credentials", "credentials", "project", "region"}
 	for _, configParam := range configParams {
@@ -91,6 +91,21 @@ func (g *googleGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg
 		return false, nil, nil, nil, err
 	}
 
+	managedGroup := false
+	if nodeType == "yorc.nodes.google.Compute" {
+		if managedGroup, err = isManagedInstanceGroup(ctx, deploymentID, nodeName); err != nil {
+			return false, nil, nil, nil, err
+		}
+	}
+	if managedGroup {
+		// Instances are generated all together as a group
+		err = g.generateManagedInstanceGroup(ctx, cfg, deploymentID, nodeName, instances, &infrastructure, outputs)
+		if err != nil {
+			return false, nil, nil, nil, err
+		}
+		instances = nil
+	}
+
 	for instNb, instanceName := range instances {
 		instanceState, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instanceName)
 		if err != nil {
@@ -132,6 +147,16 @@ func (g *googleGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg
 			if err != nil {
 				return false, nil, nil, nil, err
 			}
+		case "yorc.nodes.google.Firewall":
+			err = g.generateFirewall(ctx, cfg, deploymentID, nodeName, &infrastructure, outputs)
+			if err != nil {
+				return false, nil, nil, nil, err
+			}
+		case "yorc.nodes.google.CloudSQLInstance":
+			err = g.generateCloudSQLInstance(ctx, cfg, locationProps, deploymentID, nodeName, instanceName, &infrastructure, outputs)
+			if err != nil {
+				return false, nil, nil, nil, err
+			}
 		default:
 			return false, nil, nil, nil, errors.Errorf("Unsupported node type '%s' for node '%s' in deployment '%s'", nodeType, nodeName, deploymentID)
 		}
@@ -27,4 +27,5 @@ func init() {
 	reg.RegisterDelegates([]string{`yorc\.nodes\.google\..*`}, terraform.NewExecutor(&googleGenerator{}, commons.PreDestroyStorageInfraCallback), registry.BuiltinOrigin)
 	reg.RegisterOperationExecutor(
 		[]string{googleDeploymentArtifact}, &defaultExecutor{}, registry.BuiltinOrigin)
+	reg.RegisterInfraUsageCollector(infrastructureType, commons.NewCostInfraUsageCollector(infrastructureType, &googleGenerator{}), registry.BuiltinOrigin)
 }
@@ -0,0 +1,140 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	googleoauth "golang.org/x/oauth2/google"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// computeAPIBaseURL is the Compute Engine API URL, overridden in tests
+var computeAPIBaseURL = "https://www.googleapis.com/compute/v1"
+
+const computeReadOnlyScope = "https://www.googleapis.com/auth/compute.readonly"
+
+// IsInterruptible returns true for preemptible Computes.
+// Managed instance groups are excluded as they re-create preempted instances by themselves.
+func (g *googleGenerator) IsInterruptible(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) (bool, error) {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil || nodeType != "yorc.nodes.google.Compute" {
+		return false, err
+	}
+	managedGroup, err := isManagedInstanceGroup(ctx, deploymentID, nodeName)
+	if err != nil || managedGroup {
+		return false, err
+	}
+	return deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "preemptible")
+}
+
+// GetInterruptedInstances returns the instances which Compute Engine instance was stopped or deleted.
+// A preempted instance is stopped, its status is then TERMINATED.
+func (g *googleGenerator) GetInterruptedInstances(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instances []string) ([]string, error) {
+	locationProps, err := commons.GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getComputeAPIClient(ctx, locationProps)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "zone", true)
+	if err != nil {
+		return nil, err
+	}
+	project := locationProps.GetString("project")
+
+	var interrupted []string
+	for _, instance := range instances {
+		name := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + nodeName + "-" + instance)
+		name = strings.Replace(name, "_", "-", -1)
+		status, err := getComputeInstanceStatus(ctx, client, project, zone, name)
+		if err != nil {
+			return nil, err
+		}
+		if status == "" || status == "STOPPING" || status == "STOPPED" || status == "TERMINATED" {
+			interrupted = append(interrupted, instance)
+		}
+	}
+	return interrupted, nil
+}
+
+// getComputeAPIClient returns an HTTP client authenticated with the credentials of the location,
+// falling back to Google Application Default Credentials
+func getComputeAPIClient(ctx context.Context, locationProps config.DynamicMap) (*http.Client, error) {
+	credentials := locationProps.GetString("credentials")
+	if credentials == "" && locationProps.GetString("application_credentials") != "" {
+		credentials = locationProps.GetString("application_credentials")
+	}
+	// Use a client with timeout to retrieve tokens and call the API
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: 30 * time.Second})
+	if credentials == "" {
+		client, err := googleoauth.DefaultClient(ctx, computeReadOnlyScope)
+		return client, errors.Wrap(err, "failed to get Google default credentials")
+	}
+
+	// Credentials are either a JSON content or the path of a JSON file
+	content := []byte(credentials)
+	if !json.Valid(content) {
+		var err error
+		content, err = ioutil.ReadFile(credentials)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Google credentials file %q", credentials)
+		}
+	}
+	creds, err := googleoauth.CredentialsFromJSON(ctx, content, computeReadOnlyScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Google credentials")
+	}
+	return oauth2.NewClient(ctx, creds.TokenSource), nil
+}
+
+// getComputeInstanceStatus returns the status of a Compute Engine instance, or an empty string if it does not exist
+func getComputeInstanceStatus(ctx context.Context, client *http.Client, project, zone, name string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/projects/%s/zones/%s/instances/%s", computeAPIBaseURL, project, zone, name), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create Compute Engine request")
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get Compute Engine instance %q", name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get Compute Engine instance %q: %s", name, resp.Status)
+	}
+	instance := struct {
+		Status string `json:"status"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		return "", errors.Wrapf(err, "failed to parse Compute Engine instance %q", name)
+	}
+	return instance.Status, nil
+}
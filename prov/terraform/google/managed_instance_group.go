@@ -0,0 +1,145 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// isManagedInstanceGroup returns true if instances of a Compute node are managed by a managed instance group
+func isManagedInstanceGroup(ctx context.Context, deploymentID, nodeName string) (bool, error) {
+	return deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "managed_instance_group")
+}
+
+// generateManagedInstanceGroup generates a managed instance group, whose size is the number of instances
+// of a Compute node, created from an instance template defined by the properties of this node.
+//
+// The group decides which of its instances are deleted on scale-in and may re-create them at any time,
+// so they are not mapped to the Compute node instances: no attribute is published for a given instance
+// and no component may be hosted on such a Compute.
+func (g *googleGenerator) generateManagedInstanceGroup(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string, instances []string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.google.Compute" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	var activeInstances []string
+	for _, instanceName := range instances {
+		instanceState, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instanceName)
+		if err != nil {
+			return err
+		}
+		if instanceState != tosca.NodeStateDeleting && instanceState != tosca.NodeStateDeleted {
+			activeInstances = append(activeInstances, instanceName)
+		}
+	}
+	if len(activeInstances) == 0 {
+		// Do not generate anything, the group and its template will be deleted if they exist
+		return nil
+	}
+
+	// Features depending on a given instance are not supported by managed instance groups
+	hostedNodes, err := deployments.GetNodesHostedOn(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if len(hostedNodes) > 0 {
+		return errors.Errorf("nodes %v can't be hosted on Compute %q as its instances are managed by a managed instance group", hostedNodes, nodeName)
+	}
+	for _, reqType := range []string{"assignment", "local_storage"} {
+		reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, reqType)
+		if err != nil {
+			return err
+		}
+		if len(reqs) > 0 {
+			return errors.Errorf("%q requirements are not supported by Compute %q as its instances are managed by a managed instance group", reqType, nodeName)
+		}
+	}
+
+	instance, _, err := buildComputeInstance(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	scratchDisks, err := getScratchDisks(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	// Must be a match of regex '(?:[a-z](?:[-a-z0-9]{0,61}[a-z0-9])?)'
+	name := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + nodeName)
+	name = strings.Replace(name, "_", "-", -1)
+
+	template := &InstanceTemplate{
+		NamePrefix:        name + "-",
+		MachineType:       instance.MachineType,
+		Description:       instance.Description,
+		NetworkInterfaces: instance.NetworkInterfaces,
+		Labels:            instance.Labels,
+		Metadata:          instance.Metadata,
+		ServiceAccounts:   instance.ServiceAccounts,
+		Tags:              instance.Tags,
+		Disks: []InstanceTemplateDisk{
+			{SourceImage: instance.BootDisk.InitializeParams.Image, AutoDelete: true, Boot: true},
+		},
+		// Templates can't be updated, a new one replaces the one used by the group
+		Lifecycle: map[string]interface{}{"create_before_destroy": true},
+	}
+	for _, scratchDisk := range scratchDisks {
+		template.Disks = append(template.Disks, InstanceTemplateDisk{
+			AutoDelete: true,
+			Type:       "SCRATCH",
+			DiskType:   "local-ssd",
+			Interface:  scratchDisk.Interface,
+		})
+	}
+	if instance.Scheduling.Preemptible {
+		template.Scheduling = &InstanceTemplateScheduling{Preemptible: true, AutomaticRestart: false, OnHostMaintenance: "TERMINATE"}
+	}
+	commons.AddResource(infrastructure, "google_compute_instance_template", name, template)
+
+	groupManager := &InstanceGroupManager{
+		Name:             name,
+		Zone:             instance.Zone,
+		Description:      instance.Description,
+		BaseInstanceName: name,
+		InstanceTemplate: fmt.Sprintf("${google_compute_instance_template.%s.self_link}", name),
+		TargetSize:       len(activeInstances),
+		WaitForInstances: true,
+	}
+	log.Debugf("Add managed instance group:%+v", groupManager)
+	commons.AddResource(infrastructure, "google_compute_instance_group_manager", name, groupManager)
+
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
+	groupKey := nodeName + "-instance-group"
+	commons.AddOutput(infrastructure, groupKey, &commons.Output{
+		Value: fmt.Sprintf("${google_compute_instance_group_manager.%s.instance_group}", name)})
+	outputs[path.Join(nodeKey, "/attributes/instance_group")] = groupKey
+	return nil
+}
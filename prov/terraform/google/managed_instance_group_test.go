@@ -0,0 +1,94 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func testSimpleManagedInstanceGroup(t *testing.T, srv1 *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	ctx := context.Background()
+	// Simulate the firewall "target_tag" attribute registration
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/Firewall/0/attributes/target_tag"): []byte("fw-tag"),
+	})
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, "Compute")
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	// The last instance is being scaled down
+	err = deployments.SetInstanceStateWithContextualLogs(ctx, deploymentID, "Compute", instances[2], tosca.NodeStateDeleting)
+	require.NoError(t, err)
+
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := googleGenerator{}
+	err = g.generateManagedInstanceGroup(ctx, cfg, deploymentID, "Compute", instances, &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate managed instance group for %s", deploymentID)
+
+	name := strings.ToLower(getResourcesPrefix(cfg, deploymentID) + "compute")
+	require.Len(t, infrastructure.Resource["google_compute_instance_template"], 1, "Expected one instance template")
+	template, ok := infrastructure.Resource["google_compute_instance_template"].(map[string]interface{})[name].(*InstanceTemplate)
+	require.True(t, ok, "%s is not an InstanceTemplate", name)
+	assert.Equal(t, name+"-", template.NamePrefix)
+	assert.Equal(t, "n1-standard-1", template.MachineType)
+	assert.Equal(t, []InstanceTemplateDisk{
+		{SourceImage: "centos-cloud/centos-7", AutoDelete: true, Boot: true},
+		{AutoDelete: true, Type: "SCRATCH", DiskType: "local-ssd", Interface: "NVME"},
+	}, template.Disks)
+	assert.Equal(t, &InstanceTemplateScheduling{Preemptible: true, AutomaticRestart: false, OnHostMaintenance: "TERMINATE"}, template.Scheduling)
+	assert.Equal(t, []string{"fw-tag"}, template.Tags)
+	assert.Equal(t, map[string]string{"key1": "value1", "yorc_deployment_id": strings.ToLower(deploymentID), "yorc_node": "compute"}, template.Labels)
+	require.Len(t, template.NetworkInterfaces, 1)
+	assert.Len(t, template.NetworkInterfaces[0].AccessConfigs, 1, "Expected an external access")
+
+	require.Len(t, infrastructure.Resource["google_compute_instance_group_manager"], 1, "Expected one instance group manager")
+	manager, ok := infrastructure.Resource["google_compute_instance_group_manager"].(map[string]interface{})[name].(*InstanceGroupManager)
+	require.True(t, ok, "%s is not an InstanceGroupManager", name)
+	assert.Equal(t, &InstanceGroupManager{
+		Name:             name,
+		Zone:             "europe-west1-b",
+		BaseInstanceName: name,
+		InstanceTemplate: "${google_compute_instance_template." + name + ".self_link}",
+		TargetSize:       2,
+		WaitForInstances: true,
+	}, manager)
+
+	// Instances of the group are not mapped to the node instances
+	assert.Equal(t, map[string]string{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/Compute/attributes/instance_group"): "Compute-instance-group",
+	}, outputs)
+	assert.NotContains(t, infrastructure.Resource, "null_resource")
+	assert.Empty(t, infrastructure.Data)
+
+	// Components can't be hosted on the group instances
+	err = g.generateManagedInstanceGroup(ctx, cfg, deploymentID, "HostedCompute", []string{"0"}, &commons.Infrastructure{}, make(map[string]string))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be hosted on Compute")
+}
@@ -79,6 +79,7 @@ func (g *googleGenerator) generatePersistentDisk(ctx context.Context, cfg config
 	if err != nil {
 		return err
 	}
+	persistentDisk.Labels = addResourceLabels(cfg, deploymentID, nodeName, persistentDisk.Labels)
 
 	if size != "" {
 		// Default size unit is MB
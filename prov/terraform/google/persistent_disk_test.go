@@ -16,6 +16,7 @@ package google
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,5 +50,5 @@ func testSimplePersistentDisk(t *testing.T, cfg config.Configuration) {
 	assert.Equal(t, "1234", persistentDisk.DiskEncryptionKey.Raw)
 	assert.Equal(t, "5678", persistentDisk.DiskEncryptionKey.SHA256)
 	assert.Equal(t, "my description for persistent disk", persistentDisk.Description)
-	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2"}, persistentDisk.Labels)
+	assert.Equal(t, map[string]string{"key1": "value1", "key2": "value2", "yorc_deployment_id": strings.ToLower(deploymentID), "yorc_node": "persistentdisk"}, persistentDisk.Labels)
 }
@@ -158,8 +158,10 @@ type IPRange struct {
 type Firewall struct {
 	Name         string      `json:"name"`
 	Network      string      `json:"network"`
+	Description  string      `json:"description,omitempty"`
 	Allow        []AllowRule `json:"allow,omitempty"`
 	SourceRanges []string    `json:"source_ranges,omitempty"`
+	TargetTags   []string    `json:"target_tags,omitempty"`
 }
 
 // AllowRule represents an allowing firewall rule
@@ -167,3 +169,106 @@ type AllowRule struct {
 	Protocol string   `json:"protocol"`
 	Ports    []string `json:"ports,omitempty"`
 }
+
+// InstanceTemplate represents a Google compute instance template used by managed instance groups
+// See https://www.terraform.io/docs/providers/google/r/compute_instance_template.html
+type InstanceTemplate struct {
+	NamePrefix        string                      `json:"name_prefix"`
+	MachineType       string                      `json:"machine_type"`
+	Description       string                      `json:"description,omitempty"`
+	Disks             []InstanceTemplateDisk      `json:"disk"`
+	NetworkInterfaces []NetworkInterface          `json:"network_interface"`
+	Labels            map[string]string           `json:"labels,omitempty"`
+	Metadata          map[string]string           `json:"metadata,omitempty"`
+	Scheduling        *InstanceTemplateScheduling `json:"scheduling,omitempty"`
+	ServiceAccounts   []ServiceAccount            `json:"service_account,omitempty"`
+	Tags              []string                    `json:"tags,omitempty"`
+	Lifecycle         map[string]interface{}      `json:"lifecycle,omitempty"`
+}
+
+// InstanceTemplateDisk represents a disk of an instance template
+type InstanceTemplateDisk struct {
+	SourceImage string `json:"source_image,omitempty"`
+	AutoDelete  bool   `json:"auto_delete"`
+	Boot        bool   `json:"boot"`
+	Type        string `json:"type,omitempty"`
+	DiskType    string `json:"disk_type,omitempty"`
+	Interface   string `json:"interface,omitempty"`
+}
+
+// InstanceTemplateScheduling represents the scheduling strategy of an instance template.
+// Preemptible instances can't be automatically restarted
+type InstanceTemplateScheduling struct {
+	Preemptible       bool   `json:"preemptible"`
+	AutomaticRestart  bool   `json:"automatic_restart"`
+	OnHostMaintenance string `json:"on_host_maintenance,omitempty"`
+}
+
+// InstanceGroupManager represents a Google zonal managed instance group
+// See https://www.terraform.io/docs/providers/google/r/compute_instance_group_manager.html
+type InstanceGroupManager struct {
+	Name             string `json:"name"`
+	Zone             string `json:"zone"`
+	Description      string `json:"description,omitempty"`
+	BaseInstanceName string `json:"base_instance_name"`
+	InstanceTemplate string `json:"instance_template"`
+	TargetSize       int    `json:"target_size"`
+	WaitForInstances bool   `json:"wait_for_instances,omitempty"`
+}
+
+// SQLDatabaseInstance represents a Google Cloud SQL instance
+// See https://www.terraform.io/docs/providers/google/r/sql_database_instance.html
+type SQLDatabaseInstance struct {
+	Name            string      `json:"name"`
+	DatabaseVersion string      `json:"database_version"`
+	Region          string      `json:"region"`
+	Project         string      `json:"project,omitempty"`
+	Settings        SQLSettings `json:"settings"`
+}
+
+// SQLSettings represents the settings of a Cloud SQL instance
+type SQLSettings struct {
+	Tier                string                  `json:"tier"`
+	DiskSize            int                     `json:"disk_size,omitempty"`
+	DiskType            string                  `json:"disk_type,omitempty"`
+	DiskAutoresize      bool                    `json:"disk_autoresize"`
+	UserLabels          map[string]string       `json:"user_labels,omitempty"`
+	IPConfiguration     SQLIPConfiguration      `json:"ip_configuration"`
+	BackupConfiguration *SQLBackupConfiguration `json:"backup_configuration,omitempty"`
+}
+
+// SQLIPConfiguration represents the IP configuration of a Cloud SQL instance
+type SQLIPConfiguration struct {
+	IPV4Enabled        bool                   `json:"ipv4_enabled"`
+	RequireSSL         bool                   `json:"require_ssl,omitempty"`
+	AuthorizedNetworks []SQLAuthorizedNetwork `json:"authorized_networks,omitempty"`
+}
+
+// SQLAuthorizedNetwork represents a network authorized to connect to a Cloud SQL instance
+type SQLAuthorizedNetwork struct {
+	Value string `json:"value"`
+}
+
+// SQLBackupConfiguration represents the backup configuration of a Cloud SQL instance
+type SQLBackupConfiguration struct {
+	Enabled   bool   `json:"enabled"`
+	StartTime string `json:"start_time,omitempty"`
+}
+
+// SQLDatabase represents a database of a Cloud SQL instance
+// See https://www.terraform.io/docs/providers/google/r/sql_database.html
+type SQLDatabase struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+	Project  string `json:"project,omitempty"`
+}
+
+// SQLUser represents a user of a Cloud SQL instance
+// See https://www.terraform.io/docs/providers/google/r/sql_user.html
+type SQLUser struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+	Password string `json:"password"`
+	Host     string `json:"host,omitempty"`
+	Project  string `json:"project,omitempty"`
+}
@@ -19,9 +19,24 @@ import (
 	"fmt"
 
 	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
 func getResourcesPrefix(cfg config.Configuration, deploymentID string) string {
 	b := sha1.Sum([]byte(deploymentID))
 	return fmt.Sprintf("%s%x-", cfg.ResourcesPrefix, b[0:3])
 }
+
+// addResourceLabels adds to labels of a resource the tags identifying its deployment and node,
+// converted into valid labels. Labels already defined are kept unchanged.
+func addResourceLabels(cfg config.Configuration, deploymentID, nodeName string, labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range commons.ToLabels(commons.AddResourceTags(cfg, deploymentID, nodeName, nil)) {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,357 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+	"github.com/ystia/yorc/v4/registry"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/collector"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const (
+	interruptionDetectionActionType = "terraform-interruption-detection"
+
+	interruptionIntervalLocationProperty = "interruption_check_interval"
+	defaultInterruptionCheckInterval     = time.Minute
+
+	reprovisionOnInterruptionProperty = "reprovision_on_interruption"
+	interruptedAttribute              = "interrupted"
+)
+
+// reprovisioning tracks the scale-in/scale-out cycle replacing interrupted instances of a node.
+// Instances interrupted once the scale-in is registered are queued for the next cycle.
+type reprovisioning struct {
+	Instances     []string `json:"instances"`
+	ScaleInTaskID string   `json:"scale_in_task_id,omitempty"`
+	Queued        []string `json:"queued,omitempty"`
+}
+
+type interruptionDetectionOperator struct{}
+
+func interruptionPrefix(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-interruption")
+}
+
+// registerInterruptionDetection adds a node to the nodes watched for interrupted instances within its deployment
+// and schedules the deployment interruption detection action if not already done.
+//
+// Only nodes which instances may be reclaimed by their infrastructure, like spot or preemptible instances, are watched.
+// The check interval is defined by the interruption_check_interval property of the node location.
+func registerInterruptionDetection(ctx context.Context, cfg config.Configuration, generator commons.Generator, deploymentID, nodeName string) error {
+	checker, ok := generator.(commons.InterruptionChecker)
+	if !ok {
+		return nil
+	}
+	interruptible, err := checker.IsInterruptible(ctx, cfg, deploymentID, nodeName)
+	if err != nil || !interruptible {
+		return err
+	}
+	locationProps, err := commons.GetNodeLocationProperties(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	interval := locationProps.GetDuration(interruptionIntervalLocationProperty)
+	if interval <= 0 {
+		interval = defaultInterruptionCheckInterval
+	}
+
+	prefix := interruptionPrefix(deploymentID)
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return err
+	}
+	lock, err := consulutil.AcquireLock(cc, path.Join(prefix, ".lock"), 0)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// Stored under lock so that the action can't deregister itself in the meantime
+	if err = consulutil.StoreConsulKeyAsString(path.Join(prefix, "nodes", nodeName), ""); err != nil {
+		return err
+	}
+
+	kvp, _, err := cc.KV().Get(path.Join(prefix, "actionID"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		// Already scheduled for this deployment
+		return nil
+	}
+	id, err := scheduling.RegisterAction(cc, deploymentID, interval, &prov.Action{ActionType: interruptionDetectionActionType})
+	if err != nil {
+		return err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("Instances interruption detection scheduled every %s", interval)
+	return consulutil.StoreConsulKeyAsString(path.Join(prefix, "actionID"), id)
+}
+
+// unregisterInterruptionDetection removes a node from the nodes watched for interrupted instances
+// once all its instances are deleted. A pending re-provisioning is kept as it is driven by the scale-in of the node.
+// The interruption detection action deregisters itself once no node remains.
+func unregisterInterruptionDetection(ctx context.Context, deploymentID, nodeName string, deletedInstances []string) error {
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if !collections.ContainsString(deletedInstances, instance) {
+			// Scale-in, remaining instances are still watched
+			return nil
+		}
+	}
+	return consulutil.Delete(path.Join(interruptionPrefix(deploymentID), "nodes", nodeName), false)
+}
+
+func (o *interruptionDetectionOperator) ExecAction(ctx context.Context, cfg config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	exists, err := deployments.DoesDeploymentExists(ctx, deploymentID)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+	prefix := interruptionPrefix(deploymentID)
+	nodes, err := consulutil.GetKeys(path.Join(prefix, "nodes"))
+	if err != nil {
+		return false, err
+	}
+	pendingNodes, err := consulutil.GetKeys(path.Join(prefix, "reprovisioning"))
+	if err != nil {
+		return false, err
+	}
+
+	for _, nodeKey := range pendingNodes {
+		nodeName := path.Base(nodeKey)
+		nodeCtx := events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName})
+		if err = o.reprovisionNode(nodeCtx, cfg, deploymentID, nodeName); err != nil {
+			log.Debugf("%+v", err)
+			events.WithContextOptionalFields(nodeCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to re-provision interrupted instances of node %q: %v", nodeName, err)
+		}
+	}
+
+	if len(nodes) == 0 && len(pendingNodes) == 0 {
+		return o.deregisterIfUnused(cfg, prefix)
+	}
+
+	for _, nodeKey := range nodes {
+		nodeName := path.Base(nodeKey)
+		nodeCtx := events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName})
+		if err = o.checkNodeInterruption(nodeCtx, cfg, deploymentID, nodeName); err != nil {
+			// Keep on checking other nodes, next checks may succeed
+			log.Debugf("%+v", err)
+			events.WithContextOptionalFields(nodeCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Failed to check interrupted instances of node %q: %v", nodeName, err)
+		}
+	}
+	return false, nil
+}
+
+// deregisterIfUnused deregisters the action if no node is watched anymore and no re-provisioning is pending.
+// Nodes are checked again under lock as they may have been registered since the action started.
+func (o *interruptionDetectionOperator) deregisterIfUnused(cfg config.Configuration, prefix string) (bool, error) {
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return false, err
+	}
+	lock, err := consulutil.AcquireLock(cc, path.Join(prefix, ".lock"), 0)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+	for _, keyPath := range []string{"nodes", "reprovisioning"} {
+		keys, err := consulutil.GetKeys(path.Join(prefix, keyPath))
+		if err != nil || len(keys) > 0 {
+			return false, err
+		}
+	}
+	return true, consulutil.Delete(path.Join(prefix, "actionID"), false)
+}
+
+func (o *interruptionDetectionOperator) checkNodeInterruption(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) error {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	delegate, err := registry.GetRegistry().GetDelegateExecutor(nodeType)
+	if err != nil {
+		return err
+	}
+	e, ok := delegate.(*defaultExecutor)
+	if !ok {
+		return nil
+	}
+	checker, ok := e.generator.(commons.InterruptionChecker)
+	if !ok {
+		return nil
+	}
+
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	// Only started instances are checked, others are being created, deleted or already interrupted
+	var startedInstances []string
+	for _, instance := range instances {
+		state, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instance)
+		if err != nil {
+			return err
+		}
+		if state == tosca.NodeStateStarted {
+			startedInstances = append(startedInstances, instance)
+		}
+	}
+	if len(startedInstances) == 0 {
+		return nil
+	}
+
+	interrupted, err := checker.GetInterruptedInstances(ctx, cfg, deploymentID, nodeName, startedInstances)
+	if err != nil || len(interrupted) == 0 {
+		return err
+	}
+	for _, instance := range interrupted {
+		instanceCtx := events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instance})
+		events.WithContextOptionalFields(instanceCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Instance %q of node %q was interrupted by the infrastructure", instance, nodeName)
+		if err = deployments.SetInstanceAttribute(instanceCtx, deploymentID, nodeName, instance, interruptedAttribute, "true"); err != nil {
+			return err
+		}
+		if err = deployments.SetInstanceStateWithContextualLogs(instanceCtx, deploymentID, nodeName, instance, tosca.NodeStateError); err != nil {
+			return err
+		}
+	}
+
+	reprovision, err := deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, reprovisionOnInterruptionProperty)
+	if err != nil || !reprovision {
+		return err
+	}
+	pending, err := getReprovisioning(deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		pending = &reprovisioning{}
+	}
+	if pending.ScaleInTaskID != "" {
+		// The scale-in of the current cycle is already registered with its instances
+		pending.Queued = append(pending.Queued, interrupted...)
+	} else {
+		pending.Instances = append(pending.Instances, interrupted...)
+	}
+	if err = consulutil.StoreConsulKeyWithJSONValue(path.Join(interruptionPrefix(deploymentID), "reprovisioning", nodeName), pending); err != nil {
+		return err
+	}
+	return o.reprovisionNode(ctx, cfg, deploymentID, nodeName)
+}
+
+// reprovisionNode drives the scale-in/scale-out cycle replacing interrupted instances of a node.
+// Each step is registered as a task once no other task is running on the deployment,
+// the cycle goes on at next checks.
+func (o *interruptionDetectionOperator) reprovisionNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName string) error {
+	pending, err := getReprovisioning(deploymentID, nodeName)
+	if err != nil || pending == nil {
+		return err
+	}
+	pendingKey := path.Join(interruptionPrefix(deploymentID), "reprovisioning", nodeName)
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return err
+	}
+	tasksCollector := collector.NewCollector(cc)
+
+	if pending.ScaleInTaskID == "" {
+		instancesByNodes, err := deployments.SelectNodeStackInstancesByName(ctx, deploymentID, nodeName, pending.Instances)
+		if err != nil {
+			return err
+		}
+		data := map[string]string{"workflowName": "uninstall"}
+		for scalableNode, nodeInstances := range instancesByNodes {
+			data[path.Join("nodes", scalableNode)] = nodeInstances
+		}
+		taskID, err := tasksCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeScaleIn, data)
+		if err != nil {
+			if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+				log.Debugf("Another task is running on deployment %q, re-provisioning of node %q postponed", deploymentID, nodeName)
+				return nil
+			}
+			return err
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Removing interrupted instances %s of node %q", strings.Join(pending.Instances, ", "), nodeName)
+		pending.ScaleInTaskID = taskID
+		return consulutil.StoreConsulKeyWithJSONValue(pendingKey, pending)
+	}
+
+	status, err := tasks.GetTaskStatus(pending.ScaleInTaskID)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case tasks.TaskStatusDONE:
+		data := map[string]string{
+			"instancesDelta": strconv.Itoa(len(pending.Instances)),
+			"workflowName":   "install",
+			"nodeName":       nodeName,
+		}
+		_, err = tasksCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeScaleOut, data)
+		if err != nil {
+			if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+				log.Debugf("Another task is running on deployment %q, re-provisioning of node %q postponed", deploymentID, nodeName)
+				return nil
+			}
+			return err
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Re-provisioning %d instances of node %q", len(pending.Instances), nodeName)
+	case tasks.TaskStatusFAILED, tasks.TaskStatusCANCELED:
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Removal of interrupted instances of node %q did not succeed, they will not be re-provisioned", nodeName)
+	default:
+		// Scale-in in progress
+		return nil
+	}
+	if len(pending.Queued) > 0 {
+		// Start a new cycle for instances interrupted during this one
+		return consulutil.StoreConsulKeyWithJSONValue(pendingKey, &reprovisioning{Instances: pending.Queued})
+	}
+	return consulutil.Delete(pendingKey, false)
+}
+
+func getReprovisioning(deploymentID, nodeName string) (*reprovisioning, error) {
+	kvp, _, err := consulutil.GetKV().Get(path.Join(interruptionPrefix(deploymentID), "reprovisioning", nodeName), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, nil
+	}
+	pending := new(reprovisioning)
+	return pending, errors.Wrapf(json.Unmarshal(kvp.Value, pending), "invalid re-provisioning data for node %q", nodeName)
+}
@@ -0,0 +1,264 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// cloudConfig is the default cloud-init user data of a domain, creating the user
+// of the endpoint credentials allowed to connect with their key
+type cloudConfig struct {
+	Hostname string            `yaml:"hostname"`
+	Users    []cloudConfigUser `yaml:"users"`
+}
+
+type cloudConfigUser struct {
+	Name              string   `yaml:"name"`
+	Sudo              string   `yaml:"sudo"`
+	Shell             string   `yaml:"shell"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys"`
+}
+
+func (g *libvirtGenerator) generateDomain(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName, instanceName string, infrastructure *commons.Infrastructure, outputs map[string]string, env *[]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.libvirt.Compute" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName)
+
+	domain := Domain{
+		Name: getResourceName(cfg, deploymentID, nodeName, instanceName),
+		// Cloud images generally log on a serial console
+		Consoles: []Console{{Type: "pty", TargetPort: "0", TargetType: "serial"}},
+	}
+	pool, err := getPool(ctx, locationProps, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	// The base image is shared by all instances of the node, each instance having its own copy-on-write volume
+	image, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "image", true)
+	if err != nil {
+		return err
+	}
+	baseVolume := &Volume{
+		Name:   getResourceName(cfg, deploymentID, nodeName, "base"),
+		Pool:   pool,
+		Source: image,
+		Format: "qcow2",
+	}
+	commons.AddResource(infrastructure, "libvirt_volume", baseVolume.Name, baseVolume)
+	rootVolume := &Volume{
+		Name:         domain.Name,
+		Pool:         pool,
+		BaseVolumeID: fmt.Sprintf("${libvirt_volume.%s.id}", baseVolume.Name),
+		Format:       "qcow2",
+	}
+
+	// CPU, memory and size of the root volume are defined by the host capability
+	if err = setHostResources(ctx, deploymentID, nodeName, &domain, rootVolume); err != nil {
+		return err
+	}
+	commons.AddResource(infrastructure, "libvirt_volume", rootVolume.Name, rootVolume)
+	domain.Disks = append(domain.Disks, DomainDisk{VolumeID: fmt.Sprintf("${libvirt_volume.%s.id}", rootVolume.Name)})
+
+	user, privateKey, err := commons.GetConnInfoFromEndpointCredentials(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	cloudInit, err := getCloudInitDisk(ctx, cfg, deploymentID, nodeName, domain.Name, pool, user, privateKey)
+	if err != nil {
+		return err
+	}
+	commons.AddResource(infrastructure, "libvirt_cloudinit_disk", cloudInit.Name, cloudInit)
+	domain.CloudInit = fmt.Sprintf("${libvirt_cloudinit_disk.%s.id}", cloudInit.Name)
+
+	// Network interfaces, the first one is used to connect to the domain
+	if domain.NetworkInterfaces, err = getNetworkInterfaces(ctx, locationProps, deploymentID, nodeName); err != nil {
+		return err
+	}
+	domain.NetworkInterfaces[0].WaitForLease = true
+
+	if err = addAttachedVolumes(ctx, deploymentID, nodeName, instanceName, &domain, infrastructure, outputs); err != nil {
+		return err
+	}
+
+	commons.AddResource(infrastructure, "libvirt_domain", domain.Name, &domain)
+
+	domainIDKey := nodeName + "-" + instanceName + "-domainID"
+	commons.AddOutput(infrastructure, domainIDKey, &commons.Output{Value: fmt.Sprintf("${libvirt_domain.%s.id}", domain.Name)})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/domain_id")] = domainIDKey
+
+	// The address of the domain is the one leased on its first network interface
+	accessIP := fmt.Sprintf("${libvirt_domain.%s.network_interface.0.addresses.0}", domain.Name)
+	accessIPKey := nodeName + "-" + instanceName + "-IPAddress"
+	commons.AddOutput(infrastructure, accessIPKey, &commons.Output{Value: accessIP})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/attributes/ip_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/capabilities/endpoint/attributes/ip_address")] = accessIPKey
+
+	// Add Connection check
+	return commons.AddConnectionCheckResource(ctx, deploymentID, nodeName, infrastructure, user, privateKey, accessIP, domain.Name, env)
+}
+
+// setHostResources defines the CPUs and the memory of a domain and the size of its root volume
+// from the host capability of its node
+func setHostResources(ctx context.Context, deploymentID, nodeName string, domain *Domain, rootVolume *Volume) error {
+	numCPUs, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "num_cpus")
+	if err != nil {
+		return err
+	}
+	if numCPUs != nil && numCPUs.RawString() != "" {
+		if domain.VCPU, err = strconv.Atoi(numCPUs.RawString()); err != nil {
+			return errors.Wrapf(err, "invalid num_cpus %q for node %q", numCPUs.RawString(), nodeName)
+		}
+	}
+	memSize, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "mem_size")
+	if err != nil {
+		return err
+	}
+	if memSize != nil && memSize.RawString() != "" {
+		bytes, err := humanize.ParseBytes(memSize.RawString())
+		if err != nil {
+			return errors.Wrapf(err, "invalid mem_size %q for node %q", memSize.RawString(), nodeName)
+		}
+		domain.Memory = int64(bytes / humanize.MiByte)
+	}
+	diskSize, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "disk_size")
+	if err != nil {
+		return err
+	}
+	if diskSize != nil && diskSize.RawString() != "" {
+		// The root volume should not be smaller than the base image, this is checked by libvirt
+		if rootVolume.Size, err = toBytes(diskSize.RawString()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCloudInitDisk returns the cloud-init disk of a domain. User data are taken from the user_data property or artifact of
+// the node, or generated to create the user of the endpoint credentials and authorize their key.
+func getCloudInitDisk(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, domainName, pool, user string, privateKey *sshutil.PrivateKey) (*CloudInitDisk, error) {
+	cloudInit := &CloudInitDisk{
+		Name:     domainName + "-cloudinit.iso",
+		Pool:     pool,
+		MetaData: fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", domainName, domainName),
+	}
+	var err error
+	if cloudInit.NetworkConfig, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "network_config", false); err != nil {
+		return nil, err
+	}
+	if cloudInit.UserData, err = commons.GetUserData(ctx, cfg, deploymentID, nodeName); err != nil {
+		return nil, err
+	}
+	if cloudInit.UserData != "" {
+		return cloudInit, nil
+	}
+
+	publicKey, err := privateKey.AuthorizedKey()
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.Marshal(cloudConfig{
+		Hostname: domainName,
+		Users: []cloudConfigUser{{
+			Name:              user,
+			Sudo:              "ALL=(ALL) NOPASSWD:ALL",
+			Shell:             "/bin/bash",
+			SSHAuthorizedKeys: []string{publicKey},
+		}},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate cloud-init user data for node %q", nodeName)
+	}
+	cloudInit.UserData = "#cloud-config\n" + string(b)
+	return cloudInit, nil
+}
+
+// getNetworkInterfaces returns the network interfaces of a domain, defined by network relationships
+// to network nodes or by the network property of its location
+func getNetworkInterfaces(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) ([]NetworkInterface, error) {
+	networkReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "network")
+	if err != nil {
+		return nil, err
+	}
+	var interfaces []NetworkInterface
+	for _, networkReq := range networkReqs {
+		networkName, err := deployments.GetStringNodeProperty(ctx, deploymentID, networkReq.Node, "network_name", false)
+		if err != nil {
+			return nil, err
+		}
+		if networkName != "" {
+			interfaces = append(interfaces, NetworkInterface{NetworkName: networkName})
+			continue
+		}
+		log.Debugf("Looking for Network id for %q", networkReq.Node)
+		networkID, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, networkReq.Node, deployments.DefaultInstanceName, "network_id")
+		if err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, NetworkInterface{NetworkID: networkID})
+	}
+	if len(interfaces) == 0 {
+		interfaces = append(interfaces, NetworkInterface{NetworkName: locationProps.GetStringOrDefault("network", defaultNetwork)})
+	}
+	return interfaces, nil
+}
+
+func addAttachedVolumes(ctx context.Context, deploymentID, nodeName, instanceName string, domain *Domain, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	storageReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "local_storage")
+	if err != nil {
+		return err
+	}
+	for _, storageReq := range storageReqs {
+		volumeNodeName := storageReq.Node
+		log.Debugf("Volume attachment required form Volume named %s", volumeNodeName)
+
+		volumeID, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, volumeNodeName, instanceName, "volume_id")
+		if err != nil {
+			return err
+		}
+		domain.Disks = append(domain.Disks, DomainDisk{VolumeID: volumeID})
+
+		// Disks are attached on the virtio bus in order, the first one being the root volume
+		deviceKey := volumeNodeName + "-" + instanceName + "-device"
+		commons.AddOutput(infrastructure, deviceKey, &commons.Output{Value: fmt.Sprintf("/dev/vd%c", 'a'+len(domain.Disks)-1)})
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", volumeNodeName, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", nodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", volumeNodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+	}
+	return nil
+}
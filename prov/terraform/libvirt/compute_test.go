@@ -0,0 +1,153 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func loadTestYaml(t *testing.T) string {
+	deploymentID := path.Base(t.Name())
+	yamlName := "testdata/" + deploymentID + ".yaml"
+	err := deployments.StoreDeploymentDefinition(context.Background(), deploymentID, yamlName)
+	require.NoError(t, err, "Failed to parse "+yamlName+" definition")
+	return deploymentID
+}
+
+func getResource(t *testing.T, infrastructure *commons.Infrastructure, resourceType, name string) interface{} {
+	require.Contains(t, infrastructure.Resource, resourceType)
+	resources := infrastructure.Resource[resourceType].(map[string]interface{})
+	require.Contains(t, resources, name)
+	return resources[name]
+}
+
+func testSimpleDomain(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	infrastructure := commons.Infrastructure{}
+	g := libvirtGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateDomain(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate domain for %s", deploymentID)
+
+	domainName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	domain, ok := getResource(t, &infrastructure, "libvirt_domain", domainName).(*Domain)
+	require.True(t, ok, "%s is not a Domain", domainName)
+	assert.Equal(t, 2, domain.VCPU)
+	assert.Equal(t, int64(2048), domain.Memory)
+	require.Len(t, domain.NetworkInterfaces, 1)
+	assert.Equal(t, NetworkInterface{NetworkName: "default", WaitForLease: true}, domain.NetworkInterfaces[0])
+	require.Len(t, domain.Consoles, 1)
+
+	baseName := getResourcesPrefix(cfg, deploymentID) + "compute-base"
+	baseVolume, ok := getResource(t, &infrastructure, "libvirt_volume", baseName).(*Volume)
+	require.True(t, ok, "%s is not a Volume", baseName)
+	assert.Equal(t, "https://cloud-images.example.com/centos-7-x86_64.qcow2", baseVolume.Source)
+	assert.Equal(t, "default", baseVolume.Pool)
+
+	rootVolume, ok := getResource(t, &infrastructure, "libvirt_volume", domainName).(*Volume)
+	require.True(t, ok, "%s is not a Volume", domainName)
+	assert.Equal(t, "${libvirt_volume."+baseName+".id}", rootVolume.BaseVolumeID)
+	assert.Equal(t, uint64(20000000000), rootVolume.Size)
+	require.Len(t, domain.Disks, 1)
+	assert.Equal(t, "${libvirt_volume."+domainName+".id}", domain.Disks[0].VolumeID)
+
+	// Default user data authorize the key of the endpoint credentials
+	cloudInitName := domainName + "-cloudinit.iso"
+	cloudInit, ok := getResource(t, &infrastructure, "libvirt_cloudinit_disk", cloudInitName).(*CloudInitDisk)
+	require.True(t, ok, "%s is not a CloudInitDisk", cloudInitName)
+	assert.Equal(t, "${libvirt_cloudinit_disk."+cloudInitName+".id}", domain.CloudInit)
+	key, err := sshutil.GetPrivateKey("./testdata/mykey.pem")
+	require.NoError(t, err)
+	publicKey, err := key.AuthorizedKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(cloudInit.UserData, "#cloud-config\n"), "Unexpected user data: %s", cloudInit.UserData)
+	assert.Contains(t, cloudInit.UserData, "name: centos")
+	assert.Contains(t, cloudInit.UserData, publicKey)
+	assert.Contains(t, cloudInit.MetaData, "local-hostname: "+domainName)
+
+	instanceKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Compute", "0")
+	ipKey := outputs[path.Join(instanceKey, "capabilities/endpoint/attributes/ip_address")]
+	require.NotEmpty(t, ipKey)
+	assert.Equal(t, "${libvirt_domain."+domainName+".network_interface.0.addresses.0}", infrastructure.Output[ipKey].Value)
+	assert.Equal(t, ipKey, outputs[path.Join(instanceKey, "attributes/ip_address")])
+	assert.NotEmpty(t, outputs[path.Join(instanceKey, "attributes/domain_id")])
+
+	require.Contains(t, infrastructure.Resource, "null_resource")
+	nullResources := infrastructure.Resource["null_resource"].(map[string]interface{})
+	require.Contains(t, nullResources, domainName+"-ConnectionCheck")
+	require.Len(t, env, 1)
+	expectedKey, err := ioutil.ReadFile("./testdata/mykey.pem")
+	require.NoError(t, err)
+	assert.Equal(t, "TF_VAR_private_key="+string(expectedKey), env[0], "env var for private key expected")
+}
+
+func testDomainWithNetworksAndVolumes(t *testing.T, srv *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	// Network and volume created by previous steps of the workflow
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/PrivateNet/0/attributes/network_id"): []byte("b5ba1b4e-1c1c-4b4b-9a5a-6f1f1e1b0f42"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/Data/0/attributes/volume_id"):        []byte("/var/lib/libvirt/images/data-0.qcow2"),
+	})
+
+	infrastructure := commons.Infrastructure{}
+	g := libvirtGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateDomain(context.Background(), cfg, testLocationProperties, deploymentID, "Compute", "0", &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate domain for %s", deploymentID)
+
+	domainName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	domain, ok := getResource(t, &infrastructure, "libvirt_domain", domainName).(*Domain)
+	require.True(t, ok, "%s is not a Domain", domainName)
+	assert.Equal(t, []NetworkInterface{
+		{NetworkID: "b5ba1b4e-1c1c-4b4b-9a5a-6f1f1e1b0f42", WaitForLease: true},
+		{NetworkName: "bridged"},
+	}, domain.NetworkInterfaces)
+	require.Len(t, domain.Disks, 2)
+	assert.Equal(t, "/var/lib/libvirt/images/data-0.qcow2", domain.Disks[1].VolumeID)
+
+	rootVolume, ok := getResource(t, &infrastructure, "libvirt_volume", domainName).(*Volume)
+	require.True(t, ok, "%s is not a Volume", domainName)
+	assert.Equal(t, "images", rootVolume.Pool)
+	assert.Zero(t, rootVolume.Size, "Root volume should have the size of its base image")
+
+	cloudInit, ok := getResource(t, &infrastructure, "libvirt_cloudinit_disk", domainName+"-cloudinit.iso").(*CloudInitDisk)
+	require.True(t, ok)
+	assert.Equal(t, "#cloud-config\npackages:\n  - python\n", cloudInit.UserData)
+
+	deviceKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", "Compute", "2", "0", "attributes/device")]
+	require.NotEmpty(t, deviceKey, fmt.Sprintf("%v", outputs))
+	assert.Equal(t, "/dev/vdb", infrastructure.Output[deviceKey].Value)
+}
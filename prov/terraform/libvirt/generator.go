@@ -0,0 +1,131 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const infrastructureType = "libvirt"
+
+type libvirtGenerator struct {
+}
+
+func (g *libvirtGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, map[string]string, []string, commons.PostApplyCallback, error) {
+	log.Debugf("Generating infrastructure for deployment with id %s", deploymentID)
+
+	log.Debugf("inspecting node %s", nodeName)
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
+	if nodeType == "yorc.nodes.libvirt.Network" {
+		existingNetwork, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "network_name", false)
+		if err != nil || existingNetwork != "" {
+			// Existing networks are referenced by their names when generating the Computes connected to them
+			return false, nil, nil, nil, err
+		}
+	}
+
+	infrastructure := commons.Infrastructure{}
+
+	var locationProps config.DynamicMap
+	locationMgr, err := locations.GetManager(cfg)
+	if err == nil {
+		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
+	}
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
+
+	// Remote Configuration for Terraform State to store it in the backend defined for the location
+	infrastructure.Terraform, err = commons.GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
+
+	uri := locationProps.GetString("uri")
+	if uri == "" {
+		return false, nil, nil, nil, errors.New("missing mandatory uri property in libvirt location configuration")
+	}
+
+	// Management of variables for Terraform
+	infrastructure.Provider = map[string]interface{}{
+		"libvirt": map[string]interface{}{
+			"version": cfg.Terraform.LibvirtPluginVersionConstraint,
+			"uri":     uri,
+		},
+		"consul": commons.GetConsulProviderfiguration(cfg),
+		"null": map[string]interface{}{
+			"version": commons.NullPluginVersionConstraint,
+		},
+	}
+
+	outputs := make(map[string]string)
+	cmdEnv := make([]string, 0)
+	instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
+
+	for instNb, instanceName := range instances {
+		instanceState, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instanceName)
+		if err != nil {
+			return false, nil, nil, nil, err
+		}
+		if instanceState == tosca.NodeStateDeleting || instanceState == tosca.NodeStateDeleted {
+			// Do not generate something for this node instance (will be deleted if exists)
+			continue
+		}
+
+		switch nodeType {
+		case "yorc.nodes.libvirt.Compute":
+			err = g.generateDomain(ctx, cfg, locationProps, deploymentID, nodeName, instanceName, &infrastructure, outputs, &cmdEnv)
+		case "yorc.nodes.libvirt.Network":
+			err = g.generateNetwork(ctx, cfg, deploymentID, nodeName, instanceName, &infrastructure, outputs)
+		case "yorc.nodes.libvirt.Volume":
+			err = g.generateVolume(ctx, cfg, locationProps, deploymentID, nodeName, instanceName, instNb, &infrastructure, outputs)
+		default:
+			err = errors.Errorf("Unsupported node type '%s' for node '%s' in deployment '%s'", nodeType, nodeName, deploymentID)
+		}
+		if err != nil {
+			return false, nil, nil, nil, err
+		}
+	}
+
+	jsonInfra, err := json.MarshalIndent(infrastructure, "", "  ")
+	if err != nil {
+		return false, nil, nil, nil, errors.Wrap(err, "Failed to generate JSON of terraform Infrastructure description")
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(infrastructurePath, "infra.tf.json"), jsonInfra, 0664); err != nil {
+		return false, nil, nil, nil, errors.Wrapf(err, "Failed to write file %q", filepath.Join(infrastructurePath, "infra.tf.json"))
+	}
+
+	log.Debugf("Infrastructure generated for deployment with id %s", deploymentID)
+	return true, outputs, cmdEnv, nil, nil
+}
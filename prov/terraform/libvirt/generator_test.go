@@ -0,0 +1,145 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/locations"
+)
+
+// planOnTestDriverEnvVar is the environment variable enabling, when set to true, Terraform plans of
+// generated infrastructures on the libvirt test driver
+const planOnTestDriverEnvVar = "YORC_TEST_LIBVIRT_TERRAFORM_PLAN"
+
+// testGenerateOnTestDriver generates the infrastructure of a whole topology for a location
+// using the libvirt test driver and, if enabled, plans it against this driver
+func testGenerateOnTestDriver(t *testing.T, cfg config.Configuration, locationMgr locations.Manager) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	plan, skipReason := isPlanOnTestDriverEnabled(cfg)
+
+	err := locationMgr.CreateLocation(
+		locations.LocationConfiguration{
+			Name:       "testLibvirtTestDriver",
+			Type:       infrastructureType,
+			Properties: config.DynamicMap{"uri": "test:///default", "pool": "default"},
+		})
+	require.NoError(t, err, "Failed to create a location")
+	defer func() {
+		locationMgr.RemoveLocation("testLibvirtTestDriver")
+	}()
+
+	g := libvirtGenerator{}
+	for _, nodeName := range []string{"PrivateNet", "Data", "Compute"} {
+		infrastructurePath, err := ioutil.TempDir("", deploymentID)
+		require.NoError(t, err, "Failed to to create temporary directory")
+		defer os.RemoveAll(infrastructurePath)
+
+		generated, _, env, _, err := g.GenerateTerraformInfraForNode(context.Background(), cfg, deploymentID, nodeName, infrastructurePath)
+		require.NoError(t, err, "Unexpected error generating terraform infra for node %s", nodeName)
+		require.True(t, generated)
+
+		content, err := ioutil.ReadFile(filepath.Join(infrastructurePath, "infra.tf.json"))
+		require.NoError(t, err)
+		var infra struct {
+			Provider map[string]map[string]interface{} `json:"provider"`
+			Resource map[string]map[string]interface{} `json:"resource"`
+			Output   map[string]interface{}            `json:"output"`
+		}
+		require.NoError(t, json.Unmarshal(content, &infra))
+		assert.Equal(t, "test:///default", infra.Provider["libvirt"]["uri"])
+		assert.NotEmpty(t, infra.Output)
+
+		// Attributes set once the infrastructure is applied, and required by the Compute
+		switch nodeName {
+		case "PrivateNet":
+			assert.Len(t, infra.Resource["libvirt_network"], 1)
+			err = deployments.SetInstanceAttribute(context.Background(), deploymentID, nodeName, "0", "network_id", "b5ba1b4e-1c1c-4b4b-9a5a-6f1f1e1b0f43")
+			require.NoError(t, err)
+		case "Data":
+			assert.Len(t, infra.Resource["libvirt_volume"], 1)
+			err = deployments.SetInstanceAttribute(context.Background(), deploymentID, nodeName, "0", "volume_id", "/var/lib/libvirt/images/data-0.qcow2")
+			require.NoError(t, err)
+		case "Compute":
+			assert.Len(t, infra.Resource["libvirt_domain"], 1)
+			assert.Len(t, infra.Resource["libvirt_cloudinit_disk"], 1)
+			// Base image and root volume
+			assert.Len(t, infra.Resource["libvirt_volume"], 2)
+		}
+
+		if plan {
+			planOnTestDriver(t, cfg, infrastructurePath, env)
+		}
+	}
+	if !plan {
+		t.Skipf("%s, skipping plan on the libvirt test driver", skipReason)
+	}
+}
+
+// isPlanOnTestDriverEnabled checks if generated infrastructures should be planned on the libvirt test driver,
+// this requires Terraform and the libvirt provider to be installed. Plans are enabled by the
+// planOnTestDriverEnvVar environment variable or when the provider is found in the Terraform plugins directory.
+func isPlanOnTestDriverEnabled(cfg config.Configuration) (bool, string) {
+	if enabled, _ := strconv.ParseBool(os.Getenv(planOnTestDriverEnvVar)); enabled {
+		return true, ""
+	}
+	if cfg.Terraform.PluginsDir == "" {
+		return false, fmt.Sprintf("%s is not set and no Terraform plugins directory is configured", planOnTestDriverEnvVar)
+	}
+	plugins, _ := filepath.Glob(filepath.Join(cfg.Terraform.PluginsDir, "terraform-provider-libvirt*"))
+	if len(plugins) == 0 {
+		return false, fmt.Sprintf("%s is not set and the libvirt provider is not in Terraform plugins directory %q", planOnTestDriverEnvVar, cfg.Terraform.PluginsDir)
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return false, "terraform is not installed"
+	}
+	return true, ""
+}
+
+// planOnTestDriver checks that Terraform accepts a generated infrastructure and
+// plans to create it on the libvirt test driver
+func planOnTestDriver(t *testing.T, cfg config.Configuration, infrastructurePath string, env []string) {
+	initArgs := []string{"init", "-input=false"}
+	if cfg.Terraform.PluginsDir != "" {
+		initArgs = append(initArgs, "-plugin-dir="+cfg.Terraform.PluginsDir)
+	}
+	for _, args := range [][]string{initArgs, {"validate"}, {"plan", "-input=false", "-refresh=false", "-detailed-exitcode"}} {
+		cmd := exec.Command("terraform", args...)
+		cmd.Dir = infrastructurePath
+		cmd.Env = append(os.Environ(), env...)
+		out, err := cmd.CombinedOutput()
+		if args[0] == "plan" {
+			// Exit code 2 means that resources are planned to be created
+			exitErr, ok := err.(*exec.ExitError)
+			require.True(t, ok && exitErr.ExitCode() == 2, "Unexpected result of terraform plan: %v\n%s", err, out)
+			continue
+		}
+		require.NoError(t, err, "Failed to run terraform %s: %s", args[0], out)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *libvirtGenerator) generateNetwork(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, instanceName string, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.libvirt.Network" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	network := &Network{
+		Name:      getResourceName(cfg, deploymentID, nodeName, instanceName),
+		Autostart: true,
+	}
+	var cidr string
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+	}{
+		{&network.Mode, "mode"},
+		{&network.Domain, "domain"},
+		{&cidr, "cidr"},
+	}
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, stringParam.propertyName, false); err != nil {
+			return err
+		}
+	}
+	if cidr != "" {
+		network.Addresses = []string{cidr}
+	}
+	dhcpEnabled, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "dhcp_enabled")
+	if err != nil {
+		return err
+	}
+	network.DHCP = &DHCP{Enabled: dhcpEnabled == nil || dhcpEnabled.RawString() != "false"}
+
+	commons.AddResource(infrastructure, "libvirt_network", network.Name, network)
+
+	// Provide output for network_id and network_name attributes
+	instanceKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName, instanceName)
+	networkIDKey := nodeName + "-" + instanceName + "-networkID"
+	commons.AddOutput(infrastructure, networkIDKey, &commons.Output{Value: fmt.Sprintf("${libvirt_network.%s.id}", network.Name)})
+	outputs[path.Join(instanceKey, "attributes/network_id")] = networkIDKey
+	networkNameKey := nodeName + "-" + instanceName + "-networkName"
+	commons.AddOutput(infrastructure, networkNameKey, &commons.Output{Value: network.Name})
+	outputs[path.Join(instanceKey, "attributes/network_name")] = networkNameKey
+	return nil
+}
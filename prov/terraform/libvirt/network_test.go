@@ -0,0 +1,58 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleNetwork(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	infrastructure := commons.Infrastructure{}
+	g := libvirtGenerator{}
+	outputs := make(map[string]string)
+	err := g.generateNetwork(context.Background(), cfg, deploymentID, "Network", "0", &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate network for %s", deploymentID)
+
+	networkName := getResourcesPrefix(cfg, deploymentID) + "network-0"
+	network, ok := getResource(t, &infrastructure, "libvirt_network", networkName).(*Network)
+	require.True(t, ok, "%s is not a Network", networkName)
+	assert.Equal(t, &Network{
+		Name:      networkName,
+		Mode:      "route",
+		Addresses: []string{"10.17.3.0/24"},
+		Autostart: true,
+		DHCP:      &DHCP{Enabled: false},
+	}, network)
+
+	instanceKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Network", "0")
+	networkIDKey := outputs[path.Join(instanceKey, "attributes/network_id")]
+	require.NotEmpty(t, networkIDKey)
+	assert.Equal(t, "${libvirt_network."+networkName+".id}", infrastructure.Output[networkIDKey].Value)
+	networkNameKey := outputs[path.Join(instanceKey, "attributes/network_name")]
+	require.NotEmpty(t, networkNameKey)
+	assert.Equal(t, networkName, infrastructure.Output[networkNameKey].Value)
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+// Domain represents a libvirt domain (a Virtual Machine)
+//
+// See https://github.com/dmacvicar/terraform-provider-libvirt/blob/master/website/docs/r/domain.html.markdown
+type Domain struct {
+	Name              string             `json:"name"`
+	VCPU              int                `json:"vcpu,omitempty"`
+	Memory            int64              `json:"memory,omitempty"`
+	CloudInit         string             `json:"cloudinit,omitempty"`
+	Disks             []DomainDisk       `json:"disk,omitempty"`
+	NetworkInterfaces []NetworkInterface `json:"network_interface,omitempty"`
+	Consoles          []Console          `json:"console,omitempty"`
+}
+
+// DomainDisk is a disk of a libvirt domain
+type DomainDisk struct {
+	VolumeID string `json:"volume_id"`
+}
+
+// NetworkInterface is a network interface of a libvirt domain
+type NetworkInterface struct {
+	NetworkID    string `json:"network_id,omitempty"`
+	NetworkName  string `json:"network_name,omitempty"`
+	WaitForLease bool   `json:"wait_for_lease,omitempty"`
+}
+
+// Console is a console device of a libvirt domain, cloud images generally expecting a serial console
+type Console struct {
+	Type       string `json:"type"`
+	TargetPort string `json:"target_port"`
+	TargetType string `json:"target_type,omitempty"`
+}
+
+// Volume represents a libvirt storage volume
+//
+// See https://github.com/dmacvicar/terraform-provider-libvirt/blob/master/website/docs/r/volume.html.markdown
+type Volume struct {
+	Name         string `json:"name"`
+	Pool         string `json:"pool,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Size         uint64 `json:"size,omitempty"`
+	BaseVolumeID string `json:"base_volume_id,omitempty"`
+	Format       string `json:"format,omitempty"`
+}
+
+// CloudInitDisk represents an ISO image holding cloud-init data of a domain
+//
+// See https://github.com/dmacvicar/terraform-provider-libvirt/blob/master/website/docs/r/cloudinit.html.markdown
+type CloudInitDisk struct {
+	Name          string `json:"name"`
+	Pool          string `json:"pool,omitempty"`
+	UserData      string `json:"user_data,omitempty"`
+	MetaData      string `json:"meta_data,omitempty"`
+	NetworkConfig string `json:"network_config,omitempty"`
+}
+
+// Network represents a libvirt virtual network
+//
+// See https://github.com/dmacvicar/terraform-provider-libvirt/blob/master/website/docs/r/network.markdown
+type Network struct {
+	Name      string   `json:"name"`
+	Mode      string   `json:"mode,omitempty"`
+	Domain    string   `json:"domain,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	Autostart bool     `json:"autostart"`
+	DHCP      *DHCP    `json:"dhcp,omitempty"`
+}
+
+// DHCP defines if a DHCP server is enabled on a libvirt network
+type DHCP struct {
+	Enabled bool `json:"enabled"`
+}
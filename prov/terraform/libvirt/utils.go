@@ -0,0 +1,63 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+const (
+	defaultPool    = "default"
+	defaultNetwork = "default"
+)
+
+func getResourcesPrefix(cfg config.Configuration, deploymentID string) string {
+	b := sha1.Sum([]byte(deploymentID))
+	return fmt.Sprintf("%s%x-", cfg.ResourcesPrefix, b[0:3])
+}
+
+func getResourceName(cfg config.Configuration, deploymentID, nodeName string, suffixes ...string) string {
+	name := getResourcesPrefix(cfg, deploymentID) + strings.Join(append([]string{nodeName}, suffixes...), "-")
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// getPool returns the storage pool of a node, defined by its pool property, by its location or the libvirt default one
+func getPool(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) (string, error) {
+	pool, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "pool", false)
+	if err != nil || pool != "" {
+		return pool, err
+	}
+	return locationProps.GetStringOrDefault("pool", defaultPool), nil
+}
+
+// toBytes converts a MB size as "42" or a human readable size as "42 GB" into bytes
+func toBytes(size string) (uint64, error) {
+	// Default size unit is MB
+	if mSize, err := strconv.ParseUint(size, 10, 64); err == nil {
+		return mSize * humanize.MByte, nil
+	}
+	bSize, err := humanize.ParseBytes(size)
+	return bSize, errors.Wrapf(err, "can't convert size %q to bytes value", size)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *libvirtGenerator) generateVolume(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName, instanceName string, instanceID int, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.libvirt.Volume" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	volumes, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "volume_id", false)
+	if err != nil {
+		return err
+	}
+	var volumeID string
+	if volumes != "" {
+		tabVol := strings.Split(volumes, ",")
+		if len(tabVol) > instanceID {
+			volumeID = strings.TrimSpace(tabVol[instanceID])
+		}
+	}
+
+	// Add libvirt volume resource if not any volume ID is provided
+	if volumeID == "" {
+		size, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "size", false)
+		if err != nil {
+			return err
+		}
+		if size == "" {
+			return errors.Errorf("size is mandatory to create the volume %q", nodeName)
+		}
+		volume := &Volume{
+			Name:   getResourceName(cfg, deploymentID, nodeName, instanceName),
+			Format: "qcow2",
+		}
+		// Default size unit is MB
+		log.Debugf("Initial size property value (default is MB): %q", size)
+		if volume.Size, err = toBytes(size); err != nil {
+			return err
+		}
+		if volume.Pool, err = getPool(ctx, locationProps, deploymentID, nodeName); err != nil {
+			return err
+		}
+		commons.AddResource(infrastructure, "libvirt_volume", volume.Name, volume)
+		volumeID = fmt.Sprintf("${libvirt_volume.%s.id}", volume.Name)
+	}
+
+	// Provide output for attribute volume_id
+	volumeKey := nodeName + "-" + instanceName + "-volume"
+	commons.AddOutput(infrastructure, volumeKey, &commons.Output{Value: volumeID})
+	outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName, instanceName, "attributes/volume_id")] = volumeKey
+	return nil
+}
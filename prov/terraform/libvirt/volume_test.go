@@ -0,0 +1,98 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libvirt
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleVolume(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	infrastructure := commons.Infrastructure{}
+	g := libvirtGenerator{}
+	outputs := make(map[string]string)
+	locationProps := config.DynamicMap{"uri": "test:///default", "pool": "data"}
+	err := g.generateVolume(context.Background(), cfg, locationProps, deploymentID, "Volume", "0", 0, &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate volume for %s", deploymentID)
+
+	volumeName := getResourcesPrefix(cfg, deploymentID) + "volume-0"
+	volume, ok := getResource(t, &infrastructure, "libvirt_volume", volumeName).(*Volume)
+	require.True(t, ok, "%s is not a Volume", volumeName)
+	assert.Equal(t, &Volume{Name: volumeName, Pool: "data", Size: 512000000, Format: "qcow2"}, volume)
+	volumeKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Volume", "0", "attributes/volume_id")]
+	require.NotEmpty(t, volumeKey)
+	assert.Equal(t, "${libvirt_volume."+volumeName+".id}", infrastructure.Output[volumeKey].Value)
+
+	// Existing volumes are only referenced
+	infrastructure = commons.Infrastructure{}
+	err = g.generateVolume(context.Background(), cfg, locationProps, deploymentID, "ExistingVolume", "0", 0, &infrastructure, outputs)
+	require.NoError(t, err)
+	assert.NotContains(t, infrastructure.Resource, "libvirt_volume")
+	volumeKey = outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "ExistingVolume", "0", "attributes/volume_id")]
+	require.NotEmpty(t, volumeKey)
+	assert.Equal(t, "/var/lib/libvirt/images/data.qcow2", infrastructure.Output[volumeKey].Value)
+}
+
+func testGenerateTerraformInfraForNode(t *testing.T, cfg config.Configuration, locationMgr locations.Manager) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+
+	err := locationMgr.CreateLocation(
+		locations.LocationConfiguration{
+			Name:       "testLibvirtLocation",
+			Type:       infrastructureType,
+			Properties: testLocationProperties,
+		})
+	require.NoError(t, err, "Failed to create a location")
+	defer func() {
+		locationMgr.RemoveLocation("testLibvirtLocation")
+	}()
+
+	infrastructurePath, err := ioutil.TempDir("", deploymentID)
+	require.NoError(t, err, "Failed to to create temporary directory")
+	defer os.RemoveAll(infrastructurePath)
+
+	g := libvirtGenerator{}
+	generated, _, _, _, err := g.GenerateTerraformInfraForNode(context.Background(), cfg, deploymentID, "Volume", infrastructurePath)
+	require.NoError(t, err, "Unexpected error generating terraform infra")
+	require.True(t, generated)
+
+	content, err := ioutil.ReadFile(filepath.Join(infrastructurePath, "infra.tf.json"))
+	require.NoError(t, err)
+	var infra struct {
+		Provider map[string]map[string]interface{} `json:"provider"`
+		Resource map[string]map[string]interface{} `json:"resource"`
+	}
+	require.NoError(t, json.Unmarshal(content, &infra))
+	require.Contains(t, infra.Provider, "libvirt")
+	assert.Equal(t, "test:///default", infra.Provider["libvirt"]["uri"])
+	assert.Len(t, infra.Resource["libvirt_volume"], 1)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/helper/sizeutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
 func (g *osGenerator) generateOSBSVolume(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap,
@@ -38,6 +39,7 @@ func (g *osGenerator) generateOSBSVolume(ctx context.Context, cfg config.Configu
 		return volume, errors.Errorf("Unsupported node type for %s: %s", nodeName, nodeType)
 	}
 	volume.Name = cfg.ResourcesPrefix + nodeName + "-" + instanceName
+	volume.Metadata = commons.AddResourceTags(cfg, deploymentID, nodeName, nil)
 
 	size, err := deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "size")
 	if err != nil {
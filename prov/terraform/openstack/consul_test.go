@@ -95,5 +95,14 @@ func TestRunConsulOpenstackPackageTests(t *testing.T) {
 		t.Run("ComputeNetworkAttributes", func(t *testing.T) {
 			testComputeNetworkAttributes(t, srv)
 		})
+		t.Run("simpleSecurityGroup", func(t *testing.T) {
+			testSimpleSecurityGroup(t, srv)
+		})
+		t.Run("simpleLoadBalancer", func(t *testing.T) {
+			testSimpleLoadBalancer(t, srv)
+		})
+		t.Run("floatingIPWithDNS", func(t *testing.T) {
+			testFloatingIPWithDNS(t, srv)
+		})
 	})
 }
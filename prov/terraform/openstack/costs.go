@@ -0,0 +1,52 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+// GetPricedResources returns the resources billed for each instance of a node.
+// Computes are priced by flavor name, or by flavor ID if no name is defined.
+func (g *osGenerator) GetPricedResources(ctx context.Context, deploymentID, nodeName string) ([]commons.PricedResource, error) {
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	switch nodeType {
+	case "yorc.nodes.openstack.Compute":
+		flavor, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "flavorName", false)
+		if err != nil {
+			return nil, err
+		}
+		if flavor == "" {
+			flavor, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "flavor", false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return []commons.PricedResource{{Kind: commons.ComputeResourceKind, Type: flavor}}, nil
+	case "yorc.nodes.openstack.BlockStorage":
+		return commons.GetStoragePricedResources(ctx, deploymentID, nodeName, "")
+	case "yorc.nodes.openstack.FloatingIP":
+		return []commons.PricedResource{{Kind: commons.PublicIPResourceKind}}, nil
+	case "yorc.nodes.openstack.LoadBalancer":
+		return []commons.PricedResource{{Kind: commons.LoadBalancerResourceKind}}, nil
+	}
+	return nil, nil
+}
@@ -16,9 +16,15 @@ package openstack
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
 // An IP is...
@@ -57,3 +63,52 @@ func (g *osGenerator) generateFloatingIP(ctx context.Context, deploymentID, node
 
 	return result, nil
 }
+
+// generateFloatingIPDNSRecord adds a Designate DNS record set resolving to the address of a floating IP
+// instance when a DNS zone is defined on the floating IP node.
+// It returns the Consul key storing the DNS name of the instance or nil if no DNS zone is defined.
+func (g *osGenerator) generateFloatingIPDNSRecord(ctx context.Context, opts generateInfraOptions, ipName, address string) (*commons.ConsulKey, error) {
+	zoneID, err := deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "dns_zone_id", false)
+	if err != nil || zoneID == "" {
+		return nil, err
+	}
+	recordName, err := deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "dns_record_name", true)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := deployments.GetNodeInstancesIds(ctx, opts.deploymentID, opts.nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) > 1 {
+		// Each instance gets its own record: the instance name is appended to the first label
+		labels := strings.SplitN(recordName, ".", 2)
+		labels[0] = labels[0] + "-" + opts.instanceName
+		recordName = strings.Join(labels, ".")
+	}
+	if !strings.HasSuffix(recordName, ".") {
+		recordName += "."
+	}
+
+	recordSet := &DNSRecordSet{
+		Region:  opts.locationProps.GetStringOrDefault("region", defaultOSRegion),
+		ZoneID:  zoneID,
+		Name:    recordName,
+		Type:    "A",
+		Records: []string{address},
+	}
+	ttl, err := deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "dns_ttl", false)
+	if err != nil {
+		return nil, err
+	}
+	if ttl != "" {
+		if recordSet.TTL, err = strconv.Atoi(ttl); err != nil {
+			return nil, errors.Wrapf(err, "invalid dns_ttl value for node %q", opts.nodeName)
+		}
+	}
+	commons.AddResource(opts.infrastructure, opts.resourceTypes[dnsRecordSet], ipName, recordSet)
+
+	return &commons.ConsulKey{
+		Path:  path.Join(opts.instancesKey, opts.instanceName, "attributes/dns_name"),
+		Value: fmt.Sprintf("${%s.%s.name}", opts.resourceTypes[dnsRecordSet], ipName)}, nil
+}
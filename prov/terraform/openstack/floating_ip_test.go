@@ -27,9 +27,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
 )
 
 func testGeneratePoolIP(t *testing.T, srv1 *testutil.TestServer) {
@@ -124,3 +126,47 @@ func testGenerateMultipleIP(t *testing.T, srv1 *testutil.TestServer) {
 	ips := strings.Split(gia.Pool, ",")
 	assert.Len(t, ips, 4)
 }
+
+func testFloatingIPWithDNS(t *testing.T, srv1 *testutil.TestServer) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances")
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(instancesPrefix, "FIP/0/attributes/state"): []byte("initial"),
+		path.Join(instancesPrefix, "FIP/1/attributes/state"): []byte("initial"),
+	})
+	locationProps := config.DynamicMap{}
+	infrastructure := commons.Infrastructure{}
+	g := osGenerator{}
+	opts := generateInfraOptions{
+		infrastructure: &infrastructure,
+		locationProps:  locationProps,
+		instancesKey:   path.Join(instancesPrefix, "FIP"),
+		deploymentID:   deploymentID,
+		nodeName:       "FIP",
+		nodeType:       "yorc.nodes.openstack.FloatingIP",
+		instanceName:   "1",
+		instanceIndex:  1,
+		resourceTypes:  getOpenstackResourceTypes(locationProps),
+	}
+
+	err := g.generateFloatingIPInfra(context.Background(), opts)
+	require.NoError(t, err, "Unexpected error attempting to generate floating IP for %s", deploymentID)
+
+	recordSet, ok := infrastructure.Resource["openstack_dns_recordset_v2"].(map[string]interface{})["FIP-1"].(*DNSRecordSet)
+	require.True(t, ok, "FIP-1 is not a DNSRecordSet")
+	assert.Equal(t, &DNSRecordSet{
+		Region:  defaultOSRegion,
+		ZoneID:  "zone-1",
+		Name:    "www-1.example.com.",
+		Type:    "A",
+		TTL:     300,
+		Records: []string{"${openstack_compute_floatingip_v2.FIP-1.address}"},
+	}, recordSet)
+
+	consulKeys, ok := infrastructure.Resource[consulKeysResource].(map[string]interface{})["FIP-1"].(*commons.ConsulKeys)
+	require.True(t, ok, "FIP-1 is not a ConsulKeys")
+	assert.Contains(t, consulKeys.Keys, commons.ConsulKey{
+		Path:  path.Join(instancesPrefix, "FIP/1/attributes/dns_name"),
+		Value: "${openstack_dns_recordset_v2.FIP-1.name}"})
+}
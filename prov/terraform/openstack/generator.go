@@ -64,15 +64,11 @@ func (g *osGenerator) GenerateTerraformInfraForNode(ctx context.Context, cfg con
 func (g *osGenerator) generateTerraformInfraForNode(ctx context.Context, cfg config.Configuration, deploymentID, nodeName, infrastructurePath string) (bool, map[string]string, []string, commons.PostApplyCallback, error) {
 
 	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName)
-	terraformStateKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "terraform-state", nodeName)
 
 	infrastructure := commons.Infrastructure{}
 
 	log.Debugf("Generating infrastructure for deployment with node %s", nodeName)
 
-	// Remote Configuration for Terraform State to store it in the Consul KV store
-	infrastructure.Terraform = commons.GetBackendConfiguration(terraformStateKey, cfg)
-
 	var locationProps config.DynamicMap
 	locationMgr, err := locations.GetManager(cfg)
 	if err == nil {
@@ -81,6 +77,12 @@ func (g *osGenerator) generateTerraformInfraForNode(ctx context.Context, cfg con
 	if err != nil {
 		return false, nil, nil, nil, err
 	}
+
+	// Remote Configuration for Terraform State to store it in the backend defined for the location
+	infrastructure.Terraform, err = commons.GetBackendConfigurationForNode(cfg, deploymentID, nodeName, locationProps)
+	if err != nil {
+		return false, nil, nil, nil, err
+	}
 	var cmdEnv []string
 	infrastructure.Provider, cmdEnv = getOpenStackProviderEnv(cfg, locationProps)
 
@@ -195,6 +197,12 @@ func (g *osGenerator) generateInstanceInfra(ctx context.Context, opts generateIn
 	case "yorc.nodes.openstack.Network":
 		err = g.generateNetworkInfra(ctx, opts)
 
+	case openstackSecurityGroupType:
+		err = g.generateSecurityGroup(ctx, opts, outputs)
+
+	case openstackLoadBalancerType:
+		err = g.generateLoadBalancer(ctx, opts, outputs)
+
 	case "yorc.nodes.openstack.ServerGroup":
 		err = g.generateServerGroup(ctx,
 			serverGroupOptions{
@@ -300,6 +308,13 @@ func (g *osGenerator) generateFloatingIPInfra(ctx context.Context, opts generate
 
 	}
 	consulKeys := commons.ConsulKeys{Keys: []commons.ConsulKey{consulKey}}
+	dnsKey, err := g.generateFloatingIPDNSRecord(ctx, opts, ip.Name, consulKey.Value)
+	if err != nil {
+		return err
+	}
+	if dnsKey != nil {
+		consulKeys.Keys = append(consulKeys.Keys, *dnsKey)
+	}
 	commons.AddResource(opts.infrastructure, consulKeysResource, ip.Name, &consulKeys)
 	return err
 }
@@ -332,11 +347,16 @@ func (g *osGenerator) generateNetworkInfra(ctx context.Context, opts generateInf
 		opts.nodeName, &network)
 	commons.AddResource(opts.infrastructure, opts.resourceTypes[networkingSubnet],
 		opts.nodeName+"_subnet", &subnet)
+	nodeAttributesPrefix := path.Join(consulutil.DeploymentKVPrefix, opts.deploymentID, "topology",
+		"nodes", opts.nodeName, "attributes")
 	consulKey := commons.ConsulKey{
-		Path: path.Join(consulutil.DeploymentKVPrefix, opts.deploymentID, "topology",
-			"nodes", opts.nodeName, "attributes/network_id"),
+		Path:  path.Join(nodeAttributesPrefix, "network_id"),
 		Value: fmt.Sprintf("${%s.%s.id}", opts.resourceTypes[networkingNetwork], opts.nodeName)}
-	consulKeys := commons.ConsulKeys{Keys: []commons.ConsulKey{consulKey}}
+	// The subnet ID allows load balancers to get a virtual IP address on this network
+	subnetKey := commons.ConsulKey{
+		Path:  path.Join(nodeAttributesPrefix, "subnet_id"),
+		Value: fmt.Sprintf("${%s.%s_subnet.id}", opts.resourceTypes[networkingSubnet], opts.nodeName)}
+	consulKeys := commons.ConsulKeys{Keys: []commons.ConsulKey{consulKey, subnetKey}}
 	consulKeys.DependsOn = []string{fmt.Sprintf("%s.%s_subnet", opts.resourceTypes[networkingSubnet],
 		opts.nodeName)}
 	commons.AddResource(opts.infrastructure, consulKeysResource, opts.nodeName, &consulKeys)
@@ -27,4 +27,5 @@ func init() {
 	reg.RegisterDelegates([]string{`yorc\.nodes\.openstack\..*`}, terraform.NewExecutor(&osGenerator{}, commons.PreDestroyStorageInfraCallback), registry.BuiltinOrigin)
 	reg.RegisterOperationExecutor(
 		[]string{openstackDeploymentArtifact}, &defaultExecutor{}, registry.BuiltinOrigin)
+	reg.RegisterInfraUsageCollector(infrastructureType, commons.NewCostInfraUsageCollector(infrastructureType, &osGenerator{}), registry.BuiltinOrigin)
 }
@@ -0,0 +1,249 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+const (
+	openstackLoadBalancerType = "yorc.nodes.openstack.LoadBalancer"
+	defaultLBProtocol         = "HTTP"
+	defaultLBMethod           = "ROUND_ROBIN"
+	lbMonitorDelay            = 10
+	lbMonitorTimeout          = 5
+	lbMonitorMaxRetries       = 3
+)
+
+type lbListenerDef struct {
+	port            int
+	protocol        string
+	targetPort      int
+	targetProtocol  string
+	lbMethod        string
+	healthCheckPath string
+	tlsContainerRef string
+}
+
+func (g *osGenerator) generateLoadBalancer(ctx context.Context, opts generateInfraOptions, outputs map[string]string) error {
+	if opts.nodeType != openstackLoadBalancerType {
+		return errors.Errorf("Unsupported node type for %q: %s", opts.nodeName, opts.nodeType)
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, opts.deploymentID, "topology", "nodes", opts.nodeName)
+	region := opts.locationProps.GetStringOrDefault("region", defaultOSRegion)
+
+	lb := &LoadBalancer{
+		Region: region,
+		Name:   opts.cfg.ResourcesPrefix + opts.nodeName,
+	}
+	var err error
+	if lb.Description, err = deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "description", false); err != nil {
+		return err
+	}
+	if lb.VIPAddress, err = deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "vip_address", false); err != nil {
+		return err
+	}
+	if lb.VIPSubnetID, err = getLoadBalancerSubnetID(ctx, opts); err != nil {
+		return err
+	}
+	if lb.SecurityGroupIDs, err = getSecurityGroupsAttribute(ctx, opts.deploymentID, opts.nodeName, "security_group_id"); err != nil {
+		return err
+	}
+
+	listeners, err := getLoadBalancerListeners(ctx, opts)
+	if err != nil {
+		return err
+	}
+	targetReqs, err := deployments.GetRequirementsByTypeForNode(ctx, opts.deploymentID, opts.nodeName, "targets")
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Add load balancer:%+v", lb)
+	commons.AddResource(opts.infrastructure, opts.resourceTypes[lbLoadBalancer], opts.nodeName, lb)
+	lbID := fmt.Sprintf("${%s.%s.id}", opts.resourceTypes[lbLoadBalancer], opts.nodeName)
+
+	for i, listener := range listeners {
+		listenerName := fmt.Sprintf("%s-%d", opts.nodeName, i)
+		commons.AddResource(opts.infrastructure, opts.resourceTypes[lbListener], listenerName, &LBListener{
+			Region:                 region,
+			Name:                   fmt.Sprintf("%s-%d", lb.Name, listener.port),
+			Protocol:               listener.protocol,
+			ProtocolPort:           listener.port,
+			LoadBalancerID:         lbID,
+			DefaultTLSContainerRef: listener.tlsContainerRef,
+		})
+		commons.AddResource(opts.infrastructure, opts.resourceTypes[lbPool], listenerName, &LBPool{
+			Region:     region,
+			Name:       fmt.Sprintf("%s-%d", lb.Name, listener.port),
+			Protocol:   listener.targetProtocol,
+			LBMethod:   listener.lbMethod,
+			ListenerID: fmt.Sprintf("${%s.%s.id}", opts.resourceTypes[lbListener], listenerName),
+		})
+		poolID := fmt.Sprintf("${%s.%s.id}", opts.resourceTypes[lbPool], listenerName)
+
+		if listener.healthCheckPath != "" {
+			monitorType := defaultLBProtocol
+			if listener.targetProtocol == "HTTPS" {
+				monitorType = listener.targetProtocol
+			}
+			commons.AddResource(opts.infrastructure, opts.resourceTypes[lbMonitor], listenerName, &LBMonitor{
+				Region:     region,
+				PoolID:     poolID,
+				Type:       monitorType,
+				Delay:      lbMonitorDelay,
+				Timeout:    lbMonitorTimeout,
+				MaxRetries: lbMonitorMaxRetries,
+				URLPath:    listener.healthCheckPath,
+			})
+		}
+
+		// Add instances of each target compute as pool members
+		for _, req := range targetReqs {
+			instances, err := deployments.GetNodeInstancesIds(ctx, opts.deploymentID, req.Node)
+			if err != nil {
+				return err
+			}
+			for _, instanceName := range instances {
+				address, err := deployments.LookupInstanceAttributeValue(ctx, opts.deploymentID, req.Node, instanceName, "private_address")
+				if err != nil {
+					return err
+				}
+				commons.AddResource(opts.infrastructure, opts.resourceTypes[lbMember],
+					fmt.Sprintf("%s-%s-%s", listenerName, req.Node, instanceName), &LBMember{
+						Region:       region,
+						PoolID:       poolID,
+						Address:      address,
+						ProtocolPort: listener.targetPort,
+					})
+			}
+		}
+	}
+
+	lbIDKey := opts.nodeName + "-load-balancer-id"
+	commons.AddOutput(opts.infrastructure, lbIDKey, &commons.Output{Value: lbID})
+	outputs[path.Join(nodeKey, "/attributes/load_balancer_id")] = lbIDKey
+	vipAddressKey := opts.nodeName + "-vip-address"
+	commons.AddOutput(opts.infrastructure, vipAddressKey, &commons.Output{
+		Value: fmt.Sprintf("${%s.%s.vip_address}", opts.resourceTypes[lbLoadBalancer], opts.nodeName)})
+	outputs[path.Join(nodeKey, "/attributes/vip_address")] = vipAddressKey
+	vipPortKey := opts.nodeName + "-vip-port-id"
+	commons.AddOutput(opts.infrastructure, vipPortKey, &commons.Output{
+		Value: fmt.Sprintf("${%s.%s.vip_port_id}", opts.resourceTypes[lbLoadBalancer], opts.nodeName)})
+	outputs[path.Join(nodeKey, "/attributes/vip_port_id")] = vipPortKey
+	return nil
+}
+
+// getLoadBalancerSubnetID returns the subnet on which the load balancer virtual IP address is allocated,
+// either provided as a property or the subnet of the network the load balancer is bound to
+func getLoadBalancerSubnetID(ctx context.Context, opts generateInfraOptions) (string, error) {
+	subnetID, err := deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "vip_subnet_id", false)
+	if err != nil || subnetID != "" {
+		return subnetID, err
+	}
+	reqs, err := deployments.GetRequirementsByTypeForNode(ctx, opts.deploymentID, opts.nodeName, "network")
+	if err != nil {
+		return "", err
+	}
+	if len(reqs) == 0 {
+		return "", errors.Errorf("load balancer %q requires either a vip_subnet_id property or a network requirement", opts.nodeName)
+	}
+	return deployments.LookupInstanceAttributeValue(ctx, opts.deploymentID, reqs[0].Node, deployments.DefaultInstanceName, "subnet_id")
+}
+
+func getLoadBalancerListeners(ctx context.Context, opts generateInfraOptions) ([]lbListenerDef, error) {
+	listenersValue, err := deployments.GetNodePropertyValue(ctx, opts.deploymentID, opts.nodeName, "listeners")
+	if err != nil {
+		return nil, err
+	}
+	if listenersValue == nil || listenersValue.RawString() == "" {
+		return nil, errors.Errorf("load balancer %q requires at least one listener", opts.nodeName)
+	}
+	list, ok := listenersValue.Value.([]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve yorc.datatypes.openstack.LoadBalancerListener Tosca Value: not expected type")
+	}
+
+	listeners := make([]lbListenerDef, 0, len(list))
+	for i := range list {
+		ind := strconv.Itoa(i)
+		var port, targetPort string
+		listener := lbListenerDef{}
+		stringParams := []struct {
+			pAttr     *string
+			key       string
+			mandatory bool
+		}{
+			{&port, "port", true},
+			{&listener.protocol, "protocol", false},
+			{&targetPort, "target_port", false},
+			{&listener.targetProtocol, "target_protocol", false},
+			{&listener.lbMethod, "lb_method", false},
+			{&listener.healthCheckPath, "health_check_path", false},
+			{&listener.tlsContainerRef, "tls_container_ref", false},
+		}
+		for _, stringParam := range stringParams {
+			val, err := deployments.GetNodePropertyValue(ctx, opts.deploymentID, opts.nodeName, "listeners", ind, stringParam.key)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				*stringParam.pAttr = val.RawString()
+			}
+			if stringParam.mandatory && *stringParam.pAttr == "" {
+				return nil, errors.Errorf("missing mandatory %q value for listener %d of load balancer %q", stringParam.key, i, opts.nodeName)
+			}
+		}
+
+		if listener.port, err = strconv.Atoi(port); err != nil {
+			return nil, errors.Wrapf(err, "invalid port value for listener %d of load balancer %q", i, opts.nodeName)
+		}
+		listener.targetPort = listener.port
+		if targetPort != "" {
+			if listener.targetPort, err = strconv.Atoi(targetPort); err != nil {
+				return nil, errors.Wrapf(err, "invalid target_port value for listener %d of load balancer %q", i, opts.nodeName)
+			}
+		}
+		listener.protocol = strings.ToUpper(listener.protocol)
+		if listener.protocol == "" {
+			listener.protocol = defaultLBProtocol
+		}
+		listener.targetProtocol = strings.ToUpper(listener.targetProtocol)
+		if listener.targetProtocol == "" {
+			listener.targetProtocol = listener.protocol
+			if listener.protocol == "TERMINATED_HTTPS" {
+				// TLS is terminated by the load balancer
+				listener.targetProtocol = defaultLBProtocol
+			}
+		}
+		listener.lbMethod = strings.ToUpper(listener.lbMethod)
+		if listener.lbMethod == "" {
+			listener.lbMethod = defaultLBMethod
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
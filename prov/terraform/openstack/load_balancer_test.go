@@ -0,0 +1,97 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleLoadBalancer(t *testing.T, srv *testutil.TestServer) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances")
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(instancesPrefix, "Network/0/attributes/subnet_id"):          []byte("subnet-1"),
+		path.Join(instancesPrefix, "SecGroup/0/attributes/security_group_id"): []byte("sg-1"),
+		path.Join(instancesPrefix, "Compute/0/attributes/private_address"):    []byte("10.0.0.10"),
+		path.Join(instancesPrefix, "Compute/1/attributes/private_address"):    []byte("10.0.0.11"),
+	})
+	locationProps := config.DynamicMap{}
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := osGenerator{}
+	opts := generateInfraOptions{
+		infrastructure: &infrastructure,
+		locationProps:  locationProps,
+		deploymentID:   deploymentID,
+		nodeName:       "LB",
+		nodeType:       "yorc.nodes.openstack.LoadBalancer",
+		instanceName:   "0",
+		resourceTypes:  getOpenstackResourceTypes(locationProps),
+	}
+
+	err := g.generateLoadBalancer(context.Background(), opts, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate load balancer for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["openstack_lb_loadbalancer_v2"], 1, "Expected one load balancer")
+	lb, ok := infrastructure.Resource["openstack_lb_loadbalancer_v2"].(map[string]interface{})["LB"].(*LoadBalancer)
+	require.True(t, ok, "LB is not a LoadBalancer")
+	assert.Equal(t, &LoadBalancer{Region: defaultOSRegion, Name: "LB", VIPSubnetID: "subnet-1", SecurityGroupIDs: []string{"sg-1"}}, lb)
+
+	listenersMap := infrastructure.Resource["openstack_lb_listener_v2"].(map[string]interface{})
+	require.Len(t, listenersMap, 2)
+	assert.Equal(t, &LBListener{Region: defaultOSRegion, Name: "LB-80", Protocol: "HTTP", ProtocolPort: 80,
+		LoadBalancerID: "${openstack_lb_loadbalancer_v2.LB.id}"}, listenersMap["LB-0"])
+	assert.Equal(t, &LBListener{Region: defaultOSRegion, Name: "LB-443", Protocol: "TERMINATED_HTTPS", ProtocolPort: 443,
+		LoadBalancerID:         "${openstack_lb_loadbalancer_v2.LB.id}",
+		DefaultTLSContainerRef: "https://barbican.example.com/v1/containers/1234"}, listenersMap["LB-1"])
+
+	poolsMap := infrastructure.Resource["openstack_lb_pool_v2"].(map[string]interface{})
+	require.Len(t, poolsMap, 2)
+	assert.Equal(t, &LBPool{Region: defaultOSRegion, Name: "LB-80", Protocol: "HTTP", LBMethod: "ROUND_ROBIN",
+		ListenerID: "${openstack_lb_listener_v2.LB-0.id}"}, poolsMap["LB-0"])
+	assert.Equal(t, &LBPool{Region: defaultOSRegion, Name: "LB-443", Protocol: "HTTP", LBMethod: "LEAST_CONNECTIONS",
+		ListenerID: "${openstack_lb_listener_v2.LB-1.id}"}, poolsMap["LB-1"])
+
+	monitorsMap := infrastructure.Resource["openstack_lb_monitor_v2"].(map[string]interface{})
+	require.Len(t, monitorsMap, 1, "Expected a health monitor only for the listener defining a health check path")
+	assert.Equal(t, &LBMonitor{Region: defaultOSRegion, PoolID: "${openstack_lb_pool_v2.LB-0.id}", Type: "HTTP",
+		Delay: lbMonitorDelay, Timeout: lbMonitorTimeout, MaxRetries: lbMonitorMaxRetries, URLPath: "/health"}, monitorsMap["LB-0"])
+
+	membersMap := infrastructure.Resource["openstack_lb_member_v2"].(map[string]interface{})
+	require.Len(t, membersMap, 4)
+	for _, listenerName := range []string{"LB-0", "LB-1"} {
+		for i, address := range []string{"10.0.0.10", "10.0.0.11"} {
+			member, ok := membersMap[listenerName+"-Compute-"+[]string{"0", "1"}[i]].(*LBMember)
+			require.True(t, ok, "missing member %d for listener %s", i, listenerName)
+			assert.Equal(t, address, member.Address)
+			assert.Equal(t, 8080, member.ProtocolPort)
+			assert.Equal(t, "${openstack_lb_pool_v2."+listenerName+".id}", member.PoolID)
+		}
+	}
+
+	vipAddressKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/LB/attributes/vip_address")]
+	assert.Equal(t, "${openstack_lb_loadbalancer_v2.LB.vip_address}", infrastructure.Output[vipAddressKey].Value)
+}
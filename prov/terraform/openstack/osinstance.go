@@ -163,6 +163,15 @@ func generateComputeInstance(ctx context.Context, opts osInstanceOptions) (Compu
 		}
 	}
 
+	// Security groups defined in the topology
+	topologySecGroups, err := getSecurityGroupsAttribute(ctx, opts.deploymentID, opts.nodeName, "security_group_name")
+	if err != nil {
+		return instance, err
+	}
+	instance.SecurityGroups = append(instance.SecurityGroups, topologySecGroups...)
+
+	instance.Metadata = commons.AddResourceTags(opts.cfg, opts.deploymentID, opts.nodeName, nil)
+	instance.UserData, err = commons.GetUserData(ctx, opts.cfg, opts.deploymentID, opts.nodeName)
 	return instance, err
 }
 
@@ -30,6 +30,14 @@ const (
 	computeFloatingIPAssociate = "compute_floatingip_associate"
 	computeServerGroup         = "compute_servergroup"
 	computeVolumeAttach        = "compute_volume_attach"
+	dnsRecordSet               = "dns_recordset"
+	lbLoadBalancer             = "lb_loadbalancer"
+	lbListener                 = "lb_listener"
+	lbMember                   = "lb_member"
+	lbMonitor                  = "lb_monitor"
+	lbPool                     = "lb_pool"
+	networkingSecGroup         = "networking_secgroup"
+	networkingSecGroupRule     = "networking_secgroup_rule"
 	networkingNetwork          = "networking_network"
 	networkingSubnet           = "networking_subnet"
 	resourceTypeFormat         = "openstack_%s_%s"
@@ -49,7 +57,8 @@ func getOpenstackResourceTypes(locationProps config.DynamicMap) map[string]strin
 	// Resources for which there is just one possible version supported
 	v2Resources := []string{
 		computeInstance, computeFloatingIP, computeFloatingIPAssociate, computeServerGroup,
-		computeVolumeAttach, networkingNetwork, networkingSubnet,
+		computeVolumeAttach, dnsRecordSet, lbLoadBalancer, lbListener, lbMember, lbMonitor, lbPool,
+		networkingNetwork, networkingSecGroup, networkingSecGroupRule, networkingSubnet,
 	}
 
 	for _, resource := range v2Resources {
@@ -31,19 +31,21 @@ const (
 
 // A ComputeInstance represent an OpenStack compute
 type ComputeInstance struct {
-	Region           string           `json:"region"`
-	Name             string           `json:"name,omitempty"`
-	ImageID          string           `json:"image_id,omitempty"`
-	ImageName        string           `json:"image_name,omitempty"`
-	BootVolume       *BootVolume      `json:"block_device,omitempty"`
-	FlavorID         string           `json:"flavor_id,omitempty"`
-	FlavorName       string           `json:"flavor_name,omitempty"`
-	FloatingIP       string           `json:"floating_ip,omitempty"`
-	SecurityGroups   []string         `json:"security_groups,omitempty"`
-	AvailabilityZone string           `json:"availability_zone,omitempty"`
-	Networks         []ComputeNetwork `json:"network,omitempty"`
-	KeyPair          string           `json:"key_pair,omitempty"`
-	SchedulerHints   SchedulerHints   `json:"scheduler_hints,omitempty"`
+	Region           string            `json:"region"`
+	Name             string            `json:"name,omitempty"`
+	ImageID          string            `json:"image_id,omitempty"`
+	ImageName        string            `json:"image_name,omitempty"`
+	BootVolume       *BootVolume       `json:"block_device,omitempty"`
+	FlavorID         string            `json:"flavor_id,omitempty"`
+	FlavorName       string            `json:"flavor_name,omitempty"`
+	FloatingIP       string            `json:"floating_ip,omitempty"`
+	SecurityGroups   []string          `json:"security_groups,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Networks         []ComputeNetwork  `json:"network,omitempty"`
+	KeyPair          string            `json:"key_pair,omitempty"`
+	SchedulerHints   SchedulerHints    `json:"scheduler_hints,omitempty"`
+	UserData         string            `json:"user_data,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
 
 	commons.Resource
 }
@@ -74,11 +76,12 @@ type ComputeNetwork struct {
 
 // A BlockStorageVolume represent an OpenStack volume (BlockStorage)
 type BlockStorageVolume struct {
-	Region           string `json:"region"`
-	Size             int    `json:"size"`
-	Name             string `json:"name,omitempty"`
-	Description      string `json:"description,omitempty"`
-	AvailabilityZone string `json:"availability_zone,omitempty"`
+	Region           string            `json:"region"`
+	Size             int               `json:"size"`
+	Name             string            `json:"name,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
 }
 
 // A FloatingIP represent an OpenStack Floating IP pool configuration
@@ -139,3 +142,89 @@ type ServerGroup struct {
 type SchedulerHints struct {
 	Group string `json:"group"`
 }
+
+// A SecurityGroup represents an OpenStack networking security group
+// https://www.terraform.io/docs/providers/openstack/r/networking_secgroup_v2.html
+type SecurityGroup struct {
+	Region      string `json:"region,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// A SecurityGroupRule represents a rule of an OpenStack networking security group
+// https://www.terraform.io/docs/providers/openstack/r/networking_secgroup_rule_v2.html
+type SecurityGroupRule struct {
+	Region          string `json:"region,omitempty"`
+	Direction       string `json:"direction"`
+	EtherType       string `json:"ethertype"`
+	Protocol        string `json:"protocol,omitempty"`
+	PortRangeMin    int    `json:"port_range_min,omitempty"`
+	PortRangeMax    int    `json:"port_range_max,omitempty"`
+	RemoteIPPrefix  string `json:"remote_ip_prefix,omitempty"`
+	Description     string `json:"description,omitempty"`
+	SecurityGroupID string `json:"security_group_id"`
+}
+
+// A LoadBalancer represents an OpenStack Octavia load balancer
+// https://www.terraform.io/docs/providers/openstack/r/lb_loadbalancer_v2.html
+type LoadBalancer struct {
+	Region           string   `json:"region,omitempty"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	VIPSubnetID      string   `json:"vip_subnet_id"`
+	VIPAddress       string   `json:"vip_address,omitempty"`
+	SecurityGroupIDs []string `json:"security_group_ids,omitempty"`
+}
+
+// A LBListener represents a listener of an OpenStack Octavia load balancer
+// https://www.terraform.io/docs/providers/openstack/r/lb_listener_v2.html
+type LBListener struct {
+	Region                 string `json:"region,omitempty"`
+	Name                   string `json:"name"`
+	Protocol               string `json:"protocol"`
+	ProtocolPort           int    `json:"protocol_port"`
+	LoadBalancerID         string `json:"loadbalancer_id"`
+	DefaultTLSContainerRef string `json:"default_tls_container_ref,omitempty"`
+}
+
+// A LBPool represents a pool of members of an OpenStack Octavia load balancer listener
+// https://www.terraform.io/docs/providers/openstack/r/lb_pool_v2.html
+type LBPool struct {
+	Region     string `json:"region,omitempty"`
+	Name       string `json:"name"`
+	Protocol   string `json:"protocol"`
+	LBMethod   string `json:"lb_method"`
+	ListenerID string `json:"listener_id"`
+}
+
+// A LBMember represents a member of an OpenStack Octavia load balancer pool
+// https://www.terraform.io/docs/providers/openstack/r/lb_member_v2.html
+type LBMember struct {
+	Region       string `json:"region,omitempty"`
+	PoolID       string `json:"pool_id"`
+	Address      string `json:"address"`
+	ProtocolPort int    `json:"protocol_port"`
+}
+
+// A LBMonitor represents a health monitor of an OpenStack Octavia load balancer pool
+// https://www.terraform.io/docs/providers/openstack/r/lb_monitor_v2.html
+type LBMonitor struct {
+	Region     string `json:"region,omitempty"`
+	PoolID     string `json:"pool_id"`
+	Type       string `json:"type"`
+	Delay      int    `json:"delay"`
+	Timeout    int    `json:"timeout"`
+	MaxRetries int    `json:"max_retries"`
+	URLPath    string `json:"url_path,omitempty"`
+}
+
+// A DNSRecordSet represents an OpenStack Designate DNS record set
+// https://www.terraform.io/docs/providers/openstack/r/dns_recordset_v2.html
+type DNSRecordSet struct {
+	Region  string   `json:"region,omitempty"`
+	ZoneID  string   `json:"zone_id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl,omitempty"`
+	Records []string `json:"records"`
+}
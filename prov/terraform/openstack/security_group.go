@@ -0,0 +1,193 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+const openstackSecurityGroupType = "yorc.nodes.openstack.SecurityGroup"
+
+func (g *osGenerator) generateSecurityGroup(ctx context.Context, opts generateInfraOptions, outputs map[string]string) error {
+	if opts.nodeType != openstackSecurityGroupType {
+		return errors.Errorf("Unsupported node type for %q: %s", opts.nodeName, opts.nodeType)
+	}
+	nodeKey := path.Join(consulutil.DeploymentKVPrefix, opts.deploymentID, "topology", "nodes", opts.nodeName)
+	region := opts.locationProps.GetStringOrDefault("region", defaultOSRegion)
+
+	secGroup := &SecurityGroup{
+		Region: region,
+		Name:   opts.cfg.ResourcesPrefix + opts.nodeName,
+	}
+	var err error
+	secGroup.Description, err = deployments.GetStringNodeProperty(ctx, opts.deploymentID, opts.nodeName, "description", false)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Add security group:%+v", secGroup)
+	commons.AddResource(opts.infrastructure, opts.resourceTypes[networkingSecGroup], opts.nodeName, secGroup)
+	secGroupID := fmt.Sprintf("${%s.%s.id}", opts.resourceTypes[networkingSecGroup], opts.nodeName)
+
+	// Ingress rules allowing to reach endpoints of components hosted on computes using this security group
+	ingressPrefixes, err := deployments.GetStringArrayNodeProperty(ctx, opts.deploymentID, opts.nodeName, "ingress_cidr_blocks")
+	if err != nil {
+		return err
+	}
+	endpointRules, err := commons.GetEndpointsPortRules(ctx, opts.deploymentID, "security_group", opts.nodeName)
+	if err != nil {
+		return err
+	}
+	for _, r := range endpointRules {
+		for i, prefix := range ingressPrefixes {
+			commons.AddResource(opts.infrastructure, opts.resourceTypes[networkingSecGroupRule],
+				fmt.Sprintf("%s-ingress-%s-%d-%d", opts.nodeName, r.Protocol, r.Port, i),
+				&SecurityGroupRule{
+					Region:          region,
+					Direction:       "ingress",
+					EtherType:       getEtherType(prefix),
+					Protocol:        r.Protocol,
+					PortRangeMin:    r.Port,
+					PortRangeMax:    r.Port,
+					RemoteIPPrefix:  prefix,
+					SecurityGroupID: secGroupID,
+				})
+		}
+	}
+
+	// Rules explicitly defined on the security group.
+	// Outgoing traffic is allowed by the default rules OpenStack adds to new security groups.
+	rules, err := getSecurityGroupRules(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for i, rule := range rules {
+		rule.Region = region
+		rule.SecurityGroupID = secGroupID
+		commons.AddResource(opts.infrastructure, opts.resourceTypes[networkingSecGroupRule],
+			fmt.Sprintf("%s-rule-%d", opts.nodeName, i), rule)
+	}
+
+	secGroupIDKey := opts.nodeName + "-security-group-id"
+	commons.AddOutput(opts.infrastructure, secGroupIDKey, &commons.Output{Value: secGroupID})
+	outputs[path.Join(nodeKey, "/attributes/security_group_id")] = secGroupIDKey
+	secGroupNameKey := opts.nodeName + "-security-group-name"
+	commons.AddOutput(opts.infrastructure, secGroupNameKey, &commons.Output{
+		Value: fmt.Sprintf("${%s.%s.name}", opts.resourceTypes[networkingSecGroup], opts.nodeName)})
+	outputs[path.Join(nodeKey, "/attributes/security_group_name")] = secGroupNameKey
+	return nil
+}
+
+func getSecurityGroupRules(ctx context.Context, opts generateInfraOptions) ([]*SecurityGroupRule, error) {
+	rulesValue, err := deployments.GetNodePropertyValue(ctx, opts.deploymentID, opts.nodeName, "rules")
+	if err != nil || rulesValue == nil || rulesValue.RawString() == "" {
+		return nil, err
+	}
+	list, ok := rulesValue.Value.([]interface{})
+	if !ok {
+		return nil, errors.New("failed to retrieve yorc.datatypes.openstack.SecurityGroupRule Tosca Value: not expected type")
+	}
+
+	rules := make([]*SecurityGroupRule, 0, len(list))
+	for i := range list {
+		ind := strconv.Itoa(i)
+		rule := &SecurityGroupRule{}
+		var fromPort, toPort string
+		stringParams := []struct {
+			pAttr *string
+			key   string
+		}{
+			{&rule.Direction, "direction"},
+			{&rule.Protocol, "protocol"},
+			{&rule.Description, "description"},
+			{&rule.RemoteIPPrefix, "remote_ip_prefix"},
+			{&fromPort, "from_port"},
+			{&toPort, "to_port"},
+		}
+		for _, stringParam := range stringParams {
+			val, err := deployments.GetNodePropertyValue(ctx, opts.deploymentID, opts.nodeName, "rules", ind, stringParam.key)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				*stringParam.pAttr = val.RawString()
+			}
+		}
+
+		if rule.Direction == "" {
+			rule.Direction = "ingress"
+		}
+		switch strings.ToLower(rule.Protocol) {
+		case "":
+			rule.Protocol = "tcp"
+		case "any":
+			// Terraform matches all protocols when no protocol is set
+			rule.Protocol = ""
+		}
+		if rule.RemoteIPPrefix == "" {
+			rule.RemoteIPPrefix = "0.0.0.0/0"
+		}
+		rule.EtherType = getEtherType(rule.RemoteIPPrefix)
+		if fromPort != "" {
+			if rule.PortRangeMin, err = strconv.Atoi(fromPort); err != nil {
+				return nil, errors.Wrapf(err, "invalid from_port value for rule %d of security group %q", i, opts.nodeName)
+			}
+			rule.PortRangeMax = rule.PortRangeMin
+		}
+		if toPort != "" {
+			if rule.PortRangeMax, err = strconv.Atoi(toPort); err != nil {
+				return nil, errors.Wrapf(err, "invalid to_port value for rule %d of security group %q", i, opts.nodeName)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getEtherType returns the ether type matching a remote IP prefix
+func getEtherType(remoteIPPrefix string) string {
+	if strings.Contains(remoteIPPrefix, ":") {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
+// getSecurityGroupsAttribute returns the value of an attribute of security groups a node is bound to
+// through security_group requirements
+func getSecurityGroupsAttribute(ctx context.Context, deploymentID, nodeName, attributeName string) ([]string, error) {
+	reqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "security_group")
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, req := range reqs {
+		value, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, req.Node, deployments.DefaultInstanceName, attributeName)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleSecurityGroup(t *testing.T, srv *testutil.TestServer) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/SecGroup/0/attributes/security_group_name"): []byte("yorc-SecGroup"),
+	})
+	locationProps := config.DynamicMap{"region": "RegionTwo"}
+	infrastructure := commons.Infrastructure{}
+	outputs := make(map[string]string)
+	g := osGenerator{}
+	opts := generateInfraOptions{
+		infrastructure: &infrastructure,
+		locationProps:  locationProps,
+		deploymentID:   deploymentID,
+		nodeName:       "SecGroup",
+		nodeType:       "yorc.nodes.openstack.SecurityGroup",
+		instanceName:   "0",
+		resourceTypes:  getOpenstackResourceTypes(locationProps),
+	}
+
+	err := g.generateSecurityGroup(context.Background(), opts, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate security group for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["openstack_networking_secgroup_v2"], 1, "Expected one security group")
+	secGroup, ok := infrastructure.Resource["openstack_networking_secgroup_v2"].(map[string]interface{})["SecGroup"].(*SecurityGroup)
+	require.True(t, ok, "SecGroup is not a SecurityGroup")
+	assert.Equal(t, &SecurityGroup{Region: "RegionTwo", Name: "SecGroup", Description: "Managed by Yorc"}, secGroup)
+
+	secGroupID := "${openstack_networking_secgroup_v2.SecGroup.id}"
+	endpointRule := func(protocol string, port int, prefix, etherType string) *SecurityGroupRule {
+		return &SecurityGroupRule{Region: "RegionTwo", Direction: "ingress", EtherType: etherType, Protocol: protocol,
+			PortRangeMin: port, PortRangeMax: port, RemoteIPPrefix: prefix, SecurityGroupID: secGroupID}
+	}
+	expectedRules := map[string]*SecurityGroupRule{
+		"SecGroup-ingress-tcp-22-0":   endpointRule("tcp", 22, "10.0.0.0/8", "IPv4"),
+		"SecGroup-ingress-tcp-22-1":   endpointRule("tcp", 22, "2001:db8::/32", "IPv6"),
+		"SecGroup-ingress-tcp-8080-0": endpointRule("tcp", 8080, "10.0.0.0/8", "IPv4"),
+		"SecGroup-ingress-tcp-8080-1": endpointRule("tcp", 8080, "2001:db8::/32", "IPv6"),
+		"SecGroup-ingress-udp-9000-0": endpointRule("udp", 9000, "10.0.0.0/8", "IPv4"),
+		"SecGroup-ingress-udp-9000-1": endpointRule("udp", 9000, "2001:db8::/32", "IPv6"),
+		"SecGroup-rule-0": {Region: "RegionTwo", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp",
+			PortRangeMin: 8000, PortRangeMax: 8010, RemoteIPPrefix: "10.1.0.0/24", SecurityGroupID: secGroupID},
+		"SecGroup-rule-1": {Region: "RegionTwo", Direction: "egress", EtherType: "IPv4", Protocol: "udp",
+			PortRangeMin: 53, PortRangeMax: 53, RemoteIPPrefix: "0.0.0.0/0", SecurityGroupID: secGroupID},
+		"SecGroup-rule-2": {Region: "RegionTwo", Direction: "ingress", EtherType: "IPv4", Protocol: "icmp",
+			RemoteIPPrefix: "0.0.0.0/0", SecurityGroupID: secGroupID},
+	}
+	rulesMap := infrastructure.Resource["openstack_networking_secgroup_rule_v2"].(map[string]interface{})
+	require.Len(t, rulesMap, len(expectedRules))
+	for name, expected := range expectedRules {
+		require.Contains(t, rulesMap, name)
+		assert.Equal(t, expected, rulesMap[name], "unexpected rule %q", name)
+	}
+
+	secGroupIDKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/nodes/SecGroup/attributes/security_group_id")]
+	assert.Equal(t, secGroupID, infrastructure.Output[secGroupIDKey].Value)
+
+	// Computes bound to the security group use it
+	instance, err := generateComputeInstance(context.Background(), osInstanceOptions{
+		locationProps: locationProps,
+		deploymentID:  deploymentID,
+		nodeName:      "Compute",
+		instanceName:  "0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"yorc-SecGroup"}, instance.SecurityGroups)
+}
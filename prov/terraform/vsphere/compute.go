@@ -0,0 +1,303 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *vsphereGenerator) generateVirtualMachine(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, inv *inventory, deploymentID, nodeName, instanceName string, infrastructure *commons.Infrastructure, outputs map[string]string, env *[]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.vsphere.Compute" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID,
+		"topology", "instances")
+	instancesKey := path.Join(instancesPrefix, nodeName)
+
+	vm := VirtualMachine{
+		Name: getResourceName(cfg, deploymentID, nodeName, instanceName),
+		// Exposes disks UUIDs to the guest OS, allowing to identify attached disks
+		EnableDiskUUID: true,
+	}
+
+	// Resolve the placement of the Virtual Machine
+	var datastore, cluster, resourcePool string
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+	}{
+		{&datastore, "datastore"},
+		{&cluster, "cluster"},
+		{&resourcePool, "resource_pool"},
+		{&vm.Folder, "folder"},
+	}
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = getNodeOrLocationProperty(ctx, locationProps, deploymentID, nodeName, stringParam.propertyName); err != nil {
+			return err
+		}
+	}
+	if vm.DatastoreID, err = inv.datastoreID(ctx, datastore); err != nil {
+		return err
+	}
+	if vm.ResourcePoolID, err = inv.resourcePoolID(ctx, cluster, resourcePool); err != nil {
+		return err
+	}
+
+	// The Virtual Machine inherits its guest OS, controllers and disks from its template
+	templateName, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "template", true)
+	if err != nil {
+		return err
+	}
+	template, err := inv.template(ctx, templateName)
+	if err != nil {
+		return err
+	}
+	if len(template.disks) == 0 {
+		return errors.Errorf("template %q of node %q has no disk", templateName, nodeName)
+	}
+	vm.GuestID = template.guestID
+	vm.SCSIType = template.scsiType
+	vm.Clone.TemplateUUID = template.uuid
+	if vm.Clone.LinkedClone, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "linked_clone"); err != nil {
+		return err
+	}
+
+	// CPU, memory and size of the first disk are defined by the host capability
+	if err = setHostResources(ctx, deploymentID, nodeName, &vm, template); err != nil {
+		return err
+	}
+
+	// Network interfaces, the first one is used to connect to the Virtual Machine
+	networkNames, err := getNetworkNames(ctx, locationProps, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	customize := &Customize{LinuxOptions: LinuxOptions{HostName: vm.Name}}
+	if customize.LinuxOptions.Domain, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, "domain", false); err != nil {
+		return err
+	}
+	for _, networkName := range networkNames {
+		networkID, err := inv.networkID(ctx, networkName)
+		if err != nil {
+			return err
+		}
+		vm.NetworkInterfaces = append(vm.NetworkInterfaces, NetworkInterface{NetworkID: networkID, AdapterType: template.adapterType})
+		customize.NetworkInterfaces = append(customize.NetworkInterfaces, CustomizeNetworkInterface{})
+	}
+	var noCustomization bool
+	if noCustomization, err = deployments.GetBooleanNodeProperty(ctx, deploymentID, nodeName, "no_customization"); err != nil {
+		return err
+	}
+	if !noCustomization {
+		vm.Clone.Customize = customize
+	}
+
+	// User data are read by the cloud-init VMware guestinfo datasource
+	userData, err := commons.GetUserData(ctx, cfg, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if userData != "" {
+		vm.ExtraConfig = map[string]string{
+			"guestinfo.userdata":          base64.StdEncoding.EncodeToString([]byte(userData)),
+			"guestinfo.userdata.encoding": "base64",
+		}
+	}
+
+	// Attach virtual disks
+	if err = addAttachedDisks(ctx, locationProps, inv, deploymentID, nodeName, instanceName, &vm, infrastructure, outputs); err != nil {
+		return err
+	}
+
+	commons.AddResource(infrastructure, "vsphere_virtual_machine", vm.Name, &vm)
+
+	vmIDKey := nodeName + "-" + instanceName + "-vmID"
+	commons.AddOutput(infrastructure, vmIDKey, &commons.Output{Value: fmt.Sprintf("${vsphere_virtual_machine.%s.uuid}", vm.Name)})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/vm_id")] = vmIDKey
+
+	// The address of the Virtual Machine is the one reported by VMware Tools
+	accessIP := fmt.Sprintf("${vsphere_virtual_machine.%s.default_ip_address}", vm.Name)
+	accessIPKey := nodeName + "-" + instanceName + "-IPAddress"
+	commons.AddOutput(infrastructure, accessIPKey, &commons.Output{Value: accessIP})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/private_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/attributes/ip_address")] = accessIPKey
+	outputs[path.Join(instancesKey, instanceName, "/capabilities/endpoint/attributes/ip_address")] = accessIPKey
+
+	// Add Connection check
+	user, privateKey, err := commons.GetConnInfoFromEndpointCredentials(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	return commons.AddConnectionCheckResource(ctx, deploymentID, nodeName, infrastructure, user, privateKey, accessIP, vm.Name, env)
+}
+
+// setHostResources defines the CPUs, the memory and the disks of a Virtual Machine from the host capability
+// of its node and from its template. Disks of the template are kept, the first one being extended to the
+// disk_size of the host capability if needed.
+func setHostResources(ctx context.Context, deploymentID, nodeName string, vm *VirtualMachine, template *templateInfo) error {
+	numCPUs, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "num_cpus")
+	if err != nil {
+		return err
+	}
+	if numCPUs != nil && numCPUs.RawString() != "" {
+		if vm.NumCPUs, err = strconv.Atoi(numCPUs.RawString()); err != nil {
+			return errors.Wrapf(err, "invalid num_cpus %q for node %q", numCPUs.RawString(), nodeName)
+		}
+	}
+	memSize, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "mem_size")
+	if err != nil {
+		return err
+	}
+	if memSize != nil && memSize.RawString() != "" {
+		bytes, err := humanize.ParseBytes(memSize.RawString())
+		if err != nil {
+			return errors.Wrapf(err, "invalid mem_size %q for node %q", memSize.RawString(), nodeName)
+		}
+		vm.Memory = int64(bytes / humanize.MByte)
+	}
+
+	for i, disk := range template.disks {
+		thinProvisioned, eagerlyScrub := disk.thinProvisioned, disk.eagerlyScrub
+		vm.Disks = append(vm.Disks, Disk{
+			Label:           fmt.Sprintf("disk%d", i),
+			Size:            disk.sizeGB,
+			UnitNumber:      disk.unitNumber,
+			ThinProvisioned: &thinProvisioned,
+			EagerlyScrub:    &eagerlyScrub,
+		})
+	}
+	diskSize, err := deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, "host", "disk_size")
+	if err != nil {
+		return err
+	}
+	if diskSize != nil && diskSize.RawString() != "" {
+		size, err := sizeutil.ConvertToGB(diskSize.RawString())
+		if err != nil {
+			return err
+		}
+		// A disk can't be shrunk
+		if size > vm.Disks[0].Size {
+			vm.Disks[0].Size = size
+		}
+	}
+	return nil
+}
+
+// getNetworkNames returns the names of networks to which a Compute is connected, defined by network relationships
+// to network nodes or by the network property of its location
+func getNetworkNames(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName string) ([]string, error) {
+	networkReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "network")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, networkReq := range networkReqs {
+		name, err := deployments.GetStringNodeProperty(ctx, deploymentID, networkReq.Node, "network_name", true)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		// An empty name means the default network of the datacenter
+		names = append(names, locationProps.GetString("network"))
+	}
+	return names, nil
+}
+
+func addAttachedDisks(ctx context.Context, locationProps config.DynamicMap, inv *inventory, deploymentID, nodeName, instanceName string, vm *VirtualMachine, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+	storageReqs, err := deployments.GetRequirementsByTypeForNode(ctx, deploymentID, nodeName, "local_storage")
+	if err != nil {
+		return err
+	}
+	for _, storageReq := range storageReqs {
+		volumeNodeName := storageReq.Node
+		log.Debugf("Volume attachment required form Volume named %s", volumeNodeName)
+
+		vmdkPath, err := deployments.LookupInstanceAttributeValue(ctx, deploymentID, volumeNodeName, instanceName, "volume_id")
+		if err != nil {
+			return err
+		}
+		datastore, err := getNodeOrLocationProperty(ctx, locationProps, deploymentID, volumeNodeName, "datastore")
+		if err != nil {
+			return err
+		}
+
+		disk := Disk{
+			Label:      fmt.Sprintf("disk%d", len(vm.Disks)),
+			UnitNumber: nextUnitNumber(vm.Disks),
+			Attach:     true,
+			Path:       vmdkPath,
+		}
+		if disk.DatastoreID, err = inv.datastoreID(ctx, datastore); err != nil {
+			return err
+		}
+		unitNumber, err := deployments.GetRelationshipPropertyValueFromRequirement(ctx, deploymentID, nodeName, storageReq.Index, "unit_number")
+		if err != nil {
+			return err
+		}
+		if unitNumber != nil && unitNumber.RawString() != "" {
+			if disk.UnitNumber, err = strconv.Atoi(unitNumber.RawString()); err != nil {
+				return errors.Wrapf(err, "invalid unit_number %q for the attachment of %q to %q", unitNumber.RawString(), volumeNodeName, nodeName)
+			}
+		}
+		diskIndex := len(vm.Disks)
+		vm.Disks = append(vm.Disks, disk)
+
+		// As disks UUIDs are exposed to the guest OS, udev creates stable links to disks from their WWN
+		deviceKey := volumeNodeName + "-" + instanceName + "-device"
+		commons.AddOutput(infrastructure, deviceKey, &commons.Output{
+			Value: fmt.Sprintf(`/dev/disk/by-id/wwn-0x${lower(replace(vsphere_virtual_machine.%s.disk.%d.uuid, "-", ""))}`, vm.Name, diskIndex)})
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", volumeNodeName, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", nodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+		outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "relationship_instances", volumeNodeName, storageReq.Index, instanceName, "attributes/device")] = deviceKey
+	}
+	return nil
+}
+
+// nextUnitNumber returns the first unit number following the ones of the given disks.
+// The unit number 7 is reserved to the SCSI controller.
+func nextUnitNumber(disks []Disk) int {
+	next := 0
+	for _, d := range disks {
+		if d.UnitNumber >= next {
+			next = d.UnitNumber + 1
+		}
+	}
+	if next == 7 {
+		next++
+	}
+	return next
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+var expectedKey []byte
+
+func init() {
+	var err error
+	expectedKey, err = ioutil.ReadFile("./testdata/mykey.pem")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func loadTestYaml(t *testing.T) string {
+	deploymentID := path.Base(t.Name())
+	yamlName := "testdata/" + deploymentID + ".yaml"
+	err := deployments.StoreDeploymentDefinition(context.Background(), deploymentID, yamlName)
+	require.NoError(t, err, "Failed to parse "+yamlName+" definition")
+	return deploymentID
+}
+
+func newTestInventory(t *testing.T) *inventory {
+	inv, err := newInventory(context.Background(), testLocationProperties, "")
+	require.NoError(t, err, "Failed to connect to the vCenter simulator")
+	return inv
+}
+
+// getReferenceValue returns the managed object ID of an object of the simulator inventory
+func getReferenceValue(t *testing.T, inv *inventory, inventoryPath string) string {
+	elt, err := inv.finder.ManagedObjectList(context.Background(), inventoryPath)
+	require.NoError(t, err)
+	require.Len(t, elt, 1, "Expected a single object at path %s", inventoryPath)
+	return elt[0].Object.Reference().Value
+}
+
+func getVirtualMachine(t *testing.T, infrastructure *commons.Infrastructure, vmName string) *VirtualMachine {
+	require.Len(t, infrastructure.Resource["vsphere_virtual_machine"], 1, "Expected one virtual machine")
+	vmMap := infrastructure.Resource["vsphere_virtual_machine"].(map[string]interface{})
+	require.Contains(t, vmMap, vmName)
+	vm, ok := vmMap[vmName].(*VirtualMachine)
+	require.True(t, ok, "%s is not a VirtualMachine", vmName)
+	return vm
+}
+
+func testSimpleVirtualMachine(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	inv := newTestInventory(t)
+	defer inv.close(context.Background())
+
+	infrastructure := commons.Infrastructure{}
+	g := vsphereGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, inv, deploymentID, "Compute", "0", &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual machine for %s", deploymentID)
+
+	vmName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	vm := getVirtualMachine(t, &infrastructure, vmName)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/datastore/LocalDS_0"), vm.DatastoreID)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/host/DC0_C0/Resources"), vm.ResourcePoolID)
+	assert.Equal(t, "yorc", vm.Folder)
+	assert.Equal(t, 2, vm.NumCPUs)
+	assert.Equal(t, int64(4294), vm.Memory)
+	assert.True(t, vm.EnableDiskUUID)
+
+	template, err := inv.template(context.Background(), "DC0_H0_VM0")
+	require.NoError(t, err)
+	assert.Equal(t, template.uuid, vm.Clone.TemplateUUID)
+	assert.Equal(t, template.guestID, vm.GuestID)
+	assert.Equal(t, "pvscsi", vm.SCSIType)
+	assert.False(t, vm.Clone.LinkedClone)
+
+	require.Len(t, vm.Disks, 1)
+	assert.Equal(t, "disk0", vm.Disks[0].Label)
+	assert.Equal(t, 20, vm.Disks[0].Size, "Expected the template disk to be extended to the host disk_size")
+	assert.False(t, vm.Disks[0].Attach)
+
+	require.Len(t, vm.NetworkInterfaces, 1)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/network/VM Network"), vm.NetworkInterfaces[0].NetworkID)
+	assert.Equal(t, "e1000", vm.NetworkInterfaces[0].AdapterType)
+	require.NotNil(t, vm.Clone.Customize)
+	assert.Equal(t, LinuxOptions{HostName: vmName, Domain: "example.com"}, vm.Clone.Customize.LinuxOptions)
+	assert.Len(t, vm.Clone.Customize.NetworkInterfaces, 1)
+
+	require.Contains(t, infrastructure.Resource, "null_resource")
+	nullResources := infrastructure.Resource["null_resource"].(map[string]interface{})
+	require.Contains(t, nullResources, vmName+"-ConnectionCheck")
+	require.Len(t, env, 1)
+	assert.Equal(t, "TF_VAR_private_key="+string(expectedKey), env[0], "env var for private key expected")
+
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Compute", "0")
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/vm_id"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/private_address"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/attributes/ip_address"))
+	require.Contains(t, outputs, path.Join(instancesKey, "/capabilities/endpoint/attributes/ip_address"))
+	assert.NotContains(t, outputs, path.Join(instancesKey, "/attributes/public_address"))
+	require.Equal(t, &commons.Output{Value: fmt.Sprintf("${vsphere_virtual_machine.%s.default_ip_address}", vmName)}, infrastructure.Output[outputs[path.Join(instancesKey, "/attributes/ip_address")]])
+}
+
+func testSimpleVirtualMachineMissingTemplate(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	inv := newTestInventory(t)
+	defer inv.close(context.Background())
+
+	infrastructure := commons.Infrastructure{}
+	g := vsphereGenerator{}
+	env := make([]string, 0)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, inv, deploymentID, "Compute", "0", &infrastructure, make(map[string]string), &env)
+	require.Error(t, err, "Expected missing template error, but had no error")
+	assert.Contains(t, err.Error(), `failed to find template "unknown-template"`)
+}
+
+func testSimpleVirtualMachineWithNetworksAndDisks(t *testing.T, srv1 *testutil.TestServer, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	inv := newTestInventory(t)
+	defer inv.close(context.Background())
+
+	// Simulate the disks "volume_id" attribute registration
+	srv1.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Disk1/0/attributes/volume_id"): []byte("yorc/disk1-0.vmdk"),
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID+"/topology/instances/Disk2/0/attributes/volume_id"): []byte("disks/existing.vmdk"),
+	})
+
+	infrastructure := commons.Infrastructure{}
+	g := vsphereGenerator{}
+	env := make([]string, 0)
+	outputs := make(map[string]string)
+	err := g.generateVirtualMachine(context.Background(), cfg, testLocationProperties, inv, deploymentID, "Compute", "0", &infrastructure, outputs, &env)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual machine for %s", deploymentID)
+
+	vmName := getResourcesPrefix(cfg, deploymentID) + "compute-0"
+	vm := getVirtualMachine(t, &infrastructure, vmName)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/host/DC0_H0/Resources"), vm.ResourcePoolID, "Expected the resource pool to take precedence over the cluster")
+	assert.True(t, vm.Clone.LinkedClone)
+	assert.Nil(t, vm.Clone.Customize)
+	assert.Equal(t, 0, vm.NumCPUs)
+	assert.Equal(t, int64(0), vm.Memory)
+
+	require.Len(t, vm.NetworkInterfaces, 2)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/network/VM Network"), vm.NetworkInterfaces[0].NetworkID)
+	assert.Equal(t, getReferenceValue(t, inv, "/DC0/network/DC0_DVPG0"), vm.NetworkInterfaces[1].NetworkID)
+
+	datastoreID := getReferenceValue(t, inv, "/DC0/datastore/LocalDS_0")
+	require.Len(t, vm.Disks, 3, "Expected the template disk and two attached disks")
+	firstUnitNumber := vm.Disks[0].UnitNumber + 1
+	tests := []struct {
+		disk       string
+		index      int
+		path       string
+		unitNumber int
+	}{
+		{"Disk1", 1, "yorc/disk1-0.vmdk", firstUnitNumber},
+		{"Disk2", 2, "disks/existing.vmdk", 10},
+	}
+	instancesKey := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances")
+	for _, tt := range tests {
+		disk := vm.Disks[tt.index]
+		assert.True(t, disk.Attach, "Expected %s to be attached", tt.disk)
+		assert.Equal(t, tt.path, disk.Path)
+		assert.Equal(t, datastoreID, disk.DatastoreID)
+		assert.Equal(t, tt.unitNumber, disk.UnitNumber)
+		assert.Equal(t, fmt.Sprintf("disk%d", tt.index), disk.Label)
+		assert.Equal(t, 0, disk.Size)
+		assert.Nil(t, disk.ThinProvisioned)
+
+		deviceKey := outputs[path.Join(instancesKey, tt.disk, "0", "attributes/device")]
+		require.NotEmpty(t, deviceKey, "Expected a device output for %s", tt.disk)
+		assert.Equal(t, &commons.Output{Value: fmt.Sprintf(`/dev/disk/by-id/wwn-0x${lower(replace(vsphere_virtual_machine.%s.disk.%d.uuid, "-", ""))}`, vmName, tt.index)}, infrastructure.Output[deviceKey])
+	}
+}
+
+func TestNextUnitNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		disks []Disk
+		want  int
+	}{
+		{"NoDisk", nil, 0},
+		{"OneDisk", []Disk{{UnitNumber: 0}}, 1},
+		{"UnorderedDisks", []Disk{{UnitNumber: 3}, {UnitNumber: 1}}, 4},
+		{"SkipControllerUnit", []Disk{{UnitNumber: 6}}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextUnitNumber(tt.disks))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+var testLocationProperties config.DynamicMap
+
+// The aim of this function is to run all package tests with consul server dependency with only one consul server start
+func TestRunConsulVSpherePackageTests(t *testing.T) {
+	cfg := testutil.SetupTestConfig(t)
+	srv, _ := testutil.NewTestConsulInstance(t, &cfg)
+	defer func() {
+		srv.Stop()
+		os.RemoveAll(cfg.WorkingDirectory)
+	}()
+
+	// vCenter simulator with a datacenter DC0 made of a standalone host DC0_H0 and a cluster DC0_C0
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatalf("Failed to create vCenter simulator model: %v", err)
+	}
+	vcsim := model.Service.NewServer()
+	defer vcsim.Close()
+
+	testLocationProperties = config.DynamicMap{
+		"server":    vcsim.URL.String(),
+		"datastore": "LocalDS_0",
+		"cluster":   "DC0_C0",
+		"network":   "VM Network",
+	}
+
+	t.Run("vsphereProvider", func(t *testing.T) {
+		t.Run("simpleVirtualMachine", func(t *testing.T) {
+			testSimpleVirtualMachine(t, cfg)
+		})
+		t.Run("simpleVirtualMachineMissingTemplate", func(t *testing.T) {
+			testSimpleVirtualMachineMissingTemplate(t, cfg)
+		})
+		t.Run("simpleVirtualMachineWithNetworksAndDisks", func(t *testing.T) {
+			testSimpleVirtualMachineWithNetworksAndDisks(t, srv, cfg)
+		})
+		t.Run("simpleDisk", func(t *testing.T) {
+			testSimpleDisk(t, cfg)
+		})
+	})
+}
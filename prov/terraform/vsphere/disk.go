@@ -0,0 +1,107 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sizeutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (g *vsphereGenerator) generateVirtualDisk(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, inv *inventory, deploymentID, nodeName, instanceName string, instanceID int, infrastructure *commons.Infrastructure, outputs map[string]string) error {
+
+	nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeType != "yorc.nodes.vsphere.Disk" {
+		return errors.Errorf("Unsupported node type for %q: %s", nodeName, nodeType)
+	}
+
+	instancesPrefix := path.Join(consulutil.DeploymentKVPrefix, deploymentID,
+		"topology", "instances")
+	instancesKey := path.Join(instancesPrefix, nodeName)
+
+	var size, volumes, datastore string
+	disk := &VirtualDisk{CreateDirectories: true}
+	stringParams := []struct {
+		pAttr        *string
+		propertyName string
+		mandatory    bool
+	}{
+		{&volumes, "volume_id", false},
+		{&disk.Type, "disk_type", false},
+		{&size, "size", false},
+	}
+
+	for _, stringParam := range stringParams {
+		if *stringParam.pAttr, err = deployments.GetStringNodeProperty(ctx, deploymentID, nodeName,
+			stringParam.propertyName, stringParam.mandatory); err != nil {
+			return err
+		}
+	}
+
+	var volumeID string
+	if volumes != "" {
+		tabVol := strings.Split(volumes, ",")
+		if len(tabVol) > instanceID {
+			volumeID = strings.TrimSpace(tabVol[instanceID])
+		}
+	}
+
+	// Add vSphere virtual disk resource if not any volume ID is provided
+	if volumeID == "" {
+		if size == "" {
+			return errors.Errorf("size is mandatory to create the virtual disk %q", nodeName)
+		}
+		// Default size unit is MB
+		log.Debugf("Initial size property value (default is MB): %q", size)
+		disk.Size, err = sizeutil.ConvertToGB(size)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Computed size (in GB): %d", disk.Size)
+
+		if datastore, err = getNodeOrLocationProperty(ctx, locationProps, deploymentID, nodeName, "datastore"); err != nil {
+			return err
+		}
+		if disk.Datastore, err = inv.datastoreName(ctx, datastore); err != nil {
+			return err
+		}
+		disk.Datacenter = inv.datacenter.Name()
+
+		// Disks of a deployment are stored in a common directory of the datastore
+		name := getResourceName(cfg, deploymentID, nodeName, instanceName)
+		disk.VMDKPath = path.Join(strings.TrimSuffix(getResourcesPrefix(cfg, deploymentID), "-"), name+".vmdk")
+		commons.AddResource(infrastructure, "vsphere_virtual_disk", name, disk)
+		volumeID = fmt.Sprintf("${vsphere_virtual_disk.%s.vmdk_path}", name)
+	}
+
+	// Provide output for attribute volume_id
+	volumeKey := nodeName + "-" + instanceName + "-volume"
+	commons.AddOutput(infrastructure, volumeKey, &commons.Output{Value: volumeID})
+	outputs[path.Join(instancesKey, instanceName, "/attributes/volume_id")] = volumeKey
+	return nil
+}
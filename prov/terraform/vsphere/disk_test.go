@@ -0,0 +1,60 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func testSimpleDisk(t *testing.T, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t)
+	inv := newTestInventory(t)
+	defer inv.close(context.Background())
+
+	infrastructure := commons.Infrastructure{}
+	g := vsphereGenerator{}
+	outputs := make(map[string]string)
+	err := g.generateVirtualDisk(context.Background(), cfg, testLocationProperties, inv, deploymentID, "Disk", "0", 0, &infrastructure, outputs)
+	require.NoError(t, err, "Unexpected error attempting to generate virtual disk for %s", deploymentID)
+
+	require.Len(t, infrastructure.Resource["vsphere_virtual_disk"], 1, "Expected one virtual disk")
+	diskMap := infrastructure.Resource["vsphere_virtual_disk"].(map[string]interface{})
+	prefix := getResourcesPrefix(cfg, deploymentID)
+	diskName := prefix + "disk-0"
+	require.Contains(t, diskMap, diskName)
+	disk, ok := diskMap[diskName].(*VirtualDisk)
+	require.True(t, ok, "%s is not a VirtualDisk", diskName)
+
+	assert.Equal(t, 10, disk.Size)
+	assert.Equal(t, "eagerZeroedThick", disk.Type)
+	assert.Equal(t, "DC0", disk.Datacenter)
+	assert.Equal(t, "LocalDS_0", disk.Datastore)
+	assert.Equal(t, prefix[:len(prefix)-1]+"/"+diskName+".vmdk", disk.VMDKPath)
+	assert.True(t, disk.CreateDirectories)
+
+	volumeKey := outputs[path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", "Disk", "0", "attributes/volume_id")]
+	require.NotEmpty(t, volumeKey)
+	assert.Equal(t, &commons.Output{Value: "${vsphere_virtual_disk." + diskName + ".vmdk_path}"}, infrastructure.Output[volumeKey])
+}
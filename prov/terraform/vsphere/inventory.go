@@ -0,0 +1,212 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// The Terraform vSphere provider identifies inventory objects by their managed object IDs.
+// An inventory resolves names or paths of objects defined in the topology and in the
+// location configuration to these IDs, using the vCenter API.
+type inventory struct {
+	client     *govmomi.Client
+	finder     *find.Finder
+	datacenter *object.Datacenter
+}
+
+// templateInfo holds the properties of a template that should be reported on Virtual Machines cloned from it
+type templateInfo struct {
+	uuid        string
+	guestID     string
+	scsiType    string
+	adapterType string
+	disks       []templateDisk
+}
+
+type templateDisk struct {
+	unitNumber      int
+	sizeGB          int
+	thinProvisioned bool
+	eagerlyScrub    bool
+}
+
+// getServerURL returns the URL of the vCenter SDK endpoint defined by the server property of a location,
+// which is either a host name or a full URL
+func getServerURL(locationProps config.DynamicMap) (*url.URL, error) {
+	server := locationProps.GetString("server")
+	if server == "" {
+		return nil, errors.New("no vCenter server defined in the vSphere location configuration")
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server + "/sdk"
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid vCenter server %q", server)
+	}
+	if user := locationProps.GetString("user"); user != "" {
+		u.User = url.UserPassword(user, locationProps.GetString("password"))
+	}
+	return u, nil
+}
+
+// newInventory connects to the vCenter server of a location and looks up objects in the given datacenter,
+// or in the default one if the name is empty
+func newInventory(ctx context.Context, locationProps config.DynamicMap, datacenter string) (*inventory, error) {
+	u, err := getServerURL(locationProps)
+	if err != nil {
+		return nil, err
+	}
+	client, err := govmomi.NewClient(ctx, u, locationProps.GetBool("allow_unverified_ssl"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to vCenter server %q", u.Host)
+	}
+	inv := &inventory{client: client, finder: find.NewFinder(client.Client, true)}
+	inv.datacenter, err = inv.finder.DatacenterOrDefault(ctx, datacenter)
+	if err != nil {
+		inv.close(ctx)
+		return nil, errors.Wrapf(err, "failed to find datacenter %q", datacenter)
+	}
+	inv.finder.SetDatacenter(inv.datacenter)
+	return inv, nil
+}
+
+func (inv *inventory) close(ctx context.Context) {
+	if err := inv.client.Logout(ctx); err != nil {
+		log.Debugf("failed to logout from vCenter server: %v", err)
+	}
+}
+
+func (inv *inventory) datastore(ctx context.Context, name string) (*object.Datastore, error) {
+	ds, err := inv.finder.DatastoreOrDefault(ctx, name)
+	return ds, errors.Wrapf(err, "failed to find datastore %q", name)
+}
+
+// datastoreID returns the ID of a datastore, or of the default one if the name is empty
+func (inv *inventory) datastoreID(ctx context.Context, name string) (string, error) {
+	ds, err := inv.datastore(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return ds.Reference().Value, nil
+}
+
+// datastoreName returns the name of a datastore, which is the default one if the given name is empty
+func (inv *inventory) datastoreName(ctx context.Context, name string) (string, error) {
+	ds, err := inv.datastore(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return ds.Name(), nil
+}
+
+// resourcePoolID returns the ID of a resource pool. If no resource pool is specified, the root resource pool
+// of the given cluster is used, or the default resource pool of the datacenter if no cluster is specified either.
+func (inv *inventory) resourcePoolID(ctx context.Context, cluster, resourcePool string) (string, error) {
+	var pool *object.ResourcePool
+	var err error
+	switch {
+	case resourcePool != "":
+		pool, err = inv.finder.ResourcePool(ctx, resourcePool)
+		err = errors.Wrapf(err, "failed to find resource pool %q", resourcePool)
+	case cluster != "":
+		var c *object.ClusterComputeResource
+		c, err = inv.finder.ClusterComputeResource(ctx, cluster)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find cluster %q", cluster)
+		}
+		pool, err = c.ResourcePool(ctx)
+		err = errors.Wrapf(err, "failed to get resource pool of cluster %q", cluster)
+	default:
+		pool, err = inv.finder.DefaultResourcePool(ctx)
+		err = errors.Wrap(err, "failed to find default resource pool")
+	}
+	if err != nil {
+		return "", err
+	}
+	return pool.Reference().Value, nil
+}
+
+// networkID returns the ID of a network or distributed port group, or of the default one if the name is empty
+func (inv *inventory) networkID(ctx context.Context, name string) (string, error) {
+	n, err := inv.finder.NetworkOrDefault(ctx, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find network %q", name)
+	}
+	return n.Reference().Value, nil
+}
+
+// template returns the properties of the template (or Virtual Machine) from which Virtual Machines are cloned
+func (inv *inventory) template(ctx context.Context, name string) (*templateInfo, error) {
+	vm, err := inv.finder.VirtualMachine(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find template %q", name)
+	}
+	var moVM mo.VirtualMachine
+	if err = vm.Properties(ctx, vm.Reference(), []string{"config"}, &moVM); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve configuration of template %q", name)
+	}
+	if moVM.Config == nil {
+		return nil, errors.Errorf("no configuration available for template %q", name)
+	}
+	t := &templateInfo{uuid: moVM.Config.Uuid, guestID: moVM.Config.GuestId}
+	for _, device := range moVM.Config.Hardware.Device {
+		switch d := device.(type) {
+		case *types.ParaVirtualSCSIController:
+			t.scsiType = "pvscsi"
+		case *types.VirtualLsiLogicSASController:
+			t.scsiType = "lsilogic-sas"
+		case *types.VirtualLsiLogicController:
+			t.scsiType = "lsilogic"
+		case *types.VirtualVmxnet3:
+			t.setAdapterType("vmxnet3")
+		case *types.VirtualE1000e:
+			t.setAdapterType("e1000e")
+		case *types.VirtualE1000:
+			t.setAdapterType("e1000")
+		case *types.VirtualDisk:
+			disk := templateDisk{sizeGB: int((d.CapacityInKB + 1024*1024 - 1) / (1024 * 1024))}
+			if d.UnitNumber != nil {
+				disk.unitNumber = int(*d.UnitNumber)
+			}
+			if backing, ok := d.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+				disk.thinProvisioned = backing.ThinProvisioned != nil && *backing.ThinProvisioned
+				disk.eagerlyScrub = backing.EagerlyScrub != nil && *backing.EagerlyScrub
+			}
+			t.disks = append(t.disks, disk)
+		}
+	}
+	return t, nil
+}
+
+// setAdapterType keeps the type of the first network adapter of a template
+func (t *templateInfo) setAdapterType(adapterType string) {
+	if t.adapterType == "" {
+		t.adapterType = adapterType
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestGetServerURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    config.DynamicMap
+		wantURL  string
+		wantUser string
+		wantErr  bool
+	}{
+		{"HostName", config.DynamicMap{"server": "vcenter.example.com", "user": "admin", "password": "secret"}, "https://vcenter.example.com/sdk", "admin", false},
+		{"FullURL", config.DynamicMap{"server": "http://127.0.0.1:8989/sdk"}, "http://127.0.0.1:8989/sdk", "", false},
+		{"NoServer", config.DynamicMap{"user": "admin"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := getServerURL(tt.props)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, u.User.Username())
+			u.User = nil
+			assert.Equal(t, tt.wantURL, u.String())
+		})
+	}
+}
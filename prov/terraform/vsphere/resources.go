@@ -0,0 +1,88 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+// A VirtualMachine represent a vSphere Virtual Machine
+//
+// See https://www.terraform.io/docs/providers/vsphere/r/virtual_machine.html
+type VirtualMachine struct {
+	Name              string             `json:"name"`
+	ResourcePoolID    string             `json:"resource_pool_id"`
+	DatastoreID       string             `json:"datastore_id"`
+	Folder            string             `json:"folder,omitempty"`
+	NumCPUs           int                `json:"num_cpus,omitempty"`
+	Memory            int64              `json:"memory,omitempty"`
+	GuestID           string             `json:"guest_id,omitempty"`
+	SCSIType          string             `json:"scsi_type,omitempty"`
+	EnableDiskUUID    bool               `json:"enable_disk_uuid,omitempty"`
+	NetworkInterfaces []NetworkInterface `json:"network_interface"`
+	Disks             []Disk             `json:"disk"`
+	Clone             Clone              `json:"clone"`
+	ExtraConfig       map[string]string  `json:"extra_config,omitempty"`
+}
+
+// A NetworkInterface represents a virtual network adapter of a Virtual Machine
+type NetworkInterface struct {
+	NetworkID   string `json:"network_id"`
+	AdapterType string `json:"adapter_type,omitempty"`
+}
+
+// A Disk represents a virtual disk of a Virtual Machine, either cloned from the template or attached to it
+type Disk struct {
+	Label           string `json:"label"`
+	Size            int    `json:"size,omitempty"`
+	UnitNumber      int    `json:"unit_number"`
+	ThinProvisioned *bool  `json:"thin_provisioned,omitempty"`
+	EagerlyScrub    *bool  `json:"eagerly_scrub,omitempty"`
+	Attach          bool   `json:"attach,omitempty"`
+	Path            string `json:"path,omitempty"`
+	DatastoreID     string `json:"datastore_id,omitempty"`
+}
+
+// A Clone defines the template from which a Virtual Machine is cloned
+type Clone struct {
+	TemplateUUID string     `json:"template_uuid"`
+	LinkedClone  bool       `json:"linked_clone,omitempty"`
+	Customize    *Customize `json:"customize,omitempty"`
+}
+
+// Customize defines the guest OS customization applied on a cloned Virtual Machine
+type Customize struct {
+	LinuxOptions      LinuxOptions                `json:"linux_options"`
+	NetworkInterfaces []CustomizeNetworkInterface `json:"network_interface"`
+}
+
+// LinuxOptions defines the customization of the host name of a Linux Virtual Machine
+type LinuxOptions struct {
+	HostName string `json:"host_name"`
+	Domain   string `json:"domain"`
+}
+
+// A CustomizeNetworkInterface defines the customization of a network interface.
+// As no static address is defined, the interface is configured through DHCP.
+type CustomizeNetworkInterface struct {
+}
+
+// A VirtualDisk represents a standalone virtual disk that may be attached to Virtual Machines
+//
+// See https://www.terraform.io/docs/providers/vsphere/r/virtual_disk.html
+type VirtualDisk struct {
+	Size              int    `json:"size"`
+	VMDKPath          string `json:"vmdk_path"`
+	Datacenter        string `json:"datacenter"`
+	Datastore         string `json:"datastore"`
+	Type              string `json:"type,omitempty"`
+	CreateDirectories bool   `json:"create_directories,omitempty"`
+}
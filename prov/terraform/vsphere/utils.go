@@ -0,0 +1,47 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsphere
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+func getResourcesPrefix(cfg config.Configuration, deploymentID string) string {
+	b := sha1.Sum([]byte(deploymentID))
+	return fmt.Sprintf("%s%x-", cfg.ResourcesPrefix, b[0:3])
+}
+
+// getResourceName returns the name of a vSphere object created for the given node (and instance if any).
+// Names are also used as host names, so they are made of lower case alphanumeric characters and hyphens.
+func getResourceName(cfg config.Configuration, deploymentID, nodeName string, suffixes ...string) string {
+	name := getResourcesPrefix(cfg, deploymentID) + strings.Join(append([]string{nodeName}, suffixes...), "-")
+	return strings.Replace(strings.ToLower(name), "_", "-", -1)
+}
+
+// getNodeOrLocationProperty returns the value of a node property if it is defined,
+// else the value of the location property of the same name
+func getNodeOrLocationProperty(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName, propertyName string) (string, error) {
+	value, err := deployments.GetStringNodeProperty(ctx, deploymentID, nodeName, propertyName, false)
+	if err != nil || value != "" {
+		return value, err
+	}
+	return locationProps.GetString(propertyName), nil
+}
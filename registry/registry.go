@@ -235,9 +235,10 @@ func (r *defaultRegistry) ListVaultClientBuilders() []VaultClientBuilder {
 }
 
 func (r *defaultRegistry) RegisterInfraUsageCollector(name string, infraUsageCollector prov.InfraUsageCollector, origin string) {
-	r.infraUsageCollectorsLock.RLock()
-	defer r.infraUsageCollectorsLock.RUnlock()
-	r.infraUsageCollectors = append([]InfraUsageCollector{{Name: name, Origin: origin, InfraUsageCollector: infraUsageCollector}})
+	r.infraUsageCollectorsLock.Lock()
+	defer r.infraUsageCollectorsLock.Unlock()
+	// Put it at the beginning
+	r.infraUsageCollectors = append([]InfraUsageCollector{{Name: name, Origin: origin, InfraUsageCollector: infraUsageCollector}}, r.infraUsageCollectors...)
 }
 
 func (r *defaultRegistry) GetInfraUsageCollector(name string) (prov.InfraUsageCollector, error) {
@@ -82,6 +82,9 @@ func TestRunConsulRestPackageTests(t *testing.T) {
 		t.Run("testDeploymentHandlers", func(t *testing.T) {
 			testDeploymentHandlers(t, client, cfg, srv)
 		})
+		t.Run("testCostEstimateHandler", func(t *testing.T) {
+			testCostEstimateHandler(t, client, cfg, srv)
+		})
 		t.Run("testPostInfraUsageHandler", func(t *testing.T) {
 			testPostInfraUsageHandler(t, client, cfg, srv)
 		})
@@ -0,0 +1,135 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/csarutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// CSARSignatureHeader is the HTTP header holding the base64-encoded detached signature of an uploaded CSAR
+const CSARSignatureHeader = "X-Yorc-CSAR-Signature"
+
+// csarVerifier checks signatures of uploaded CSARs.
+//
+// A nil csarVerifier means that verification is disabled.
+type csarVerifier struct {
+	serverKeys  *csarutil.TrustedKeys
+	tenantsKeys map[string]*csarutil.TrustedKeys
+	// tenantField returns the tenant identified by the subject of a TLS client certificate
+	tenantField func(pkix.Name) string
+}
+
+// tenantCertificateFields are the supported fields of TLS client certificates subjects identifying tenants
+var tenantCertificateFields = map[string]func(pkix.Name) string{
+	"common_name":         func(n pkix.Name) string { return n.CommonName },
+	"organization":        func(n pkix.Name) string { return firstValue(n.Organization) },
+	"organizational_unit": func(n pkix.Name) string { return firstValue(n.OrganizationalUnit) },
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func newCSARVerifier(cfg config.CSARVerification) (*csarVerifier, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	field := cfg.TenantCertificateField
+	if field == "" {
+		field = "common_name"
+	}
+	tenantField, ok := tenantCertificateFields[field]
+	if !ok {
+		return nil, errors.Errorf("unsupported CSAR verification tenant certificate field %q, expecting one of common_name, organization or organizational_unit", field)
+	}
+	v := &csarVerifier{tenantsKeys: make(map[string]*csarutil.TrustedKeys, len(cfg.Tenants)), tenantField: tenantField}
+	var err error
+	v.serverKeys, err = csarutil.LoadTrustedKeys(cfg.TrustedKeys...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load CSAR verification trusted keys")
+	}
+	for tenant, tenantCfg := range cfg.Tenants {
+		// Keys trusted server-wide are also trusted for every tenant
+		keyFiles := append(append([]string{}, cfg.TrustedKeys...), tenantCfg.TrustedKeys...)
+		v.tenantsKeys[tenant], err = csarutil.LoadTrustedKeys(keyFiles...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load CSAR verification trusted keys of tenant %q", tenant)
+		}
+	}
+	return v, nil
+}
+
+// requestTenant returns the tenant of a request, identified by the configured field of the subject
+// of its TLS client certificate.
+//
+// An empty string is returned for requests without client certificate.
+func (v *csarVerifier) requestTenant(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return v.tenantField(r.TLS.PeerCertificates[0].Subject)
+}
+
+// verify checks the signature of the CSAR uploaded by r and stored into archivePath
+func (v *csarVerifier) verify(r *http.Request, archivePath string) *Error {
+	if v == nil {
+		return nil
+	}
+	tenant := v.requestTenant(r)
+	keys, ok := v.tenantsKeys[tenant]
+	if !ok {
+		keys = v.serverKeys
+	}
+	if keys.Len() == 0 {
+		return newForbiddenRequest(fmt.Sprintf("no keys are trusted to verify CSARs of tenant %q", tenant))
+	}
+
+	var signature []byte
+	if h := r.Header.Get(CSARSignatureHeader); h != "" {
+		var err error
+		signature, err = base64.StdEncoding.DecodeString(h)
+		if err != nil {
+			return newBadRequestMessage(fmt.Sprintf("invalid %s header, expecting a base64-encoded signature", CSARSignatureHeader))
+		}
+	}
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return newInternalServerError(err)
+	}
+	defer archive.Close()
+	fi, err := archive.Stat()
+	if err != nil {
+		return newInternalServerError(err)
+	}
+	err = csarutil.VerifyArchive(archive, fi.Size(), signature, keys)
+	if err != nil {
+		log.Printf("Rejecting CSAR uploaded by tenant %q: %v", tenant, err)
+		return newForbiddenRequest(fmt.Sprintf("CSAR signature verification failed: %v", err))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func writeTestECDSAKey(t *testing.T, path string) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return key
+}
+
+func testSignature(t *testing.T, key *ecdsa.PrivateKey, content []byte) string {
+	digest := sha256.Sum256(content)
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestCSARVerifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-csar-verification-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	serverKey := writeTestECDSAKey(t, filepath.Join(dir, "server.pem"))
+	tenantKey := writeTestECDSAKey(t, filepath.Join(dir, "tenant.pem"))
+	archivePath := filepath.Join(dir, "deployment.zip")
+	archive := []byte("CSAR content")
+	require.NoError(t, ioutil.WriteFile(archivePath, archive, 0644))
+
+	v, err := newCSARVerifier(config.CSARVerification{})
+	require.NoError(t, err)
+	require.Nil(t, v, "verification should be disabled without keys")
+
+	_, err = newCSARVerifier(config.CSARVerification{TrustedKeys: []string{filepath.Join(dir, "missing.pem")}})
+	require.Error(t, err)
+
+	v, err = newCSARVerifier(config.CSARVerification{
+		TrustedKeys: []string{filepath.Join(dir, "server.pem")},
+		Tenants: map[string]config.CSARVerificationTenant{
+			"team-a": {TrustedKeys: []string{filepath.Join(dir, "tenant.pem")}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		tenant     string
+		signature  string
+		wantStatus int
+	}{
+		{"ServerKey", "", testSignature(t, serverKey, archive), 0},
+		{"ServerKeyForTenant", "team-a", testSignature(t, serverKey, archive), 0},
+		{"TenantKey", "team-a", testSignature(t, tenantKey, archive), 0},
+		{"TenantKeyForOtherTenant", "team-b", testSignature(t, tenantKey, archive), http.StatusForbidden},
+		{"Unsigned", "", "", http.StatusForbidden},
+		{"InvalidHeader", "", "not base64!", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/deployments", nil)
+			if tt.tenant != "" {
+				r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tt.tenant}}}}
+			}
+			if tt.signature != "" {
+				r.Header.Set(CSARSignatureHeader, tt.signature)
+			}
+			verifyErr := v.verify(r, archivePath)
+			if tt.wantStatus == 0 {
+				require.Nil(t, verifyErr)
+				return
+			}
+			require.NotNil(t, verifyErr)
+			require.Equal(t, tt.wantStatus, verifyErr.Status)
+		})
+	}
+}
+
+func TestCSARVerifierTenantCertificateField(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-csar-verification-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTestECDSAKey(t, filepath.Join(dir, "server.pem"))
+	cfg := config.CSARVerification{TrustedKeys: []string{filepath.Join(dir, "server.pem")}}
+
+	r := httptest.NewRequest(http.MethodPost, "/deployments", nil)
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{
+		CommonName:         "alice",
+		Organization:       []string{"team-a"},
+		OrganizationalUnit: []string{"unit-a", "unit-b"},
+	}}}}
+	for field, expected := range map[string]string{
+		"":                    "alice",
+		"common_name":         "alice",
+		"organization":        "team-a",
+		"organizational_unit": "unit-a",
+	} {
+		cfg.TenantCertificateField = field
+		v, err := newCSARVerifier(cfg)
+		require.NoError(t, err)
+		require.Equal(t, expected, v.requestTenant(r), "field %q", field)
+	}
+	v, err := newCSARVerifier(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "", v.requestTenant(httptest.NewRequest(http.MethodPost, "/deployments", nil)))
+
+	cfg.TenantCertificateField = "email"
+	_, err = newCSARVerifier(cfg)
+	require.Error(t, err)
+}
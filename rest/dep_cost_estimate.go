@@ -0,0 +1,161 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/registry"
+)
+
+func (s *Server) getCostEstimateHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	if _, ok := r.URL.Query()["planned"]; ok {
+		kvp, _, err := s.consulClient.KV().Get(costEstimateKey(id), nil)
+		if err != nil {
+			log.Panicf("%v", errors.Wrap(err, consulutil.ConsulGenericErrMsg))
+		}
+		if kvp == nil || len(kvp.Value) == 0 {
+			writeError(w, r, errNotFound)
+			return
+		}
+		estimate := new(DeploymentCostEstimate)
+		if err = json.Unmarshal(kvp.Value, estimate); err != nil {
+			writeError(w, r, newInternalServerError(errors.Wrap(err, "invalid planned cost estimate")))
+			return
+		}
+		encodeJSONResponse(w, r, estimate)
+		return
+	}
+
+	estimate, err := s.estimateDeploymentCost(ctx, id)
+	if err != nil {
+		writeError(w, r, newInternalServerError(err))
+		return
+	}
+	encodeJSONResponse(w, r, estimate)
+}
+
+// costEstimateKey is the key of the cost estimate of a deployment computed before deploying it
+func costEstimateKey(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "cost_estimate")
+}
+
+// estimateDeploymentCost returns the estimated hourly cost of the nodes of a deployment
+// for which a cost estimator is registered for their infrastructure.
+func (s *Server) estimateDeploymentCost(ctx context.Context, deploymentID string) (*DeploymentCostEstimate, error) {
+	nodes, err := deployments.GetNodes(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(nodes)
+	result := &DeploymentCostEstimate{Nodes: make([]NodeCostEstimate, 0)}
+	for _, nodeName := range nodes {
+		infraType, err := deployments.GetNodeInfrastructureType(ctx, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if infraType == "" {
+			continue
+		}
+		collector, err := registry.GetRegistry().GetInfraUsageCollector(infraType)
+		if err != nil {
+			// No usage collector for this infrastructure
+			continue
+		}
+		estimator, ok := collector.(prov.CostEstimator)
+		if !ok {
+			continue
+		}
+		nbInstances, err := deployments.GetNbInstancesForNode(ctx, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		estimate, err := estimator.EstimateNodeCost(ctx, s.config, deploymentID, nodeName, int(nbInstances))
+		if err != nil {
+			return nil, err
+		}
+		if estimate == nil {
+			continue
+		}
+		nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if result.Currency == "" {
+			result.Currency = estimate.Currency
+		}
+		result.HourlyCost += estimate.HourlyCost
+		result.Nodes = append(result.Nodes, NodeCostEstimate{
+			Name:         nodeName,
+			Type:         nodeType,
+			Instances:    int(nbInstances),
+			CostEstimate: *estimate,
+		})
+	}
+	return result, nil
+}
+
+// storeDeploymentPlan stores the estimated cost of the nodes of a deployment before deploying them,
+// and publishes it in deployment logs
+func (s *Server) storeDeploymentPlan(ctx context.Context, deploymentID string) error {
+	estimate, err := s.estimateDeploymentCost(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	if len(estimate.Nodes) == 0 {
+		return nil
+	}
+	if err = consulutil.StoreConsulKeyWithJSONValue(costEstimateKey(deploymentID), estimate); err != nil {
+		return err
+	}
+	logger := events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID)
+	logger.Registerf("Deployment plan:")
+	for _, node := range estimate.Nodes {
+		logger.Registerf("  - node %q of type %q: %d instance(s), estimated hourly cost %.4f %s",
+			node.Name, node.Type, node.Instances, node.HourlyCost, node.Currency)
+		if len(node.UnpricedResources) > 0 {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf(
+				"    resources of node %q not found in price table, not part of the estimate: %v", node.Name, node.UnpricedResources)
+		}
+	}
+	logger.Registerf("Estimated hourly cost of the deployment: %.4f %s", estimate.HourlyCost, estimate.Currency)
+	return nil
+}
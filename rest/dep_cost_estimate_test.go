@@ -0,0 +1,76 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+func testCostEstimateHandler(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	t.Parallel()
+	deploymentID := "testCostEstimateHandler"
+	prepareTest(t, deploymentID, client, srv)
+	defer cleanTest(deploymentID, "")
+
+	getEstimate := func(url string) (int, *DeploymentCostEstimate) {
+		req := httptest.NewRequest("GET", url, nil)
+		req.Header.Add("Accept", mimeTypeApplicationJSON)
+		resp := newTestHTTPRouter(client, cfg, req)
+		require.NotNil(t, resp, "unexpected nil response")
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		estimate := new(DeploymentCostEstimate)
+		require.NoError(t, json.Unmarshal(body, estimate))
+		return resp.StatusCode, estimate
+	}
+
+	// No estimator for the nodes of this deployment
+	status, estimate := getEstimate("/deployments/" + deploymentID + "/cost_estimate")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, estimate.Nodes, 0)
+
+	status, _ = getEstimate("/deployments/" + deploymentID + "/cost_estimate?planned")
+	require.Equal(t, http.StatusNotFound, status)
+
+	planned := &DeploymentCostEstimate{
+		Currency:   "USD",
+		HourlyCost: 0.0116,
+		Nodes: []NodeCostEstimate{
+			{Name: "Compute", Type: "yorc.nodes.aws.Compute", Instances: 1, CostEstimate: prov.CostEstimate{Currency: "USD", HourlyCost: 0.0116}},
+		},
+	}
+	require.NoError(t, consulutil.StoreConsulKeyWithJSONValue(costEstimateKey(deploymentID), planned))
+	status, estimate = getEstimate("/deployments/" + deploymentID + "/cost_estimate?planned")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, planned, estimate)
+
+	status, _ = getEstimate("/deployments/unknownDeployment/cost_estimate?planned")
+	require.Equal(t, http.StatusNotFound, status)
+}
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/ystia/yorc/v4/helper/collections"
@@ -101,6 +102,9 @@ func (s *Server) newCustomCommandHandler(w http.ResponseWriter, r *http.Request)
 	data[path.Join("nodes", nodeName)] = strings.Join(instances, ",")
 	data["commandName"] = ccRequest.CustomCommandName
 	data["interfaceName"] = ccRequest.InterfaceName
+	if _, ok := r.URL.Query()["checkMode"]; ok {
+		data["checkMode"] = strconv.FormatBool(true)
+	}
 
 	for _, name := range inputsName {
 		if err != nil {
@@ -0,0 +1,112 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/terraform/commons"
+)
+
+func (s *Server) getTerraformStateHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	nodeName := params.ByName("nodeName")
+
+	if !checkNodeExists(ctx, w, r, id, nodeName) {
+		return
+	}
+
+	state, err := commons.PullState(ctx, s.config, id, nodeName)
+	if err != nil {
+		writeError(w, r, newInternalServerError(err))
+		return
+	}
+	if len(state) == 0 {
+		writeError(w, r, errNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mimeTypeApplicationJSON)
+	w.Write(state)
+}
+
+func (s *Server) putTerraformStateHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	nodeName := params.ByName("nodeName")
+
+	if !checkNodeExists(ctx, w, r, id, nodeName) {
+		return
+	}
+	if !checkBlockingOperationOnDeployment(ctx, id, w, r) {
+		return
+	}
+
+	var force bool
+	var err error
+	if value := r.URL.Query().Get("force"); value != "" {
+		if force, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, newBadRequestParameter("force", errors.Wrap(err, "force query parameter must be a boolean value")))
+			return
+		}
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if err = commons.PushState(ctx, s.config, id, nodeName, body, force); err != nil {
+		if commons.IsInvalidStateError(err) {
+			writeError(w, r, newBadRequestError(err))
+		} else {
+			writeError(w, r, newInternalServerError(err))
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func checkNodeExists(ctx context.Context, w http.ResponseWriter, r *http.Request, deploymentID, nodeName string) bool {
+	dExits, err := deployments.DoesDeploymentExists(ctx, deploymentID)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return false
+	}
+	nodeExists, err := deployments.DoesNodeExist(ctx, deploymentID, nodeName)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !nodeExists {
+		writeError(w, r, errNotFound)
+		return false
+	}
+	return true
+}
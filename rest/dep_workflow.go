@@ -77,6 +77,9 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		data["continueOnError"] = strconv.FormatBool(false)
 	}
+	if _, ok := r.URL.Query()["checkMode"]; ok {
+		data["checkMode"] = strconv.FormatBool(true)
+	}
 	// Get instances selection if provided in the request body
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
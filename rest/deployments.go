@@ -56,7 +56,9 @@ func extractFile(f *zip.File, path string) {
 
 // unzipArchiveGetTopology unzips an archive and return the path to its topology
 // yaml file
-func unzipArchiveGetTopology(workingDir, deploymentID string, r *http.Request) (string, *Error) {
+//
+// The archive signature is checked by verifier before extracting it.
+func unzipArchiveGetTopology(workingDir, deploymentID string, r *http.Request, verifier *csarVerifier) (string, *Error) {
 	var err error
 	var file *os.File
 
@@ -76,6 +78,12 @@ func unzipArchiveGetTopology(workingDir, deploymentID string, r *http.Request) (
 	if err != nil {
 		return "", newInternalServerError(err)
 	}
+	if verifyErr := verifier.verify(r, file.Name()); verifyErr != nil {
+		// Do not keep untrusted content around
+		file.Close()
+		os.RemoveAll(uploadPath)
+		return "", verifyErr
+	}
 	destDir := filepath.Join(uploadPath, "overlay")
 	if err = os.MkdirAll(destDir, 0775); err != nil {
 		return "", newInternalServerError(err)
@@ -166,7 +174,7 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("Analyzing deployment %s\n", uid)
 
-	yamlFile, archiveErr := unzipArchiveGetTopology(s.config.WorkingDirectory, uid, r)
+	yamlFile, archiveErr := unzipArchiveGetTopology(s.config.WorkingDirectory, uid, r, s.csarVerifier)
 	if archiveErr != nil {
 		log.Printf("Error analyzing archive for deployment %s\n", uid)
 		writeError(w, r, archiveErr)
@@ -196,6 +204,11 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 		log.Debugf("ERROR: %+v", err)
 		log.Panic(err)
 	}
+	if err := s.storeDeploymentPlan(ctx, uid); err != nil {
+		// The deployment goes on without cost estimate
+		log.Printf("Failed to estimate costs of deployment %q: %v", uid, err)
+	}
+
 	data := map[string]string{
 		"workflowName": "install",
 	}
@@ -97,6 +97,7 @@ type Server struct {
 	config         config.Configuration
 	hostsPoolMgr   hostspool.Manager
 	locationMgr    locations.Manager
+	csarVerifier   *csarVerifier
 }
 
 // Shutdown stops the HTTP server
@@ -116,6 +117,10 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 	if err != nil {
 		return nil, err
 	}
+	csarVerifier, err := newCSARVerifier(configuration.CSARVerification)
+	if err != nil {
+		return nil, err
+	}
 	listener, err := net.Listen(addr.Network(), addr.String())
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to bind on %s", addr)
@@ -136,6 +141,7 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 		config:         configuration,
 		hostsPoolMgr:   hostspool.NewManager(client, configuration),
 		locationMgr:    locations.NewManager(client, configuration),
+		csarVerifier:   csarVerifier,
 	}
 
 	httpServer.registerHandlers()
@@ -175,6 +181,9 @@ func (s *Server) registerHandlers() {
 	s.router.Head("/logs", commonHandlers.ThenFunc(s.headLogsEventsIndex))
 	s.router.Get("/deployments/:id/nodes/:nodeName", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getNodeHandler))
 	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getNodeInstanceHandler))
+	s.router.Get("/deployments/:id/nodes/:nodeName/terraform_state", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getTerraformStateHandler))
+	s.router.Put("/deployments/:id/nodes/:nodeName/terraform_state", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.putTerraformStateHandler))
+	s.router.Get("/deployments/:id/cost_estimate", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getCostEstimateHandler))
 	s.router.Get("/deployments/:id/outputs", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listOutputsHandler))
 	s.router.Get("/deployments/:id/outputs/:opt", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getOutputHandler))
 	s.router.Get("/deployments/:id/tasks/:taskId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getTaskHandler))
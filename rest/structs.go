@@ -22,6 +22,7 @@ import (
 
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments/store"
+	"github.com/ystia/yorc/v4/prov"
 	"github.com/ystia/yorc/v4/prov/hostspool"
 	"github.com/ystia/yorc/v4/registry"
 	"github.com/ystia/yorc/v4/tosca"
@@ -337,3 +338,18 @@ type Info struct {
 	YorcVersion string `json:"yorc_version"`
 	GitCommit   string `json:"git_commit"`
 }
+
+// DeploymentCostEstimate is the estimated hourly cost of the resources of a deployment
+type DeploymentCostEstimate struct {
+	Currency   string             `json:"currency,omitempty"`
+	HourlyCost float64            `json:"hourly_cost"`
+	Nodes      []NodeCostEstimate `json:"nodes"`
+}
+
+// NodeCostEstimate is the estimated hourly cost of all instances of a node
+type NodeCostEstimate struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Instances int    `json:"instances"`
+	prov.CostEstimate
+}
@@ -18,10 +18,16 @@ package server
 import (
 	// Registering AWS delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/terraform/aws"
+	// Registering Azure delegate executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/terraform/azure"
 	// Registering Google Cloud delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/terraform/google"
 	// Registering openstack delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/terraform/openstack"
+	// Registering vSphere delegate executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/terraform/vsphere"
+	// Registering libvirt delegate executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/terraform/libvirt"
 	// Registering ansible operation executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/ansible"
 	// Registering kubernetes operation executor in the registry
@@ -30,6 +36,8 @@ import (
 	_ "github.com/ystia/yorc/v4/prov/slurm"
 	// Registering hosts pool delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/hostspool"
+	// Registering docker delegate executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/docker"
 	// Registering builtin Tosca definition files
 	_ "github.com/ystia/yorc/v4/tosca"
 	// Registering builtin HashiCorp Vault Client Builder
@@ -30,6 +30,7 @@ import (
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/ansible"
 	"github.com/ystia/yorc/v4/prov/monitoring"
 	"github.com/ystia/yorc/v4/prov/scheduling/scheduler"
 	"github.com/ystia/yorc/v4/rest"
@@ -135,6 +136,11 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 		return err
 	}
 
+	err = ansible.RegisterExecutableArtifacts(configuration)
+	if err != nil {
+		return err
+	}
+
 	httpServer, err := rest.NewServer(configuration, client, shutdownCh)
 	if err != nil {
 		return err
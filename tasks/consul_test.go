@@ -61,6 +61,9 @@ func TestRunConsulTasksPackageTests(t *testing.T) {
 		t.Run("TestGetTaskInput", func(t *testing.T) {
 			testGetTaskInput(t)
 		})
+		t.Run("TestIsCheckModeTask", func(t *testing.T) {
+			testIsCheckModeTask(t)
+		})
 		t.Run("TestGetInstances", func(t *testing.T) {
 			testGetInstances(t)
 		})
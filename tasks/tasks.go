@@ -391,6 +391,20 @@ func GetTaskData(taskID, dataName string) (string, error) {
 	return value, nil
 }
 
+// IsCheckModeTask checks if a task was submitted in check mode, meaning that operations
+// should only report changes they would make instead of applying them
+func IsCheckModeTask(taskID string) (bool, error) {
+	checkMode, err := GetTaskData(taskID, "checkMode")
+	if err != nil {
+		if IsTaskDataNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	b, err := strconv.ParseBool(checkMode)
+	return b, errors.Wrapf(err, "failed to parse \"checkMode\" flag for task %q", taskID)
+}
+
 // GetAllTaskData returns all registered data for a task
 func GetAllTaskData(taskID string) (map[string]string, error) {
 	dataPrefix := path.Join(consulutil.TasksPrefix, taskID, "data")
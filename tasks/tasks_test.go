@@ -49,6 +49,7 @@ func populateKV(t *testing.T, srv *testutil.TestServer) {
 		consulutil.TasksPrefix + "/t1/type":             []byte("0"),
 		consulutil.TasksPrefix + "/t1/data/inputs/i0":   []byte("0"),
 		consulutil.TasksPrefix + "/t1/data/nodes/node1": []byte("0,1,2"),
+		consulutil.TasksPrefix + "/t1/data/checkMode":   []byte("true"),
 		consulutil.TasksPrefix + "/t2/targetId":         []byte("id1"),
 		consulutil.TasksPrefix + "/t2/status":           []byte("1"),
 		consulutil.TasksPrefix + "/t2/type":             []byte("1"),
@@ -58,6 +59,7 @@ func populateKV(t *testing.T, srv *testutil.TestServer) {
 		consulutil.TasksPrefix + "/t3/data/nodes/n1":    []byte("2"),
 		consulutil.TasksPrefix + "/t3/data/nodes/n2":    []byte("2"),
 		consulutil.TasksPrefix + "/t3/data/nodes/n3":    []byte("2"),
+		consulutil.TasksPrefix + "/t3/data/checkMode":   []byte("notabool"),
 		consulutil.TasksPrefix + "/t4/targetId":         []byte("id1"),
 		consulutil.TasksPrefix + "/t4/status":           []byte("3"),
 		consulutil.TasksPrefix + "/t4/type":             []byte("3"),
@@ -380,6 +382,31 @@ func testGetTaskInput(t *testing.T) {
 	}
 }
 
+func testIsCheckModeTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskID  string
+		want    bool
+		wantErr bool
+	}{
+		{"CheckMode", "t1", true, false},
+		{"NoCheckModeData", "t2", false, false},
+		{"InvalidCheckModeData", "t3", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsCheckModeTask(tt.taskID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsCheckModeTask() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsCheckModeTask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func testGetInstances(t *testing.T) {
 	type args struct {
 		taskID       string
@@ -31,6 +31,7 @@ import (
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/metricsutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
 	"github.com/ystia/yorc/v4/prov/operations"
 	"github.com/ystia/yorc/v4/prov/scheduling"
 	"github.com/ystia/yorc/v4/registry"
@@ -170,6 +171,11 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, deploymentID s
 	}
 	s.setStatus(tasks.TaskStepStatusRUNNING)
 
+	checkMode, err := tasks.IsCheckModeTask(s.t.taskID)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancelWf := context.WithCancel(ctx)
 	defer cancelWf()
 	if !s.IsOnCancelPath {
@@ -208,9 +214,11 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, deploymentID s
 					hook(ctx, cfg, s.t.taskID, deploymentID, s.Target, activity)
 				}
 			}()
-			err := s.runActivity(ctx, cfg, deploymentID, workflowName, bypassErrors, w, activity)
+			err := s.runActivity(ctx, cfg, deploymentID, workflowName, bypassErrors, checkMode, w, activity)
 			if err != nil {
-				setNodeStatus(ctx, s.t.taskID, deploymentID, s.Target, tosca.NodeStateError.String())
+				if !checkMode {
+					setNodeStatus(ctx, s.t.taskID, deploymentID, s.Target, tosca.NodeStateError.String())
+				}
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("TaskStep %q: error details: %+v", s.Name, err)
 				// Set step in error but continue if needed
 				s.setStatus(tasks.TaskStepStatusERROR)
@@ -241,7 +249,7 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, deploymentID s
 	return nil
 }
 
-func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, activity builder.Activity) error {
+func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, deploymentID, workflowName string, bypassErrors, checkMode bool, w *worker, activity builder.Activity) error {
 	// Get activity related instances
 	instances, err := tasks.GetInstances(wfCtx, s.t.taskID, deploymentID, s.Target)
 	if err != nil {
@@ -251,6 +259,10 @@ func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, depl
 	eventInfo := &events.WorkflowStepInfo{WorkflowName: workflowName, NodeName: s.Target, StepName: s.Name}
 	switch activity.Type() {
 	case builder.ActivityTypeDelegate:
+		if checkMode {
+			events.WithContextOptionalFields(wfCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Delegate operation %q of node %q is not supported in check mode, skipping it", activity.Value(), s.Target)
+			return nil
+		}
 		nodeType, err := deployments.GetNodeType(wfCtx, deploymentID, s.Target)
 		if err != nil {
 			return err
@@ -290,6 +302,10 @@ func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, depl
 			s.publishInstanceRelatedEvents(wfCtx, deploymentID, instanceName, eventInfo, tasks.TaskStepStatusDONE)
 		}
 	case builder.ActivityTypeSetState:
+		if checkMode {
+			// States are left unchanged in check mode
+			return nil
+		}
 		setNodeStatus(wfCtx, s.t.taskID, deploymentID, s.Target, activity.Value())
 	case builder.ActivityTypeCallOperation:
 		inputParameters, err := s.getActivityInputParameters(wfCtx, activity, deploymentID, workflowName)
@@ -315,6 +331,15 @@ func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, depl
 			return err
 		}
 		wfCtx = operations.SetOperationLogFields(wfCtx, op)
+		var checkModeExec prov.CheckModeOperationExecutor
+		if checkMode {
+			var ok bool
+			checkModeExec, ok = exec.(prov.CheckModeOperationExecutor)
+			if !ok || s.Async {
+				events.WithContextOptionalFields(wfCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("Operation %q of node %q is not supported in check mode, skipping it", op.Name, s.Target)
+				return nil
+			}
+		}
 		for _, instanceName := range instances {
 			// Check for specific info about relationships
 			eventInfo.OperationName = op.Name
@@ -364,6 +389,9 @@ func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, depl
 		} else {
 			err = func() error {
 				defer metrics.MeasureSinceWithLabels(metricsutil.CleanupMetricKey([]string{"executor", "operation", "duration"}), time.Now(), executorOperationLabels)
+				if checkModeExec != nil {
+					return checkModeExec.ExecOperationInCheckMode(wfCtx, cfg, s.t.taskID, deploymentID, s.Target, op)
+				}
 				return exec.ExecOperation(wfCtx, cfg, s.t.taskID, deploymentID, s.Target, op)
 			}()
 		}
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/sshutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/tasks"
 )
@@ -214,6 +215,13 @@ func setTaskStatus(ctx context.Context, targetID, taskID string, status tasks.Ta
 		return checkAndSetTaskStatus(ctx, targetID, taskID, status, errReason)
 	}
 
+	if status == tasks.TaskStatusDONE || status == tasks.TaskStatusFAILED || status == tasks.TaskStatusCANCELED {
+		// Close OpenSSH master connections shared by operations of the task
+		if err = sshutil.CloseControlMasters(sshutil.ControlMastersDir(taskID)); err != nil {
+			log.Printf("[WARNING] Failed to close SSH master connections of taskID:%q due to error:%+v", taskID, err)
+		}
+	}
+
 	// Emit event for status change
 	// wfName may be empty as this data is not filled for non-workflow task type (as for custom command by instance)
 	wfName, _ := tasks.GetTaskData(taskID, "workflowName")
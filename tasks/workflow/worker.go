@@ -293,12 +293,19 @@ func (w *worker) runCustomCommand(ctx context.Context, t *taskExecution) (contex
 		return ctx, errors.Wrapf(err, "expecting custom command to be related to \"1\" node while it is actually related to \"%d\" nodes", len(nodes))
 	}
 	nodeName := nodes[0]
+	checkMode, err := tasks.IsCheckModeTask(t.taskID)
+	if err != nil {
+		return ctx, err
+	}
 	nodeType, err := deployments.GetNodeType(ctx, t.targetID, nodeName)
 	if err != nil {
 		return ctx, err
 	}
 	op, err := operations.GetOperation(ctx, t.targetID, nodeName, interfaceName+"."+commandName, "", "", nil)
 	if err != nil {
+		if checkMode {
+			return ctx, errors.Wrapf(err, "Command TaskExecution failed for node %q", nodeName)
+		}
 		err = setNodeStatus(ctx, t.taskID, t.targetID, nodeName, tosca.NodeStateError.String())
 		if err != nil {
 			log.Printf("Deployment id: %q, Task id: %q, Failed to set status for node %q: %+v", t.targetID, t.taskID, nodeName, err)
@@ -307,6 +314,9 @@ func (w *worker) runCustomCommand(ctx context.Context, t *taskExecution) (contex
 	}
 	exec, err := getOperationExecutor(ctx, t.targetID, op.ImplementationArtifact)
 	if err != nil {
+		if checkMode {
+			return ctx, errors.Wrapf(err, "Command TaskExecution failed for node %q", nodeName)
+		}
 		err = setNodeStatus(ctx, t.taskID, t.targetID, nodeName, tosca.NodeStateError.String())
 		if err != nil {
 			log.Printf("Deployment id: %q, Task id: %q, Failed to set status for node %q: %+v", t.targetID, t.taskID, nodeName, err)
@@ -317,6 +327,14 @@ func (w *worker) runCustomCommand(ctx context.Context, t *taskExecution) (contex
 	ctx = operations.SetOperationLogFields(ctx, op)
 	ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName, events.OperationName: op.Name})
 
+	if checkMode {
+		checkModeExec, ok := exec.(prov.CheckModeOperationExecutor)
+		if !ok {
+			return ctx, errors.Errorf("operation %q of node %q is not supported in check mode", op.Name, nodeName)
+		}
+		return ctx, checkModeExec.ExecOperationInCheckMode(ctx, w.cfg, t.taskID, t.targetID, nodeName, op)
+	}
+
 	executorOperationLabels := []metrics.Label{
 		metrics.Label{Name: "Deployment", Value: t.targetID},
 		metrics.Label{Name: "Name", Value: op.Name},
@@ -638,6 +656,8 @@ func (w *worker) runPurge(ctx context.Context, t *taskExecution) error {
 		// note that it is expected to be on 2 different lines to let final function detect it
 		return err
 	}
+	// Delegate executors data should be removed while nodes definitions are still available
+	w.purgeNodesData(ctx, t.targetID)
 	kv := w.consulClient.KV()
 	// Remove from KV all tasks from the current target deployment, except this purge task
 	tasksList, err := deployments.GetDeploymentTaskList(ctx, t.targetID)
@@ -686,6 +706,35 @@ func (w *worker) runPurge(ctx context.Context, t *taskExecution) error {
 	return deployments.TagDeploymentAsPurged(ctx, t.cc, t.targetID)
 }
 
+// purgeNodesData removes data stored by delegate executors outside of the deployment, such as Terraform states.
+//
+// Errors are only logged as they should not prevent the deployment from being purged.
+func (w *worker) purgeNodesData(ctx context.Context, deploymentID string) {
+	nodes, err := deployments.GetNodes(ctx, deploymentID)
+	if err != nil {
+		log.Printf("Deployment %q: failed to get nodes to purge their data: %v", deploymentID, err)
+		return
+	}
+	reg := registry.GetRegistry()
+	for _, nodeName := range nodes {
+		nodeType, err := deployments.GetNodeType(ctx, deploymentID, nodeName)
+		if err != nil {
+			log.Printf("Deployment %q: failed to get type of node %q to purge its data: %v", deploymentID, nodeName, err)
+			continue
+		}
+		exec, err := reg.GetDelegateExecutor(nodeType)
+		if err != nil {
+			// Not a delegate node
+			continue
+		}
+		if purger, ok := exec.(prov.DeploymentPurger); ok {
+			if err = purger.PurgeNode(ctx, w.cfg, deploymentID, nodeName); err != nil {
+				log.Printf("Deployment %q: failed to purge data of node %q, it should be removed manually: %v", deploymentID, nodeName, err)
+			}
+		}
+	}
+}
+
 func (w *worker) runScaleOut(ctx context.Context, t *taskExecution) error {
 	status, err := deployments.GetDeploymentStatus(ctx, t.targetID)
 	if err != nil {
@@ -36,10 +36,14 @@ func TestAssets(t *testing.T) {
 		t.Run("NormativeTypes", testAssetNormativeParsing)
 		t.Run("OpenstackTypes", testAssetYorcOpenStackParsing)
 		t.Run("AWSTypes", testAssetYorcAwsParsing)
+		t.Run("AzureTypes", testAssetYorcAzureParsing)
 		t.Run("GoogleTypes", testAssetYorcGoogleParsing)
+		t.Run("VSphereTypes", testAssetYorcVSphereParsing)
+		t.Run("LibvirtTypes", testAssetYorcLibvirtParsing)
 		t.Run("YorcTypes", testAssetYorcParsing)
 		t.Run("SlurmTypes", testAssetYorcSlurmParsing)
 		t.Run("HostsPoolTypes", testAssetYorcHostsPoolParsing)
+		t.Run("DockerTypes", testAssetYorcDockerParsing)
 	})
 }
 
@@ -69,11 +73,26 @@ func testAssetYorcAwsParsing(t *testing.T) {
 	checkBuiltinTypesPath(t, "yorc-aws-types")
 }
 
+func testAssetYorcAzureParsing(t *testing.T) {
+	t.Parallel()
+	checkBuiltinTypesPath(t, "yorc-azure-types")
+}
+
 func testAssetYorcGoogleParsing(t *testing.T) {
 	t.Parallel()
 	checkBuiltinTypesPath(t, "yorc-google-types")
 }
 
+func testAssetYorcVSphereParsing(t *testing.T) {
+	t.Parallel()
+	checkBuiltinTypesPath(t, "yorc-vsphere-types")
+}
+
+func testAssetYorcLibvirtParsing(t *testing.T) {
+	t.Parallel()
+	checkBuiltinTypesPath(t, "yorc-libvirt-types")
+}
+
 func testAssetYorcParsing(t *testing.T) {
 	t.Parallel()
 	checkBuiltinTypesPath(t, "yorc-types")
@@ -88,3 +107,8 @@ func testAssetYorcHostsPoolParsing(t *testing.T) {
 	t.Parallel()
 	checkBuiltinTypesPath(t, "yorc-hostspool-types")
 }
+
+func testAssetYorcDockerParsing(t *testing.T) {
+	t.Parallel()
+	checkBuiltinTypesPath(t, "yorc-docker-types")
+}
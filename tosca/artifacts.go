@@ -70,6 +70,9 @@ type ArtifactDefinition struct {
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 	Repository  string `yaml:"repository,omitempty" json:"repository,omitempty"`
 	DeployPath  string `yaml:"deploy_path,omitempty" json:"deploy_path,omitempty"`
+	// Checksum of the artifact file, verified when the artifact is downloaded from its repository
+	Checksum          string `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	ChecksumAlgorithm string `yaml:"checksum_algorithm,omitempty" json:"checksum_algorithm,omitempty"`
 	// Extra types used in list (A4C) mode
 	name string
 }
@@ -88,6 +91,9 @@ func (a *ArtifactDefinition) UnmarshalYAML(unmarshal func(interface{}) error) er
 		Repository  string `yaml:"repository,omitempty"`
 		DeployPath  string `yaml:"deploy_path,omitempty"`
 
+		Checksum          string `yaml:"checksum,omitempty"`
+		ChecksumAlgorithm string `yaml:"checksum_algorithm,omitempty"`
+
 		// Extra types
 		MimeType string                 `yaml:"mime_type,omitempty"`
 		XXX      map[string]interface{} `yaml:",inline"`
@@ -101,6 +107,8 @@ func (a *ArtifactDefinition) UnmarshalYAML(unmarshal func(interface{}) error) er
 	a.Description = str.Description
 	a.Repository = str.Repository
 	a.DeployPath = str.DeployPath
+	a.Checksum = str.Checksum
+	a.ChecksumAlgorithm = str.ChecksumAlgorithm
 	if str.File == "" && len(str.XXX) == 1 {
 		for k, v := range str.XXX {
 			a.name = k
@@ -57,7 +57,9 @@ artifact:
   description: artifact_description
   type: artifact_type_name
   repository: artifact_repository_name
-  deploy_path: file_deployment_path`
+  deploy_path: file_deployment_path
+  checksum: 9b74c9897bac770ffc029102a200c5de
+  checksum_algorithm: MD5`
 
 	var artMap map[string]ArtifactDefinition
 
@@ -71,6 +73,8 @@ artifact:
 	assert.Equal(t, "artifact_type_name", art.Type)
 	assert.Equal(t, "artifact_repository_name", art.Repository)
 	assert.Equal(t, "file_deployment_path", art.DeployPath)
+	assert.Equal(t, "9b74c9897bac770ffc029102a200c5de", art.Checksum)
+	assert.Equal(t, "MD5", art.ChecksumAlgorithm)
 }
 
 func artifactDefinitionConcreteUnmarshalYAMLFailure(t *testing.T) {
@@ -14,6 +14,12 @@
 
 package tosca
 
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 const (
 	// TemplateName is the optional descriptive name for the template provided
 	// in the template metadata
@@ -99,6 +105,42 @@ type Credential struct {
 	Keys      map[string]string `yaml:"keys,omitempty" json:"keys,omitempty"`
 }
 
+// UnmarshalYAML unmarshals a yaml into a Credential
+//
+// The token may be a get_secret function, its representation is then kept
+// as token to be resolved when the credential is used.
+func (c *Credential) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str struct {
+		TokenType string            `yaml:"token_type"`
+		Token     interface{}       `yaml:"token"`
+		User      string            `yaml:"user,omitempty"`
+		Protocol  string            `yaml:"protocol,omitempty"`
+		Keys      map[string]string `yaml:"keys,omitempty"`
+	}
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	c.TokenType = str.TokenType
+	c.User = str.User
+	c.Protocol = str.Protocol
+	c.Keys = str.Keys
+	switch t := str.Token.(type) {
+	case nil:
+	case map[interface{}]interface{}:
+		f, err := parseFunction(t)
+		if err != nil {
+			return errors.Wrap(err, "invalid credential token")
+		}
+		if f.Operator != GetSecretOperator {
+			return errors.Errorf("only %s functions are supported in credential tokens, got %q", GetSecretOperator, f.Operator)
+		}
+		c.Token = f.String()
+	default:
+		c.Token = fmt.Sprint(t)
+	}
+	return nil
+}
+
 // A Policy represents a Tosca Policy definition
 // See https://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.2/os/TOSCA-Simple-Profile-YAML-v1.2-os.html#DEFN_ELEMENT_POLICY_DEF
 // for the first implementation, we don't handle triggers
@@ -55,3 +55,39 @@ topology_template:
 	require.Equal(t, false, *input.Required)
 	require.Equal(t, `http://10.197.132.16/sla`, input.Value.GetLiteral())
 }
+
+func TestRepositoryCredentials(t *testing.T) {
+	data := `
+repositories:
+  literal:
+    url: https://repo.example.com/
+    credential:
+      user: myuser
+      token: my_super_secret_passwd
+  secret:
+    url: https://repo.example.com/
+    credential:
+      user: myuser
+      token: { get_secret: [/secrets/data/repo, "data=token"] }
+`
+	topo := Topology{}
+
+	err := yaml.Unmarshal([]byte(data), &topo)
+	require.NoError(t, err)
+	require.Equal(t, "my_super_secret_passwd", topo.Repositories["literal"].Credit.Token)
+	require.Equal(t, "myuser", topo.Repositories["secret"].Credit.User)
+	fn, err := ParseFunction(topo.Repositories["secret"].Credit.Token)
+	require.NoError(t, err)
+	require.Equal(t, GetSecretOperator, fn.Operator)
+	require.Len(t, fn.Operands, 2)
+	require.Equal(t, "/secrets/data/repo", fn.Operands[0].String())
+
+	err = yaml.Unmarshal([]byte(`
+repositories:
+  other:
+    url: https://repo.example.com/
+    credential:
+      token: { get_input: token }
+`), &topo)
+	require.Error(t, err)
+}